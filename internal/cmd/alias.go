@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+)
+
+var aliasDelete bool
+
+// newAliasCmd builds the `alias` subcommand for a provider.
+func newAliasCmd(providerName string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias [<conversation-id> <name>]",
+		Short: "Name a conversation for use with -c",
+		Long: fmt.Sprintf(`Give a conversation a friendly name, then continue it with -c <name>:
+  ask %[1]s alias <conversation-id> infra-debug
+  ask %[1]s -c infra-debug "follow up"
+With no arguments, list the aliases. Remove one with: ask %[1]s alias -d <name>`, providerName),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if aliasDelete {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			if len(args) == 1 {
+				return fmt.Errorf("expected <conversation-id> <name>, or no arguments to list")
+			}
+			return cobra.MaximumNArgs(2)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAlias(providerName, args)
+		},
+	}
+	cmd.Flags().BoolVarP(&aliasDelete, "delete", "d", false, "Remove the named alias")
+	return cmd
+}
+
+func runAlias(providerName string, args []string) error {
+	state := config.LoadState()
+
+	switch {
+	case aliasDelete:
+		removed := false
+		if err := config.UpdateState(func(state *config.State) {
+			removed = state.RemoveAlias(providerName, args[0])
+		}); err != nil {
+			return err
+		}
+		if !removed {
+			return fmt.Errorf("no %s alias named %q", providerName, args[0])
+		}
+		fmt.Printf("Removed alias %s\n", args[0])
+
+	case len(args) == 0:
+		aliases := state.Aliases[providerName]
+		if len(aliases) == 0 {
+			fmt.Println("No aliases.")
+			return nil
+		}
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  %-20s %s\n", name, aliases[name])
+		}
+
+	default:
+		id, name := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+		if id == "" || name == "" || strings.ContainsAny(name, " \t\n") {
+			return fmt.Errorf("alias name must be a single word")
+		}
+		// Allow aliasing through another alias.
+		var target string
+		if err := config.UpdateState(func(state *config.State) {
+			target = state.ResolveAlias(providerName, id)
+			state.SetAlias(providerName, name, target)
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("Aliased %s → %s\n", name, target)
+	}
+	return nil
+}
+
+// resolveConversationID trims ref and maps a conversation alias to its ID.
+func resolveConversationID(providerName, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	return config.LoadState().ResolveAlias(providerName, ref)
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+	"github.com/kyupark/ask/internal/provider/anthropicapi"
+	claudepkg "github.com/kyupark/ask/internal/provider/claude"
+)
+
+var (
+	anthropicAPIModel          string
+	anthropicAPIThinkingBudget int
+	anthropicAPIResume         bool
+	anthropicAPIConversation   string
+)
+
+var anthropicAPICmd = &cobra.Command{
+	Use:   "anthropic-api [question]",
+	Short: "Anthropic API commands",
+	Long: `Ask Claude through the Anthropic Messages API with an API key instead of
+claude.ai cookies. Usage is billed to the key's account.
+  <question>      Ask a question (saves to history)
+  ask-incognito  Ask a question (no history)
+  list           List saved conversations
+  show           Show a conversation transcript
+  export         Export a conversation
+  retry          Get a new answer to the last question
+  delete         Delete a conversation by ID
+  models         Show available models
+  alias          Name a conversation for use with -c
+The key comes from anthropic_api.api_key or ANTHROPIC_API_KEY; with one set
+the API also joins ask all. Conversations are kept locally, since the API
+stores none. To fall back to the API when claude.ai fails:
+  ask config set claude.api_fallback true`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !flagEdit {
+			return cmd.Help()
+		}
+		return runAnthropicAPIAsk(cmd, args, false)
+	},
+}
+
+var anthropicAPIAskIncognitoCmd = &cobra.Command{
+	Use:   "ask-incognito [question]",
+	Short: "Ask the Anthropic API (no history)",
+	Args:  questionArgs,
+	RunE:  func(cmd *cobra.Command, args []string) error { return runAnthropicAPIAsk(cmd, args, true) },
+}
+
+var anthropicAPIListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved Anthropic API conversations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runList(cmd.Context(), newAnthropicAPIProvider(), listOptions())
+	},
+}
+
+var anthropicAPIDeleteCmd = &cobra.Command{
+	Use:   "delete <conversation-id>",
+	Short: "Delete an Anthropic API conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDelete(cmd.Context(), newAnthropicAPIProvider(), args[0])
+	},
+}
+
+var anthropicAPIModelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Show available Anthropic API models",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runModels(newAnthropicAPIProvider())
+	},
+}
+
+func init() {
+	anthropicAPICmd.Flags().StringVarP(&anthropicAPIModel, "model", "m", "", "Model (e.g. 'claude-opus-4-6', 'claude-sonnet-4-6')")
+	anthropicAPICmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print Claude's thinking before the answer (needs a thinking budget)")
+	anthropicAPICmd.Flags().IntVar(&anthropicAPIThinkingBudget, "thinking-budget", 0, "Thinking budget in tokens (min 1024)")
+	anthropicAPICmd.Flags().BoolVarP(&anthropicAPIResume, "resume", "r", false, "Resume last conversation")
+	anthropicAPICmd.Flags().StringVarP(&anthropicAPIConversation, "conversation", "c", "", "Continue a specific conversation by ID or alias")
+	anthropicAPIAskIncognitoCmd.Flags().StringVarP(&anthropicAPIModel, "model", "m", "", "Model (e.g. 'claude-opus-4-6', 'claude-sonnet-4-6')")
+	anthropicAPIAskIncognitoCmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print Claude's thinking before the answer (needs a thinking budget)")
+	anthropicAPIAskIncognitoCmd.Flags().IntVar(&anthropicAPIThinkingBudget, "thinking-budget", 0, "Thinking budget in tokens (min 1024)")
+	anthropicAPICmd.AddCommand(anthropicAPIAskIncognitoCmd)
+	addListFlags(anthropicAPIListCmd)
+	anthropicAPICmd.AddCommand(anthropicAPIListCmd)
+	anthropicAPICmd.AddCommand(newShowCmd("anthropic-api"))
+	anthropicAPICmd.AddCommand(newExportCmd("anthropic-api"))
+	anthropicAPICmd.AddCommand(newRetryCmd("anthropic-api", &anthropicAPIResume, runAnthropicAPIAsk))
+	anthropicAPICmd.AddCommand(anthropicAPIDeleteCmd)
+	anthropicAPICmd.AddCommand(anthropicAPIModelsCmd)
+	anthropicAPICmd.AddCommand(newAliasCmd("anthropic-api"))
+	rootCmd.AddCommand(anthropicAPICmd)
+}
+
+func runAnthropicAPIAsk(cmd *cobra.Command, args []string, temporary bool) error {
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
+
+	p := newAnthropicAPIProvider()
+	if anthropicAPIThinkingBudget != 0 {
+		if anthropicAPIThinkingBudget < claudepkg.MinThinkingBudget {
+			return fmt.Errorf("--thinking-budget must be at least %d tokens", claudepkg.MinThinkingBudget)
+		}
+		p.(*anthropicapi.Provider).SetThinkingBudget(anthropicAPIThinkingBudget)
+	}
+
+	model := globalCfg.AnthropicAPI.Model
+	if anthropicAPIModel != "" {
+		model = anthropicAPIModel
+	}
+
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: temporary,
+		OnText: func(text string) {
+			fmt.Print(text)
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", "anthropic-api", "err", err)
+		},
+	}
+
+	if !temporary {
+		if anthropicAPIConversation != "" {
+			opts.ConversationID = resolveConversationID("anthropic-api", anthropicAPIConversation)
+		} else if anthropicAPIResume {
+			state := config.LoadState()
+			if conv := lastConversation(state, "anthropic-api"); conv != nil {
+				opts.ConversationID = conv.ConversationID
+			} else {
+				fmt.Fprintln(os.Stderr, "No previous conversation found for anthropic-api — starting new")
+			}
+		}
+	}
+	opts.Retry = retryLast && opts.ConversationID != ""
+
+	// Save conversation state and capture ID for hint.
+	var lastConvID string
+	if !temporary {
+		opts.OnConversation = func(convID, parentMsgID, respID string) {
+			lastConvID = convID
+			saveConversationState("anthropic-api", query, &config.ConversationState{
+				ConversationID: convID,
+			})
+		}
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf("anthropic-api")
+	}
+
+	applySystemPrompt("anthropic-api", &opts)
+	applyShowThinking(&opts)
+	applyStreamJSON("anthropic-api", &opts)
+	rec := recordHistory("anthropic-api", opts.Model, query, &opts)
+
+	err = askCached(cmd.Context(), p, query, opts)
+	rec.finish(err)
+	if err != nil {
+		if interrupted.Load() {
+			return stopInterrupted("anthropic-api", lastConvID, temporary)
+		}
+		emitStreamError("anthropic-api", err)
+		return err
+	}
+
+	finishAnswer("anthropic-api")
+
+	if lastConvID != "" && !temporary {
+		printConversationHint("anthropic-api", lastConvID)
+	}
+
+	return nil
+}
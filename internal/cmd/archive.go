@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+func runArchive(ctx context.Context, p provider.Provider, conversationID string, archived bool) error {
+	conversationID = resolveConversationID(p.Name(), conversationID)
+	if conversationID == "" {
+		return fmt.Errorf("conversation ID is required")
+	}
+
+	archiver, ok := p.(provider.Archiver)
+	if !ok {
+		return fmt.Errorf("%s does not support archiving conversations", p.Name())
+	}
+
+	autoLoadCookies(ctx, p)
+
+	if err := archiver.ArchiveConversation(ctx, conversationID, archived, updateOptions()); err != nil {
+		return err
+	}
+
+	if archived {
+		fmt.Printf("Archived conversation: %s\n", conversationID)
+	} else {
+		fmt.Printf("Unarchived conversation: %s\n", conversationID)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+// in --diff-code output.
+const diffContext = 3
+
+// codeFenceRE matches a fenced Markdown code block; group 1 is its body.
+var codeFenceRE = regexp.MustCompile("(?s)```[^\\n]*\\n(.*?)```")
+
+// printAnswerComparison asks the comparer provider to summarize where the
+// answers agree and disagree, printing the result under a header. With
+// code set it first prints a unified diff of each answer's code against
+// the first answer that has any.
+func printAnswerComparison(ctx context.Context, comparer, query string, results []providerResult, code bool) error {
+	var answered []providerResult
+	for _, r := range results {
+		if r.err == nil && strings.TrimSpace(r.output) != "" {
+			answered = append(answered, r)
+		}
+	}
+	if len(answered) < 2 {
+		return fmt.Errorf("only %d provider(s) answered — comparing needs at least two", len(answered))
+	}
+
+	if code {
+		printCodeDiffs(answered)
+	}
+
+	p, model, err := newProviderByName(comparer)
+	if err != nil {
+		return err
+	}
+	autoLoadCookies(ctx, p)
+
+	fmt.Printf("\n━━━ comparison by %s ━━━\n\n", comparer)
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: true,
+		OnText: func(text string) {
+			fmt.Print(text)
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", comparer, "err", err)
+		},
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf(comparer)
+	}
+	cctx, cancel := withProviderTimeout(ctx)
+	defer cancel()
+	if err := sendPrompt(cctx, p, comparisonPrompt(query, answered), opts); err != nil {
+		return fmt.Errorf("comparison by %s: %w", comparer, err)
+	}
+	fmt.Println()
+	return nil
+}
+
+// comparisonPrompt asks for a structured agreement/disagreement summary.
+func comparisonPrompt(question string, answers []providerResult) string {
+	var b strings.Builder
+	b.WriteString("Several AI assistants answered the same question. Compare their answers.\n\n")
+	b.WriteString("Question:\n")
+	b.WriteString(question)
+	b.WriteString("\n")
+	for _, r := range answers {
+		fmt.Fprintf(&b, "\n=== %s ===\n%s\n", r.name, strings.TrimSpace(r.output))
+	}
+	b.WriteString("\nReply with these sections, naming the assistants:\n")
+	b.WriteString("## Agreement\nPoints every assistant makes or accepts.\n")
+	b.WriteString("## Disagreement\nEach point where they contradict each other: who says what, and which is right if you can tell.\n")
+	b.WriteString("## Unique points\nUseful points only one assistant makes.\n")
+	b.WriteString("Be brief; do not repeat the answers.")
+	return b.String()
+}
+
+// printCodeDiffs prints a unified diff of every answer's code blocks
+// against the first answer with code.
+func printCodeDiffs(answers []providerResult) {
+	var base *providerResult
+	var baseCode string
+	for i := range answers {
+		if c := codeBlocks(answers[i].output); c != "" {
+			base, baseCode = &answers[i], c
+			break
+		}
+	}
+	if base == nil {
+		fmt.Println("\n(no code blocks to diff)")
+		return
+	}
+
+	for _, r := range answers {
+		if r.name == base.name {
+			continue
+		}
+		code := codeBlocks(r.output)
+		if code == "" {
+			fmt.Printf("\n(%s has no code blocks)\n", r.name)
+			continue
+		}
+		diff := unifiedDiff(base.name, r.name, baseCode, code)
+		if diff == "" {
+			fmt.Printf("\n(%s code matches %s)\n", r.name, base.name)
+			continue
+		}
+		fmt.Println()
+		fmt.Print(colorizeDiff(diff))
+	}
+}
+
+// codeBlocks returns the bodies of all fenced code blocks in text, joined
+// in order.
+func codeBlocks(text string) string {
+	var blocks []string
+	for _, m := range codeFenceRE.FindAllStringSubmatch(text, -1) {
+		blocks = append(blocks, strings.TrimRight(m[1], "\n"))
+	}
+	if len(blocks) == 0 {
+		return ""
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// unifiedDiff returns a unified diff of a and b, or "" when they are
+// equal. It uses a longest-common-subsequence line match, which is fine
+// for the size of code in an answer.
+func unifiedDiff(nameA, nameB, a, b string) string {
+	x, y := diffLines(a), diffLines(b)
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Walk the table into an edit script.
+	type edit struct {
+		op   byte // ' ', '-' or '+'
+		line string
+		ai   int // line index in a before this edit
+		bi   int // line index in b before this edit
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			edits = append(edits, edit{' ', x[i], i, j})
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', x[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', y[j], i, j})
+			j++
+		}
+	}
+
+	// Group changes, with context, into hunks.
+	var out strings.Builder
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			k++
+			continue
+		}
+		start := max(0, k-diffContext)
+		end := k
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			// Stop once a run of unchanged lines is too long to bridge.
+			run := end
+			for run < len(edits) && edits[run].op == ' ' {
+				run++
+			}
+			if run == len(edits) || run-end > 2*diffContext {
+				end = min(end+diffContext, len(edits))
+				break
+			}
+			end = run
+		}
+
+		aStart, bStart := edits[start].ai, edits[start].bi
+		aLen, bLen := 0, 0
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				aLen++
+			}
+			if e.op != '-' {
+				bLen++
+			}
+		}
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		for _, e := range edits[start:end] {
+			out.WriteByte(e.op)
+			out.WriteString(e.line)
+			out.WriteByte('\n')
+		}
+		k = end
+	}
+	return out.String()
+}
+
+func diffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// hunkRange formats a unified diff range: 1-based start and length, with
+// an empty range reported at the line before it.
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// colorizeDiff colors removed and added lines when stdout is a terminal.
+func colorizeDiff(diff string) string {
+	fi, err := os.Stdout.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 || os.Getenv("NO_COLOR") != "" {
+		return diff
+	}
+	lines := strings.SplitAfter(diff, "\n")
+	for i, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "---"), strings.HasPrefix(l, "+++"):
+			lines[i] = "\x1b[1m" + strings.TrimSuffix(l, "\n") + "\x1b[0m\n"
+		case strings.HasPrefix(l, "@@"):
+			lines[i] = "\x1b[36m" + strings.TrimSuffix(l, "\n") + "\x1b[0m\n"
+		case strings.HasPrefix(l, "-"):
+			lines[i] = "\x1b[31m" + strings.TrimSuffix(l, "\n") + "\x1b[0m\n"
+		case strings.HasPrefix(l, "+"):
+			lines[i] = "\x1b[32m" + strings.TrimSuffix(l, "\n") + "\x1b[0m\n"
+		}
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// minColumnWidth is the narrowest column --columns lays out; with
+	// more providers than fit, the table continues in another band.
+	minColumnWidth = 30
+	columnGap      = " │ "
+	// defaultTerminalWidth is used when stdout is not a terminal and
+	// COLUMNS is unset.
+	defaultTerminalWidth = 120
+)
+
+// askAllJSONResult is one provider's answer in `ask all --json` output.
+type askAllJSONResult struct {
+	Provider       string `json:"provider"`
+	Model          string `json:"model,omitempty"`
+	Answer         string `json:"answer,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	Error          string `json:"error,omitempty"`
+	TimedOut       bool   `json:"timed_out,omitempty"`
+	DurationMs     int64  `json:"duration_ms"`
+}
+
+// printAskAllJSON writes every answer as one JSON document.
+func printAskAllJSON(askAllID, question string, results []providerResult) error {
+	out := struct {
+		ID        string             `json:"id,omitempty"`
+		Question  string             `json:"question"`
+		Responses []askAllJSONResult `json:"responses"`
+	}{ID: askAllID, Question: question, Responses: []askAllJSONResult{}}
+	for _, r := range results {
+		jr := askAllJSONResult{
+			Provider:       r.name,
+			Model:          r.model,
+			Answer:         strings.TrimSpace(r.output),
+			ConversationID: r.conversationID,
+			DurationMs:     r.elapsed.Milliseconds(),
+		}
+		if r.err != nil {
+			jr.Error = r.err.Error()
+			jr.TimedOut = errors.As(r.err, new(timedOutError))
+		}
+		out.Responses = append(out.Responses, jr)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// printAskAllColumns lays the answers out side by side, wrapped to width.
+// Providers that don't fit across continue in a further band below.
+func printAskAllColumns(results []providerResult, width int) {
+	if len(results) == 0 {
+		return
+	}
+	gap := utf8.RuneCountInString(columnGap)
+	perBand := max(1, (width+gap)/(minColumnWidth+gap))
+	perBand = min(perBand, len(results))
+	colWidth := (width - gap*(perBand-1)) / perBand
+
+	for start := 0; start < len(results); start += perBand {
+		band := results[start:min(start+perBand, len(results))]
+		if start > 0 {
+			fmt.Println()
+		}
+
+		var headers, rules []string
+		cells := make([][]string, len(band))
+		rows := 0
+		for i, r := range band {
+			title := r.name
+			if r.model != "" {
+				title += " (" + r.model + ")"
+			}
+			headers = append(headers, padRight(truncateRunes(title, colWidth), colWidth))
+			rules = append(rules, strings.Repeat("─", colWidth))
+
+			text := strings.TrimSpace(r.output)
+			if r.err != nil {
+				if text != "" {
+					text += "\n\n"
+				}
+				text += "error: " + r.err.Error()
+			}
+			cells[i] = wrapText(text, colWidth)
+			rows = max(rows, len(cells[i]))
+		}
+
+		fmt.Println(strings.TrimRight(strings.Join(headers, columnGap), " "))
+		fmt.Println(strings.Join(rules, "─┼─"))
+		for row := 0; row < rows; row++ {
+			line := make([]string, len(band))
+			for i := range band {
+				cell := ""
+				if row < len(cells[i]) {
+					cell = cells[i][row]
+				}
+				line[i] = padRight(cell, colWidth)
+			}
+			fmt.Println(strings.TrimRight(strings.Join(line, columnGap), " "))
+		}
+	}
+}
+
+// wrapText word-wraps text to lines of at most width runes, keeping the
+// text's own line breaks. Words longer than a line are split.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		para = strings.TrimRight(para, " \t\r")
+		if para == "" {
+			lines = append(lines, "")
+			continue
+		}
+		indent := para[:len(para)-len(strings.TrimLeft(para, " "))]
+		if utf8.RuneCountInString(indent) >= width/2 {
+			indent = ""
+		}
+		line := indent
+		for _, word := range strings.Fields(para) {
+			for utf8.RuneCountInString(word) > width {
+				if strings.TrimSpace(line) != "" {
+					lines = append(lines, line)
+				}
+				r := []rune(word)
+				lines = append(lines, string(r[:width]))
+				word = string(r[width:])
+				line = ""
+			}
+			switch {
+			case strings.TrimSpace(line) == "":
+				line += word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+func truncateRunes(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// terminalWidth returns stdout's width in columns: the terminal's own
+// size, then COLUMNS, then defaultTerminalWidth.
+func terminalWidth() int {
+	if w := stdoutWidth(); w > 0 {
+		return w
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultTerminalWidth
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// prefixColors are the ANSI colors cycled through for provider prefixes.
+var prefixColors = []string{"36", "33", "35", "32", "34", "31", "96", "93", "95"}
+
+// liveWriter interleaves the answers of several providers on stdout as
+// they stream. Every line starts with the provider's "[name]" prefix; when
+// another provider writes mid-line the line is broken and picked up again
+// under a fresh prefix, so no provider waits for another to finish.
+type liveWriter struct {
+	mu      sync.Mutex
+	width   int
+	color   bool
+	colors  map[string]string
+	open    string // provider whose line is unfinished on screen
+	started time.Time
+	// stopped holds providers given up on, whose late text is dropped.
+	stopped map[string]bool
+}
+
+func newLiveWriter(names []string) *liveWriter {
+	w := &liveWriter{
+		colors:  make(map[string]string),
+		started: time.Now(),
+		stopped: make(map[string]bool),
+	}
+	if fi, err := os.Stdout.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 && os.Getenv("NO_COLOR") == "" {
+		w.color = true
+	}
+	for i, name := range names {
+		w.width = max(w.width, len(name)+2)
+		w.colors[name] = prefixColors[i%len(prefixColors)]
+	}
+	return w
+}
+
+// prefix returns the padded, colored "[name]" label.
+func (w *liveWriter) prefix(name string) string {
+	label := fmt.Sprintf("%-*s", w.width, "["+name+"]")
+	if w.color {
+		label = "\x1b[1;" + w.colors[name] + "m" + label + "\x1b[0m"
+	}
+	return label + " "
+}
+
+// write streams a piece of name's answer.
+func (w *liveWriter) write(name, text string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped[name] {
+		return
+	}
+
+	for _, seg := range strings.SplitAfter(text, "\n") {
+		if seg == "" {
+			continue
+		}
+		if w.open != name {
+			w.closeLine()
+			fmt.Print(w.prefix(name))
+			w.open = name
+		}
+		fmt.Print(seg)
+		if strings.HasSuffix(seg, "\n") {
+			w.open = ""
+		}
+	}
+}
+
+// closeLine ends the unfinished line, if any. Callers hold mu.
+func (w *liveWriter) closeLine() {
+	if w.open != "" {
+		fmt.Println()
+		w.open = ""
+	}
+}
+
+// done reports that name has finished answering.
+func (w *liveWriter) done(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closeLine()
+	status := fmt.Sprintf("done in %s", time.Since(w.started).Round(100*time.Millisecond))
+	if w.color {
+		status = "\x1b[2m" + status + "\x1b[0m"
+	}
+	fmt.Println(w.prefix(name) + status)
+}
+
+// note prints a status line for name, such as an error or a conversation
+// ID. Errors go to stderr like the rest of the CLI's errors.
+func (w *liveWriter) note(name, msg string, isErr bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closeLine()
+	out := os.Stdout
+	if isErr {
+		out = os.Stderr
+	}
+	fmt.Fprintln(out, w.prefix(name)+msg)
+}
+
+// stop drops anything name writes from now on.
+func (w *liveWriter) stop(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopped[name] = true
+}
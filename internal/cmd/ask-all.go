@@ -1,10 +1,13 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -12,31 +15,59 @@ import (
 	"time"
 
 	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/history"
 	"github.com/spf13/cobra"
 
 	"github.com/kyupark/ask/internal/provider"
+	"github.com/kyupark/ask/internal/provider/anthropicapi"
 	"github.com/kyupark/ask/internal/provider/chatgpt"
 	"github.com/kyupark/ask/internal/provider/claude"
+	"github.com/kyupark/ask/internal/provider/deepseek"
 	"github.com/kyupark/ask/internal/provider/gemini"
 	"github.com/kyupark/ask/internal/provider/grok"
+	"github.com/kyupark/ask/internal/provider/lechat"
+	"github.com/kyupark/ask/internal/provider/ollama"
 	"github.com/kyupark/ask/internal/provider/perplexity"
 )
 
 var askAllCmd = &cobra.Command{
 	Use:   "all [question]",
 	Short: "Ask all providers at once",
-	Long: `Ask all five AI providers simultaneously and display results as they arrive.
-Runs in standard mode by default.
+	Long: `Ask every AI provider simultaneously and stream their answers together,
+each line prefixed with the provider's name, so slow providers don't hold
+up fast ones. With --sequential each answer is printed whole, under a
+header, as its provider finishes. --columns waits for every answer and lays
+them out side by side; --json prints one JSON document with every answer.
+
+--diff then sends all the answers to another provider (--diff-with,
+claude by default) for a summary of where they agree and where they
+contradict each other. --diff-code also prints a unified diff of each
+answer's code blocks against the first answer's.
+
+--timeout gives each provider a deadline (timeouts.ask_all in config,
+otherwise the overall timeout). Providers still answering when it passes
+are marked as timed out, keeping whatever they had sent, and the rest of
+the answers are shown without them.
+
+  ask all --diff "is it safe to rebase a pushed branch?"
+  ask all --diff --diff-code "bash one-liner to count lines in *.go files"
 
 Subcommands:
   list           List recent ask-all conversations from local state`,
-	Args: cobra.MinimumNArgs(1),
+	Args: questionArgs,
 	RunE: runAskAll,
 }
 
 var askAllResume bool
 var askAllConversationID string
 var askAllListLimit int
+var askAllSequential bool
+var askAllColumns bool
+var askAllJSON bool
+var askAllDiff bool
+var askAllDiffWith string
+var askAllDiffCode bool
+var askAllTimeout time.Duration
 
 var askAllListCmd = &cobra.Command{
 	Use:   "list",
@@ -48,6 +79,15 @@ var askAllListCmd = &cobra.Command{
 func init() {
 	askAllCmd.Flags().BoolVarP(&askAllResume, "resume-all", "r", false, "Continue the last all conversation state for each provider")
 	askAllCmd.Flags().StringVarP(&askAllConversationID, "conversation", "c", "", "Continue a specific all conversation ID")
+	askAllCmd.Flags().BoolVar(&askAllSequential, "sequential", false, "Print each answer whole as its provider finishes instead of interleaving lines")
+	askAllCmd.Flags().BoolVar(&askAllColumns, "columns", false, "Show the answers side by side once all have finished")
+	askAllCmd.Flags().BoolVar(&askAllJSON, "json", false, "Print all answers as one JSON document")
+	askAllCmd.Flags().BoolVar(&askAllDiff, "diff", false, "Have a provider summarize where the answers agree and contradict each other")
+	askAllCmd.Flags().StringVar(&askAllDiffWith, "diff-with", "claude", "Provider that compares the answers for --diff")
+	askAllCmd.Flags().BoolVar(&askAllDiffCode, "diff-code", false, "With --diff, also print a unified diff of the answers' code blocks")
+	askAllCmd.Flags().DurationVar(&askAllTimeout, "timeout", 0, "Give up on providers that have not finished after this long (e.g. 45s)")
+	askAllCmd.MarkFlagsMutuallyExclusive("sequential", "columns", "json")
+	askAllCmd.MarkFlagsMutuallyExclusive("diff", "json")
 	askAllListCmd.Flags().IntVarP(&askAllListLimit, "limit", "n", 20, "Maximum recent ask-all conversations to show")
 	askAllCmd.AddCommand(askAllListCmd)
 	rootCmd.AddCommand(askAllCmd)
@@ -59,9 +99,37 @@ type providerResult struct {
 	model           string
 	output          string
 	err             error
+	elapsed         time.Duration
 	conversationID  string
 	parentMessageID string
 	responseID      string
+	sources         []answerSource
+}
+
+// askAllGrace is how long past the deadline ask all waits for providers
+// to hand back what they have before leaving them out.
+const askAllGrace = 2 * time.Second
+
+// timedOutError marks a provider that did not finish within ask all's
+// per-provider deadline.
+type timedOutError struct {
+	after time.Duration
+}
+
+func (e timedOutError) Error() string {
+	return "timed out after " + e.after.String()
+}
+
+// askAllDeadline is how long each provider gets: --timeout, then
+// timeouts.ask_all, then the overall timeout; zero means no limit.
+func askAllDeadline() time.Duration {
+	switch {
+	case askAllTimeout > 0:
+		return askAllTimeout
+	case globalCfg.Timeouts.AskAll > 0:
+		return time.Duration(globalCfg.Timeouts.AskAll) * time.Second
+	}
+	return providerTimeout()
 }
 
 type askAllEntry struct {
@@ -70,8 +138,26 @@ type askAllEntry struct {
 }
 
 func runAskAll(cmd *cobra.Command, args []string) error {
-	query := strings.Join(args, " ")
-	timeout := providerTimeout()
+	if askAllJSON && flagStreamJSON {
+		return fmt.Errorf("--json cannot be combined with --stream-json")
+	}
+	if askAllDiff {
+		if flagStreamJSON {
+			return fmt.Errorf("--diff cannot be combined with --stream-json")
+		}
+		if !slices.Contains(providerNames, askAllDiffWith) {
+			return fmt.Errorf("unknown provider %q (use %s)", askAllDiffWith, strings.Join(providerNames, ", "))
+		}
+	} else if askAllDiffCode || cmd.Flags().Changed("diff-with") {
+		return fmt.Errorf("--diff-code and --diff-with need --diff")
+	}
+	if askAllTimeout < 0 {
+		return fmt.Errorf("--timeout must not be negative")
+	}
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
 	entries := askAllEntries()
 	state := config.LoadState()
 
@@ -87,7 +173,9 @@ func runAskAll(cmd *cobra.Command, args []string) error {
 			}
 		}
 	} else if askAllResume {
-		if state.LastAskAllID != "" {
+		// Per-directory resume goes by each provider's conversation here
+		// rather than the last ask-all anywhere.
+		if state.LastAskAllID != "" && resumeDir() == "" {
 			if bundle := state.GetAskAllConversation(state.LastAskAllID); bundle != nil {
 				for k, v := range bundle.Providers {
 					if v != nil {
@@ -98,7 +186,7 @@ func runAskAll(cmd *cobra.Command, args []string) error {
 		}
 		if len(resumeByProvider) == 0 {
 			for _, e := range entries {
-				if conv := state.GetConversation(e.p.Name()); conv != nil {
+				if conv := lastConversation(state, e.p.Name()); conv != nil {
 					resumeByProvider[e.p.Name()] = conv
 				}
 			}
@@ -111,100 +199,99 @@ func runAskAll(cmd *cobra.Command, args []string) error {
 		wgCookies.Add(1)
 		go func(p provider.Provider) {
 			defer wgCookies.Done()
-			autoLoadCookies(cmd.Context(), p)
+			loadAskCookies(cmd.Context(), p)
 		}(e.p)
 	}
 	wgCookies.Wait()
 
-	// Fan out: ask all providers in parallel, buffer responses.
+	// Live output interleaves lines as they stream; otherwise answers are
+	// buffered and printed whole.
+	collect := askAllColumns || askAllJSON
+	var live *liveWriter
+	if !flagStreamJSON && !askAllSequential && !collect {
+		names := make([]string, len(entries))
+		for i, e := range entries {
+			names[i] = e.p.Name()
+		}
+		live = newLiveWriter(names)
+	}
+
+	// Fan out: ask all providers in parallel, buffer responses. Providers
+	// still running a little after the deadline are left out, so one that
+	// hangs does not hold up the others' answers.
 	results := make(chan providerResult, len(entries))
-	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	deadline := askAllDeadline()
+	ctx, cancel := context.WithCancel(cmd.Context())
+	var expired <-chan time.Time
+	if deadline > 0 {
+		ctx, cancel = context.WithTimeout(cmd.Context(), deadline)
+		timer := time.NewTimer(deadline + askAllGrace)
+		defer timer.Stop()
+		expired = timer.C
+	}
 	defer cancel()
+	partials := newAskAllPartials()
 	for _, e := range entries {
 		go func(p provider.Provider, model string) {
-			var buf bytes.Buffer
-			var lastConversationID string
-			var lastParentMessageID string
-			var lastResponseID string
-			opts := provider.AskOptions{
-				Model:     model,
-				Verbose:   globalCfg.Verbose,
-				Temporary: false,
-				OnConversation: func(conversationID, parentMessageID, responseID string) {
-					lastConversationID = conversationID
-					lastParentMessageID = parentMessageID
-					lastResponseID = responseID
-				},
-				OnText: func(text string) {
-					buf.WriteString(text)
-				},
-				OnError: func(err error) {
-					if globalCfg.Verbose {
-						fmt.Fprintf(os.Stderr, "[%s] error: %v\n", p.Name(), err)
-					}
-				},
-			}
-			if globalCfg.Verbose {
-				opts.LogFunc = func(format string, args ...any) {
-					fmt.Fprintf(os.Stderr, "[%s] %s\n", p.Name(), fmt.Sprintf(format, args...))
-				}
-			}
-			if conv := resumeByProvider[p.Name()]; conv != nil {
-				opts.ConversationID = conv.ConversationID
-				opts.ParentMessageID = conv.ParentMessageID
-				opts.ResponseID = conv.ResponseID
-			}
-			err := p.Ask(ctx, query, opts)
-			if err != nil && p.Name() == "grok" {
-				if globalCfg.Verbose {
-					fmt.Fprintf(os.Stderr, "[%s] retrying once after error: %v\n", p.Name(), err)
-				}
-				buf.Reset()
-				select {
-				case <-ctx.Done():
-					err = ctx.Err()
-				default:
-					time.Sleep(400 * time.Millisecond)
-					err = p.Ask(ctx, query, opts)
-				}
-			}
-
-			if err != nil && strings.TrimSpace(buf.String()) != "" {
-				if globalCfg.Verbose {
-					fmt.Fprintf(os.Stderr, "[%s] ignoring trailing error after response: %v\n", p.Name(), err)
-				}
-				err = nil
-			}
-			results <- providerResult{
-				name:            p.Name(),
-				model:           model,
-				output:          buf.String(),
-				err:             err,
-				conversationID:  lastConversationID,
-				parentMessageID: lastParentMessageID,
-				responseID:      lastResponseID,
-			}
+			results <- askAllOne(ctx, p, model, query, resumeByProvider[p.Name()], live, partials)
 		}(e.p, e.model)
 	}
 
+	startedAt := time.Now()
 	updatedState := false
 	bundleProviders := make(map[string]*config.ConversationState)
+	var collected []providerResult
+	finished := make(map[string]bool, len(entries))
+	var overdue []providerResult
 
 	// Print results as they arrive.
 	for i := 0; i < len(entries); i++ {
-		r := <-results
-		if i > 0 {
-			fmt.Println()
+		var r providerResult
+		if len(overdue) == 0 {
+			select {
+			case r = <-results:
+			case <-expired:
+				expired = nil
+				overdue = askAllStragglers(entries, finished, deadline, partials)
+			}
 		}
-		if r.model != "" {
-			fmt.Printf("━━━ %s (%s) ━━━\n\n", r.name, r.model)
-		} else {
-			fmt.Printf("━━━ %s ━━━\n\n", r.name)
+		if len(overdue) > 0 {
+			r, overdue = overdue[0], overdue[1:]
+			if live != nil {
+				live.stop(r.name)
+			}
+		}
+		finished[r.name] = true
+		entry := &history.Entry{
+			Provider:       r.name,
+			Model:          r.model,
+			ConversationID: r.conversationID,
+			Question:       query,
+			Answer:         r.output,
+			CreatedAt:      startedAt,
+			CompletedAt:    time.Now(),
 		}
 		if r.err != nil {
-			fmt.Fprintf(os.Stderr, "  error: %v\n", r.err)
-		} else {
-			fmt.Println(strings.TrimRight(r.output, "\n"))
+			entry.Error = r.err.Error()
+		}
+		addHistoryEntry(entry)
+		addUsage(entry)
+		runHooks(entry)
+		collected = append(collected, r)
+
+		switch {
+		case flagStreamJSON && r.err != nil:
+			emitStreamError(r.name, r.err)
+		case flagStreamJSON:
+			finishAnswer(r.name)
+		case live != nil && r.err != nil:
+			live.note(r.name, "error: "+r.err.Error(), true)
+		case live != nil:
+			live.done(r.name)
+		case collect:
+			// Printed once every provider has answered.
+		default:
+			printAskAllResult(i, r)
 		}
 
 		if r.conversationID != "" {
@@ -212,26 +299,241 @@ func runAskAll(cmd *cobra.Command, args []string) error {
 				ConversationID:  r.conversationID,
 				ParentMessageID: r.parentMessageID,
 				ResponseID:      r.responseID,
+				Title:           sessionTitle(query),
+				UpdatedAt:       time.Now(),
+			}
+			if prev := resumeByProvider[r.name]; prev != nil && prev.ConversationID == r.conversationID && prev.Title != "" {
+				cs.Title = prev.Title
 			}
-			state.SetConversation(r.name, cs)
 			bundleProviders[r.name] = cs
 			updatedState = true
-			fmt.Printf("\nConversation: %s\n", r.conversationID)
+			switch {
+			case live != nil:
+				live.note(r.name, "conversation "+r.conversationID, false)
+			case !flagStreamJSON && !collect:
+				fmt.Printf("\nConversation: %s\n", r.conversationID)
+			}
 		}
 	}
 
+	// Collected answers are shown in the providers' usual order.
+	order := make(map[string]int, len(entries))
+	for i, e := range entries {
+		order[e.p.Name()] = i
+	}
+	sort.Slice(collected, func(i, j int) bool { return order[collected[i].name] < order[collected[j].name] })
+
+	var askAllID string
 	if updatedState {
-		askAllID := fmt.Sprintf("aa_%d", time.Now().UnixNano())
-		state.SetAskAllConversation(askAllID, query, bundleProviders)
-		_ = config.SaveState(state)
-		fmt.Printf("\nAll conversation: %s\n", askAllID)
-		fmt.Printf("  ask all -c %s \"follow up\"\n", askAllID)
-		fmt.Println("  ask all -c <id> \"follow up\"")
+		askAllID = fmt.Sprintf("aa_%d", time.Now().UnixNano())
+		_ = config.UpdateState(func(state *config.State) {
+			dir := resumeDir()
+			for name, cs := range bundleProviders {
+				state.SetConversation(name, cs)
+				if dir != "" {
+					state.SetDirConversation(dir, name, cs)
+				}
+			}
+			state.SetAskAllConversation(askAllID, query, bundleProviders)
+		})
+	}
+
+	switch {
+	case askAllJSON:
+		if err := printAskAllJSON(askAllID, query, collected); err != nil {
+			return err
+		}
+	case askAllColumns:
+		printAskAllColumns(collected, terminalWidth())
+	}
+
+	if askAllDiff {
+		if err := printAnswerComparison(cmd.Context(), askAllDiffWith, query, collected, askAllDiffCode); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	if updatedState {
+		if flagStreamJSON || askAllJSON {
+			fmt.Fprintf(os.Stderr, "All conversation: %s\n", askAllID)
+		} else {
+			fmt.Printf("\nAll conversation: %s\n", askAllID)
+			fmt.Printf("  ask all -c %s \"follow up\"\n", askAllID)
+			fmt.Println("  ask all -c <id> \"follow up\"")
+		}
 	}
 
 	return nil
 }
 
+// askAllPartials holds each provider's answer as it streams, so the text
+// of one given up on at the deadline is still at hand.
+type askAllPartials struct {
+	mu   sync.Mutex
+	text map[string]*strings.Builder
+}
+
+func newAskAllPartials() *askAllPartials {
+	return &askAllPartials{text: make(map[string]*strings.Builder)}
+}
+
+func (a *askAllPartials) write(name, text string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sb := a.text[name]
+	if sb == nil {
+		sb = &strings.Builder{}
+		a.text[name] = sb
+	}
+	sb.WriteString(text)
+}
+
+func (a *askAllPartials) reset(name string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.text, name)
+}
+
+func (a *askAllPartials) get(name string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if sb := a.text[name]; sb != nil {
+		return sb.String()
+	}
+	return ""
+}
+
+// askAllStragglers returns a timed-out result, with what it has sent so
+// far, for every entry that has not finished.
+func askAllStragglers(entries []askAllEntry, finished map[string]bool, deadline time.Duration, partials *askAllPartials) []providerResult {
+	var late []providerResult
+	for _, e := range entries {
+		if !finished[e.p.Name()] {
+			late = append(late, providerResult{
+				name:    e.p.Name(),
+				model:   e.model,
+				output:  partials.get(e.p.Name()),
+				err:     timedOutError{after: deadline},
+				elapsed: deadline,
+			})
+		}
+	}
+	return late
+}
+
+// askAllOne asks one provider and buffers its answer in partial (nil for
+// a buffer of its own). conv, when set, continues an earlier
+// conversation; live, when set, also streams the answer as it arrives.
+func askAllOne(ctx context.Context, p provider.Provider, model, query string, conv *config.ConversationState, live *liveWriter, partial *askAllPartials) providerResult {
+	start := time.Now()
+	if partial == nil {
+		partial = newAskAllPartials()
+	}
+	name := p.Name()
+	var lastConversationID string
+	var lastParentMessageID string
+	var lastResponseID string
+	var sources sourceList
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: false,
+		OnConversation: func(conversationID, parentMessageID, responseID string) {
+			lastConversationID = conversationID
+			lastParentMessageID = parentMessageID
+			lastResponseID = responseID
+		},
+		OnText: func(text string) {
+			partial.write(name, text)
+		},
+		OnSource: func(name, url string) {
+			sources.add(name, url)
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", p.Name(), "err", err)
+		},
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf(p.Name())
+	}
+	applySystemPrompt(p.Name(), &opts)
+	if conv != nil {
+		opts.ConversationID = conv.ConversationID
+		opts.ParentMessageID = conv.ParentMessageID
+		opts.ResponseID = conv.ResponseID
+	}
+	if flagStreamJSON {
+		applyStreamJSON(p.Name(), &opts)
+		// Keep buffering so trailing errors after a response are still ignored.
+		emitText := opts.OnText
+		opts.OnText = func(text string) {
+			partial.write(name, text)
+			emitText(text)
+		}
+	}
+	if live != nil {
+		opts.OnText = func(text string) {
+			partial.write(name, text)
+			live.write(p.Name(), text)
+		}
+	}
+	err := askCached(ctx, p, query, opts)
+	if err != nil && p.Name() == "grok" {
+		slog.Debug("retrying once after error", "provider", p.Name(), "err", err)
+		if live != nil && partial.get(name) != "" {
+			live.note(p.Name(), "(retrying)", false)
+		}
+		partial.reset(name)
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		default:
+			time.Sleep(400 * time.Millisecond)
+			err = askCached(ctx, p, query, opts)
+		}
+	}
+
+	// A provider cut off by the deadline keeps what it sent, but is
+	// marked as timed out rather than passed off as a full answer.
+	if dl, ok := ctx.Deadline(); ok && err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = timedOutError{after: dl.Sub(start).Round(time.Second)}
+	} else if err != nil && strings.TrimSpace(partial.get(name)) != "" {
+		slog.Debug("ignoring trailing error after response", "provider", p.Name(), "err", err)
+		err = nil
+	}
+	return providerResult{
+		name:            p.Name(),
+		model:           model,
+		output:          partial.get(name),
+		err:             err,
+		elapsed:         time.Since(start),
+		conversationID:  lastConversationID,
+		parentMessageID: lastParentMessageID,
+		responseID:      lastResponseID,
+		sources:         sources.list,
+	}
+}
+
+// printAskAllResult writes one provider's buffered answer under a header.
+func printAskAllResult(i int, r providerResult) {
+	if i > 0 {
+		fmt.Println()
+	}
+	if r.model != "" {
+		fmt.Printf("━━━ %s (%s) ━━━\n\n", r.name, r.model)
+	} else {
+		fmt.Printf("━━━ %s ━━━\n\n", r.name)
+	}
+	if out := strings.TrimRight(r.output, "\n"); out != "" {
+		fmt.Println(out)
+	}
+	if r.err != nil {
+		fmt.Fprintf(os.Stderr, "  error: %v\n", r.err)
+	} else {
+		printSources(r.sources)
+	}
+}
+
 func runAskAllList(cmd *cobra.Command, args []string) error {
 	state := config.LoadState()
 	if len(state.AskAll) == 0 {
@@ -322,13 +624,57 @@ func askAllProviderNames(bundle *config.AskAllConversationState) []string {
 }
 
 func askAllEntries() []askAllEntry {
-	return []askAllEntry{
+	entries := []askAllEntry{
 		{newChatGPTProvider(), askAllChatGPTModel()},
 		{newClaudeProvider(), askAllClaudeModel()},
+		{newDeepSeekProvider(), askAllDeepSeekModel()},
 		{newGeminiProvider(), askAllGeminiModel()},
 		{newGrokProvider(), askAllGrokModel()},
-		{newPerplexityProvider(), askAllPerplexityModel()},
+		{newLeChatProvider(), askAllLeChatModel()},
 	}
+	// A local server is opt-in: without a configured model most users
+	// have no Ollama running and it would only add an error.
+	if model := askAllOllamaModel(); model != "" {
+		entries = append(entries, askAllEntry{newOllamaProvider(), model})
+	}
+	// Likewise the API is billed per token, so it only joins with a key.
+	if anthropicAPIKey() != "" {
+		entries = append(entries, askAllEntry{newAnthropicAPIProvider(), askAllAnthropicAPIModel()})
+	}
+	return append(entries, askAllEntry{newPerplexityProvider(), askAllPerplexityModel()})
+}
+
+// providerNames lists every supported provider.
+var providerNames = []string{"anthropic-api", "chatgpt", "claude", "deepseek", "gemini", "grok", "lechat", "ollama", "perplexity"}
+
+// newProviderByName builds a configured provider and its default model by
+// provider name.
+func newProviderByName(name string) (provider.Provider, string, error) {
+	switch name {
+	case "anthropic-api":
+		return newAnthropicAPIProvider(), askAllAnthropicAPIModel(), nil
+	case "chatgpt":
+		return newChatGPTProvider(), askAllChatGPTModel(), nil
+	case "claude":
+		return newClaudeProvider(), askAllClaudeModel(), nil
+	case "deepseek":
+		return newDeepSeekProvider(), askAllDeepSeekModel(), nil
+	case "gemini":
+		return newGeminiProvider(), askAllGeminiModel(), nil
+	case "grok":
+		return newGrokProvider(), askAllGrokModel(), nil
+	case "lechat":
+		return newLeChatProvider(), askAllLeChatModel(), nil
+	case "ollama":
+		return newOllamaProvider(), askAllOllamaModel(), nil
+	case "perplexity":
+		return newPerplexityProvider(), askAllPerplexityModel(), nil
+	}
+	return nil, "", fmt.Errorf("unknown provider %q", name)
+}
+
+func askAllAnthropicAPIModel() string {
+	return strings.TrimSpace(globalCfg.AnthropicAPI.Model)
 }
 
 func askAllChatGPTModel() string {
@@ -345,6 +691,10 @@ func askAllClaudeModel() string {
 	return "claude-opus-4-6"
 }
 
+func askAllDeepSeekModel() string {
+	return deepseek.ResolveModel(globalCfg.DeepSeek.Model)
+}
+
 func askAllGeminiModel() string {
 	if model := strings.TrimSpace(globalCfg.Gemini.Model); model != "" {
 		return model
@@ -356,6 +706,14 @@ func askAllGrokModel() string {
 	return grok.ResolveModel(strings.TrimSpace(globalCfg.Grok.Model))
 }
 
+func askAllLeChatModel() string {
+	return strings.TrimSpace(globalCfg.LeChat.Model)
+}
+
+func askAllOllamaModel() string {
+	return strings.TrimSpace(globalCfg.Ollama.Model)
+}
+
 func askAllPerplexityModel() string {
 	if model := strings.TrimSpace(globalCfg.Perplexity.Model); model != "" {
 		return model
@@ -363,6 +721,52 @@ func askAllPerplexityModel() string {
 	return "pplx_reasoning"
 }
 
+func newLeChatProvider() provider.Provider {
+	p := lechat.New(
+		globalCfg.LeChat.BaseURL,
+		globalCfg.UserAgent,
+		providerTimeout(),
+	)
+	p.SetCookies(map[string]string{
+		lechat.SessionCookieName: globalCfg.LeChat.SessionCookie,
+	})
+	p.SetProxy(providerProxy("lechat"))
+	return p
+}
+
+func newOllamaProvider() provider.Provider {
+	p := ollama.New(
+		globalCfg.Ollama.BaseURL,
+		globalCfg.Ollama.Model,
+		providerTimeout(),
+	)
+	p.SetProxy(providerProxy("ollama"))
+	p.SetDataDir(filepath.Join(config.DataDir(), "ollama"))
+	return p
+}
+
+// anthropicAPIKey returns the configured Anthropic API key, then
+// ANTHROPIC_API_KEY.
+func anthropicAPIKey() string {
+	if key := globalCfg.AnthropicAPI.APIKey; key != "" {
+		return key
+	}
+	return os.Getenv(anthropicapi.APIKeyEnv)
+}
+
+func newAnthropicAPIProvider() provider.Provider {
+	p := anthropicapi.New(
+		globalCfg.AnthropicAPI.BaseURL,
+		globalCfg.AnthropicAPI.APIKey,
+		providerTimeout(),
+	)
+	p.SetProxy(providerProxy("anthropic-api"))
+	p.SetDataDir(filepath.Join(config.DataDir(), "anthropic-api"))
+	p.SetMaxTokens(globalCfg.AnthropicAPI.MaxTokens)
+	p.SetThinkingBudget(globalCfg.AnthropicAPI.ThinkingBudget)
+	return p
+}
+
 func newPerplexityProvider() provider.Provider {
 	p := perplexity.New(
 		globalCfg.Perplexity.BaseURL,
@@ -373,6 +777,7 @@ func newPerplexityProvider() provider.Provider {
 		"cf_clearance":                     globalCfg.Perplexity.CfClearance,
 		"__Secure-next-auth.session-token": globalCfg.Perplexity.SessionCookie,
 	})
+	p.SetProxy(providerProxy("perplexity"))
 	if mode := globalCfg.Perplexity.Mode; mode != "" {
 		p.SetMode(mode)
 	}
@@ -394,9 +799,31 @@ func newChatGPTProvider() provider.Provider {
 		"cf_clearance":                     globalCfg.ChatGPT.CfClearance,
 		"_puid":                            globalCfg.ChatGPT.PUID,
 	})
+	p.SetProxy(providerProxy("chatgpt"))
+	applyChatGPTSession(p)
+	p.SetAccount(chatgptAccountID())
 	if effort := globalCfg.ChatGPT.Effort; effort != "" {
 		p.SetThinkingEffort(effort)
 	}
+	p.SetTimezone(globalCfg.ChatGPT.Timezone)
+	p.SetLocale(globalCfg.ChatGPT.Locale)
+	applyChatGPTFingerprint(p)
+	p.SetLimitsHandler(saveChatGPTLimits)
+	return p
+}
+
+func newDeepSeekProvider() provider.Provider {
+	p := deepseek.New(
+		globalCfg.DeepSeek.BaseURL,
+		globalCfg.UserAgent,
+		providerTimeout(),
+	)
+	p.SetCookies(map[string]string{
+		"ds_session_id": globalCfg.DeepSeek.SessionID,
+	})
+	p.SetUserToken(globalCfg.DeepSeek.UserToken)
+	p.SetProxy(providerProxy("deepseek"))
+	p.SetWebSearch(globalCfg.DeepSeek.Search)
 	return p
 }
 
@@ -410,6 +837,8 @@ func newGeminiProvider() provider.Provider {
 		"__Secure-1PSIDTS": globalCfg.Gemini.PSIDTS,
 		"__Secure-1PSIDCC": globalCfg.Gemini.PSIDCC,
 	})
+	p.SetProxy(providerProxy("gemini"))
+	p.SetCookieRefresh(config.LoadState().GeminiCookiesRotatedAt, saveGeminiCookies)
 	return p
 }
 
@@ -422,12 +851,16 @@ func newGrokProvider() provider.Provider {
 		"auth_token": globalCfg.Grok.AuthToken,
 		"ct0":        globalCfg.Grok.CT0,
 	})
+	p.SetProxy(providerProxy("grok"))
 	if globalCfg.Grok.DeepSearch {
 		p.SetDeepSearch(true)
 	}
 	if globalCfg.Grok.Reasoning {
 		p.SetReasoning(true)
 	}
+	// The config value was checked when it was set.
+	persona, _ := grok.ResolvePersona(globalCfg.Grok.Persona)
+	p.SetPersona(persona)
 	return p
 }
 
@@ -446,8 +879,10 @@ func newClaudeProvider() provider.Provider {
 	p.SetCookies(map[string]string{
 		"sessionKey": globalCfg.Claude.SessionKey,
 	})
+	p.SetProxy(providerProxy("claude"))
 	if effort := globalCfg.Claude.Effort; effort != "" {
 		p.SetThinkingEffort(effort)
 	}
+	p.SetThinkingBudget(globalCfg.Claude.ThinkingBudget)
 	return p
 }
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// toolProvider returns the provider for commands that put a canned prompt
+// to one provider: the --provider flag, else default_provider.
+func toolProvider(name string) (string, error) {
+	if name == "" {
+		name = globalCfg.DefaultProvider
+	}
+	if name == "" {
+		return "", fmt.Errorf("no provider — pass --provider or run: ask config set default_provider chatgpt")
+	}
+	if err := validateProviderList([]string{name}); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// askOnce sends prompt to the named provider as a temporary chat and
+// returns the whole answer. onText, if set, also sees the answer as it
+// streams in.
+func askOnce(ctx context.Context, name, prompt string, onText func(string)) (string, error) {
+	p, model, err := newProviderByName(name)
+	if err != nil {
+		return "", err
+	}
+	autoLoadCookies(ctx, p)
+
+	var b strings.Builder
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: true,
+		OnText: func(text string) {
+			b.WriteString(text)
+			if onText != nil {
+				onText(text)
+			}
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", name, "err", err)
+		},
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf(name)
+	}
+	actx, cancel := withProviderTimeout(ctx)
+	defer cancel()
+	err = sendPrompt(actx, p, prompt, opts)
+	answer := strings.TrimSpace(b.String())
+	// As in ask all, an error after a full answer is ignored.
+	if err != nil && answer == "" {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	if answer == "" {
+		return "", fmt.Errorf("%s: empty answer", name)
+	}
+	return answer, nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// auditRecord is one line of the audit log: a prompt as it was sent.
+type auditRecord struct {
+	Time           time.Time `json:"time"`
+	Provider       string    `json:"provider"`
+	Model          string    `json:"model,omitempty"`
+	ConversationID string    `json:"conversation_id,omitempty"`
+	Temporary      bool      `json:"temporary,omitempty"`
+	Prompt         string    `json:"prompt"`
+	SystemPrompt   string    `json:"system_prompt,omitempty"`
+	Attachments    []string  `json:"attachments,omitempty"`
+	Command        string    `json:"command,omitempty"`
+	User           string    `json:"user,omitempty"`
+	Host           string    `json:"host,omitempty"`
+	Cwd            string    `json:"cwd,omitempty"`
+}
+
+// auditMu keeps concurrent asks (ask all) from interleaving lines.
+var auditMu sync.Mutex
+
+// auditPath returns where the audit log is written.
+func auditPath() string {
+	if globalCfg.Audit.Path != "" {
+		return globalCfg.Audit.Path
+	}
+	return filepath.Join(config.DataDir(), "audit.jsonl")
+}
+
+// auditPrompt appends the prompt about to be sent to providerName to the
+// audit log when audit.enabled is set. Incognito asks are logged too:
+// they leave the machine all the same. An unwritable log stops the ask,
+// so nothing is sent unrecorded.
+func auditPrompt(providerName, query string, opts provider.AskOptions) error {
+	if !globalCfg.Audit.Enabled {
+		return nil
+	}
+	rec := auditRecord{
+		Time:           time.Now().UTC(),
+		Provider:       providerName,
+		Model:          opts.Model,
+		ConversationID: opts.ConversationID,
+		Temporary:      opts.Temporary,
+		Prompt:         query,
+		SystemPrompt:   opts.SystemPrompt,
+	}
+	for _, a := range opts.Attachments {
+		if abs, err := filepath.Abs(a); err == nil {
+			a = abs
+		}
+		rec.Attachments = append(rec.Attachments, a)
+	}
+	if currentCmd != nil {
+		rec.Command = currentCmd.CommandPath()
+	}
+	if u, err := user.Current(); err == nil {
+		rec.User = u.Username
+	}
+	rec.Host, _ = os.Hostname()
+	rec.Cwd, _ = os.Getwd()
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	path := auditPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+	// One write per record, so O_APPEND keeps records from other
+	// processes whole.
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("audit log: %w", err)
+	}
+	return f.Close()
+}
+
+// sendPrompt is p.Ask through the middleware hooks, after redacting the
+// system prompt and attachments and recording the prompt in the audit log.
+func sendPrompt(ctx context.Context, p provider.Provider, query string, opts provider.AskOptions) error {
+	flush, err := applyMiddleware(ctx, p.Name(), &query, &opts)
+	if err != nil {
+		return err
+	}
+	cleanup, err := redactOptions(&opts)
+	if err != nil {
+		flush(err)
+		return err
+	}
+	defer cleanup()
+	if err = auditPrompt(p.Name(), query, opts); err == nil {
+		err = p.Ask(ctx, query, opts)
+	}
+	flush(err)
+	return err
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/export"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var (
+	backupProviders   []string
+	backupOut         string
+	backupFormat      string
+	backupIncremental bool
+)
+
+// backupManifestFile records what a backup directory holds, per provider.
+const backupManifestFile = ".backup.json"
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Save every remote conversation as dated files",
+	Long: `Page through all of a provider's conversations, fetch each transcript
+and write it under <out>/<provider>/ as <date>-<title>-<id>.<format>:
+
+  ask backup --provider chatgpt --out ~/ai-backup
+  ask backup -p chatgpt -p claude --out ~/ai-backup --format json --incremental
+
+With --incremental only conversations updated since the last backup into
+the same directory are fetched again. Providers that don't report update
+times (grok) have every conversation fetched again.`,
+	Args: cobra.NoArgs,
+	RunE: runBackup,
+}
+
+func init() {
+	backupCmd.Flags().StringSliceVarP(&backupProviders, "provider", "p", nil, "Provider to back up (repeatable: "+strings.Join(providerNames, ", ")+")")
+	backupCmd.Flags().StringVarP(&backupOut, "out", "o", "", "Directory to write the backup to")
+	backupCmd.Flags().StringVarP(&backupFormat, "format", "f", "md", "File format (md, json, html)")
+	backupCmd.Flags().BoolVar(&backupIncremental, "incremental", false, "Only fetch conversations changed since the last backup")
+	_ = backupCmd.MarkFlagRequired("provider")
+	_ = backupCmd.MarkFlagRequired("out")
+	rootCmd.AddCommand(backupCmd)
+}
+
+// backupManifest maps conversation ID to what was last written for it.
+type backupManifest map[string]backupRecord
+
+type backupRecord struct {
+	File      string    `json:"file"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	BackedUp  time.Time `json:"backed_up"`
+}
+
+func runBackup(cmd *cobra.Command, args []string) error {
+	// Validate the format before hitting the network.
+	if err := export.Write(io.Discard, backupFormat, "", &provider.Transcript{}); err != nil {
+		return err
+	}
+	for _, name := range backupProviders {
+		if !slices.Contains(providerNames, name) {
+			return fmt.Errorf("unknown provider %q (use %s)", name, strings.Join(providerNames, ", "))
+		}
+	}
+
+	var errs []error
+	for _, name := range backupProviders {
+		p, _, err := newProviderByName(name)
+		if err != nil {
+			return err
+		}
+		if err := backupProvider(cmd.Context(), p); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backupProvider writes every conversation of p that needs it and keeps
+// going past ones that fail, which are reported together at the end.
+func backupProvider(ctx context.Context, p provider.Provider) error {
+	lister, ok := p.(provider.Lister)
+	if !ok {
+		return fmt.Errorf("%s does not support listing conversations", p.Name())
+	}
+	fetcher, ok := p.(provider.TranscriptFetcher)
+	if !ok {
+		return fmt.Errorf("%s does not support fetching transcripts", p.Name())
+	}
+
+	dir := filepath.Join(backupOut, p.Name())
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	manifest := loadBackupManifest(dir)
+
+	autoLoadCookies(ctx, p)
+	listOpts := provider.ListOptions{Verbose: globalCfg.Verbose}
+	transcriptOpts := provider.TranscriptOptions{Verbose: globalCfg.Verbose}
+	if globalCfg.Verbose {
+		listOpts.LogFunc = debugLogf(p.Name())
+		transcriptOpts.LogFunc = debugLogf(p.Name())
+	}
+	conversations, err := listEvery(ctx, lister, listOpts)
+	if err != nil {
+		return fmt.Errorf("listing conversations: %w", err)
+	}
+
+	var written, skipped, failed int
+	for i, c := range conversations {
+		if ctx.Err() != nil {
+			break
+		}
+		if prev, ok := manifest[c.ID]; ok && backupIncremental && backupUpToDate(dir, c, prev) {
+			skipped++
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "[%s] %d/%d %s\n", p.Name(), i+1, len(conversations), truncateRunes(c.Title, 60))
+		t, err := fetcher.FetchTranscript(ctx, c.ID, transcriptOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] %s: %v\n", p.Name(), c.ID, err)
+			failed++
+			continue
+		}
+		if t.ID == "" {
+			t.ID = c.ID
+		}
+		if t.Title == "" {
+			t.Title = c.Title
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = c.CreatedAt
+		}
+
+		name := backupFileName(c, t)
+		if err := writeBackupFile(filepath.Join(dir, name), p.Name(), t); err != nil {
+			return err
+		}
+		// A renamed conversation gets a new file; drop the old one.
+		if prev, ok := manifest[c.ID]; ok && prev.File != name {
+			_ = os.Remove(filepath.Join(dir, prev.File))
+		}
+		manifest[c.ID] = backupRecord{File: name, UpdatedAt: c.UpdatedAt, BackedUp: time.Now()}
+		written++
+	}
+
+	if err := saveBackupManifest(dir, manifest); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "[%s] %d written, %d unchanged, %d failed → %s\n", p.Name(), written, skipped, failed, dir)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d conversation(s) could not be fetched", failed)
+	}
+	return nil
+}
+
+// backupUpToDate reports whether the file prev recorded for c still holds
+// its latest state. Listings without update times, like Grok's, can't
+// tell a continued conversation from an untouched one, so those are
+// always fetched again.
+func backupUpToDate(dir string, c provider.Conversation, prev backupRecord) bool {
+	if c.UpdatedAt.IsZero() || c.UpdatedAt.After(prev.UpdatedAt) {
+		return false
+	}
+	if filepath.Ext(prev.File) != "."+export.Extension(backupFormat) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(dir, prev.File))
+	return err == nil
+}
+
+// backupFileName is <date>-<title>-<id>.<ext>, dated by when the
+// conversation started.
+func backupFileName(c provider.Conversation, t *provider.Transcript) string {
+	date := "undated"
+	for _, ts := range []time.Time{t.CreatedAt, c.CreatedAt, c.UpdatedAt} {
+		if !ts.IsZero() {
+			date = ts.Local().Format("2006-01-02")
+			break
+		}
+	}
+	parts := []string{date}
+	if slug := fileSlug(t.Title, 50); slug != "" {
+		parts = append(parts, slug)
+	}
+	parts = append(parts, fileSlug(t.ID, 80))
+	return strings.Join(parts, "-") + "." + export.Extension(backupFormat)
+}
+
+// fileSlug keeps letters and digits of s, joining runs of anything else
+// with single dashes, and cuts it to limit runes.
+func fileSlug(s string, limit int) string {
+	var b strings.Builder
+	dash := false
+	n := 0
+	for _, r := range strings.ToLower(s) {
+		if n >= limit {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+			n++
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+			n++
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+func writeBackupFile(path, providerName string, t *provider.Transcript) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export.Write(f, backupFormat, providerName, t); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if !t.UpdatedAt.IsZero() {
+		_ = os.Chtimes(path, t.UpdatedAt, t.UpdatedAt)
+	}
+	return nil
+}
+
+func loadBackupManifest(dir string) backupManifest {
+	m := backupManifest{}
+	data, err := os.ReadFile(filepath.Join(dir, backupManifestFile))
+	if err == nil {
+		_ = json.Unmarshal(data, &m)
+	}
+	return m
+}
+
+func saveBackupManifest(dir string, m backupManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, backupManifestFile), data, 0o600)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// fakeLister is a provider with a fixed conversation list whose
+// transcripts are counted as they are fetched.
+type fakeLister struct {
+	conversations []provider.Conversation
+	fetched       map[string]int
+	// pages counts ListConversations calls.
+	pages int
+}
+
+func (f *fakeLister) Name() string                                           { return "fake" }
+func (f *fakeLister) CookieSpecs() []provider.CookieSpec                     { return nil }
+func (f *fakeLister) SetCookies(map[string]string)                           {}
+func (f *fakeLister) Ask(context.Context, string, provider.AskOptions) error { return nil }
+
+func (f *fakeLister) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
+	f.pages++
+	return provider.Page(f.conversations, opts), nil
+}
+
+func (f *fakeLister) FetchTranscript(ctx context.Context, id string, opts provider.TranscriptOptions) (*provider.Transcript, error) {
+	if f.fetched == nil {
+		f.fetched = map[string]int{}
+	}
+	f.fetched[id]++
+	for _, c := range f.conversations {
+		if c.ID == id {
+			return &provider.Transcript{ID: id, Title: c.Title, Messages: []provider.Message{{Role: "user", Text: "hi"}}}, nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+func TestBackupIncremental(t *testing.T) {
+	globalCfg = &config.Config{}
+	backupOut, backupFormat, backupIncremental = t.TempDir(), "md", true
+	t.Cleanup(func() { backupOut, backupFormat, backupIncremental = "", "md", false })
+
+	updated := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := &fakeLister{conversations: []provider.Conversation{
+		{ID: "dated", Title: "Dated", UpdatedAt: updated},
+		// Listings like Grok's carry no update time.
+		{ID: "undated", Title: "Undated"},
+	}}
+	ctx := context.Background()
+	if err := backupProvider(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	if err := backupProvider(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	if n := p.fetched["dated"]; n != 1 {
+		t.Errorf("unchanged conversation fetched %d times, want 1", n)
+	}
+	if n := p.fetched["undated"]; n != 2 {
+		t.Errorf("conversation with zero UpdatedAt fetched %d times, want 2", n)
+	}
+
+	manifest := loadBackupManifest(filepath.Join(backupOut, "fake"))
+	if rec := manifest["undated"]; !rec.UpdatedAt.IsZero() || rec.File == "" {
+		t.Errorf("manifest entry = %+v, want a file with zero UpdatedAt", rec)
+	}
+
+	p.conversations[0].UpdatedAt = updated.Add(time.Hour)
+	if err := backupProvider(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	if n := p.fetched["dated"]; n != 2 {
+		t.Errorf("continued conversation fetched %d times in all, want 2", n)
+	}
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/logging"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var (
+	batchProvider    string
+	batchModel       string
+	batchInput       string
+	batchOutput      string
+	batchConcurrency int
+	batchIncognito   bool
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Ask every question in a file and write JSONL results",
+	Long: `Run each line of the input file as an independent question (a new
+conversation each) and write one JSON object per question:
+
+  {"index":0,"question":"...","provider":"chatgpt","model":"...","answer":"...",
+   "sources":[{"name":"...","url":"..."}],"conversation_id":"...","duration_ms":1234}
+
+Failed questions carry an "error" field instead of an answer. Blank lines
+and lines starting with # are skipped. Results are written in input order.
+
+  ask batch --provider chatgpt --input questions.txt --output results.jsonl -j 3`,
+	Args: cobra.NoArgs,
+	RunE: runBatch,
+}
+
+func init() {
+	batchCmd.Flags().StringVarP(&batchProvider, "provider", "p", "", "Provider to ask ("+strings.Join(providerNames, ", ")+")")
+	batchCmd.Flags().StringVarP(&batchModel, "model", "m", "", "Model override")
+	batchCmd.Flags().StringVarP(&batchInput, "input", "i", "-", "Questions file, one per line (- for stdin)")
+	batchCmd.Flags().StringVarP(&batchOutput, "output", "o", "-", "JSONL results file (- for stdout)")
+	batchCmd.Flags().IntVarP(&batchConcurrency, "concurrency", "j", 1, "Questions to run at once")
+	batchCmd.Flags().BoolVar(&batchIncognito, "incognito", false, "Don't save the conversations to provider history")
+	_ = batchCmd.MarkFlagRequired("provider")
+	rootCmd.AddCommand(batchCmd)
+}
+
+// batchResult is one line of batch output.
+type batchResult struct {
+	Index          int               `json:"index"`
+	Question       string            `json:"question"`
+	Provider       string            `json:"provider"`
+	Model          string            `json:"model,omitempty"`
+	Answer         string            `json:"answer,omitempty"`
+	Sources        []provider.Source `json:"sources,omitempty"`
+	ConversationID string            `json:"conversation_id,omitempty"`
+	Error          string            `json:"error,omitempty"`
+	DurationMs     int64             `json:"duration_ms"`
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(providerNames, batchProvider) {
+		return fmt.Errorf("unknown provider %q (use %s)", batchProvider, strings.Join(providerNames, ", "))
+	}
+	if batchConcurrency < 1 {
+		batchConcurrency = 1
+	}
+
+	questions, err := readBatchQuestions(batchInput)
+	if err != nil {
+		return err
+	}
+	if len(questions) == 0 {
+		return fmt.Errorf("no questions in %s", batchInput)
+	}
+
+	var out io.Writer = os.Stdout
+	if batchOutput != "-" {
+		f, err := os.Create(batchOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	// Each worker owns a provider: provider instances keep per-session
+	// state and are not safe for concurrent use.
+	workers := min(batchConcurrency, len(questions))
+	jobs := make(chan int)
+	results := make(chan batchResult)
+	var wg sync.WaitGroup
+	for range workers {
+		p, defaultModel, err := newProviderByName(batchProvider)
+		if err != nil {
+			return err
+		}
+		autoLoadCookies(cmd.Context(), p)
+		model := defaultModel
+		if batchModel != "" {
+			model = batchModel
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results <- askBatchQuestion(cmd.Context(), p, model, i, questions[i])
+			}
+		}()
+	}
+	go func() {
+		for i := range questions {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	// Hold out-of-order results until their turn so the file follows the
+	// input order.
+	enc := json.NewEncoder(out)
+	pending := make(map[int]batchResult)
+	next, failed := 0, 0
+	for r := range results {
+		pending[r.Index] = r
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			if r.Error != "" {
+				failed++
+			}
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", next, len(questions), batchStatus(r))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d question(s) failed", failed, len(questions))
+	}
+	return nil
+}
+
+func askBatchQuestion(ctx context.Context, p provider.Provider, model string, index int, question string) (r batchResult) {
+	r = batchResult{Index: index, Question: question, Provider: p.Name(), Model: model}
+	start := time.Now()
+	defer func() { r.DurationMs = time.Since(start).Milliseconds() }()
+
+	query, err := redactOutbound(question)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+
+	var answer strings.Builder
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: batchIncognito,
+		OnText:    func(text string) { answer.WriteString(text) },
+		OnSource: func(name, url string) {
+			r.Sources = append(r.Sources, provider.Source{Name: name, URL: url})
+		},
+		OnConversation: func(conversationID, parentMessageID, responseID string) {
+			r.ConversationID = conversationID
+		},
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = logging.Logf(slog.With("provider", p.Name(), "question", index))
+	}
+	applySystemPrompt(p.Name(), &opts)
+
+	qctx, cancel := withProviderTimeout(ctx)
+	defer cancel()
+	err = askCached(qctx, p, query, opts)
+	r.Answer = strings.TrimSpace(answer.String())
+	// A trailing stream error after a complete answer is not a failure.
+	if err != nil && r.Answer == "" {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// readBatchQuestions reads one question per line, skipping blank lines and
+// # comments.
+func readBatchQuestions(path string) ([]string, error) {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var questions []string
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		questions = append(questions, line)
+	}
+	return questions, scanner.Err()
+}
+
+func batchStatus(r batchResult) string {
+	q := r.Question
+	if len([]rune(q)) > 60 {
+		q = string([]rune(q)[:57]) + "..."
+	}
+	if r.Error != "" {
+		return fmt.Sprintf("FAIL %s: %s", q, r.Error)
+	}
+	return fmt.Sprintf("ok   %s (%.1fs)", q, float64(r.DurationMs)/1000)
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var (
+	benchRuns      int
+	benchProviders []string
+	benchJSON      bool
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [prompt]",
+	Short: "Measure each provider's latency and failure rate",
+	Long: `Ask every provider the same prompt --runs times and report, per
+provider, the time to first token, the total latency, the streaming speed
+in characters per second after the first token, and how many runs failed.
+
+Providers are benchmarked side by side; each provider's runs go one after
+another. Questions are sent as temporary chats where the provider supports
+them, so no conversations are left behind.
+
+  ask bench "say hi" --runs 3
+  ask bench -p chatgpt,claude --json "explain TCP slow start"`,
+	Args: questionArgs,
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().IntVarP(&benchRuns, "runs", "n", 3, "Times to ask each provider")
+	benchCmd.Flags().StringSliceVarP(&benchProviders, "providers", "p", nil, "Providers to benchmark (default: those ask all uses)")
+	benchCmd.Flags().BoolVar(&benchJSON, "json", false, "Print the results as JSON")
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchRun is the measurement of one question.
+type benchRun struct {
+	TTFTMs  int64  `json:"ttft_ms,omitempty"`
+	TotalMs int64  `json:"total_ms"`
+	Chars   int    `json:"chars"`
+	Error   string `json:"error,omitempty"`
+}
+
+// benchResult summarizes one provider's runs. Averages cover the
+// successful runs only.
+type benchResult struct {
+	Provider     string     `json:"provider"`
+	Model        string     `json:"model,omitempty"`
+	Runs         []benchRun `json:"runs"`
+	Failures     int        `json:"failures"`
+	FailureRate  float64    `json:"failure_rate"`
+	AvgTTFTMs    int64      `json:"avg_ttft_ms,omitempty"`
+	AvgTotalMs   int64      `json:"avg_total_ms,omitempty"`
+	CharsPerSec  float64    `json:"chars_per_sec,omitempty"`
+	entryOrder   int
+	successCount int
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if benchRuns < 1 {
+		return fmt.Errorf("--runs must be at least 1")
+	}
+	if flagStreamJSON {
+		return fmt.Errorf("--stream-json is not supported by bench (use --json)")
+	}
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
+
+	entries, err := benchEntries()
+	if err != nil {
+		return err
+	}
+
+	var wgCookies sync.WaitGroup
+	for _, e := range entries {
+		wgCookies.Add(1)
+		go func(p provider.Provider) {
+			defer wgCookies.Done()
+			autoLoadCookies(cmd.Context(), p)
+		}(e.p)
+	}
+	wgCookies.Wait()
+
+	fmt.Fprintf(os.Stderr, "[bench] %d providers × %d runs...\n", len(entries), benchRuns)
+	results := make([]benchResult, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func(i int, e askAllEntry) {
+			defer wg.Done()
+			r := benchResult{Provider: e.p.Name(), Model: e.model, entryOrder: i}
+			for n := 0; n < benchRuns; n++ {
+				if cmd.Context().Err() != nil {
+					break
+				}
+				run := benchOne(cmd.Context(), e.p, e.model, query)
+				if run.Error != "" {
+					fmt.Fprintf(os.Stderr, "[bench] %s run %d: %s\n", r.Provider, n+1, run.Error)
+				} else {
+					fmt.Fprintf(os.Stderr, "[bench] %s run %d: %s\n", r.Provider, n+1, time.Duration(run.TotalMs)*time.Millisecond)
+				}
+				r.Runs = append(r.Runs, run)
+			}
+			r.summarize()
+			results[i] = r
+		}(i, e)
+	}
+	wg.Wait()
+
+	// Fastest first; providers that never answered go last.
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if (a.successCount == 0) != (b.successCount == 0) {
+			return a.successCount > 0
+		}
+		if a.successCount == 0 {
+			return a.entryOrder < b.entryOrder
+		}
+		return a.AvgTotalMs < b.AvgTotalMs
+	})
+
+	if benchJSON {
+		data, err := json.MarshalIndent(struct {
+			Prompt  string        `json:"prompt"`
+			Runs    int           `json:"runs"`
+			Results []benchResult `json:"results"`
+		}{query, benchRuns, results}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	printBenchTable(results)
+	return nil
+}
+
+// benchEntries returns the providers named by --providers, or the ones
+// ask all uses.
+func benchEntries() ([]askAllEntry, error) {
+	if len(benchProviders) == 0 {
+		return askAllEntries(), nil
+	}
+	var entries []askAllEntry
+	for _, name := range benchProviders {
+		if !slices.Contains(providerNames, name) {
+			return nil, fmt.Errorf("unknown provider %q (use %s)", name, strings.Join(providerNames, ", "))
+		}
+		p, model, err := newProviderByName(name)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, askAllEntry{p, model})
+	}
+	return entries, nil
+}
+
+// benchOne asks once and times the answer.
+func benchOne(ctx context.Context, p provider.Provider, model, query string) benchRun {
+	var (
+		mu        sync.Mutex
+		firstText time.Time
+		chars     int
+	)
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: true,
+		OnText: func(text string) {
+			mu.Lock()
+			defer mu.Unlock()
+			if firstText.IsZero() && text != "" {
+				firstText = time.Now()
+			}
+			chars += utf8.RuneCountInString(text)
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", p.Name(), "err", err)
+		},
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf(p.Name())
+	}
+	applySystemPrompt(p.Name(), &opts)
+
+	rctx, cancel := withProviderTimeout(ctx)
+	defer cancel()
+	start := time.Now()
+	err := sendPrompt(rctx, p, query, opts)
+	total := time.Since(start)
+
+	mu.Lock()
+	defer mu.Unlock()
+	run := benchRun{TotalMs: total.Milliseconds(), Chars: chars}
+	if !firstText.IsZero() {
+		run.TTFTMs = firstText.Sub(start).Milliseconds()
+	}
+	// As in ask all, an error after a full answer doesn't fail the run.
+	switch {
+	case err != nil && chars == 0:
+		run.Error = err.Error()
+	case err == nil && chars == 0:
+		run.Error = "empty answer"
+	}
+	return run
+}
+
+// summarize fills in the failure count and the averages.
+func (r *benchResult) summarize() {
+	var ttft, total, streamMs int64
+	var chars int
+	for _, run := range r.Runs {
+		if run.Error != "" {
+			r.Failures++
+			continue
+		}
+		r.successCount++
+		ttft += run.TTFTMs
+		total += run.TotalMs
+		streamMs += run.TotalMs - run.TTFTMs
+		chars += run.Chars
+	}
+	if len(r.Runs) > 0 {
+		r.FailureRate = float64(r.Failures) / float64(len(r.Runs))
+	}
+	if r.successCount == 0 {
+		return
+	}
+	r.AvgTTFTMs = ttft / int64(r.successCount)
+	r.AvgTotalMs = total / int64(r.successCount)
+	if streamMs > 0 {
+		r.CharsPerSec = float64(chars) / (float64(streamMs) / 1000)
+	}
+}
+
+func printBenchTable(results []benchResult) {
+	width := len("provider")
+	for _, r := range results {
+		width = max(width, len(r.Provider))
+	}
+	fmt.Printf("%-*s  %8s  %8s  %9s  %8s  %s\n", width, "provider", "ttft", "total", "chars/s", "failed", "model")
+	for _, r := range results {
+		ttft, total, speed := "-", "-", "-"
+		if r.successCount > 0 {
+			ttft = formatBenchMs(r.AvgTTFTMs)
+			total = formatBenchMs(r.AvgTotalMs)
+			if r.CharsPerSec > 0 {
+				speed = fmt.Sprintf("%.0f", r.CharsPerSec)
+			}
+		}
+		failed := fmt.Sprintf("%d/%d", r.Failures, len(r.Runs))
+		fmt.Printf("%-*s  %8s  %8s  %9s  %8s  %s\n", width, r.Provider, ttft, total, speed, failed, r.Model)
+	}
+}
+
+func formatBenchMs(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(10 * time.Millisecond).String()
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var flagCache string
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage cached answers",
+	Long: `Answers asked with --cache are kept under the user cache directory and
+reused when the same question is asked of the same provider and model,
+with the same system prompt, within the given time:
+
+  ask chatgpt --cache "capital of France?"        (reuse for 1h)
+  ask chatgpt --cache=24h "capital of France?"
+
+Only new conversations are cached; follow-ups, retries and questions with
+attachments always go to the provider.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete every cached answer",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := os.RemoveAll(responseCacheDir()); err != nil {
+			return err
+		}
+		fmt.Println("Cache cleared.")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&flagCache, "cache", "", "Reuse the answer to an identical question younger than this (--cache=30m; default 1h)")
+	rootCmd.PersistentFlags().Lookup("cache").NoOptDefVal = "1h"
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// cachedAnswer is one stored answer.
+type cachedAnswer struct {
+	Provider string         `json:"provider"`
+	Model    string         `json:"model,omitempty"`
+	Created  time.Time      `json:"created"`
+	Text     string         `json:"text"`
+	Sources  []cachedSource `json:"sources,omitempty"`
+}
+
+type cachedSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func responseCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "ask", "responses")
+}
+
+// responseCacheKey identifies a question: everything that shapes the
+// answer and is known before asking.
+func responseCacheKey(name, query string, opts provider.AskOptions) string {
+	h := sha256.New()
+	for _, part := range []string{name, opts.Model, opts.SystemPrompt, query} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// askCached is p.Ask with the middleware hooks, the prompt size check,
+// --cache, --auto-continue, --stats and the daemon: a fresh stored answer
+// is replayed through the callbacks instead of asking, and a new answer is
+// stored.
+func askCached(ctx context.Context, p provider.Provider, query string, opts provider.AskOptions) error {
+	flush, err := applyMiddleware(ctx, p.Name(), &query, &opts)
+	if err != nil {
+		return err
+	}
+	err = askStored(ctx, p, query, opts)
+	flush(err)
+	return err
+}
+
+func askStored(ctx context.Context, p provider.Provider, query string, opts provider.AskOptions) error {
+	if err := checkPromptSize(p.Name(), query, opts); err != nil {
+		return err
+	}
+	stats := trackStats(p.Name(), &opts)
+	defer stats.stop()
+	if flagCache == "" || opts.ConversationID != "" || opts.Retry || len(opts.Attachments) > 0 {
+		return askContinuing(ctx, p, query, opts)
+	}
+	ttl, err := time.ParseDuration(flagCache)
+	if err != nil || ttl <= 0 {
+		return fmt.Errorf("invalid --cache duration %q (e.g. --cache=30m)", flagCache)
+	}
+	path := filepath.Join(responseCacheDir(), responseCacheKey(p.Name(), query, opts)+".json")
+
+	if data, err := os.ReadFile(path); err == nil {
+		var c cachedAnswer
+		if json.Unmarshal(data, &c) == nil && time.Since(c.Created) < ttl {
+			slog.Debug("answer from cache", "provider", p.Name(), "age", time.Since(c.Created).Round(time.Second))
+			if opts.OnText != nil {
+				opts.OnText(c.Text)
+			}
+			if opts.OnSource != nil {
+				for _, s := range c.Sources {
+					opts.OnSource(s.Name, s.URL)
+				}
+			}
+			if opts.OnDone != nil {
+				opts.OnDone()
+			}
+			return nil
+		}
+	}
+
+	c := cachedAnswer{Provider: p.Name(), Model: opts.Model, Created: time.Now()}
+	var text strings.Builder
+	onText, onSource := opts.OnText, opts.OnSource
+	opts.OnText = func(t string) {
+		text.WriteString(t)
+		if onText != nil {
+			onText(t)
+		}
+	}
+	opts.OnSource = func(name, url string) {
+		c.Sources = append(c.Sources, cachedSource{name, url})
+		if onSource != nil {
+			onSource(name, url)
+		}
+	}
+	if err := askContinuing(ctx, p, query, opts); err != nil {
+		return err
+	}
+	c.Text = text.String()
+	if strings.TrimSpace(c.Text) == "" {
+		return nil
+	}
+	data, err := json.Marshal(c)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0o600)
+	}
+	if err != nil {
+		slog.Debug("caching answer failed", "provider", p.Name(), "err", err)
+	}
+	return nil
+}
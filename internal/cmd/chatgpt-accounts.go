@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	chatgptpkg "github.com/kyupark/ask/internal/provider/chatgpt"
+)
+
+var chatgptAccount string
+
+var chatgptAccountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "List the personal account and workspaces you can use",
+	Long: `List the accounts your ChatGPT login belongs to: your personal
+account and any team or enterprise workspaces. Pass an ID to --account,
+or save it with 'ask config set chatgpt.account <id>', to ask and list
+conversations in that workspace.`,
+	Args: cobra.NoArgs,
+	RunE: runChatGPTAccounts,
+}
+
+func runChatGPTAccounts(cmd *cobra.Command, args []string) error {
+	p := chatgptpkg.New(
+		globalCfg.ChatGPT.BaseURL,
+		"",
+		globalCfg.UserAgent,
+		providerTimeout(),
+	)
+	p.SetCookies(map[string]string{
+		"__Secure-next-auth.session-token": globalCfg.ChatGPT.SessionToken,
+		"cf_clearance":                     globalCfg.ChatGPT.CfClearance,
+		"_puid":                            globalCfg.ChatGPT.PUID,
+	})
+	p.SetProxy(providerProxy("chatgpt"))
+	applyChatGPTSession(p)
+	autoLoadCookies(cmd.Context(), p)
+
+	var logf func(string, ...any)
+	if globalCfg.Verbose {
+		logf = debugLogf("chatgpt")
+	}
+	accounts, err := p.ListAccounts(cmd.Context(), logf)
+	if err != nil {
+		return err
+	}
+
+	current := chatgptAccountID()
+	width := len("id")
+	for _, a := range accounts {
+		width = max(width, len(a.ID))
+	}
+	fmt.Printf("  %-*s  %-10s  %-8s  %s\n", width, "id", "kind", "plan", "name")
+	for _, a := range accounts {
+		mark := " "
+		if a.ID == current || (current == "" && a.Structure == "personal") {
+			mark = "*"
+		}
+		name := a.Name
+		if name == "" && a.Structure == "personal" {
+			name = "(personal)"
+		}
+		fmt.Printf("%s %-*s  %-10s  %-8s  %s\n", mark, width, a.ID, orDash(a.Structure), orDash(a.Plan), name)
+	}
+	return nil
+}
+
+// chatgptAccountID is the account or workspace ChatGPT requests act as:
+// --account, else the chatgpt.account config, else the personal account.
+func chatgptAccountID() string {
+	if v := strings.TrimSpace(chatgptAccount); v != "" {
+		return v
+	}
+	return strings.TrimSpace(globalCfg.ChatGPT.Account)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
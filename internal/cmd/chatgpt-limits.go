@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	chatgptpkg "github.com/kyupark/ask/internal/provider/chatgpt"
+)
+
+// chatgptLowLimit is the number of remaining uses at which an ask warns
+// that a cap is close.
+const chatgptLowLimit = 3
+
+var chatgptLimitsCmd = &cobra.Command{
+	Use:   "limits",
+	Short: "Show remaining ChatGPT quota per model and feature",
+	Long: `Show the usage caps ChatGPT reports for your account: uses left per
+feature (such as deep research), models whose cap is hit, and when each
+resets. Asks warn on their own when a cap seen here or in an earlier
+answer is close or hit.`,
+	Args: cobra.NoArgs,
+	RunE: runChatGPTLimits,
+}
+
+func runChatGPTLimits(cmd *cobra.Command, args []string) error {
+	p := chatgptpkg.New(
+		globalCfg.ChatGPT.BaseURL,
+		"",
+		globalCfg.UserAgent,
+		providerTimeout(),
+	)
+	p.SetCookies(map[string]string{
+		"__Secure-next-auth.session-token": globalCfg.ChatGPT.SessionToken,
+		"cf_clearance":                     globalCfg.ChatGPT.CfClearance,
+		"_puid":                            globalCfg.ChatGPT.PUID,
+	})
+	p.SetProxy(providerProxy("chatgpt"))
+	applyChatGPTSession(p)
+	p.SetAccount(chatgptAccountID())
+	p.SetLimitsHandler(saveChatGPTLimits)
+	autoLoadCookies(cmd.Context(), p)
+
+	var logf func(string, ...any)
+	if globalCfg.Verbose {
+		logf = debugLogf("chatgpt")
+	}
+	limits, err := p.FetchLimits(cmd.Context(), logf)
+	if err != nil {
+		return err
+	}
+	if len(limits) == 0 {
+		fmt.Println("ChatGPT reports no limits in effect.")
+		return nil
+	}
+
+	width := len("model/feature")
+	for _, l := range limits {
+		width = max(width, len(l.Name))
+	}
+	fmt.Printf("%-*s  %-10s  %s\n", width, "model/feature", "remaining", "resets")
+	for _, l := range limits {
+		remaining := "unknown"
+		switch {
+		case l.Exhausted:
+			remaining = "none"
+		case l.Remaining >= 0:
+			remaining = fmt.Sprint(l.Remaining)
+		}
+		resets := "-"
+		if !l.ResetsAt.IsZero() {
+			resets = formatTime(l.ResetsAt.Local())
+		}
+		fmt.Printf("%-*s  %-10s  %s\n", width, l.Name, remaining, resets)
+	}
+	return nil
+}
+
+// saveChatGPTLimits merges reported quotas into the saved state, newest
+// report winning per name.
+func saveChatGPTLimits(limits []chatgptpkg.Limit) {
+	now := time.Now()
+	_ = config.UpdateState(func(state *config.State) {
+		for _, l := range limits {
+			ls := config.ChatGPTLimitState{
+				Name:       l.Name,
+				Remaining:  l.Remaining,
+				Exhausted:  l.Exhausted,
+				ResetsAt:   l.ResetsAt,
+				ObservedAt: now,
+			}
+			replaced := false
+			for i := range state.ChatGPTLimits {
+				if state.ChatGPTLimits[i].Name == l.Name {
+					state.ChatGPTLimits[i] = ls
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				state.ChatGPTLimits = append(state.ChatGPTLimits, ls)
+			}
+		}
+	})
+}
+
+// warnChatGPTLimits prints a warning before asking when the saved quotas
+// say the model's or the feature's cap is hit or close. Quotas whose
+// reset time has passed are ignored.
+func warnChatGPTLimits(model string, research bool) {
+	names := []string{model}
+	if research {
+		names = append(names, "deep_research")
+	}
+	now := time.Now()
+	for _, l := range config.LoadState().ChatGPTLimits {
+		if !slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, l.Name) }) {
+			continue
+		}
+		resetsAt := ""
+		if !l.ResetsAt.IsZero() {
+			if l.ResetsAt.Before(now) {
+				continue
+			}
+			resetsAt = " (resets " + formatTime(l.ResetsAt.Local()) + ")"
+		} else if now.Sub(l.ObservedAt) > 24*time.Hour {
+			// Without a reset time, an old report says nothing about now.
+			continue
+		}
+		switch {
+		case l.Exhausted:
+			fmt.Fprintf(os.Stderr, "[chatgpt] warning: %s limit reached%s; ChatGPT may answer with another model or refuse\n", l.Name, resetsAt)
+		case l.Remaining >= 0 && l.Remaining <= chatgptLowLimit:
+			fmt.Fprintf(os.Stderr, "[chatgpt] warning: %d %s use(s) left%s\n", l.Remaining, l.Name, resetsAt)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
 
@@ -17,6 +18,11 @@ var (
 	chatgptEffort       string
 	chatgptResume       bool
 	chatgptConversation string
+	chatgptAttach       []string
+	chatgptListArchived bool
+	chatgptSearch       bool
+	chatgptResearch     bool
+	chatgptGPT          string
 )
 
 var chatgptCmd = &cobra.Command{
@@ -27,10 +33,20 @@ var chatgptCmd = &cobra.Command{
   ask-incognito  Ask a question (no history)
   list           List recent conversations
 	delete         Delete a conversation by ID
-	models         Show available models`,
+	rename         Rename a conversation
+	archive        Archive a conversation (unarchive to restore)
+	share          Create a public share link
+	models         Show available models
+	gpts           List pinned custom GPTs
+	limits         Show remaining quota per model
+	alias          Name a conversation for use with -c
+	show           Show a conversation transcript
+	export         Export a transcript (md, json, html)
+	retry          Get a new answer to the last question
+	followup       Ask a suggested follow-up by number`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		if len(args) == 0 && !flagEdit {
 			return cmd.Help()
 		}
 		return runChatGPTAsk(cmd, args, false)
@@ -40,10 +56,17 @@ var chatgptCmd = &cobra.Command{
 var chatgptAskIncognitoCmd = &cobra.Command{
 	Use:   "ask-incognito [question]",
 	Short: "Ask ChatGPT (no history)",
-	Args:  cobra.MinimumNArgs(1),
+	Args:  questionArgs,
 	RunE:  func(cmd *cobra.Command, args []string) error { return runChatGPTAsk(cmd, args, true) },
 }
 
+var chatgptGPTsCmd = &cobra.Command{
+	Use:   "gpts",
+	Short: "List your pinned custom GPTs",
+	Args:  cobra.NoArgs,
+	RunE:  runChatGPTGPTs,
+}
+
 var chatgptListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List recent ChatGPT conversations",
@@ -58,6 +81,72 @@ var chatgptDeleteCmd = &cobra.Command{
 	RunE:  runChatGPTDelete,
 }
 
+var chatgptRenameCmd = &cobra.Command{
+	Use:   "rename <conversation-id> <title>",
+	Short: "Rename a ChatGPT conversation",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, _, err := newProviderByName("chatgpt")
+		if err != nil {
+			return err
+		}
+		return runRename(cmd.Context(), p, args[0], strings.Join(args[1:], " "))
+	},
+}
+
+var chatgptArchiveCmd = &cobra.Command{
+	Use:   "archive <conversation-id>",
+	Short: "Archive a ChatGPT conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, _, err := newProviderByName("chatgpt")
+		if err != nil {
+			return err
+		}
+		return runArchive(cmd.Context(), p, args[0], true)
+	},
+}
+
+var chatgptUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive <conversation-id>",
+	Short: "Restore an archived ChatGPT conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, _, err := newProviderByName("chatgpt")
+		if err != nil {
+			return err
+		}
+		return runArchive(cmd.Context(), p, args[0], false)
+	},
+}
+
+var chatgptShareCmd = &cobra.Command{
+	Use:   "share <conversation-id>",
+	Short: "Create a public share link for a ChatGPT conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, _, err := newProviderByName("chatgpt")
+		if err != nil {
+			return err
+		}
+		return runShare(cmd.Context(), p, args[0])
+	},
+}
+
+var chatgptFollowupCmd = &cobra.Command{
+	Use:   "followup <n>",
+	Short: "Ask a suggested follow-up from the last ChatGPT answer",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := followUpQuestion("chatgpt", args[0])
+		if err != nil {
+			return err
+		}
+		chatgptResume = true
+		return runChatGPTAsk(cmd, []string{q}, false)
+	},
+}
+
 var chatgptModelsCmd = &cobra.Command{
 	Use:   "models",
 	Short: "Show available ChatGPT models (fetches from account if possible)",
@@ -69,18 +158,53 @@ func init() {
 	chatgptCmd.Flags().StringVarP(&chatgptModel, "model", "m", "", "Model override (e.g. 'auto', 'gpt-5-2', 'gpt-5-2-thinking')")
 	chatgptCmd.Flags().StringVar(&chatgptEffort, "effort", "", "Thinking effort (none, low, medium, high, xhigh)")
 	chatgptCmd.Flags().BoolVarP(&chatgptResume, "resume", "r", false, "Resume last conversation")
-	chatgptCmd.Flags().StringVar(&chatgptConversation, "conversation", "", "Continue a specific conversation by ID")
+	chatgptCmd.Flags().StringVar(&forkParent, "parent", "", "Branch the conversation after this message ID instead of its latest answer")
+	chatgptCmd.Flags().BoolVar(&forkPick, "pick-parent", false, "Choose an earlier answer to branch the conversation from")
+	chatgptCmd.Flags().StringVarP(&chatgptConversation, "conversation", "c", "", "Continue a specific conversation by ID or alias")
 	chatgptAskIncognitoCmd.Flags().StringVarP(&chatgptModel, "model", "m", "", "Model override (e.g. 'auto', 'gpt-5-2', 'gpt-5-2-thinking')")
 	chatgptAskIncognitoCmd.Flags().StringVar(&chatgptEffort, "effort", "", "Thinking effort (none, low, medium, high, xhigh)")
+	chatgptCmd.Flags().StringArrayVar(&chatgptAttach, "attach", nil, "Attach a local file (repeatable)")
+	chatgptAskIncognitoCmd.Flags().StringArrayVar(&chatgptAttach, "attach", nil, "Attach a local file (repeatable)")
+	chatgptCmd.Flags().BoolVar(&chatgptSearch, "search", false, "Browse the web and cite sources")
+	chatgptAskIncognitoCmd.Flags().BoolVar(&chatgptSearch, "search", false, "Browse the web and cite sources")
+	chatgptCmd.Flags().BoolVar(&chatgptResearch, "deep-research", false, "Run a deep research task and print its report (can take many minutes)")
+	chatgptAskIncognitoCmd.Flags().BoolVar(&chatgptResearch, "deep-research", false, "Run a deep research task and print its report (can take many minutes)")
+	chatgptCmd.Flags().StringVar(&chatgptGPT, "gpt", "", "Ask a custom GPT by ID (g-…, see 'ask chatgpt gpts')")
+	chatgptCmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print reasoning summaries before the answer")
+	chatgptAskIncognitoCmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print reasoning summaries before the answer")
+	chatgptAskIncognitoCmd.Flags().StringVar(&chatgptGPT, "gpt", "", "Ask a custom GPT by ID (g-…, see 'ask chatgpt gpts')")
+	chatgptCmd.Flags().StringVar(&downloadDir, "download", "", "Save generated images and code interpreter files to this directory")
+	chatgptAskIncognitoCmd.Flags().StringVar(&downloadDir, "download", "", "Save generated images and code interpreter files to this directory")
+	chatgptCmd.PersistentFlags().StringVar(&chatgptAccount, "account", "", "Use this workspace account ID (see 'ask chatgpt accounts')")
+	chatgptListCmd.Flags().BoolVar(&chatgptListArchived, "archived", false, "List archived conversations")
 	chatgptCmd.AddCommand(chatgptAskIncognitoCmd)
+	addListFlags(chatgptListCmd)
 	chatgptCmd.AddCommand(chatgptListCmd)
 	chatgptCmd.AddCommand(chatgptDeleteCmd)
+	chatgptCmd.AddCommand(chatgptRenameCmd)
+	chatgptCmd.AddCommand(chatgptArchiveCmd)
+	chatgptCmd.AddCommand(chatgptUnarchiveCmd)
+	chatgptCmd.AddCommand(chatgptShareCmd)
 	chatgptCmd.AddCommand(chatgptModelsCmd)
+	chatgptCmd.AddCommand(chatgptGPTsCmd)
+	chatgptCmd.AddCommand(chatgptLimitsCmd)
+	chatgptCmd.AddCommand(chatgptAccountsCmd)
+	chatgptCmd.AddCommand(newAliasCmd("chatgpt"))
+	chatgptCmd.AddCommand(newShowCmd("chatgpt"))
+	chatgptCmd.AddCommand(newExportCmd("chatgpt"))
+	chatgptCmd.AddCommand(newRetryCmd("chatgpt", &chatgptResume, runChatGPTAsk))
+	chatgptCmd.AddCommand(chatgptFollowupCmd)
 	rootCmd.AddCommand(chatgptCmd)
 }
 
 func runChatGPTAsk(cmd *cobra.Command, args []string, temporary bool) error {
-	query := strings.Join(args, " ")
+	if chatgptGPT != "" && !strings.HasPrefix(chatgptGPT, "g-") {
+		return fmt.Errorf("invalid GPT ID %q (expected g-…, see 'ask chatgpt gpts')", chatgptGPT)
+	}
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
 
 	model := strings.TrimSpace(globalCfg.ChatGPT.Model)
 	if explicitModel := strings.TrimSpace(chatgptModel); explicitModel != "" {
@@ -101,8 +225,11 @@ func runChatGPTAsk(cmd *cobra.Command, args []string, temporary bool) error {
 		"cf_clearance":                     globalCfg.ChatGPT.CfClearance,
 		"_puid":                            globalCfg.ChatGPT.PUID,
 	})
+	p.SetProxy(providerProxy("chatgpt"))
+	applyChatGPTSession(p)
+	p.SetAccount(chatgptAccountID())
 
-	autoLoadCookies(cmd.Context(), p)
+	loadAskCookies(cmd.Context(), p)
 
 	// Apply thinking effort — skip default for debug.
 	effort := chatgptEffort
@@ -112,27 +239,41 @@ func runChatGPTAsk(cmd *cobra.Command, args []string, temporary bool) error {
 	if effort != "" {
 		p.SetThinkingEffort(effort)
 	}
-
+	p.SetTimezone(globalCfg.ChatGPT.Timezone)
+	p.SetLocale(globalCfg.ChatGPT.Locale)
+	p.SetWebSearch(chatgptSearch)
+	p.SetDeepResearch(chatgptResearch)
+	p.SetGizmo(chatgptGPT)
+	applyChatGPTFingerprint(p)
+	p.SetLimitsHandler(saveChatGPTLimits)
+	warnChatGPTLimits(model, chatgptResearch)
+
+	var sources sourceList
 	opts := provider.AskOptions{
-		Model:     model,
-		Verbose:   globalCfg.Verbose,
-		Temporary: temporary,
+		Model:       model,
+		Verbose:     globalCfg.Verbose,
+		Temporary:   temporary,
+		Attachments: chatgptAttach,
 		OnText: func(text string) {
 			fmt.Print(text)
 		},
+		OnSource: func(name, url string) {
+			sources.add(name, url)
+		},
+		OnProgress: func(status string) {
+			fmt.Fprintf(os.Stderr, "\n[%s]\n", status)
+		},
 		OnError: func(err error) {
-			if globalCfg.Verbose {
-				fmt.Fprintf(os.Stderr, "[chatgpt] error: %v\n", err)
-			}
+			slog.Debug("stream error", "provider", "chatgpt", "err", err)
 		},
 	}
 
 	if !temporary {
 		if chatgptConversation != "" {
-			opts.ConversationID = chatgptConversation
+			opts.ConversationID = resolveConversationID("chatgpt", chatgptConversation)
 		} else if chatgptResume {
 			state := config.LoadState()
-			if conv := state.GetConversation("chatgpt"); conv != nil {
+			if conv := lastConversation(state, "chatgpt"); conv != nil {
 				opts.ConversationID = conv.ConversationID
 				opts.ParentMessageID = conv.ParentMessageID
 			} else {
@@ -140,35 +281,71 @@ func runChatGPTAsk(cmd *cobra.Command, args []string, temporary bool) error {
 			}
 		}
 	}
+	opts.Retry = retryLast && opts.ConversationID != ""
+	if err := applyParent(cmd.Context(), p, &opts); err != nil {
+		return err
+	}
+
+	var followUps []string
+	opts.OnFollowUps = func(suggestions []string) {
+		followUps = suggestions
+	}
+
+	applyDownload(&opts)
+	var images []provider.GeneratedImage
+	opts.OnImage = func(img provider.GeneratedImage) {
+		images = append(images, img)
+	}
+	var files []provider.GeneratedFile
+	opts.OnFile = func(f provider.GeneratedFile) {
+		files = append(files, f)
+	}
 
 	// Save conversation state and capture ID for hint.
 	var lastConvID string
 	if !temporary {
 		opts.OnConversation = func(convID, parentMsgID, respID string) {
 			lastConvID = convID
-			state := config.LoadState()
-			state.SetConversation("chatgpt", &config.ConversationState{
+			saveConversationState("chatgpt", query, &config.ConversationState{
 				ConversationID:  convID,
 				ParentMessageID: parentMsgID,
 			})
-			_ = config.SaveState(state)
 		}
 	}
 	if globalCfg.Verbose {
-		opts.LogFunc = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format+"\n", args...)
-		}
+		opts.LogFunc = debugLogf("chatgpt")
 	}
 
-	if err := p.Ask(cmd.Context(), query, opts); err != nil {
+	applySystemPrompt("chatgpt", &opts)
+	applyShowThinking(&opts)
+	applyStreamJSON("chatgpt", &opts)
+	rec := recordHistory("chatgpt", opts.Model, query, &opts)
+
+	err = askCached(cmd.Context(), p, query, opts)
+	rec.finish(err)
+	if err != nil {
+		if interrupted.Load() {
+			return stopInterrupted("chatgpt", lastConvID, temporary)
+		}
+		emitStreamError("chatgpt", err)
 		return err
 	}
 
-	fmt.Println()
+	// Follow-ups arrive after the conversation ID, so attach them afterwards.
+	if lastConvID != "" && len(followUps) > 0 {
+		saveFollowUps("chatgpt", lastConvID, followUps)
+	}
+
+	finishAnswer("chatgpt")
+
+	printSources(sources.list)
+	printImages(images)
+	printFiles(files)
+
+	printFollowUps("chatgpt", followUps)
 
 	if lastConvID != "" && !temporary {
-		fmt.Fprintf(os.Stderr, "\nConversation: %s\n", lastConvID)
-		fmt.Fprintf(os.Stderr, "  ask chatgpt -c %s \"follow up\"\n", lastConvID)
+		printConversationHint("chatgpt", lastConvID)
 	}
 
 	return nil
@@ -187,8 +364,13 @@ func runChatGPTList(cmd *cobra.Command, args []string) error {
 		"cf_clearance":                     globalCfg.ChatGPT.CfClearance,
 		"_puid":                            globalCfg.ChatGPT.PUID,
 	})
+	p.SetProxy(providerProxy("chatgpt"))
+	applyChatGPTSession(p)
+	p.SetAccount(chatgptAccountID())
 
-	return runList(cmd.Context(), p, 20)
+	opts := listOptions()
+	opts.Archived = chatgptListArchived
+	return runList(cmd.Context(), p, opts)
 }
 
 func runChatGPTDelete(cmd *cobra.Command, args []string) error {
@@ -204,6 +386,9 @@ func runChatGPTDelete(cmd *cobra.Command, args []string) error {
 		"cf_clearance":                     globalCfg.ChatGPT.CfClearance,
 		"_puid":                            globalCfg.ChatGPT.PUID,
 	})
+	p.SetProxy(providerProxy("chatgpt"))
+	applyChatGPTSession(p)
+	p.SetAccount(chatgptAccountID())
 
 	return runDelete(cmd.Context(), p, args[0])
 }
@@ -220,13 +405,14 @@ func runChatGPTModels(cmd *cobra.Command, args []string) error {
 		"cf_clearance":                     globalCfg.ChatGPT.CfClearance,
 		"_puid":                            globalCfg.ChatGPT.PUID,
 	})
+	p.SetProxy(providerProxy("chatgpt"))
+	applyChatGPTSession(p)
+	p.SetAccount(chatgptAccountID())
 	autoLoadCookies(cmd.Context(), p)
 
 	var logf func(string, ...any)
 	if globalCfg.Verbose {
-		logf = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format+"\n", args...)
-		}
+		logf = debugLogf("chatgpt")
 	}
 
 	models, err := p.FetchAvailableModels(cmd.Context(), logf)
@@ -247,3 +433,113 @@ func runChatGPTModels(cmd *cobra.Command, args []string) error {
 
 	return runModels(p)
 }
+
+func runChatGPTGPTs(cmd *cobra.Command, args []string) error {
+	p := chatgptpkg.New(
+		globalCfg.ChatGPT.BaseURL,
+		"",
+		globalCfg.UserAgent,
+		providerTimeout(),
+	)
+	p.SetCookies(map[string]string{
+		"__Secure-next-auth.session-token": globalCfg.ChatGPT.SessionToken,
+		"cf_clearance":                     globalCfg.ChatGPT.CfClearance,
+		"_puid":                            globalCfg.ChatGPT.PUID,
+	})
+	p.SetProxy(providerProxy("chatgpt"))
+	applyChatGPTSession(p)
+	p.SetAccount(chatgptAccountID())
+	autoLoadCookies(cmd.Context(), p)
+
+	var logf func(string, ...any)
+	if globalCfg.Verbose {
+		logf = debugLogf("chatgpt")
+	}
+
+	gpts, err := p.ListGPTs(cmd.Context(), logf)
+	if err != nil {
+		return err
+	}
+	if len(gpts) == 0 {
+		fmt.Println("No pinned GPTs.")
+		return nil
+	}
+	for _, g := range gpts {
+		fmt.Printf("  %-24s %s\n", g.ID, g.Name)
+		if d := strings.TrimSpace(g.Description); d != "" {
+			fmt.Printf("  %-24s %s\n", "", d)
+		}
+	}
+	return nil
+}
+
+// applyChatGPTFingerprint sets the browser fingerprint for ChatGPT requests:
+// the per-install profile (generated and saved on first use) with any
+// config overrides layered on top.
+func applyChatGPTFingerprint(p *chatgptpkg.Provider) {
+	saved := config.LoadState().ChatGPTFingerprint
+	if saved == nil {
+		_ = config.UpdateState(func(state *config.State) {
+			// Another run may have generated it since.
+			if state.ChatGPTFingerprint == nil {
+				fp := chatgptpkg.RandomFingerprint()
+				state.ChatGPTFingerprint = &config.FingerprintConfig{
+					ScreenWidth:  fp.ScreenWidth,
+					ScreenHeight: fp.ScreenHeight,
+					PageWidth:    fp.PageWidth,
+					PageHeight:   fp.PageHeight,
+					PixelRatio:   fp.PixelRatio,
+					Cores:        fp.Cores,
+				}
+			}
+			saved = state.ChatGPTFingerprint
+		})
+	}
+
+	p.SetFingerprint(fingerprintFromConfig(*saved))
+	p.SetFingerprint(fingerprintFromConfig(globalCfg.ChatGPT.Fingerprint))
+}
+
+// applyChatGPTSession restores the device ID, access token and sentinel
+// handshake saved by earlier runs and saves them again as they change.
+func applyChatGPTSession(p *chatgptpkg.Provider) {
+	if s := config.LoadState().ChatGPTSession; s != nil {
+		p.SetSession(chatgptpkg.Session{
+			DeviceID:    s.DeviceID,
+			AccessToken: s.AccessToken,
+			TokenExpiry: s.TokenExpiry,
+			Account:     s.Account,
+			ChatToken:   s.ChatToken,
+			ProofToken:  s.ProofToken,
+			SentinelAt:  s.SentinelAt,
+		})
+	}
+	p.SetSessionHandler(saveChatGPTSession)
+}
+
+func saveChatGPTSession(s chatgptpkg.Session) {
+	_ = config.UpdateState(func(state *config.State) {
+		state.ChatGPTSession = &config.ChatGPTSessionState{
+			DeviceID:    s.DeviceID,
+			AccessToken: s.AccessToken,
+			TokenExpiry: s.TokenExpiry,
+			Account:     s.Account,
+			ChatToken:   s.ChatToken,
+			ProofToken:  s.ProofToken,
+			SentinelAt:  s.SentinelAt,
+		}
+	})
+}
+
+func fingerprintFromConfig(c config.FingerprintConfig) chatgptpkg.Fingerprint {
+	return chatgptpkg.Fingerprint{
+		ScreenWidth:  c.ScreenWidth,
+		ScreenHeight: c.ScreenHeight,
+		PageWidth:    c.PageWidth,
+		PageHeight:   c.PageHeight,
+		PixelRatio:   c.PixelRatio,
+		Cores:        c.Cores,
+		ScriptSrc:    c.ScriptSrc,
+		DPL:          c.DPL,
+	}
+}
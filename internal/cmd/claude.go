@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -15,8 +15,12 @@ import (
 var (
 	claudeModel          string
 	claudeThinkingEffort string
+	claudeThinkingBudget int
+	claudeSearch         bool
+	claudeNoSearch       bool
 	claudeResume         bool
 	claudeConversation   string
+	claudeAttach         []string
 )
 
 var claudeCmd = &cobra.Command{
@@ -27,10 +31,15 @@ var claudeCmd = &cobra.Command{
   ask-incognito  Ask a question (no history)
   list           List recent conversations
 	delete         Delete a conversation by ID
-	models         Show available models and modes`,
+	models         Show available models
+	alias          Name a conversation for use with -c and modes
+	export         Export a transcript (md, json, html)
+	retry          Get a new answer to the last question
+With claude.api_fallback set, a question claude.ai fails to answer is
+asked again through the Anthropic API (see ask anthropic-api).`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		if len(args) == 0 && !flagEdit {
 			return cmd.Help()
 		}
 		return runClaudeAsk(cmd, args, false)
@@ -40,7 +49,7 @@ var claudeCmd = &cobra.Command{
 var claudeAskIncognitoCmd = &cobra.Command{
 	Use:   "ask-incognito [question]",
 	Short: "Ask Claude (no history)",
-	Args:  cobra.MinimumNArgs(1),
+	Args:  questionArgs,
 	RunE:  func(cmd *cobra.Command, args []string) error { return runClaudeAsk(cmd, args, true) },
 }
 
@@ -71,19 +80,42 @@ var claudeModelsCmd = &cobra.Command{
 func init() {
 	claudeCmd.Flags().StringVarP(&claudeModel, "model", "m", "", "Model override (e.g. 'claude-opus-4-6', 'claude-sonnet-4-6')")
 	claudeCmd.Flags().StringVar(&claudeThinkingEffort, "effort", "", "Thinking effort (low, medium, high, max)")
+	claudeCmd.Flags().IntVar(&claudeThinkingBudget, "thinking-budget", 0, "Thinking budget in tokens (min 1024)")
+	claudeCmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print Claude's thinking before the answer")
+	claudeCmd.Flags().BoolVar(&claudeSearch, "search", false, "Let Claude search the web and cite sources")
+	claudeCmd.Flags().BoolVar(&claudeNoSearch, "no-search", false, "Turn web search off for this question")
+	claudeCmd.MarkFlagsMutuallyExclusive("search", "no-search")
 	claudeCmd.Flags().BoolVarP(&claudeResume, "resume", "r", false, "Resume last conversation")
-	claudeCmd.Flags().StringVar(&claudeConversation, "conversation", "", "Continue a specific conversation by ID")
+	claudeCmd.Flags().StringVar(&forkParent, "parent", "", "Branch the conversation after this message ID instead of its latest answer")
+	claudeCmd.Flags().BoolVar(&forkPick, "pick-parent", false, "Choose an earlier answer to branch the conversation from")
+	claudeCmd.Flags().StringVarP(&claudeConversation, "conversation", "c", "", "Continue a specific conversation by ID or alias")
 	claudeAskIncognitoCmd.Flags().StringVarP(&claudeModel, "model", "m", "", "Model override (e.g. 'claude-opus-4-6', 'claude-sonnet-4-6')")
 	claudeAskIncognitoCmd.Flags().StringVar(&claudeThinkingEffort, "effort", "", "Thinking effort (low, medium, high, max)")
+	claudeAskIncognitoCmd.Flags().IntVar(&claudeThinkingBudget, "thinking-budget", 0, "Thinking budget in tokens (min 1024)")
+	claudeAskIncognitoCmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print Claude's thinking before the answer")
+	claudeAskIncognitoCmd.Flags().BoolVar(&claudeSearch, "search", false, "Let Claude search the web and cite sources")
+	claudeAskIncognitoCmd.Flags().BoolVar(&claudeNoSearch, "no-search", false, "Turn web search off for this question")
+	claudeAskIncognitoCmd.MarkFlagsMutuallyExclusive("search", "no-search")
+	claudeCmd.Flags().StringVar(&downloadDir, "download", "", "Save files Claude creates to this directory")
+	claudeAskIncognitoCmd.Flags().StringVar(&downloadDir, "download", "", "Save files Claude creates to this directory")
+	claudeCmd.Flags().StringArrayVar(&claudeAttach, "attach", nil, "Attach a local file or image (repeatable)")
+	claudeAskIncognitoCmd.Flags().StringArrayVar(&claudeAttach, "attach", nil, "Attach a local file or image (repeatable)")
 	claudeCmd.AddCommand(claudeAskIncognitoCmd)
+	addListFlags(claudeListCmd)
 	claudeCmd.AddCommand(claudeListCmd)
 	claudeCmd.AddCommand(claudeDeleteCmd)
 	claudeCmd.AddCommand(claudeModelsCmd)
+	claudeCmd.AddCommand(newAliasCmd("claude"))
+	claudeCmd.AddCommand(newExportCmd("claude"))
+	claudeCmd.AddCommand(newRetryCmd("claude", &claudeResume, runClaudeAsk))
 	rootCmd.AddCommand(claudeCmd)
 }
 
 func runClaudeAsk(cmd *cobra.Command, args []string, temporary bool) error {
-	query := strings.Join(args, " ")
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
 
 	p := claudepkg.New(
 		globalCfg.Claude.BaseURL,
@@ -95,8 +127,9 @@ func runClaudeAsk(cmd *cobra.Command, args []string, temporary bool) error {
 	p.SetCookies(map[string]string{
 		"sessionKey": globalCfg.Claude.SessionKey,
 	})
+	p.SetProxy(providerProxy("claude"))
 
-	autoLoadCookies(cmd.Context(), p)
+	loadAskCookies(cmd.Context(), p)
 
 	effort := claudeThinkingEffort
 	if effort == "" {
@@ -106,31 +139,45 @@ func runClaudeAsk(cmd *cobra.Command, args []string, temporary bool) error {
 		effort = "medium"
 	}
 	p.SetThinkingEffort(effort)
+	budget := claudeThinkingBudget
+	if budget == 0 {
+		budget = globalCfg.Claude.ThinkingBudget
+	}
+	if budget != 0 && budget < claudepkg.MinThinkingBudget {
+		return fmt.Errorf("--thinking-budget must be at least %d tokens", claudepkg.MinThinkingBudget)
+	}
+	p.SetThinkingBudget(budget)
+	if claudeSearch || claudeNoSearch {
+		p.SetWebSearch(claudeSearch)
+	}
 	model := globalCfg.Claude.Model
 	if claudeModel != "" {
 		model = claudeModel
 	}
 
+	var sources sourceList
 	opts := provider.AskOptions{
-		Model:     model,
-		Verbose:   globalCfg.Verbose,
-		Temporary: temporary,
+		Model:       model,
+		Verbose:     globalCfg.Verbose,
+		Temporary:   temporary,
+		Attachments: claudeAttach,
 		OnText: func(text string) {
 			fmt.Print(text)
 		},
+		OnSource: func(name, url string) {
+			sources.add(name, url)
+		},
 		OnError: func(err error) {
-			if globalCfg.Verbose {
-				fmt.Fprintf(os.Stderr, "[claude] error: %v\n", err)
-			}
+			slog.Debug("stream error", "provider", "claude", "err", err)
 		},
 	}
 
 	if !temporary {
 		if claudeConversation != "" {
-			opts.ConversationID = claudeConversation
+			opts.ConversationID = resolveConversationID("claude", claudeConversation)
 		} else if claudeResume {
 			state := config.LoadState()
-			if conv := state.GetConversation("claude"); conv != nil {
+			if conv := lastConversation(state, "claude"); conv != nil {
 				opts.ConversationID = conv.ConversationID
 				opts.ParentMessageID = conv.ParentMessageID
 			} else {
@@ -138,35 +185,63 @@ func runClaudeAsk(cmd *cobra.Command, args []string, temporary bool) error {
 			}
 		}
 	}
+	opts.Retry = retryLast && opts.ConversationID != ""
+	if err := applyParent(cmd.Context(), p, &opts); err != nil {
+		return err
+	}
+
+	applyDownload(&opts)
+	var files []provider.GeneratedFile
+	opts.OnFile = func(f provider.GeneratedFile) {
+		files = append(files, f)
+	}
 
 	// Save conversation state and capture ID for hint.
 	var lastConvID string
 	if !temporary {
 		opts.OnConversation = func(convID, parentMsgID, respID string) {
 			lastConvID = convID
-			state := config.LoadState()
-			state.SetConversation("claude", &config.ConversationState{
+			saveConversationState("claude", query, &config.ConversationState{
 				ConversationID:  convID,
 				ParentMessageID: parentMsgID,
 			})
-			_ = config.SaveState(state)
 		}
 	}
 	if globalCfg.Verbose {
-		opts.LogFunc = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format+"\n", args...)
-		}
+		opts.LogFunc = debugLogf("claude")
 	}
 
-	if err := p.Ask(cmd.Context(), query, opts); err != nil {
+	applySystemPrompt("claude", &opts)
+	applyShowThinking(&opts)
+	applyStreamJSON("claude", &opts)
+	answered := false
+	onText := opts.OnText
+	opts.OnText = func(text string) {
+		answered = true
+		onText(text)
+	}
+	rec := recordHistory("claude", opts.Model, query, &opts)
+
+	err = askCached(cmd.Context(), p, query, opts)
+	rec.finish(err)
+	if err != nil {
+		if interrupted.Load() {
+			return stopInterrupted("claude", lastConvID, temporary)
+		}
+		emitStreamError("claude", err)
+		if globalCfg.Claude.APIFallback && !answered && cmd.Context().Err() == nil {
+			fmt.Fprintf(os.Stderr, "[fallback] claude failed: %v\n[fallback] trying anthropic-api\n", err)
+			return askWithFailover(cmd.Context(), []string{"anthropic-api"}, query)
+		}
 		return err
 	}
 
-	fmt.Println()
+	finishAnswer("claude")
+	printSources(sources.list)
+	printFiles(files)
 
 	if lastConvID != "" && !temporary {
-		fmt.Fprintf(os.Stderr, "\nConversation: %s\n", lastConvID)
-		fmt.Fprintf(os.Stderr, "  ask claude -c %s \"follow up\"\n", lastConvID)
+		printConversationHint("claude", lastConvID)
 	}
 
 	return nil
@@ -183,8 +258,9 @@ func runClaudeList(cmd *cobra.Command, args []string) error {
 	p.SetCookies(map[string]string{
 		"sessionKey": globalCfg.Claude.SessionKey,
 	})
+	p.SetProxy(providerProxy("claude"))
 
-	return runList(cmd.Context(), p, 20)
+	return runList(cmd.Context(), p, listOptions())
 }
 
 func runClaudeDelete(cmd *cobra.Command, args []string) error {
@@ -198,6 +274,7 @@ func runClaudeDelete(cmd *cobra.Command, args []string) error {
 	p.SetCookies(map[string]string{
 		"sessionKey": globalCfg.Claude.SessionKey,
 	})
+	p.SetProxy(providerProxy("claude"))
 
 	return runDelete(cmd.Context(), p, args[0])
 }
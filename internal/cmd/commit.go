@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// maxCommitDiffBytes caps the staged diff sent for a commit message; the
+// diffstat still covers every file when the diff itself is cut.
+const maxCommitDiffBytes = 64 << 10
+
+var (
+	commitProvider string
+	commitApply    bool
+)
+
+var commitCmd = &cobra.Command{
+	Use:   "commit [hint]",
+	Short: "Write a commit message for the staged changes",
+	Long: `Send the staged diff (git diff --cached) to a provider and print a
+commit message in the Conventional Commits style. Words after the command
+are passed along as a hint, such as the reason for the change.
+
+With --apply the message is used to commit right away.
+
+  ask commit
+  ask commit -p claude --apply "fixes the login loop on Safari"`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runCommit,
+}
+
+func init() {
+	commitCmd.Flags().StringVarP(&commitProvider, "provider", "p", "", "Provider that writes the message (default: default_provider)")
+	commitCmd.Flags().BoolVar(&commitApply, "apply", false, "Commit the staged changes with the message")
+	rootCmd.AddCommand(commitCmd)
+}
+
+func runCommit(cmd *cobra.Command, args []string) error {
+	name, err := toolProvider(commitProvider)
+	if err != nil {
+		return err
+	}
+	diff, err := gitOutput("diff", "--cached", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return fmt.Errorf("nothing staged — git add the changes first")
+	}
+	stat, err := gitOutput("diff", "--cached", "--no-color", "--stat")
+	if err != nil {
+		return err
+	}
+
+	prompt, err := redactOutbound(commitPrompt(stat, truncateMiddle(diff, maxCommitDiffBytes), strings.Join(args, " ")))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "[commit] %s is writing the message...\n", name)
+	answer, err := askOnce(cmd.Context(), name, prompt, nil)
+	if err != nil {
+		return err
+	}
+	msg := cleanCommitMessage(answer)
+
+	if !commitApply {
+		fmt.Println(msg)
+		return nil
+	}
+	c := exec.Command("git", "commit", "-F", "-")
+	c.Stdin = strings.NewReader(msg + "\n")
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// gitOutput runs git with args in the current directory and returns its
+// stdout, folding stderr into the error.
+func gitOutput(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("git", args...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// commitPrompt asks for a Conventional Commits message and nothing else.
+func commitPrompt(stat, diff, hint string) string {
+	var b strings.Builder
+	b.WriteString("Write a git commit message for the staged changes below, following Conventional Commits.\n\n")
+	b.WriteString("- First line: type(optional scope): summary, at most 72 characters, imperative mood, no trailing period. ")
+	b.WriteString("Use one of feat, fix, docs, style, refactor, perf, test, build, ci, chore, revert.\n")
+	b.WriteString("- Then a blank line and a short body wrapped at 72 columns saying what changed and why, ")
+	b.WriteString("unless the change is trivial.\n")
+	b.WriteString("- Reply with the commit message only: no preamble, no code fences.\n")
+	if hint != "" {
+		fmt.Fprintf(&b, "\nThe author's note on the change: %s\n", hint)
+	}
+	fmt.Fprintf(&b, "\nFiles changed:\n%s\nDiff:\n%s", stat, diff)
+	return b.String()
+}
+
+// cleanCommitMessage strips a code fence wrapped around the message, which
+// providers add despite being asked not to.
+func cleanCommitMessage(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "```") {
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			s = s[i+1:]
+		}
+		s = strings.TrimSuffix(strings.TrimSpace(s), "```")
+	}
+	return strings.TrimSpace(s)
+}
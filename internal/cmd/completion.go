@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// completionTimeout bounds the network lookup behind conversation ID
+// completion so a slow provider doesn't hang the shell.
+const completionTimeout = 5 * time.Second
+
+// registerCompletions wires dynamic completion into every provider
+// command: -m/--model completes model IDs, --conversation and
+// conversation-ID arguments complete recent conversations. It runs before
+// Execute so it sees the commands each provider file registered.
+func registerCompletions() {
+	for _, name := range providerNames {
+		root, _, err := rootCmd.Find([]string{name})
+		if err != nil || root == rootCmd {
+			continue
+		}
+		cmds := append([]*cobra.Command{root}, root.Commands()...)
+		for _, c := range cmds {
+			if c.Flags().Lookup("model") != nil {
+				_ = c.RegisterFlagCompletionFunc("model", completeModels(name))
+			}
+			if c.Flags().Lookup("conversation") != nil {
+				_ = c.RegisterFlagCompletionFunc("conversation", completeConversations(name))
+			}
+			if c.ValidArgsFunction == nil && strings.Contains(c.Use, "<conversation-id") {
+				complete := completeConversations(name)
+				c.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+					if len(args) > 0 {
+						return nil, cobra.ShellCompDirectiveNoFileComp
+					}
+					return complete(cmd, args, toComplete)
+				}
+			}
+		}
+	}
+
+	_ = askAllCmd.RegisterFlagCompletionFunc("conversation", completeAskAllConversations)
+}
+
+// completionConfig loads the config when completion runs, since the
+// hidden __complete command skips the root PersistentPreRun.
+func completionConfig() {
+	if globalCfg == nil {
+		globalCfg = config.Load()
+		applyTimeouts()
+	}
+}
+
+func completeModels(name string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completionConfig()
+		p, _, err := newProviderByName(name)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		ml, ok := p.(provider.ModelLister)
+		if !ok {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var out []string
+		for _, m := range ml.ListModels().Models {
+			if strings.HasPrefix(m.ID, toComplete) {
+				out = append(out, m.ID+"\t"+m.Name)
+			}
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeConversations offers the last conversation from state first,
+// then aliases, then the provider's recent conversations.
+func completeConversations(name string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completionConfig()
+
+		var out []string
+		seen := make(map[string]bool)
+		add := func(id, title string) {
+			if id == "" || seen[id] || !strings.HasPrefix(id, toComplete) {
+				return
+			}
+			seen[id] = true
+			if title != "" {
+				id += "\t" + title
+			}
+			out = append(out, id)
+		}
+
+		state := config.LoadState()
+		if cs := state.GetConversation(name); cs != nil {
+			add(cs.ConversationID, cs.Title)
+		}
+		aliases := make([]string, 0, len(state.Aliases[name]))
+		for alias := range state.Aliases[name] {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+		for _, alias := range aliases {
+			add(alias, "alias for "+state.Aliases[name][alias])
+		}
+
+		p, _, err := newProviderByName(name)
+		if err != nil {
+			return out, cobra.ShellCompDirectiveNoFileComp
+		}
+		lister, ok := p.(provider.Lister)
+		if !ok {
+			return out, cobra.ShellCompDirectiveNoFileComp
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+		autoLoadCookies(ctx, p)
+		convs, err := lister.ListConversations(ctx, provider.ListOptions{Limit: 20})
+		if err == nil {
+			for _, c := range convs {
+				add(c.ID, c.Title)
+			}
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
+}
+
+func completeAskAllConversations(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	state := config.LoadState()
+	ids := make([]string, 0, len(state.AskAll))
+	for id := range state.AskAll {
+		if strings.HasPrefix(id, toComplete) {
+			ids = append(ids, id)
+		}
+	}
+	// Newest first.
+	sort.Slice(ids, func(i, j int) bool {
+		return state.AskAll[ids[i]].CreatedAt.After(state.AskAll[ids[j]].CreatedAt)
+	})
+
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id
+		if q := state.AskAll[id].Question; q != "" {
+			out[i] += "\t" + q
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -22,17 +21,9 @@ var configShowCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		masked := *globalCfg
-		masked.Perplexity.CfClearance = maskSecret(masked.Perplexity.CfClearance)
-		masked.Perplexity.SessionCookie = maskSecret(masked.Perplexity.SessionCookie)
-		masked.ChatGPT.SessionToken = maskSecret(masked.ChatGPT.SessionToken)
-		masked.ChatGPT.CfClearance = maskSecret(masked.ChatGPT.CfClearance)
-		masked.ChatGPT.PUID = maskSecret(masked.ChatGPT.PUID)
-		masked.Gemini.PSID = maskSecret(masked.Gemini.PSID)
-		masked.Gemini.PSIDTS = maskSecret(masked.Gemini.PSIDTS)
-		masked.Gemini.PSIDCC = maskSecret(masked.Gemini.PSIDCC)
-		masked.Grok.AuthToken = maskSecret(masked.Grok.AuthToken)
-		masked.Grok.CT0 = maskSecret(masked.Grok.CT0)
-		masked.Claude.SessionKey = maskSecret(masked.Claude.SessionKey)
+		for _, field := range masked.SecretFields() {
+			*field = maskSecret(*field)
+		}
 
 		out, err := json.MarshalIndent(masked, "", "  ")
 		if err != nil {
@@ -44,70 +35,81 @@ var configShowCmd = &cobra.Command{
 	},
 }
 
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a config value",
+	Long: `Print a single config value, unmasked, for use in scripts.
+Lists print comma-separated. Run "ask config keys" for the key names.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, err := lookupConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), k.get(globalCfg))
+		return nil
+	},
+}
+
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set a config value",
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		key := strings.ToLower(args[0])
+		k, err := lookupConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+		if err := k.set(globalCfg, args[1]); err != nil {
+			return err
+		}
+
+		if err := cfgpkg.Save(globalCfg); err != nil {
+			return err
+		}
+
 		value := args[1]
+		if k.secret {
+			value = maskSecret(value)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "set %s=%s\n", k.name, value)
+		return nil
+	},
+}
 
-		switch key {
-		case "chatgpt.model":
-			globalCfg.ChatGPT.Model = value
-		case "chatgpt.effort":
-			globalCfg.ChatGPT.Effort = value
-		case "claude.model":
-			globalCfg.Claude.Model = value
-		case "claude.effort":
-			globalCfg.Claude.Effort = value
-		case "perplexity.model":
-			globalCfg.Perplexity.Model = value
-		case "perplexity.mode":
-			globalCfg.Perplexity.Mode = value
-		case "perplexity.focus":
-			globalCfg.Perplexity.SearchFocus = value
-		case "gemini.model":
-			globalCfg.Gemini.Model = value
-		case "grok.model":
-			globalCfg.Grok.Model = value
-		case "grok.deepsearch":
-			parsed, err := strconv.ParseBool(value)
-			if err != nil {
-				return fmt.Errorf("invalid bool for %s: %q", key, value)
-			}
-			globalCfg.Grok.DeepSearch = parsed
-		case "grok.reasoning":
-			parsed, err := strconv.ParseBool(value)
-			if err != nil {
-				return fmt.Errorf("invalid bool for %s: %q", key, value)
-			}
-			globalCfg.Grok.Reasoning = parsed
-		case "timeout":
-			parsed, err := strconv.Atoi(value)
-			if err != nil {
-				return fmt.Errorf("invalid int for %s: %q", key, value)
-			}
-			globalCfg.Timeout = parsed
-		case "verbose":
-			parsed, err := strconv.ParseBool(value)
-			if err != nil {
-				return fmt.Errorf("invalid bool for %s: %q", key, value)
-			}
-			globalCfg.Verbose = parsed
-		default:
-			return fmt.Errorf("unsupported config key: %s", key)
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Reset a config value to its default",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		k, err := lookupConfigKey(args[0])
+		if err != nil {
+			return err
 		}
+		k.unset(globalCfg)
 
 		if err := cfgpkg.Save(globalCfg); err != nil {
 			return err
 		}
 
-		fmt.Fprintf(cmd.OutOrStdout(), "set %s=%s\n", key, value)
+		fmt.Fprintf(cmd.OutOrStdout(), "unset %s\n", k.name)
 		return nil
 	},
 }
 
+var configKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "List config keys",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		out := cmd.OutOrStdout()
+		for _, k := range configKeys {
+			fmt.Fprintf(out, "%-34s %s\n", k.name, k.help)
+		}
+		fmt.Fprintf(out, "%-34s %s\n", redactPatternPrefix+"<name>", "custom redaction regex")
+	},
+}
+
 var configPathCmd = &cobra.Command{
 	Use:   "path",
 	Short: "Print config file path",
@@ -117,13 +119,62 @@ var configPathCmd = &cobra.Command{
 	},
 }
 
+var configMigrateSecretsCmd = &cobra.Command{
+	Use:   "migrate-secrets [keychain|file]",
+	Short: "Move session tokens and cookies to the OS keychain (or back)",
+	Long: `Move every session token and cookie out of the plaintext config file
+into the OS credential store (Keychain on macOS, the Secret Service on
+Linux, Credential Manager on Windows), and keep them there on later saves.
+
+Run "ask config migrate-secrets file" to move them back into the file.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{cfgpkg.SecretStoreKeychain, cfgpkg.SecretStoreFile},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cfgpkg.SecretStoreKeychain
+		if len(args) == 1 {
+			store = strings.ToLower(args[0])
+		}
+
+		// Save from a fresh load so command-line overrides aren't persisted.
+		cfg := cfgpkg.Load()
+		if err := cfgpkg.MigrateSecrets(cfg, store); err != nil {
+			if len(cfg.StoredSecrets) == 0 {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+		}
+
+		if store == cfgpkg.SecretStoreKeychain {
+			fmt.Fprintf(cmd.OutOrStdout(), "%d secret(s) stored in the OS keychain\n", len(cfg.StoredSecrets))
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "secrets stored in %s\n", cfgpkg.FilePath())
+		}
+		return nil
+	},
+}
+
 func init() {
 	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configKeysCmd)
 	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configMigrateSecretsCmd)
 	rootCmd.AddCommand(configCmd)
 }
 
+// splitList parses a comma-separated config value.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func maskSecret(v string) string {
 	if v == "" {
 		return ""
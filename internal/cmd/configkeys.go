@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cfgpkg "github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/cookies"
+	"github.com/kyupark/ask/internal/provider/claude"
+	"github.com/kyupark/ask/internal/provider/grok"
+)
+
+// configKey is one settable config value. unset restores the zero value,
+// which Load turns back into the default where there is one.
+type configKey struct {
+	name   string
+	help   string
+	secret bool
+	get    func(c *cfgpkg.Config) string
+	set    func(c *cfgpkg.Config, v string) error
+	unset  func(c *cfgpkg.Config)
+}
+
+func stringKey(name, help string, field func(c *cfgpkg.Config) *string) configKey {
+	return configKey{
+		name:  name,
+		help:  help,
+		get:   func(c *cfgpkg.Config) string { return *field(c) },
+		set:   func(c *cfgpkg.Config, v string) error { *field(c) = v; return nil },
+		unset: func(c *cfgpkg.Config) { *field(c) = "" },
+	}
+}
+
+func intKey(name, help string, field func(c *cfgpkg.Config) *int) configKey {
+	return configKey{
+		name: name,
+		help: help,
+		get:  func(c *cfgpkg.Config) string { return strconv.Itoa(*field(c)) },
+		set: func(c *cfgpkg.Config, v string) error {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 0 {
+				return fmt.Errorf("invalid int for %s: %q", name, v)
+			}
+			*field(c) = parsed
+			return nil
+		},
+		unset: func(c *cfgpkg.Config) { *field(c) = 0 },
+	}
+}
+
+func boolKey(name, help string, field func(c *cfgpkg.Config) *bool) configKey {
+	return configKey{
+		name: name,
+		help: help,
+		get:  func(c *cfgpkg.Config) string { return strconv.FormatBool(*field(c)) },
+		set: func(c *cfgpkg.Config, v string) error {
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid bool for %s: %q", name, v)
+			}
+			*field(c) = parsed
+			return nil
+		},
+		unset: func(c *cfgpkg.Config) { *field(c) = false },
+	}
+}
+
+func listKey(name, help string, field func(c *cfgpkg.Config) *[]string) configKey {
+	return configKey{
+		name:  name,
+		help:  help,
+		get:   func(c *cfgpkg.Config) string { return strings.Join(*field(c), ",") },
+		set:   func(c *cfgpkg.Config, v string) error { *field(c) = splitList(v); return nil },
+		unset: func(c *cfgpkg.Config) { *field(c) = nil },
+	}
+}
+
+// validated wraps k's setter with a check on the raw value.
+func validated(k configKey, check func(v string) error) configKey {
+	set := k.set
+	k.set = func(c *cfgpkg.Config, v string) error {
+		if err := check(v); err != nil {
+			return err
+		}
+		return set(c, v)
+	}
+	return k
+}
+
+// configKeys lists every key accepted by `ask config get/set/unset`, in
+// the order `ask config keys` prints them. Session secrets are appended
+// from Config.SecretFields.
+var configKeys = func() []configKey {
+	keys := []configKey{
+		stringKey("user_agent", "User-Agent sent to every provider", func(c *cfgpkg.Config) *string { return &c.UserAgent }),
+		intKey("timeout", "overall limit per question in seconds (0 for none)", func(c *cfgpkg.Config) *int { return &c.Timeout }),
+		intKey("timeouts.connect", "seconds to connect and finish the TLS handshake", func(c *cfgpkg.Config) *int { return &c.Timeouts.Connect }),
+		intKey("timeouts.response_header", "seconds to wait for a response to start", func(c *cfgpkg.Config) *int { return &c.Timeouts.ResponseHeader }),
+		intKey("timeouts.stream_idle", "seconds a streaming answer may stall", func(c *cfgpkg.Config) *int { return &c.Timeouts.StreamIdle }),
+		intKey("timeouts.ask_all", "seconds each provider gets in ask all before it is left out", func(c *cfgpkg.Config) *int { return &c.Timeouts.AskAll }),
+		boolKey("verbose", "log requests to stderr", func(c *cfgpkg.Config) *bool { return &c.Verbose }),
+		stringKey("system_prompt", "instructions sent with every question", func(c *cfgpkg.Config) *string { return &c.SystemPrompt }),
+		stringKey("edit_template", "file the --edit buffer starts from", func(c *cfgpkg.Config) *string { return &c.EditTemplate }),
+		validated(
+			listKey("browsers", "cookie search order, comma-separated", func(c *cfgpkg.Config) *[]string { return &c.Browsers }),
+			func(v string) error {
+				for _, b := range splitList(v) {
+					if !cookies.ValidBrowser(b) {
+						return fmt.Errorf("unsupported browser %q (use %s)", b, strings.Join(cookies.DefaultBrowsers, ", "))
+					}
+				}
+				return nil
+			}),
+		validated(
+			stringKey("default_provider", "provider for bare ask \"question\"", func(c *cfgpkg.Config) *string { return &c.DefaultProvider }),
+			func(v string) error { return validateProviderList([]string{v}) }),
+		validated(
+			listKey("failover", "provider order for ask failover, comma-separated", func(c *cfgpkg.Config) *[]string { return &c.Failover }),
+			func(v string) error { return validateProviderList(splitList(v)) }),
+		validated(
+			stringKey("proxy", "proxy URL for every provider (http, https, socks5)", func(c *cfgpkg.Config) *string { return &c.Proxy }),
+			validateProxy),
+		boolKey("resume_per_directory", "make --resume continue the conversation last used in this directory", func(c *cfgpkg.Config) *bool { return &c.ResumePerDirectory }),
+		intKey("max_prompt_tokens", "refuse prompts estimated above this many tokens (0 for each model's context size)", func(c *cfgpkg.Config) *int { return &c.MaxPromptTokens }),
+		boolKey("history.disabled", "stop recording questions and answers", func(c *cfgpkg.Config) *bool { return &c.History.Disabled }),
+		boolKey("usage.disabled", "stop recording usage (provider, model, sizes, durations)", func(c *cfgpkg.Config) *bool { return &c.Usage.Disabled }),
+		stringKey("hooks.webhook", "URL that receives every answer as a JSON POST", func(c *cfgpkg.Config) *string { return &c.Hooks.Webhook }),
+		stringKey("hooks.command", "shell command run with every answer as JSON on stdin", func(c *cfgpkg.Config) *string { return &c.Hooks.Command }),
+		boolKey("hooks.on_error", "run the hooks for failed asks too", func(c *cfgpkg.Config) *bool { return &c.Hooks.OnError }),
+		stringKey("hooks.pre_send", "shell command that gets every prompt as JSON and may print it back changed", func(c *cfgpkg.Config) *string { return &c.Hooks.PreSend }),
+		stringKey("hooks.post_receive", "shell command that gets every answer as JSON and may print it back changed", func(c *cfgpkg.Config) *string { return &c.Hooks.PostReceive }),
+		boolKey("audit.enabled", "append every prompt sent, with where and when, to the audit log", func(c *cfgpkg.Config) *bool { return &c.Audit.Enabled }),
+		stringKey("audit.path", "audit log file (default audit.jsonl in the data directory)", func(c *cfgpkg.Config) *string { return &c.Audit.Path }),
+		boolKey("redact.enabled", "redact prompts before sending", func(c *cfgpkg.Config) *bool { return &c.Redact.Enabled }),
+		listKey("redact.rules", "built-in redaction rules to apply, comma-separated", func(c *cfgpkg.Config) *[]string { return &c.Redact.Rules }),
+
+		stringKey("anthropic_api.model", "default Anthropic API model", func(c *cfgpkg.Config) *string { return &c.AnthropicAPI.Model }),
+		stringKey("anthropic_api.system_prompt", "Anthropic API instructions (overrides system_prompt)", func(c *cfgpkg.Config) *string { return &c.AnthropicAPI.SystemPrompt }),
+		intKey("anthropic_api.max_tokens", "Anthropic API answer length cap in tokens (0 for 8192)", func(c *cfgpkg.Config) *int { return &c.AnthropicAPI.MaxTokens }),
+		validated(
+			intKey("anthropic_api.thinking_budget", "Anthropic API thinking budget in tokens (0 for no thinking)", func(c *cfgpkg.Config) *int { return &c.AnthropicAPI.ThinkingBudget }),
+			validateThinkingBudget,
+		),
+		stringKey("anthropic_api.base_url", "Anthropic API base URL", func(c *cfgpkg.Config) *string { return &c.AnthropicAPI.BaseURL }),
+		validated(
+			stringKey("anthropic_api.proxy", "Anthropic API proxy URL (overrides proxy)", func(c *cfgpkg.Config) *string { return &c.AnthropicAPI.Proxy }),
+			validateProxy),
+
+		stringKey("chatgpt.model", "default ChatGPT model", func(c *cfgpkg.Config) *string { return &c.ChatGPT.Model }),
+		stringKey("chatgpt.system_prompt", "ChatGPT instructions (overrides system_prompt)", func(c *cfgpkg.Config) *string { return &c.ChatGPT.SystemPrompt }),
+		stringKey("chatgpt.effort", "default ChatGPT reasoning effort", func(c *cfgpkg.Config) *string { return &c.ChatGPT.Effort }),
+		stringKey("chatgpt.base_url", "ChatGPT base URL", func(c *cfgpkg.Config) *string { return &c.ChatGPT.BaseURL }),
+		validated(
+			stringKey("chatgpt.proxy", "ChatGPT proxy URL (overrides proxy)", func(c *cfgpkg.Config) *string { return &c.ChatGPT.Proxy }),
+			validateProxy),
+		validated(
+			stringKey("chatgpt.timezone", "IANA timezone reported to ChatGPT", func(c *cfgpkg.Config) *string { return &c.ChatGPT.Timezone }),
+			func(v string) error {
+				if _, err := time.LoadLocation(v); err != nil {
+					return fmt.Errorf("invalid timezone for chatgpt.timezone: %q", v)
+				}
+				return nil
+			}),
+		stringKey("chatgpt.locale", "BCP 47 locale reported to ChatGPT", func(c *cfgpkg.Config) *string { return &c.ChatGPT.Locale }),
+		stringKey("chatgpt.account", "ChatGPT workspace account ID (see ask chatgpt accounts)", func(c *cfgpkg.Config) *string { return &c.ChatGPT.Account }),
+		intKey("chatgpt.fingerprint.screen_width", "reported screen width", func(c *cfgpkg.Config) *int { return &c.ChatGPT.Fingerprint.ScreenWidth }),
+		intKey("chatgpt.fingerprint.screen_height", "reported screen height", func(c *cfgpkg.Config) *int { return &c.ChatGPT.Fingerprint.ScreenHeight }),
+		intKey("chatgpt.fingerprint.page_width", "reported page width", func(c *cfgpkg.Config) *int { return &c.ChatGPT.Fingerprint.PageWidth }),
+		intKey("chatgpt.fingerprint.page_height", "reported page height", func(c *cfgpkg.Config) *int { return &c.ChatGPT.Fingerprint.PageHeight }),
+		intKey("chatgpt.fingerprint.pixel_ratio", "reported device pixel ratio", func(c *cfgpkg.Config) *int { return &c.ChatGPT.Fingerprint.PixelRatio }),
+		intKey("chatgpt.fingerprint.cores", "reported CPU core count", func(c *cfgpkg.Config) *int { return &c.ChatGPT.Fingerprint.Cores }),
+		stringKey("chatgpt.fingerprint.script_src", "reported page script URL", func(c *cfgpkg.Config) *string { return &c.ChatGPT.Fingerprint.ScriptSrc }),
+		stringKey("chatgpt.fingerprint.dpl", "reported deployment ID", func(c *cfgpkg.Config) *string { return &c.ChatGPT.Fingerprint.DPL }),
+
+		stringKey("claude.model", "default Claude model", func(c *cfgpkg.Config) *string { return &c.Claude.Model }),
+		stringKey("claude.system_prompt", "Claude instructions (overrides system_prompt)", func(c *cfgpkg.Config) *string { return &c.Claude.SystemPrompt }),
+		stringKey("claude.effort", "default Claude reasoning effort", func(c *cfgpkg.Config) *string { return &c.Claude.Effort }),
+		validated(
+			intKey("claude.thinking_budget", "Claude thinking budget in tokens (0 for the claude.ai default)", func(c *cfgpkg.Config) *int { return &c.Claude.ThinkingBudget }),
+			validateThinkingBudget,
+		),
+		stringKey("claude.base_url", "Claude base URL", func(c *cfgpkg.Config) *string { return &c.Claude.BaseURL }),
+		boolKey("claude.api_fallback", "ask the Anthropic API when claude.ai fails", func(c *cfgpkg.Config) *bool { return &c.Claude.APIFallback }),
+		validated(
+			stringKey("claude.proxy", "Claude proxy URL (overrides proxy)", func(c *cfgpkg.Config) *string { return &c.Claude.Proxy }),
+			validateProxy),
+
+		stringKey("deepseek.model", "default DeepSeek model", func(c *cfgpkg.Config) *string { return &c.DeepSeek.Model }),
+		stringKey("deepseek.system_prompt", "DeepSeek instructions (overrides system_prompt)", func(c *cfgpkg.Config) *string { return &c.DeepSeek.SystemPrompt }),
+		boolKey("deepseek.search", "use DeepSeek web search by default", func(c *cfgpkg.Config) *bool { return &c.DeepSeek.Search }),
+		stringKey("deepseek.base_url", "DeepSeek base URL", func(c *cfgpkg.Config) *string { return &c.DeepSeek.BaseURL }),
+		validated(
+			stringKey("deepseek.proxy", "DeepSeek proxy URL (overrides proxy)", func(c *cfgpkg.Config) *string { return &c.DeepSeek.Proxy }),
+			validateProxy),
+
+		stringKey("gemini.model", "default Gemini model", func(c *cfgpkg.Config) *string { return &c.Gemini.Model }),
+		stringKey("gemini.system_prompt", "Gemini instructions (overrides system_prompt)", func(c *cfgpkg.Config) *string { return &c.Gemini.SystemPrompt }),
+		validated(
+			stringKey("gemini.proxy", "Gemini proxy URL (overrides proxy)", func(c *cfgpkg.Config) *string { return &c.Gemini.Proxy }),
+			validateProxy),
+
+		stringKey("grok.model", "default Grok model", func(c *cfgpkg.Config) *string { return &c.Grok.Model }),
+		stringKey("grok.system_prompt", "Grok instructions (overrides system_prompt)", func(c *cfgpkg.Config) *string { return &c.Grok.SystemPrompt }),
+		boolKey("grok.deepsearch", "use DeepSearch by default", func(c *cfgpkg.Config) *bool { return &c.Grok.DeepSearch }),
+		boolKey("grok.reasoning", "use reasoning by default", func(c *cfgpkg.Config) *bool { return &c.Grok.Reasoning }),
+		validated(
+			stringKey("grok.persona", "Grok persona ("+strings.Join(grok.PersonaNames, ", ")+")", func(c *cfgpkg.Config) *string { return &c.Grok.Persona }),
+			func(v string) error { _, err := grok.ResolvePersona(v); return err }),
+		validated(
+			stringKey("grok.proxy", "Grok proxy URL (overrides proxy)", func(c *cfgpkg.Config) *string { return &c.Grok.Proxy }),
+			validateProxy),
+
+		stringKey("lechat.model", "default Le Chat model", func(c *cfgpkg.Config) *string { return &c.LeChat.Model }),
+		stringKey("lechat.system_prompt", "Le Chat instructions (overrides system_prompt)", func(c *cfgpkg.Config) *string { return &c.LeChat.SystemPrompt }),
+		stringKey("lechat.base_url", "Le Chat base URL", func(c *cfgpkg.Config) *string { return &c.LeChat.BaseURL }),
+		validated(
+			stringKey("lechat.proxy", "Le Chat proxy URL (overrides proxy)", func(c *cfgpkg.Config) *string { return &c.LeChat.Proxy }),
+			validateProxy),
+
+		stringKey("ollama.model", "default Ollama model (also includes Ollama in ask all)", func(c *cfgpkg.Config) *string { return &c.Ollama.Model }),
+		stringKey("ollama.system_prompt", "Ollama instructions (overrides system_prompt)", func(c *cfgpkg.Config) *string { return &c.Ollama.SystemPrompt }),
+		stringKey("ollama.base_url", "Ollama server URL (default OLLAMA_HOST or http://localhost:11434)", func(c *cfgpkg.Config) *string { return &c.Ollama.BaseURL }),
+		validated(
+			stringKey("ollama.proxy", "Ollama proxy URL (overrides proxy)", func(c *cfgpkg.Config) *string { return &c.Ollama.Proxy }),
+			validateProxy),
+
+		stringKey("perplexity.model", "default Perplexity model", func(c *cfgpkg.Config) *string { return &c.Perplexity.Model }),
+		stringKey("perplexity.system_prompt", "Perplexity instructions (overrides system_prompt)", func(c *cfgpkg.Config) *string { return &c.Perplexity.SystemPrompt }),
+		stringKey("perplexity.mode", "default Perplexity mode", func(c *cfgpkg.Config) *string { return &c.Perplexity.Mode }),
+		stringKey("perplexity.focus", "default Perplexity search focus", func(c *cfgpkg.Config) *string { return &c.Perplexity.SearchFocus }),
+		stringKey("perplexity.base_url", "Perplexity base URL", func(c *cfgpkg.Config) *string { return &c.Perplexity.BaseURL }),
+		validated(
+			stringKey("perplexity.proxy", "Perplexity proxy URL (overrides proxy)", func(c *cfgpkg.Config) *string { return &c.Perplexity.Proxy }),
+			validateProxy),
+	}
+
+	var secrets []string
+	for name := range (&cfgpkg.Config{}).SecretFields() {
+		secrets = append(secrets, name)
+	}
+	sort.Strings(secrets)
+	for _, name := range secrets {
+		help := "session cookie"
+		if strings.HasSuffix(name, ".api_key") {
+			help = "API key"
+		}
+		k := stringKey(name, help, func(c *cfgpkg.Config) *string { return c.SecretFields()[name] })
+		k.secret = true
+		keys = append(keys, k)
+	}
+	return keys
+}()
+
+// validateProxy accepts the proxy URL schemes httpclient can dial.
+func validateProxy(v string) error {
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid proxy URL: %q", v)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return nil
+	}
+	return fmt.Errorf("unsupported proxy scheme %q (use http, https, socks5 or socks5h)", u.Scheme)
+}
+
+// validateThinkingBudget rejects budgets below Claude's minimum; 0 clears.
+func validateThinkingBudget(v string) error {
+	n, err := strconv.Atoi(v)
+	if err == nil && n != 0 && n < claude.MinThinkingBudget {
+		return fmt.Errorf("thinking budget must be 0 or at least %d tokens", claude.MinThinkingBudget)
+	}
+	return nil
+}
+
+// redactPatternPrefix introduces the per-name custom redaction keys.
+const redactPatternPrefix = "redact.pattern."
+
+// lookupConfigKey resolves a key name, including redact.pattern.<name>.
+func lookupConfigKey(name string) (configKey, error) {
+	name = strings.ToLower(name)
+	for _, k := range configKeys {
+		if k.name == name {
+			return k, nil
+		}
+	}
+
+	if pattern, ok := strings.CutPrefix(name, redactPatternPrefix); ok && pattern != "" {
+		return configKey{
+			name: name,
+			get:  func(c *cfgpkg.Config) string { return c.Redact.Patterns[pattern] },
+			set: func(c *cfgpkg.Config, v string) error {
+				if _, err := regexp.Compile(v); err != nil {
+					return fmt.Errorf("invalid regex for %s: %w", name, err)
+				}
+				if c.Redact.Patterns == nil {
+					c.Redact.Patterns = make(map[string]string)
+				}
+				c.Redact.Patterns[pattern] = v
+				return nil
+			},
+			unset: func(c *cfgpkg.Config) { delete(c.Redact.Patterns, pattern) },
+		}, nil
+	}
+
+	return configKey{}, fmt.Errorf("unsupported config key: %s (see: ask config keys)", name)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var flagAutoContinue bool
+
+// maxContinues caps how many times one answer is continued.
+const maxContinues = 3
+
+// continuePrompt asks for the rest of a cut-off answer.
+const continuePrompt = "Continue exactly where your last message stopped. Do not repeat anything and do not add a preamble."
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagAutoContinue, "auto-continue", false, "Ask the provider to continue answers cut off at its length limit")
+}
+
+// askContinuing is askDirect with --auto-continue: when the answer stops
+// at the provider's length limit, or ends inside an open code fence, it
+// asks for the rest in the same conversation and streams it through the
+// same callbacks, so the parts read as one answer.
+func askContinuing(ctx context.Context, p provider.Provider, query string, opts provider.AskOptions) error {
+	if !flagAutoContinue {
+		return askDirect(ctx, p, query, opts)
+	}
+
+	var (
+		conversationID, parentMessageID, responseID string
+		truncated                                   bool
+		text                                        strings.Builder
+	)
+	onConversation, onText, onTruncated := opts.OnConversation, opts.OnText, opts.OnTruncated
+	opts.OnConversation = func(c, m, r string) {
+		conversationID, parentMessageID, responseID = c, m, r
+		if onConversation != nil {
+			onConversation(c, m, r)
+		}
+	}
+	opts.OnTruncated = func() {
+		truncated = true
+		if onTruncated != nil {
+			onTruncated()
+		}
+	}
+	opts.OnText = func(t string) {
+		text.WriteString(t)
+		if onText != nil {
+			onText(t)
+		}
+	}
+
+	if err := askDirect(ctx, p, query, opts); err != nil {
+		return err
+	}
+	for n := 1; n <= maxContinues; n++ {
+		inFence := openFence(text.String())
+		if !truncated && !inFence {
+			return nil
+		}
+		if conversationID == "" {
+			fmt.Fprintf(os.Stderr, "[%s] answer was cut off; it cannot be continued without a conversation\n", p.Name())
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "[%s] answer was cut off; continuing (%d/%d)\n", p.Name(), n, maxContinues)
+
+		next := opts
+		next.ConversationID = conversationID
+		next.ParentMessageID = parentMessageID
+		next.ResponseID = responseID
+		next.Retry = false
+		next.Attachments = nil
+		var stitch fenceStitcher
+		if inFence {
+			stitch.out = opts.OnText
+			next.OnText = stitch.write
+		}
+		truncated = false
+		err := askDirect(ctx, p, continuePrompt, next)
+		stitch.flush()
+		if err != nil {
+			return fmt.Errorf("continuing: %w", err)
+		}
+	}
+	return nil
+}
+
+// openFence reports whether markdown text ends inside a ``` code block.
+func openFence(text string) bool {
+	open := false
+	for line := range strings.SplitSeq(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			open = !open
+		}
+	}
+	return open
+}
+
+// fenceStitcher drops the fence opener a model tends to repeat when it
+// continues an answer that was cut off inside a code block, which would
+// otherwise close the block instead.
+type fenceStitcher struct {
+	out  func(string)
+	head strings.Builder
+	done bool
+}
+
+func (s *fenceStitcher) write(t string) {
+	if s.done {
+		s.out(t)
+		return
+	}
+	s.head.WriteString(t)
+	head := s.head.String()
+	trimmed := strings.TrimLeft(head, " \t\r\n")
+	if trimmed == "" {
+		return
+	}
+	if !strings.HasPrefix(trimmed, "```") && !strings.HasPrefix("```", trimmed) {
+		s.flush()
+		return
+	}
+	i := strings.IndexByte(trimmed, '\n')
+	if i < 0 {
+		return
+	}
+	s.done = true
+	if rest := trimmed[i+1:]; rest != "" {
+		s.out(rest)
+	}
+}
+
+func (s *fenceStitcher) flush() {
+	if s.done || s.out == nil {
+		return
+	}
+	s.done = true
+	if head := s.head.String(); head != "" {
+		s.out(head)
+	}
+}
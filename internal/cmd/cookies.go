@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cfgpkg "github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/cookies"
+	"github.com/kyupark/ask/internal/provider/lechat"
+)
+
+var cookiesImportProvider string
+
+var cookiesCmd = &cobra.Command{
+	Use:   "cookies",
+	Short: "Manage provider cookies",
+}
+
+var cookiesImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import cookies from a cookies.txt or JSON export",
+	Long: `Import provider cookies from a file instead of a local browser, for
+headless servers or machines without a supported browser.
+
+Accepts Netscape cookies.txt (as written by curl or "Get cookies.txt"
+extensions) or a JSON array of {domain, name, value} objects (as written
+by EditThisCookie and similar). Matching cookies are stored in the config
+file under each provider.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCookiesImport,
+}
+
+func init() {
+	cookiesImportCmd.Flags().StringVarP(&cookiesImportProvider, "provider", "p", "", "Only import cookies for this provider")
+	cookiesCmd.AddCommand(cookiesImportCmd)
+	rootCmd.AddCommand(cookiesCmd)
+}
+
+func runCookiesImport(cmd *cobra.Command, args []string) error {
+	list, err := cookies.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	names := providerNames
+	if cookiesImportProvider != "" {
+		if !slices.Contains(providerNames, cookiesImportProvider) {
+			return fmt.Errorf("unknown provider %q", cookiesImportProvider)
+		}
+		names = []string{cookiesImportProvider}
+	}
+
+	imported := 0
+	for _, name := range names {
+		p, _, err := newProviderByName(name)
+		if err != nil {
+			return err
+		}
+		found := make(map[string]string)
+		for _, spec := range p.CookieSpecs() {
+			for k, v := range cookies.Match(list, cookies.Spec{Domain: spec.Domain, Names: spec.Names}) {
+				if found[k] == "" {
+					found[k] = v
+				}
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		stored := storeProviderCookies(name, found)
+		sort.Strings(stored)
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", name, strings.Join(stored, ", "))
+		imported += len(stored)
+	}
+
+	if imported == 0 {
+		return fmt.Errorf("no provider cookies found in %s", args[0])
+	}
+	return cfgpkg.Save(globalCfg)
+}
+
+// providerCookieFields maps each cookie a provider uses to the config
+// field that stores it.
+func providerCookieFields(providerName string) map[string]*string {
+	switch providerName {
+	case "chatgpt":
+		return map[string]*string{
+			"__Secure-next-auth.session-token": &globalCfg.ChatGPT.SessionToken,
+			"cf_clearance":                     &globalCfg.ChatGPT.CfClearance,
+			"_puid":                            &globalCfg.ChatGPT.PUID,
+		}
+	case "claude":
+		return map[string]*string{
+			"sessionKey": &globalCfg.Claude.SessionKey,
+		}
+	case "deepseek":
+		return map[string]*string{
+			"ds_session_id": &globalCfg.DeepSeek.SessionID,
+		}
+	case "gemini":
+		return map[string]*string{
+			"__Secure-1PSID":   &globalCfg.Gemini.PSID,
+			"__Secure-1PSIDTS": &globalCfg.Gemini.PSIDTS,
+			"__Secure-1PSIDCC": &globalCfg.Gemini.PSIDCC,
+		}
+	case "grok":
+		return map[string]*string{
+			"auth_token": &globalCfg.Grok.AuthToken,
+			"ct0":        &globalCfg.Grok.CT0,
+		}
+	case "lechat":
+		return map[string]*string{
+			lechat.SessionCookieName: &globalCfg.LeChat.SessionCookie,
+		}
+	case "perplexity":
+		return map[string]*string{
+			"cf_clearance":                     &globalCfg.Perplexity.CfClearance,
+			"__Secure-next-auth.session-token": &globalCfg.Perplexity.SessionCookie,
+		}
+	}
+	return nil
+}
+
+// storeProviderCookies copies known cookie values into the provider's
+// config section and returns the names that were stored.
+func storeProviderCookies(providerName string, values map[string]string) []string {
+	fields := providerCookieFields(providerName)
+	var stored []string
+	for name, value := range values {
+		if field, ok := fields[name]; ok {
+			*field = value
+			stored = append(stored, name)
+		}
+	}
+	return stored
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// daemonPingTimeout bounds the check for a running daemon, which every
+// question pays when none is running.
+const daemonPingTimeout = 200 * time.Millisecond
+
+// providerSetupFlags change how a provider is set up for one question in
+// ways the daemon's shared provider can't follow; with any of them set the
+// question is asked directly.
+var providerSetupFlags = []string{
+	"account", "deep-research", "deepsearch", "effort", "focus", "gpt", "media",
+	"mode", "no-search", "persona", "reasoning", "search", "thinking-budget",
+}
+
+// currentCmd is the command being run, noted before it runs so asks can
+// tell which flags were given.
+var currentCmd *cobra.Command
+
+var (
+	daemonOnce sync.Once
+	daemonUp   bool
+)
+
+// errDaemonUnreachable means the question never reached the daemon and
+// can be asked directly instead.
+var errDaemonUnreachable = errors.New("daemon unreachable")
+
+type daemonClient struct {
+	http *http.Client
+}
+
+func newDaemonClient() *daemonClient {
+	path := daemonSocketPath()
+	return &daemonClient{http: &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}}
+}
+
+func (c *daemonClient) getJSON(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://ask"+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("daemon: HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *daemonClient) post(ctx context.Context, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://ask"+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// daemonRunning reports, once per process, whether a daemon answers on
+// the socket.
+func daemonRunning() bool {
+	daemonOnce.Do(func() {
+		if inDaemon {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), daemonPingTimeout)
+		defer cancel()
+		daemonUp = newDaemonClient().getJSON(ctx, "/status", &daemonStatus{}) == nil
+	})
+	return daemonUp
+}
+
+// daemonCanAnswer reports whether the running daemon can answer for p as
+// it is set up for this question.
+func daemonCanAnswer(p provider.Provider) bool {
+	if !slices.Contains(providerNames, p.Name()) || !daemonRunning() {
+		return false
+	}
+	if currentCmd != nil {
+		for _, name := range providerSetupFlags {
+			if f := currentCmd.Flags().Lookup(name); f != nil && f.Changed {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// loadAskCookies is autoLoadCookies for a provider about to be asked a
+// question. When the daemon will answer, its provider has cookies and the
+// browser scan is skipped.
+func loadAskCookies(ctx context.Context, p provider.Provider) {
+	if daemonCanAnswer(p) {
+		return
+	}
+	autoLoadCookies(ctx, p)
+}
+
+// askDirect asks the daemon when it can answer, and p otherwise. The
+// system prompt and attachments are redacted first.
+func askDirect(ctx context.Context, p provider.Provider, query string, opts provider.AskOptions) error {
+	cleanup, err := redactOptions(&opts)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	if err := auditPrompt(p.Name(), query, opts); err != nil {
+		return err
+	}
+	if daemonCanAnswer(p) {
+		err := newDaemonClient().ask(ctx, p.Name(), query, opts)
+		if !errors.Is(err, errDaemonUnreachable) {
+			return err
+		}
+		// The daemon went away: ask directly, with the cookies skipped
+		// earlier.
+		autoLoadCookies(ctx, p)
+	}
+	return p.Ask(ctx, query, opts)
+}
+
+// ask sends a question to the daemon and replays its answer through
+// opts' callbacks as if the provider had been asked here.
+func (c *daemonClient) ask(ctx context.Context, name, query string, opts provider.AskOptions) error {
+	req := daemonAskRequest{
+		Provider:        name,
+		Query:           query,
+		Model:           opts.Model,
+		Temporary:       opts.Temporary,
+		ConversationID:  opts.ConversationID,
+		ParentMessageID: opts.ParentMessageID,
+		ResponseID:      opts.ResponseID,
+		Retry:           opts.Retry,
+		SystemPrompt:    opts.SystemPrompt,
+		Verbose:         opts.LogFunc != nil,
+	}
+	// The daemon runs elsewhere; relative paths mean nothing there.
+	for _, a := range opts.Attachments {
+		abs, err := filepath.Abs(a)
+		if err != nil {
+			return err
+		}
+		req.Attachments = append(req.Attachments, abs)
+	}
+	if opts.ImageDir != "" {
+		abs, err := filepath.Abs(opts.ImageDir)
+		if err != nil {
+			return err
+		}
+		req.ImageDir = abs
+	}
+	if opts.DownloadDir != "" {
+		abs, err := filepath.Abs(opts.DownloadDir)
+		if err != nil {
+			return err
+		}
+		req.DownloadDir = abs
+	}
+	for name, set := range map[string]bool{
+		"conversation": opts.OnConversation != nil,
+		"thinking":     opts.OnThinking != nil,
+		"source":       opts.OnSource != nil,
+		"progress":     opts.OnProgress != nil,
+		"followups":    opts.OnFollowUps != nil,
+		"image":        opts.OnImage != nil,
+		"file":         opts.OnFile != nil,
+		"media":        opts.OnMedia != nil,
+		"truncated":    opts.OnTruncated != nil,
+	} {
+		if set {
+			req.Callbacks = append(req.Callbacks, name)
+		}
+	}
+
+	body, _ := json.Marshal(req)
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://ask/ask", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(hreq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: %v", errDaemonUnreachable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("daemon: %s", apiErr.Error.Message)
+	}
+	if opts.LogFunc != nil {
+		opts.LogFunc("[daemon] answering through %s", daemonSocketPath())
+	}
+
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var ev daemonEvent
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			return fmt.Errorf("daemon: %w", err)
+		}
+		switch ev.Type {
+		case "text":
+			if opts.OnText != nil {
+				opts.OnText(ev.Text)
+			}
+		case "log":
+			if opts.LogFunc != nil {
+				opts.LogFunc("%s", ev.Text)
+			}
+		case "conversation":
+			if opts.OnConversation != nil {
+				opts.OnConversation(ev.ConversationID, ev.ParentMessageID, ev.ResponseID)
+			}
+		case "thinking":
+			if opts.OnThinking != nil {
+				opts.OnThinking(ev.Text)
+			}
+		case "source":
+			if opts.OnSource != nil {
+				opts.OnSource(ev.Name, ev.URL)
+			}
+		case "progress":
+			if opts.OnProgress != nil {
+				opts.OnProgress(ev.Text)
+			}
+		case "followups":
+			if opts.OnFollowUps != nil {
+				opts.OnFollowUps(ev.FollowUps)
+			}
+		case "image":
+			if opts.OnImage != nil && ev.Image != nil {
+				opts.OnImage(*ev.Image)
+			}
+		case "file":
+			if opts.OnFile != nil && ev.File != nil {
+				opts.OnFile(*ev.File)
+			}
+		case "media":
+			if opts.OnMedia != nil && ev.Media != nil {
+				opts.OnMedia(*ev.Media)
+			}
+		case "truncated":
+			if opts.OnTruncated != nil {
+				opts.OnTruncated()
+			}
+		case "error":
+			return errors.New(ev.Error)
+		case "done":
+			if opts.OnDone != nil {
+				opts.OnDone()
+			}
+			return nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("daemon: %w", err)
+	}
+	return fmt.Errorf("daemon: answer ended early")
+}
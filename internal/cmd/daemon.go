@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+const (
+	// daemonRewarmEvery is how often the daemon refreshes cookies and
+	// tokens while idle.
+	daemonRewarmEvery = 10 * time.Minute
+)
+
+// inDaemon is set while this process is the daemon, so its own asks go
+// straight to the providers.
+var inDaemon bool
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep providers warm so questions start instantly",
+	Long: `Run in the foreground, holding every provider ask all uses with its
+cookies loaded and, where the provider allows, its tokens fetched ahead
+of time. While it runs, questions from other ask invocations are sent to
+it over a local socket and skip the browser cookie scan and sign-in.
+
+Start it in the background or from a login item:
+
+  ask daemon &
+
+Questions whose flags change how a provider is set up (such as --effort
+or --focus) are still asked directly. Restart the daemon after changing
+the config.
+
+Subcommands:
+  status  Show whether the daemon is running and what it holds
+  stop    Stop the daemon`,
+	Args: cobra.NoArgs,
+	RunE: runDaemon,
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the daemon is running and what it holds",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := newDaemonClient()
+		var st daemonStatus
+		if err := c.getJSON(cmd.Context(), "/status", &st); err != nil {
+			fmt.Println("The daemon is not running.")
+			return nil
+		}
+		fmt.Printf("Running (pid %d) since %s, %d questions answered\n", st.PID, formatTime(st.Started), st.Served)
+		fmt.Printf("Socket: %s\n", daemonSocketPath())
+		for _, b := range st.Providers {
+			warm := "cookies loaded"
+			if !b.WarmedAt.IsZero() {
+				warm = "warmed " + formatTime(b.WarmedAt)
+			}
+			if b.WarmError != "" {
+				warm = "warm failed: " + b.WarmError
+			}
+			fmt.Printf("  %-14s %s\n", b.Name, warm)
+		}
+		return nil
+	},
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the daemon",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := newDaemonClient().post(cmd.Context(), "/stop"); err != nil {
+			return fmt.Errorf("the daemon is not running")
+		}
+		fmt.Println("Daemon stopped.")
+		return nil
+	},
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonStatusCmd, daemonStopCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
+
+// daemonSocketPath is the daemon's Unix socket: in $XDG_RUNTIME_DIR when
+// set, which is private and cleared on logout, else in the data dir.
+func daemonSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "ask.sock")
+	}
+	return filepath.Join(config.DataDir(), "daemon.sock")
+}
+
+// daemonAskRequest is a question sent to the daemon: the provider, the
+// options that travel, and which callbacks the client has set.
+type daemonAskRequest struct {
+	Provider        string   `json:"provider"`
+	Query           string   `json:"query"`
+	Model           string   `json:"model,omitempty"`
+	Temporary       bool     `json:"temporary,omitempty"`
+	ConversationID  string   `json:"conversation_id,omitempty"`
+	ParentMessageID string   `json:"parent_message_id,omitempty"`
+	ResponseID      string   `json:"response_id,omitempty"`
+	Retry           bool     `json:"retry,omitempty"`
+	Attachments     []string `json:"attachments,omitempty"`
+	SystemPrompt    string   `json:"system_prompt,omitempty"`
+	ImageDir        string   `json:"image_dir,omitempty"`
+	DownloadDir     string   `json:"download_dir,omitempty"`
+	Verbose         bool     `json:"verbose,omitempty"`
+	Callbacks       []string `json:"callbacks,omitempty"`
+}
+
+// daemonEvent is one line of the daemon's answer stream.
+type daemonEvent struct {
+	Type            string                   `json:"type"`
+	Text            string                   `json:"text,omitempty"`
+	Name            string                   `json:"name,omitempty"`
+	URL             string                   `json:"url,omitempty"`
+	ConversationID  string                   `json:"conversation_id,omitempty"`
+	ParentMessageID string                   `json:"parent_message_id,omitempty"`
+	ResponseID      string                   `json:"response_id,omitempty"`
+	FollowUps       []string                 `json:"follow_ups,omitempty"`
+	Image           *provider.GeneratedImage `json:"image,omitempty"`
+	File            *provider.GeneratedFile  `json:"file,omitempty"`
+	Media           *provider.MediaResult    `json:"media,omitempty"`
+	Error           string                   `json:"error,omitempty"`
+}
+
+type daemonStatus struct {
+	PID       int                    `json:"pid"`
+	Started   time.Time              `json:"started"`
+	Served    int64                  `json:"served"`
+	Providers []daemonProviderStatus `json:"providers"`
+}
+
+type daemonProviderStatus struct {
+	Name      string    `json:"name"`
+	WarmedAt  time.Time `json:"warmed_at,omitzero"`
+	WarmError string    `json:"warm_error,omitempty"`
+}
+
+type daemonServer struct {
+	serveState
+	started time.Time
+	served  atomic.Int64
+
+	warmMu sync.Mutex
+	warmed map[string]daemonProviderStatus
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	inDaemon = true
+	path := daemonSocketPath()
+	if err := newDaemonClient().getJSON(cmd.Context(), "/status", &daemonStatus{}); err == nil {
+		return fmt.Errorf("the daemon is already running (socket %s)", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	_ = os.Remove(path) // left behind by a daemon that didn't exit cleanly
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return err
+	}
+
+	d := &daemonServer{
+		serveState: serveState{backends: make(map[string]*serveBackend)},
+		started:    time.Now(),
+		warmed:     make(map[string]daemonProviderStatus),
+	}
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /ask", d.handleAsk)
+	mux.HandleFunc("GET /status", d.handleStatus)
+	mux.HandleFunc("POST /stop", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+		cancel()
+	})
+	srv := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	fmt.Fprintf(os.Stderr, "[daemon] listening on %s\n", path)
+	go d.warmLoop(ctx)
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "[daemon] stopped")
+	return nil
+}
+
+// warmLoop loads every ask all provider at start, then refreshes cookies
+// and tokens every daemonRewarmEvery.
+func (d *daemonServer) warmLoop(ctx context.Context) {
+	var names []string
+	for _, e := range askAllEntries() {
+		names = append(names, e.p.Name())
+	}
+	for round := 0; ; round++ {
+		var wg sync.WaitGroup
+		for _, name := range names {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				// The first round creates the backends, which loads cookies.
+				b, err := d.backend(ctx, name)
+				if err != nil {
+					slog.Debug("daemon: provider unavailable", "provider", name, "err", err)
+					return
+				}
+				if round > 0 {
+					b.mu.Lock()
+					autoLoadCookies(ctx, b.p)
+					b.mu.Unlock()
+				}
+				d.warm(ctx, name, b)
+			}(name)
+		}
+		wg.Wait()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(daemonRewarmEvery):
+		}
+	}
+}
+
+// warm prepares a provider's next question, if it knows how.
+func (d *daemonServer) warm(ctx context.Context, name string, b *serveBackend) {
+	w, ok := b.p.(provider.Warmer)
+	if !ok {
+		d.warmMu.Lock()
+		d.warmed[name] = daemonProviderStatus{Name: name}
+		d.warmMu.Unlock()
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var logf func(string, ...any)
+	if globalCfg.Verbose {
+		logf = debugLogf(name)
+	}
+	err := w.Warm(ctx, logf)
+	if err != nil {
+		slog.Debug("daemon: warming failed", "provider", name, "err", err)
+	}
+	d.setWarmed(name, err)
+}
+
+func (d *daemonServer) setWarmed(name string, err error) {
+	d.warmMu.Lock()
+	defer d.warmMu.Unlock()
+	st := daemonProviderStatus{Name: name, WarmedAt: time.Now()}
+	if err != nil {
+		st = daemonProviderStatus{Name: name, WarmError: err.Error()}
+	}
+	d.warmed[name] = st
+}
+
+func (d *daemonServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	st := daemonStatus{PID: os.Getpid(), Started: d.started, Served: d.served.Load()}
+	d.warmMu.Lock()
+	for _, p := range d.warmed {
+		st.Providers = append(st.Providers, p)
+	}
+	d.warmMu.Unlock()
+	sort.Slice(st.Providers, func(i, j int) bool { return st.Providers[i].Name < st.Providers[j].Name })
+	writeJSON(w, http.StatusOK, st)
+}
+
+func (d *daemonServer) handleAsk(w http.ResponseWriter, r *http.Request) {
+	var req daemonAskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if err := validateProviderList([]string{req.Provider}); err != nil {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	b, err := d.backend(r.Context(), req.Provider)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	d.served.Add(1)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	var mu sync.Mutex
+	send := func(ev daemonEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		data, _ := json.Marshal(ev)
+		w.Write(append(data, '\n'))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	opts := provider.AskOptions{
+		Model:           req.Model,
+		Verbose:         req.Verbose,
+		Temporary:       req.Temporary,
+		ConversationID:  req.ConversationID,
+		ParentMessageID: req.ParentMessageID,
+		ResponseID:      req.ResponseID,
+		Retry:           req.Retry,
+		Attachments:     req.Attachments,
+		SystemPrompt:    req.SystemPrompt,
+		ImageDir:        req.ImageDir,
+		DownloadDir:     req.DownloadDir,
+		OnText:          func(text string) { send(daemonEvent{Type: "text", Text: text}) },
+		OnError:         func(err error) { slog.Debug("stream error", "provider", req.Provider, "err", err) },
+	}
+	if opts.Model == "" {
+		opts.Model = b.model
+	}
+	if req.Verbose {
+		opts.LogFunc = func(format string, args ...any) {
+			send(daemonEvent{Type: "log", Text: fmt.Sprintf(format, args...)})
+		}
+	}
+	for _, c := range req.Callbacks {
+		switch c {
+		case "conversation":
+			opts.OnConversation = func(conversationID, parentMessageID, responseID string) {
+				send(daemonEvent{Type: "conversation", ConversationID: conversationID, ParentMessageID: parentMessageID, ResponseID: responseID})
+			}
+		case "thinking":
+			opts.OnThinking = func(text string) { send(daemonEvent{Type: "thinking", Text: text}) }
+		case "source":
+			opts.OnSource = func(name, url string) { send(daemonEvent{Type: "source", Name: name, URL: url}) }
+		case "progress":
+			opts.OnProgress = func(status string) { send(daemonEvent{Type: "progress", Text: status}) }
+		case "followups":
+			opts.OnFollowUps = func(s []string) { send(daemonEvent{Type: "followups", FollowUps: s}) }
+		case "image":
+			opts.OnImage = func(img provider.GeneratedImage) { send(daemonEvent{Type: "image", Image: &img}) }
+		case "file":
+			opts.OnFile = func(f provider.GeneratedFile) { send(daemonEvent{Type: "file", File: &f}) }
+		case "media":
+			opts.OnMedia = func(m provider.MediaResult) { send(daemonEvent{Type: "media", Media: &m}) }
+		case "truncated":
+			opts.OnTruncated = func() { send(daemonEvent{Type: "truncated"}) }
+		}
+	}
+
+	// The client recorded the prompt in its audit log before sending it here.
+	b.mu.Lock()
+	err = b.p.Ask(r.Context(), req.Query, opts)
+	b.mu.Unlock()
+	if err != nil {
+		send(daemonEvent{Type: "error", Error: err.Error()})
+	} else {
+		send(daemonEvent{Type: "done"})
+	}
+	// Get the next question's tokens while nobody is waiting.
+	go d.warm(context.WithoutCancel(r.Context()), req.Provider, b)
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+	deepseekpkg "github.com/kyupark/ask/internal/provider/deepseek"
+)
+
+var (
+	deepseekModel        string
+	deepseekSearch       bool
+	deepseekResume       bool
+	deepseekConversation string
+)
+
+var deepseekCmd = &cobra.Command{
+	Use:   "deepseek [question]",
+	Short: "DeepSeek commands",
+	Long: `Interact with DeepSeek (chat.deepseek.com) using browser cookies.
+  <question>      Ask a question (saves to history)
+  ask-incognito  Ask a question (no history)
+  list           List recent conversations
+  delete         Delete a conversation by ID
+  models         Show available models
+  alias          Name a conversation for use with -c
+Model aliases: chat, v3, reasoner, r1, think`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !flagEdit {
+			return cmd.Help()
+		}
+		return runDeepSeekAsk(cmd, args, false)
+	},
+}
+
+var deepseekAskIncognitoCmd = &cobra.Command{
+	Use:   "ask-incognito [question]",
+	Short: "Ask DeepSeek (no history)",
+	Args:  questionArgs,
+	RunE:  func(cmd *cobra.Command, args []string) error { return runDeepSeekAsk(cmd, args, true) },
+}
+
+var deepseekListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent DeepSeek conversations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runList(cmd.Context(), newDeepSeekProvider(), listOptions())
+	},
+}
+
+var deepseekDeleteCmd = &cobra.Command{
+	Use:   "delete <conversation-id>",
+	Short: "Delete a DeepSeek conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDelete(cmd.Context(), newDeepSeekProvider(), args[0])
+	},
+}
+
+var deepseekModelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Show available DeepSeek models",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p := deepseekpkg.New("", "", providerTimeout())
+		return runModels(p)
+	},
+}
+
+func init() {
+	deepseekCmd.Flags().StringVarP(&deepseekModel, "model", "m", "", "Model (e.g. 'chat', 'r1')")
+	deepseekCmd.Flags().BoolVar(&deepseekSearch, "search", false, "Let DeepSeek search the web")
+	deepseekCmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print R1's reasoning before the answer")
+	deepseekCmd.Flags().BoolVarP(&deepseekResume, "resume", "r", false, "Resume last conversation")
+	deepseekCmd.Flags().StringVarP(&deepseekConversation, "conversation", "c", "", "Continue a specific conversation by ID or alias")
+	deepseekAskIncognitoCmd.Flags().StringVarP(&deepseekModel, "model", "m", "", "Model (e.g. 'chat', 'r1')")
+	deepseekAskIncognitoCmd.Flags().BoolVar(&deepseekSearch, "search", false, "Let DeepSeek search the web")
+	deepseekAskIncognitoCmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print R1's reasoning before the answer")
+	deepseekCmd.AddCommand(deepseekAskIncognitoCmd)
+	addListFlags(deepseekListCmd)
+	deepseekCmd.AddCommand(deepseekListCmd)
+	deepseekCmd.AddCommand(deepseekDeleteCmd)
+	deepseekCmd.AddCommand(deepseekModelsCmd)
+	deepseekCmd.AddCommand(newAliasCmd("deepseek"))
+	rootCmd.AddCommand(deepseekCmd)
+}
+
+func runDeepSeekAsk(cmd *cobra.Command, args []string, temporary bool) error {
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
+
+	p := deepseekpkg.New(
+		globalCfg.DeepSeek.BaseURL,
+		globalCfg.UserAgent,
+		providerTimeout(),
+	)
+	p.SetCookies(map[string]string{
+		"ds_session_id": globalCfg.DeepSeek.SessionID,
+	})
+	p.SetUserToken(globalCfg.DeepSeek.UserToken)
+	p.SetProxy(providerProxy("deepseek"))
+
+	loadAskCookies(cmd.Context(), p)
+
+	p.SetWebSearch(deepseekSearch || globalCfg.DeepSeek.Search)
+	model := globalCfg.DeepSeek.Model
+	if deepseekModel != "" {
+		model = deepseekModel
+	}
+
+	opts := provider.AskOptions{
+		Model:     deepseekpkg.ResolveModel(model),
+		Verbose:   globalCfg.Verbose,
+		Temporary: temporary,
+		OnText: func(text string) {
+			fmt.Print(text)
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", "deepseek", "err", err)
+		},
+	}
+
+	if !temporary {
+		if deepseekConversation != "" {
+			opts.ConversationID = resolveConversationID("deepseek", deepseekConversation)
+		} else if deepseekResume {
+			state := config.LoadState()
+			if conv := lastConversation(state, "deepseek"); conv != nil {
+				opts.ConversationID = conv.ConversationID
+				opts.ParentMessageID = conv.ParentMessageID
+			} else {
+				fmt.Fprintln(os.Stderr, "No previous conversation found for deepseek — starting new")
+			}
+		}
+	}
+
+	// Save conversation state and capture ID for hint.
+	var lastConvID string
+	if !temporary {
+		opts.OnConversation = func(convID, parentMsgID, respID string) {
+			lastConvID = convID
+			saveConversationState("deepseek", query, &config.ConversationState{
+				ConversationID:  convID,
+				ParentMessageID: parentMsgID,
+			})
+		}
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf("deepseek")
+	}
+
+	applySystemPrompt("deepseek", &opts)
+	applyShowThinking(&opts)
+	applyStreamJSON("deepseek", &opts)
+	rec := recordHistory("deepseek", opts.Model, query, &opts)
+
+	err = askCached(cmd.Context(), p, query, opts)
+	rec.finish(err)
+	if err != nil {
+		if interrupted.Load() {
+			return stopInterrupted("deepseek", lastConvID, temporary)
+		}
+		emitStreamError("deepseek", err)
+		return err
+	}
+
+	finishAnswer("deepseek")
+
+	if lastConvID != "" && !temporary {
+		printConversationHint("deepseek", lastConvID)
+	}
+
+	return nil
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/kyupark/ask/internal/config"
@@ -11,7 +10,7 @@ import (
 )
 
 func runDelete(ctx context.Context, p provider.Provider, conversationID string) error {
-	conversationID = strings.TrimSpace(conversationID)
+	conversationID = resolveConversationID(p.Name(), conversationID)
 	if conversationID == "" {
 		return fmt.Errorf("conversation ID is required")
 	}
@@ -25,9 +24,7 @@ func runDelete(ctx context.Context, p provider.Provider, conversationID string)
 
 	opts := provider.DeleteOptions{Verbose: globalCfg.Verbose}
 	if globalCfg.Verbose {
-		opts.LogFunc = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, "[%s] %s\n", p.Name(), fmt.Sprintf(format, args...))
-		}
+		opts.LogFunc = debugLogf(p.Name())
 	}
 
 	if err := deleter.DeleteConversation(ctx, conversationID, opts); err != nil {
@@ -40,13 +37,31 @@ func runDelete(ctx context.Context, p provider.Provider, conversationID string)
 }
 
 func clearConversationState(providerName, conversationID string) {
-	state := config.LoadState()
-	changed := false
+	_ = config.UpdateState(func(state *config.State) {
+		clearConversation(state, providerName, conversationID)
+	})
+}
+
+// clearConversation removes every reference to a conversation from state.
+func clearConversation(state *config.State, providerName, conversationID string) {
 	deleteAll := providerName == "grok" && strings.EqualFold(strings.TrimSpace(conversationID), "all")
 
+	if deleteAll {
+		delete(state.Aliases, providerName)
+	} else {
+		state.RemoveAliasesTo(providerName, conversationID)
+	}
+
 	if conv := state.GetConversation(providerName); conv != nil && (deleteAll || conv.ConversationID == conversationID) {
 		delete(state.LastConversation, providerName)
-		changed = true
+	}
+	for dir, convs := range state.DirConversations {
+		if conv := convs[providerName]; conv != nil && (deleteAll || conv.ConversationID == conversationID) {
+			delete(convs, providerName)
+			if len(convs) == 0 {
+				delete(state.DirConversations, dir)
+			}
+		}
 	}
 
 	for id, bundle := range state.AskAll {
@@ -59,11 +74,6 @@ func clearConversationState(providerName, conversationID string) {
 			if state.LastAskAllID == id {
 				state.LastAskAllID = ""
 			}
-			changed = true
 		}
 	}
-
-	if changed {
-		_ = config.SaveState(state)
-	}
 }
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/cookies"
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+	"github.com/kyupark/ask/internal/provider/anthropicapi"
+	"github.com/kyupark/ask/internal/provider/lechat"
+)
+
+const doctorTimeout = 20 * time.Second
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [provider...]",
+	Short: "Diagnose cookie, network, and auth problems",
+	Long: `Check each provider (or only those named) for:
+  cookies   session cookies in config and in local browsers
+  tls       a Chrome-fingerprinted TLS handshake to the provider
+  auth      an authenticated request (listing one conversation)
+
+Failed checks print a suggested fix.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// providerSites describes each provider's web endpoints and the cookies
+// that make up a logged-in session.
+var providerSites = map[string]struct {
+	site     string   // homepage, used for the TLS check
+	login    string   // page that starts the web login flow
+	required []string // cookies without which auth cannot work
+}{
+	"anthropic-api": {"https://api.anthropic.com/", "", nil},
+	"chatgpt":       {"https://chatgpt.com/", "https://chatgpt.com/auth/login", []string{"__Secure-next-auth.session-token"}},
+	"claude":        {"https://claude.ai/", "https://claude.ai/login", []string{"sessionKey"}},
+	"deepseek":      {"https://chat.deepseek.com/", "https://chat.deepseek.com/sign_in", []string{"ds_session_id"}},
+	"gemini":        {"https://gemini.google.com/", "https://accounts.google.com/ServiceLogin?continue=https://gemini.google.com/app", []string{"__Secure-1PSID", "__Secure-1PSIDTS"}},
+	"grok":          {"https://x.com/", "https://x.com/i/flow/login", []string{"auth_token", "ct0"}},
+	"lechat":        {"https://chat.mistral.ai/", "https://chat.mistral.ai/chat", []string{lechat.SessionCookieName}},
+	"ollama":        {"http://localhost:11434/", "", nil},
+	"perplexity":    {"https://www.perplexity.ai/", "https://www.perplexity.ai/", []string{"__Secure-next-auth.session-token"}},
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	names := providerNames
+	if len(args) > 0 {
+		for _, a := range args {
+			if !slices.Contains(providerNames, a) {
+				return fmt.Errorf("unknown provider %q", a)
+			}
+		}
+		names = args
+	}
+
+	failed := 0
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("━━━ %s ━━━\n", name)
+		failed += doctorProvider(cmd.Context(), name)
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	fmt.Println("All checks passed.")
+	return nil
+}
+
+// doctorProvider runs every check for one provider and returns the
+// number that failed.
+func doctorProvider(ctx context.Context, name string) int {
+	info := providerSites[name]
+	failed := 0
+	report := func(ok bool, check, detail, fix string) {
+		mark := "ok  "
+		if !ok {
+			mark = "FAIL"
+			failed++
+		}
+		fmt.Printf("  [%s] %-8s %s\n", mark, check, detail)
+		if !ok && fix != "" {
+			fmt.Printf("         fix: %s\n", fix)
+		}
+	}
+
+	p, _, err := newProviderByName(name)
+	if err != nil {
+		report(false, "setup", err.Error(), "")
+		return failed
+	}
+	// Providers with a configurable server are checked at that server.
+	if b, ok := p.(interface{ BaseURL() string }); ok {
+		info.site = b.BaseURL() + "/"
+	}
+
+	// Cookies: config first, then what the browsers would supply.
+	have := make(map[string]string)
+	for cookie, field := range providerCookieFields(name) {
+		if *field != "" {
+			have[cookie] = "config"
+		}
+	}
+
+	var specs []cookies.Spec
+	for _, s := range p.CookieSpecs() {
+		specs = append(specs, cookies.Spec{Domain: s.Domain, Names: s.Names})
+	}
+	var trace []string
+	result, _ := cookies.ExtractMulti(ctx, specs, globalCfg.Browsers, func(format string, args ...any) {
+		trace = append(trace, fmt.Sprintf(format, args...))
+	})
+	for cookie, v := range result.Cookies {
+		if v != "" {
+			have[cookie] = result.Browser
+		}
+	}
+
+	var missing, found []string
+	for _, c := range info.required {
+		if src := have[c]; src != "" {
+			found = append(found, fmt.Sprintf("%s (%s)", c, src))
+		} else {
+			missing = append(missing, c)
+		}
+	}
+	switch {
+	case len(info.required) == 0:
+		fmt.Printf("  [skip] %-8s none needed\n", "cookies")
+	case len(missing) == 0:
+		report(true, "cookies", strings.Join(found, ", "), "")
+	default:
+		browsers := globalCfg.Browsers
+		if len(browsers) == 0 {
+			browsers = cookies.DefaultBrowsers
+		}
+		report(false, "cookies", "missing "+strings.Join(missing, ", "),
+			fmt.Sprintf("log in to %s in one of [%s], or run: ask cookies import <file>",
+				strings.TrimSuffix(strings.TrimPrefix(info.site, "https://"), "/"), strings.Join(browsers, ", ")))
+		if globalCfg.Verbose {
+			for _, line := range trace {
+				fmt.Fprintf(os.Stderr, "         %s\n", strings.TrimSpace(line))
+			}
+		}
+	}
+
+	// TLS: a bare homepage request through the uTLS client.
+	tctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	req, _ := http.NewRequestWithContext(tctx, http.MethodGet, info.site, nil)
+	req.Header.Set("User-Agent", globalCfg.UserAgent)
+	start := time.Now()
+	resp, err := httpclient.NewWithProxy(doctorTimeout, providerProxy(name)).Do(req)
+	cancel()
+	if err != nil {
+		fix := "check network access, proxy settings, and DNS for " + req.URL.Host
+		if name == "ollama" {
+			fix = "start the server with: ollama serve (or set ollama.base_url)"
+		}
+		report(false, "tls", err.Error(), fix)
+	} else {
+		resp.Body.Close()
+		detail := fmt.Sprintf("%s %s %d in %s", req.URL.Host, resp.Proto, resp.StatusCode, time.Since(start).Round(time.Millisecond))
+		if resp.StatusCode == http.StatusForbidden && resp.Header.Get("Cf-Mitigated") != "" {
+			report(false, "tls", detail+" (Cloudflare challenge)", "open "+info.site+" in your browser to refresh cf_clearance")
+		} else {
+			report(true, "tls", detail, "")
+		}
+	}
+
+	// Key: API providers authenticate with a key instead of cookies, and
+	// their conversations are local, so there is no auth call to make.
+	if name == "anthropic-api" {
+		if anthropicAPIKey() != "" {
+			report(true, "key", "API key set", "")
+		} else {
+			report(false, "key", "no API key", "ask config set anthropic_api.api_key <key> (or set "+anthropicapi.APIKeyEnv+")")
+		}
+	}
+	if len(info.required) == 0 {
+		return failed
+	}
+
+	// Auth: the cheapest authenticated call every provider supports.
+	lister, ok := p.(provider.Lister)
+	if !ok {
+		return failed
+	}
+	if len(missing) > 0 {
+		fmt.Printf("  [skip] %-8s no session cookies\n", "auth")
+		return failed
+	}
+	if len(result.Cookies) > 0 {
+		p.SetCookies(result.Cookies)
+	}
+	actx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	_, err = lister.ListConversations(actx, provider.ListOptions{Limit: 1})
+	if err != nil {
+		report(false, "auth", err.Error(), authFix(name, err))
+	} else {
+		report(true, "auth", "listed conversations", "")
+	}
+	return failed
+}
+
+// authFix suggests a remedy for a failed authenticated request.
+func authFix(name string, err error) string {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, provider.ErrAuthExpired), strings.Contains(msg, "missing"):
+		return fmt.Sprintf("session expired or invalid — log in again in your browser, then rerun: ask doctor %s", name)
+	case errors.Is(err, provider.ErrCloudflareBlocked):
+		return fmt.Sprintf("blocked by Cloudflare — open the site in your browser to pass its check, then rerun: ask doctor %s", name)
+	case errors.Is(err, provider.ErrRateLimited):
+		return "rate limited — wait a few minutes and retry"
+	case isTimeout(err), strings.Contains(msg, "deadline exceeded"), strings.Contains(msg, "timeout"):
+		return "request timed out — check network access"
+	}
+	return fmt.Sprintf("rerun with -v for request logs: ask %s list -v", name)
+}
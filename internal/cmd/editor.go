@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// editPrompt opens the user's editor on a buffer holding the edit
+// template followed by the question so far, and returns what was saved.
+func editPrompt(initial string) (string, error) {
+	var template string
+	path := flagEditTemplate
+	if path == "" {
+		path = globalCfg.EditTemplate
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading edit template: %w", err)
+		}
+		template = string(data)
+	}
+	buf := template
+	if initial != "" {
+		if buf != "" && !strings.HasSuffix(buf, "\n") {
+			buf += "\n"
+		}
+		buf += initial + "\n"
+	}
+
+	f, err := os.CreateTemp("", "ask-prompt-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(buf); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if err := runEditor(f.Name()); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	prompt := strings.TrimSpace(string(data))
+	// An untouched template is as good as an empty buffer: nothing was
+	// written, so nothing should be sent.
+	if prompt == "" || (initial == "" && prompt == strings.TrimSpace(template)) {
+		return "", fmt.Errorf("empty prompt, nothing sent")
+	}
+	return prompt, nil
+}
+
+// runEditor runs $VISUAL, then $EDITOR, then vi on path and waits for it
+// to exit. The variable may carry arguments, as in "code --wait".
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(editor)
+		c = exec.Command(fields[0], append(fields[1:], path)...)
+	} else {
+		c = exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	}
+	// The editor draws on stderr so stdout can still be piped.
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q: %w", editor, err)
+	}
+	return nil
+}
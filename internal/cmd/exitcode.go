@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// Exit statuses for the kinds of failure a script may want to handle
+// differently. 2 is left alone, as shells use it for misuse.
+const (
+	exitFailure          = 1
+	exitAuthExpired      = 3
+	exitRateLimited      = 4
+	exitCloudflare       = 5
+	exitModelUnavailable = 6
+	exitUnavailable      = 7
+	exitTimeout          = 8
+	exitInterrupted      = 130
+)
+
+// errorKinds maps each provider error kind to its exit status and a hint
+// on what to do about it.
+var errorKinds = []struct {
+	kind error
+	code int
+	hint string
+}{
+	{provider.ErrAuthExpired, exitAuthExpired, "log in to the provider again in your browser (or run ask login <provider>), then retry; ask doctor checks every session"},
+	{provider.ErrRateLimited, exitRateLimited, "wait a few minutes and retry, or ask another provider"},
+	{provider.ErrCloudflareBlocked, exitCloudflare, "open the site in your browser to pass Cloudflare's check, then retry so the fresh cf_clearance cookie is used"},
+	{provider.ErrModelUnavailable, exitModelUnavailable, "pick another model with -m; ask models lists them"},
+	{provider.ErrUnavailable, exitUnavailable, "the provider is having trouble; retry later or ask another provider"},
+}
+
+// timeoutHint is the hint for requests that ran out of time.
+const timeoutHint = "retry, or allow more time with ask config set timeout (or timeouts.*)"
+
+// isTimeout reports whether err is a deadline or network timeout.
+func isTimeout(err error) bool {
+	var t interface{ Timeout() bool }
+	return errors.As(err, &t) && t.Timeout()
+}
+
+// ExitCode is the process exit status for an error returned by Execute.
+func ExitCode(err error) int {
+	if errors.Is(err, errInterrupted) {
+		return exitInterrupted
+	}
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			return k.code
+		}
+	}
+	if isTimeout(err) {
+		return exitTimeout
+	}
+	return exitFailure
+}
+
+// errorHint returns what to do about err, or "" when there is nothing
+// more specific to say than the error itself.
+func errorHint(err error) string {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			return k.hint
+		}
+	}
+	if isTimeout(err) {
+		return timeoutHint
+	}
+	return ""
+}
+
+// printErrorHint tells, after a failed command's error, how to fix it,
+// naming the provider when the command was one.
+func printErrorHint(err error) {
+	hint := errorHint(err)
+	if hint == "" {
+		return
+	}
+	if name := commandProvider(); name != "" {
+		hint = strings.ReplaceAll(hint, "<provider>", name)
+	}
+	fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+}
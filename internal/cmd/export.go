@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/export"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+)
+
+// newExportCmd builds the export subcommand for a provider.
+func newExportCmd(providerName string) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "export <conversation-id>",
+		Short: fmt.Sprintf("Export a %s conversation transcript to a file", providerName),
+		Long: fmt.Sprintf(`Fetch the full %s transcript and write it as Markdown, JSON, or HTML.
+
+By default the file is written to <provider>-<id>.<format> in the current
+directory; use -o - to write to stdout.`, providerName),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, _, err := newProviderByName(providerName)
+			if err != nil {
+				return err
+			}
+			return runExport(cmd.Context(), p, args[0])
+		},
+	}
+	c.Flags().StringVarP(&exportFormat, "format", "f", "md", "Output format (md, json, html)")
+	c.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file (default <provider>-<id>.<format>, - for stdout)")
+	return c
+}
+
+// fetchTranscript loads a conversation transcript for any provider
+// implementing TranscriptFetcher.
+func fetchTranscript(ctx context.Context, p provider.Provider, conversationID string) (*provider.Transcript, error) {
+	fetcher, ok := p.(provider.TranscriptFetcher)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support fetching transcripts", p.Name())
+	}
+	conversationID = resolveConversationID(p.Name(), conversationID)
+
+	autoLoadCookies(ctx, p)
+
+	opts := provider.TranscriptOptions{Verbose: globalCfg.Verbose}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf(p.Name())
+	}
+
+	t, err := fetcher.FetchTranscript(ctx, conversationID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if t.ID == "" {
+		t.ID = conversationID
+	}
+	return t, nil
+}
+
+func runExport(ctx context.Context, p provider.Provider, conversationID string) error {
+	// Validate the format before hitting the network.
+	if err := export.Write(io.Discard, exportFormat, p.Name(), &provider.Transcript{}); err != nil {
+		return err
+	}
+
+	t, err := fetchTranscript(ctx, p, conversationID)
+	if err != nil {
+		return err
+	}
+
+	if exportOutput == "-" {
+		return export.Write(os.Stdout, exportFormat, p.Name(), t)
+	}
+
+	path := exportOutput
+	if path == "" {
+		id := strings.NewReplacer("/", "_", "\\", "_").Replace(t.ID)
+		path = fmt.Sprintf("%s-%s.%s", p.Name(), id, export.Extension(exportFormat))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export.Write(f, exportFormat, p.Name(), t); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d message(s) to %s\n", len(t.Messages), path)
+	return nil
+}
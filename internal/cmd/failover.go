@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var failoverChain string
+
+var failoverCmd = &cobra.Command{
+	Use:   "failover [question]",
+	Short: "Ask providers in order until one answers",
+	Long: `Ask the first provider in the failover chain; if it fails (expired
+cookies, rate limit, timeout) before answering, ask the next one, and so on.
+The provider that answered is reported on stderr.
+
+The chain comes from --chain or the failover config key:
+  ask config set failover chatgpt,claude,perplexity`,
+	Args: questionArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chain := globalCfg.Failover
+		if failoverChain != "" {
+			chain = splitList(failoverChain)
+		}
+		if len(chain) == 0 {
+			return fmt.Errorf("no failover chain — pass --chain or run: ask config set failover chatgpt,claude")
+		}
+		if err := validateProviderList(chain); err != nil {
+			return err
+		}
+
+		query, send, err := prepareQuery(args)
+		if err != nil || !send {
+			return err
+		}
+		return askWithFailover(cmd.Context(), chain, query)
+	},
+}
+
+func init() {
+	failoverCmd.Flags().StringVar(&failoverChain, "chain", "", "Comma-separated provider order (overrides the failover config)")
+	rootCmd.AddCommand(failoverCmd)
+}
+
+// validateProviderList checks every name is a known provider.
+func validateProviderList(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(providerNames, name) {
+			return fmt.Errorf("unknown provider %q (use %s)", name, strings.Join(providerNames, ", "))
+		}
+	}
+	return nil
+}
+
+// askWithFailover asks each provider in chain until one answers. Once a
+// provider has streamed any text, its error is final: the partial answer
+// is already on screen.
+func askWithFailover(ctx context.Context, chain []string, query string) error {
+	var errs []error
+	for i, name := range chain {
+		p, model, err := newProviderByName(name)
+		if err != nil {
+			return err
+		}
+		autoLoadCookies(ctx, p)
+
+		var convID string
+		answered := false
+		opts := provider.AskOptions{
+			Model:   model,
+			Verbose: globalCfg.Verbose,
+			OnText: func(text string) {
+				fmt.Print(text)
+			},
+			OnConversation: func(conversationID, parentMessageID, responseID string) {
+				convID = conversationID
+				saveConversationState(name, query, &config.ConversationState{
+					ConversationID:  conversationID,
+					ParentMessageID: parentMessageID,
+					ResponseID:      responseID,
+				})
+			},
+		}
+		if globalCfg.Verbose {
+			opts.LogFunc = debugLogf(name)
+		}
+		applySystemPrompt(name, &opts)
+		applyStreamJSON(name, &opts)
+		onText := opts.OnText
+		opts.OnText = func(text string) {
+			answered = true
+			onText(text)
+		}
+		rec := recordHistory(name, model, query, &opts)
+
+		pctx, cancel := withProviderTimeout(ctx)
+		err = askCached(pctx, p, query, opts)
+		cancel()
+		rec.finish(err)
+
+		if err == nil || answered {
+			if err != nil {
+				emitStreamError(name, err)
+				return err
+			}
+			finishAnswer(name)
+			fmt.Fprintf(os.Stderr, "\nAnswered by: %s\n", name)
+			if convID != "" {
+				fmt.Fprintf(os.Stderr, "  ask %s -c %s \"follow up\"\n", name, convID)
+			}
+			return nil
+		}
+
+		emitStreamError(name, err)
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		// The user pressed Ctrl-C: stop rather than move on.
+		if errors.Is(ctx.Err(), context.Canceled) {
+			break
+		}
+		if i+1 < len(chain) {
+			fmt.Fprintf(os.Stderr, "[failover] %s failed: %v\n[failover] trying %s\n", name, err, chain[i+1])
+		}
+	}
+	return fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kyupark/ask/internal/config"
+)
+
+// printFollowUps lists suggested follow-up questions after an answer.
+func printFollowUps(providerName string, suggestions []string) {
+	if len(suggestions) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Follow-ups:")
+	for i, s := range suggestions {
+		fmt.Fprintf(os.Stderr, "  [%d] %s\n", i+1, s)
+	}
+	fmt.Fprintf(os.Stderr, "  ask %s followup <n>\n", providerName)
+}
+
+// followUpQuestion resolves a 1-based pick against the follow-up
+// suggestions saved with the provider's last conversation.
+func followUpQuestion(providerName, arg string) (string, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(arg))
+	if err != nil {
+		return "", fmt.Errorf("invalid follow-up number %q", arg)
+	}
+	conv := lastConversation(config.LoadState(), providerName)
+	if conv == nil || len(conv.FollowUps) == 0 {
+		return "", fmt.Errorf("no follow-up suggestions saved for %s", providerName)
+	}
+	if n < 1 || n > len(conv.FollowUps) {
+		return "", fmt.Errorf("follow-up number must be between 1 and %d", len(conv.FollowUps))
+	}
+	return conv.FollowUps[n-1], nil
+}
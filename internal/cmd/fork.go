@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// Bound to --parent and --pick-parent on providers whose conversations
+// are message trees (ChatGPT, Claude).
+var (
+	forkParent string
+	forkPick   bool
+)
+
+// applyParent branches the conversation at an earlier message when
+// --parent or --pick-parent is set, instead of appending to its tip. With
+// no -c or -r the provider's saved conversation is used.
+func applyParent(ctx context.Context, p provider.Provider, opts *provider.AskOptions) error {
+	if forkParent == "" && !forkPick {
+		return nil
+	}
+	if opts.Temporary {
+		return fmt.Errorf("--parent cannot be used with ask-incognito")
+	}
+	if opts.ConversationID == "" {
+		conv := lastConversation(config.LoadState(), p.Name())
+		if conv == nil || conv.ConversationID == "" {
+			return fmt.Errorf("--parent needs a conversation: use -c <id> or ask a question first")
+		}
+		opts.ConversationID = conv.ConversationID
+	}
+
+	parent := forkParent
+	if forkPick {
+		picked, err := pickParent(ctx, p, opts.ConversationID)
+		if err != nil {
+			return err
+		}
+		parent = picked
+	}
+	opts.ParentMessageID = parent
+	return nil
+}
+
+// pickParent lists the answers in a conversation and asks which one to
+// branch after.
+func pickParent(ctx context.Context, p provider.Provider, conversationID string) (string, error) {
+	t, err := fetchTranscript(ctx, p, conversationID)
+	if err != nil {
+		return "", err
+	}
+
+	var answers []provider.Message
+	question := ""
+	for _, m := range t.Messages {
+		if m.Role == "user" {
+			question = m.Text
+			continue
+		}
+		if m.ID == "" {
+			continue
+		}
+		answers = append(answers, m)
+		fmt.Fprintf(os.Stderr, "  [%d] %s\n", len(answers), sessionTitle(question))
+		fmt.Fprintf(os.Stderr, "      → %s\n", sessionTitle(m.Text))
+	}
+	if len(answers) == 0 {
+		return "", fmt.Errorf("no answers to branch from in conversation %s", conversationID)
+	}
+
+	fmt.Fprintf(os.Stderr, "Branch after answer [1-%d]: ", len(answers))
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading choice: %w", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(answers) {
+		return "", fmt.Errorf("invalid choice %q", strings.TrimSpace(line))
+	}
+	return answers[n-1].ID, nil
+}
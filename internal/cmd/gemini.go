@@ -2,8 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -16,6 +17,7 @@ var (
 	geminiModel        string
 	geminiResume       bool
 	geminiConversation string
+	geminiAttach       []string
 )
 
 var geminiCmd = &cobra.Command{
@@ -26,10 +28,13 @@ var geminiCmd = &cobra.Command{
   ask-incognito  Ask a question (no history)
   list           List recent conversations
 	delete         Delete a conversation by ID
-	models         Show available models`,
+	models         Show available models
+	alias          Name a conversation for use with -c
+	export         Export a transcript (md, json, html)
+	retry          Get a new answer to the last question`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		if len(args) == 0 && !flagEdit {
 			return cmd.Help()
 		}
 		return runGeminiAsk(cmd, args, false)
@@ -39,7 +44,7 @@ var geminiCmd = &cobra.Command{
 var geminiAskIncognitoCmd = &cobra.Command{
 	Use:   "ask-incognito [question]",
 	Short: "Ask Gemini (no history)",
-	Args:  cobra.MinimumNArgs(1),
+	Args:  questionArgs,
 	RunE:  func(cmd *cobra.Command, args []string) error { return runGeminiAsk(cmd, args, true) },
 }
 
@@ -70,123 +75,166 @@ var geminiModelsCmd = &cobra.Command{
 func init() {
 	geminiCmd.Flags().StringVarP(&geminiModel, "model", "m", "", "Model (e.g. 'gemini-3-pro', 'gemini-3-flash', 'gemini-deep-research')")
 	geminiCmd.Flags().BoolVarP(&geminiResume, "resume", "r", false, "Resume last conversation")
-	geminiCmd.Flags().StringVar(&geminiConversation, "conversation", "", "Continue a specific conversation by ID")
+	geminiCmd.Flags().StringVarP(&geminiConversation, "conversation", "c", "", "Continue a specific conversation by ID or alias")
 	geminiAskIncognitoCmd.Flags().StringVarP(&geminiModel, "model", "m", "", "Model (e.g. 'gemini-3-pro', 'gemini-3-flash', 'gemini-deep-research')")
+	geminiCmd.Flags().StringArrayVar(&geminiAttach, "attach", nil, "Attach a local image or file (repeatable)")
+	geminiAskIncognitoCmd.Flags().StringArrayVar(&geminiAttach, "attach", nil, "Attach a local image or file (repeatable)")
+	geminiCmd.Flags().StringVar(&imageOut, "image-out", "", "Save generated images to this directory")
+	geminiAskIncognitoCmd.Flags().StringVar(&imageOut, "image-out", "", "Save generated images to this directory")
+	geminiCmd.Flags().StringVar(&downloadDir, "download", "", "Save generated images to this directory (same as --image-out)")
+	geminiAskIncognitoCmd.Flags().StringVar(&downloadDir, "download", "", "Save generated images to this directory (same as --image-out)")
 	geminiCmd.AddCommand(geminiAskIncognitoCmd)
+	addListFlags(geminiListCmd)
 	geminiCmd.AddCommand(geminiListCmd)
 	geminiCmd.AddCommand(geminiDeleteCmd)
 	geminiCmd.AddCommand(geminiModelsCmd)
+	geminiCmd.AddCommand(newAliasCmd("gemini"))
+	geminiCmd.AddCommand(newExportCmd("gemini"))
+	geminiCmd.AddCommand(newRetryCmd("gemini", &geminiResume, runGeminiAsk))
 	rootCmd.AddCommand(geminiCmd)
 }
 
 func runGeminiAsk(cmd *cobra.Command, args []string, temporary bool) error {
-	query := strings.Join(args, " ")
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
 
 	model := geminiModel
 	if model == "" {
 		model = globalCfg.Gemini.Model
 	}
 
-	p := geminipkg.New(
-		globalCfg.UserAgent,
-		providerTimeout(),
-	)
+	p := newGeminiProvider()
 
-	p.SetCookies(map[string]string{
-		"__Secure-1PSID":   globalCfg.Gemini.PSID,
-		"__Secure-1PSIDTS": globalCfg.Gemini.PSIDTS,
-		"__Secure-1PSIDCC": globalCfg.Gemini.PSIDCC,
-	})
+	loadAskCookies(cmd.Context(), p)
 
-	autoLoadCookies(cmd.Context(), p)
+	var sources sourceList
 
 	opts := provider.AskOptions{
-		Model:     model,
-		Verbose:   globalCfg.Verbose,
-		Temporary: temporary,
+		Model:       model,
+		Verbose:     globalCfg.Verbose,
+		Temporary:   temporary,
+		Attachments: geminiAttach,
+		ImageDir:    imageOut,
 		OnText: func(text string) {
 			fmt.Print(text)
 		},
+		OnSource: func(name, url string) {
+			sources.add(name, url)
+		},
 		OnError: func(err error) {
-			if globalCfg.Verbose {
-				fmt.Fprintf(os.Stderr, "[gemini] error: %v\n", err)
-			}
+			slog.Debug("stream error", "provider", "gemini", "err", err)
 		},
 	}
 
 	if !temporary {
 		if geminiConversation != "" {
-			opts.ConversationID = geminiConversation
+			opts.ConversationID = resolveConversationID("gemini", geminiConversation)
+			// Reuse the saved reply IDs when this is the last conversation;
+			// otherwise the provider looks them up.
+			if conv := lastConversation(config.LoadState(), "gemini"); conv != nil && conv.ConversationID == opts.ConversationID {
+				opts.ResponseID = conv.ResponseID
+				opts.ParentMessageID = conv.ParentMessageID
+			}
 		} else if geminiResume {
 			state := config.LoadState()
-			if conv := state.GetConversation("gemini"); conv != nil {
+			if conv := lastConversation(state, "gemini"); conv != nil {
 				opts.ConversationID = conv.ConversationID
 				opts.ResponseID = conv.ResponseID
+				opts.ParentMessageID = conv.ParentMessageID
 			} else {
 				fmt.Fprintln(os.Stderr, "No previous conversation found for gemini — starting new")
 			}
 		}
 	}
+	opts.Retry = retryLast && opts.ConversationID != ""
+
+	applyDownload(&opts)
+	var images []provider.GeneratedImage
+	opts.OnImage = func(img provider.GeneratedImage) {
+		images = append(images, img)
+	}
 
 	// Save conversation state and capture ID for hint.
 	var lastConvID string
 	if !temporary {
 		opts.OnConversation = func(convID, parentMsgID, respID string) {
 			lastConvID = convID
-			state := config.LoadState()
-			state.SetConversation("gemini", &config.ConversationState{
-				ConversationID: convID,
-				ResponseID:     respID,
+			saveConversationState("gemini", query, &config.ConversationState{
+				ConversationID:  convID,
+				ParentMessageID: parentMsgID,
+				ResponseID:      respID,
 			})
-			_ = config.SaveState(state)
 		}
 	}
 	if globalCfg.Verbose {
-		opts.LogFunc = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format+"\n", args...)
-		}
+		opts.LogFunc = debugLogf("gemini")
 	}
 
-	if err := p.Ask(cmd.Context(), query, opts); err != nil {
+	applySystemPrompt("gemini", &opts)
+	applyStreamJSON("gemini", &opts)
+	rec := recordHistory("gemini", opts.Model, query, &opts)
+
+	err = askCached(cmd.Context(), p, query, opts)
+	rec.finish(err)
+	if err != nil {
+		if interrupted.Load() {
+			return stopInterrupted("gemini", lastConvID, temporary)
+		}
+		emitStreamError("gemini", err)
 		return err
 	}
 
-	fmt.Println()
+	finishAnswer("gemini")
+	printSources(sources.list)
+	printImages(images)
 
 	if lastConvID != "" && !temporary {
-		fmt.Fprintf(os.Stderr, "\nConversation: %s\n", lastConvID)
-		fmt.Fprintf(os.Stderr, "  ask gemini -c %s \"follow up\"\n", lastConvID)
+		printConversationHint("gemini", lastConvID)
 	}
 
 	return nil
 }
 
 func runGeminiList(cmd *cobra.Command, args []string) error {
-	p := geminipkg.New(
-		globalCfg.UserAgent,
-		providerTimeout(),
-	)
-
-	p.SetCookies(map[string]string{
-		"__Secure-1PSID":   globalCfg.Gemini.PSID,
-		"__Secure-1PSIDTS": globalCfg.Gemini.PSIDTS,
-		"__Secure-1PSIDCC": globalCfg.Gemini.PSIDCC,
-	})
-
-	return runList(cmd.Context(), p, 20)
+	p := newGeminiProvider()
+	return runList(cmd.Context(), p, listOptions())
 }
 
 func runGeminiDelete(cmd *cobra.Command, args []string) error {
-	p := geminipkg.New(
-		globalCfg.UserAgent,
-		providerTimeout(),
-	)
+	p := newGeminiProvider()
+	return runDelete(cmd.Context(), p, args[0])
+}
 
-	p.SetCookies(map[string]string{
-		"__Secure-1PSID":   globalCfg.Gemini.PSID,
-		"__Secure-1PSIDTS": globalCfg.Gemini.PSIDTS,
-		"__Secure-1PSIDCC": globalCfg.Gemini.PSIDCC,
-	})
+// saveGeminiCookies persists rotated Gemini session cookies so the next run
+// starts from the fresh values. The config is reloaded from disk rather
+// than saving globalCfg, which carries command-line overrides.
+func saveGeminiCookies(cookies map[string]string, rotatedAt time.Time) {
+	cfg := config.Load()
+	changed := false
+	for name, field := range map[string]*string{
+		"__Secure-1PSIDTS": &cfg.Gemini.PSIDTS,
+		"__Secure-1PSIDCC": &cfg.Gemini.PSIDCC,
+	} {
+		if v := cookies[name]; v != "" && v != *field {
+			*field = v
+			changed = true
+		}
+	}
+	// Cookies loaded from a browser belong to a different session than the
+	// config's; the browser keeps those fresh itself.
+	if changed && cookies["__Secure-1PSID"] == cfg.Gemini.PSID {
+		if err := config.Save(cfg); err != nil {
+			slog.Debug("saving rotated cookies failed", "provider", "gemini", "err", err)
+		}
+		globalCfg.Gemini.PSIDTS = cfg.Gemini.PSIDTS
+		globalCfg.Gemini.PSIDCC = cfg.Gemini.PSIDCC
+	}
 
-	return runDelete(cmd.Context(), p, args[0])
+	if !rotatedAt.IsZero() {
+		_ = config.UpdateState(func(state *config.State) {
+			state.GeminiCookiesRotatedAt = rotatedAt
+		})
+	}
 }
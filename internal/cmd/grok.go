@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
 
@@ -16,6 +17,7 @@ var (
 	grokModel        string
 	grokDeepsearch   bool
 	grokReasoning    bool
+	grokPersona      string
 	grokResume       bool
 	grokConversation string
 )
@@ -29,10 +31,14 @@ var grokCmd = &cobra.Command{
   list           List recent conversations
   delete         Delete a conversation by ID
   models         Show available models
+  alias          Name a conversation for use with -c
+  show           Show a conversation transcript
+  export         Export a transcript (md, json, html)
+  retry          Get a new answer to the last question
 Model aliases: auto, fast, expert, thinking, 4.20, 4, 3, 2, mini`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		if len(args) == 0 && !flagEdit {
 			return cmd.Help()
 		}
 		return runGrokAsk(cmd, args, false)
@@ -42,7 +48,7 @@ Model aliases: auto, fast, expert, thinking, 4.20, 4, 3, 2, mini`,
 var grokAskIncognitoCmd = &cobra.Command{
 	Use:   "ask-incognito [question]",
 	Short: "Ask Grok (no local resume state)",
-	Args:  cobra.MinimumNArgs(1),
+	Args:  questionArgs,
 	RunE:  func(cmd *cobra.Command, args []string) error { return runGrokAsk(cmd, args, true) },
 }
 
@@ -55,7 +61,7 @@ var grokListCmd = &cobra.Command{
 
 var grokDeleteCmd = &cobra.Command{
 	Use:   "delete <conversation-id|all>",
-	Short: "Delete Grok conversations (use 'all' for bulk delete)",
+	Short: "Delete a Grok conversation (or 'all' to clear history)",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runGrokDelete,
 }
@@ -75,19 +81,31 @@ func init() {
 	grokCmd.Flags().BoolVar(&grokDeepsearch, "deepsearch", false, "Enable DeepSearch mode")
 	grokCmd.Flags().BoolVar(&grokReasoning, "reasoning", false, "Enable Reasoning mode")
 	grokCmd.Flags().BoolVarP(&grokResume, "resume", "r", false, "Resume last conversation")
-	grokCmd.Flags().StringVar(&grokConversation, "conversation", "", "Continue a specific conversation by ID")
+	grokCmd.Flags().StringVarP(&grokConversation, "conversation", "c", "", "Continue a specific conversation by ID or alias")
 	grokAskIncognitoCmd.Flags().StringVarP(&grokModel, "model", "m", "", "Model override (e.g. 'auto', '4.20', 'fast', 'expert', 'thinking')")
 	grokAskIncognitoCmd.Flags().BoolVar(&grokDeepsearch, "deepsearch", false, "Enable DeepSearch mode")
 	grokAskIncognitoCmd.Flags().BoolVar(&grokReasoning, "reasoning", false, "Enable Reasoning mode")
+	grokCmd.Flags().StringVar(&grokPersona, "persona", "", "Persona to answer as ("+strings.Join(grokpkg.PersonaNames, ", ")+")")
+	grokAskIncognitoCmd.Flags().StringVar(&grokPersona, "persona", "", "Persona to answer as ("+strings.Join(grokpkg.PersonaNames, ", ")+")")
+	grokCmd.Flags().StringVar(&imageOut, "image-out", "", "Save generated images to this directory")
+	grokAskIncognitoCmd.Flags().StringVar(&imageOut, "image-out", "", "Save generated images to this directory")
 	grokCmd.AddCommand(grokAskIncognitoCmd)
+	addListFlags(grokListCmd)
 	grokCmd.AddCommand(grokListCmd)
 	grokCmd.AddCommand(grokDeleteCmd)
 	grokCmd.AddCommand(grokModelsCmd)
+	grokCmd.AddCommand(newAliasCmd("grok"))
+	grokCmd.AddCommand(newShowCmd("grok"))
+	grokCmd.AddCommand(newExportCmd("grok"))
+	grokCmd.AddCommand(newRetryCmd("grok", &grokResume, runGrokAsk))
 	rootCmd.AddCommand(grokCmd)
 }
 
 func runGrokAsk(cmd *cobra.Command, args []string, temporary bool) error {
-	query := strings.Join(args, " ")
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
 
 	p := grokpkg.New(
 		globalCfg.UserAgent,
@@ -98,8 +116,9 @@ func runGrokAsk(cmd *cobra.Command, args []string, temporary bool) error {
 		"auth_token": globalCfg.Grok.AuthToken,
 		"ct0":        globalCfg.Grok.CT0,
 	})
+	p.SetProxy(providerProxy("grok"))
 
-	autoLoadCookies(cmd.Context(), p)
+	loadAskCookies(cmd.Context(), p)
 
 	// Apply mode overrides.
 	if cmd.Flags().Changed("deepsearch") {
@@ -117,22 +136,36 @@ func runGrokAsk(cmd *cobra.Command, args []string, temporary bool) error {
 	} else if globalCfg.Grok.Reasoning {
 		p.SetReasoning(true)
 	}
+	persona := globalCfg.Grok.Persona
+	if grokPersona != "" {
+		persona = grokPersona
+	}
+	systemPromptName, err := grokpkg.ResolvePersona(persona)
+	if err != nil {
+		return err
+	}
+	p.SetPersona(systemPromptName)
+
 	model := globalCfg.Grok.Model
 	if grokModel != "" {
 		model = grokModel
 	}
 
+	var sources sourceList
+
 	opts := provider.AskOptions{
 		Model:     model,
 		Verbose:   globalCfg.Verbose,
 		Temporary: temporary,
+		ImageDir:  imageOut,
 		OnText: func(text string) {
 			fmt.Print(text)
 		},
+		OnSource: func(name, url string) {
+			sources.add(name, url)
+		},
 		OnError: func(err error) {
-			if globalCfg.Verbose {
-				fmt.Fprintf(os.Stderr, "[grok] error: %v\n", err)
-			}
+			slog.Debug("stream error", "provider", "grok", "err", err)
 		},
 	}
 
@@ -142,44 +175,57 @@ func runGrokAsk(cmd *cobra.Command, args []string, temporary bool) error {
 
 	if !temporary {
 		if grokConversation != "" {
-			opts.ConversationID = grokConversation
+			opts.ConversationID = resolveConversationID("grok", grokConversation)
 		} else if grokResume {
 			state := config.LoadState()
-			if conv := state.GetConversation("grok"); conv != nil {
+			if conv := lastConversation(state, "grok"); conv != nil {
 				opts.ConversationID = conv.ConversationID
 			} else {
 				fmt.Fprintln(os.Stderr, "No previous conversation found for grok — starting new")
 			}
 		}
 	}
+	opts.Retry = retryLast && opts.ConversationID != ""
+
+	var images []provider.GeneratedImage
+	opts.OnImage = func(img provider.GeneratedImage) {
+		images = append(images, img)
+	}
 
 	// Save conversation state and capture ID for hint.
 	var lastConvID string
 	if !temporary {
 		opts.OnConversation = func(convID, parentMsgID, respID string) {
 			lastConvID = convID
-			state := config.LoadState()
-			state.SetConversation("grok", &config.ConversationState{
+			saveConversationState("grok", query, &config.ConversationState{
 				ConversationID: convID,
 			})
-			_ = config.SaveState(state)
 		}
 	}
 	if globalCfg.Verbose {
-		opts.LogFunc = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format+"\n", args...)
-		}
+		opts.LogFunc = debugLogf("grok")
 	}
 
-	if err := p.Ask(cmd.Context(), query, opts); err != nil {
+	applySystemPrompt("grok", &opts)
+	applyStreamJSON("grok", &opts)
+	rec := recordHistory("grok", opts.Model, query, &opts)
+
+	err = askCached(cmd.Context(), p, query, opts)
+	rec.finish(err)
+	if err != nil {
+		if interrupted.Load() {
+			return stopInterrupted("grok", lastConvID, temporary)
+		}
+		emitStreamError("grok", err)
 		return err
 	}
 
-	fmt.Println()
+	finishAnswer("grok")
+	printSources(sources.list)
+	printImages(images)
 
 	if lastConvID != "" && !temporary {
-		fmt.Fprintf(os.Stderr, "\nConversation: %s\n", lastConvID)
-		fmt.Fprintf(os.Stderr, "  ask grok -c %s \"follow up\"\n", lastConvID)
+		printConversationHint("grok", lastConvID)
 	}
 
 	return nil
@@ -195,8 +241,9 @@ func runGrokList(cmd *cobra.Command, args []string) error {
 		"auth_token": globalCfg.Grok.AuthToken,
 		"ct0":        globalCfg.Grok.CT0,
 	})
+	p.SetProxy(providerProxy("grok"))
 
-	return runList(cmd.Context(), p, 20)
+	return runList(cmd.Context(), p, listOptions())
 }
 
 func runGrokDelete(cmd *cobra.Command, args []string) error {
@@ -209,6 +256,7 @@ func runGrokDelete(cmd *cobra.Command, args []string) error {
 		"auth_token": globalCfg.Grok.AuthToken,
 		"ct0":        globalCfg.Grok.CT0,
 	})
+	p.SetProxy(providerProxy("grok"))
 
 	return runDelete(cmd.Context(), p, args[0])
 }
@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/history"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var (
+	historyListLimit    int
+	historyListProvider string
+)
+
+var historySearchCmd = &cobra.Command{
+	Use:   "search <term>",
+	Short: "Search recorded exchanges and synced conversation titles",
+	Long: `Search the questions and answers recorded here, and the titles of the
+conversations mirrored by ` + "`ask sync`" + ` from every provider, without going online.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runHistorySearch,
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Browse locally recorded questions and answers",
+	Long: `Every question and answer is recorded in a local SQLite database
+(` + "`ask history path`" + ` prints its location). Incognito asks are not recorded.
+Disable recording with: ask config set history.disabled true`,
+}
+
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent history entries",
+	Args:  cobra.NoArgs,
+	RunE:  runHistoryList,
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a recorded question and answer",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistoryShow,
+}
+
+var historyPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print history database path",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Fprintln(cmd.OutOrStdout(), history.Path())
+	},
+}
+
+func init() {
+	historyListCmd.Flags().IntVarP(&historyListLimit, "limit", "n", 20, "Maximum entries to show")
+	historyListCmd.Flags().StringVarP(&historyListProvider, "provider", "p", "", "Only show entries for this provider")
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historySearchCmd.Flags().IntVarP(&historyListLimit, "limit", "n", 20, "Maximum results of each kind to show")
+	historySearchCmd.Flags().StringVarP(&historyListProvider, "provider", "p", "", "Only search this provider")
+	historyCmd.AddCommand(historySearchCmd)
+	historyCmd.AddCommand(historyPathCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistoryList(cmd *cobra.Command, args []string) error {
+	store, err := history.Open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	entries, err := store.List(history.ListOptions{Limit: historyListLimit, Provider: historyListProvider})
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("No history found.")
+		return nil
+	}
+
+	fmt.Printf("Found %d exchange(s):\n\n", len(entries))
+	for _, e := range entries {
+		fmt.Printf("  #%d [%s] %s\n", e.ID, e.Provider, sessionTitle(e.Question))
+		fmt.Printf("    %s", formatTime(e.CreatedAt))
+		if e.Model != "" {
+			fmt.Printf(" · %s", e.Model)
+		}
+		if e.Error != "" {
+			fmt.Print(" · error")
+		}
+		fmt.Println()
+		fmt.Println()
+	}
+	return nil
+}
+
+func runHistorySearch(cmd *cobra.Command, args []string) error {
+	term := strings.Join(args, " ")
+	store, err := history.Open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	opts := history.SearchOptions{Limit: historyListLimit, Provider: historyListProvider}
+	entries, err := store.Search(term, opts)
+	if err != nil {
+		return err
+	}
+	conversations, err := store.SearchConversations(term, opts)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 && len(conversations) == 0 {
+		fmt.Printf("Nothing matches %q.\n", term)
+		return nil
+	}
+
+	if len(entries) > 0 {
+		fmt.Printf("Found %d exchange(s):\n\n", len(entries))
+		for _, e := range entries {
+			fmt.Printf("  #%d [%s] %s\n", e.ID, e.Provider, sessionTitle(e.Question))
+			fmt.Printf("    %s", formatTime(e.CreatedAt))
+			if e.ConversationID != "" {
+				fmt.Printf(" · %s", e.ConversationID)
+			}
+			fmt.Println()
+			fmt.Println()
+		}
+	}
+	if len(conversations) > 0 {
+		fmt.Printf("Found %d synced conversation(s):\n\n", len(conversations))
+		for _, c := range conversations {
+			fmt.Printf("  [%s] %s\n", c.Provider, c.Title)
+			fmt.Printf("    ID: %s", c.ID)
+			if ts := c.UpdatedAt; !ts.IsZero() {
+				fmt.Printf(" · %s", formatTime(ts))
+			} else if !c.CreatedAt.IsZero() {
+				fmt.Printf(" · %s", formatTime(c.CreatedAt))
+			}
+			fmt.Println()
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+func runHistoryShow(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid history ID %q", args[0])
+	}
+
+	store, err := history.Open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	e, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("#%d [%s]", e.ID, e.Provider)
+	if e.Model != "" {
+		fmt.Printf(" %s", e.Model)
+	}
+	fmt.Printf(" — %s\n", formatTime(e.CreatedAt))
+	if e.ConversationID != "" {
+		fmt.Printf("Conversation: %s\n", e.ConversationID)
+	}
+	fmt.Println()
+	fmt.Printf("Q: %s\n\n", e.Question)
+	fmt.Println(strings.TrimRight(e.Answer, "\n"))
+	if e.Error != "" {
+		fmt.Printf("\n[error] %s\n", e.Error)
+	}
+	if len(e.Sources) > 0 {
+		fmt.Println()
+		fmt.Println("Sources:")
+		for i, src := range e.Sources {
+			fmt.Printf("  [%d] %s\n", i+1, src.Name)
+			fmt.Printf("      %s\n", src.URL)
+		}
+	}
+	return nil
+}
+
+// historyRecorder captures one ask for the history database.
+type historyRecorder struct {
+	entry  history.Entry
+	answer strings.Builder
+	// usageOnly is set when only the ask's usage is recorded: history is
+	// disabled or the ask is incognito.
+	usageOnly bool
+	// hooks is set when the finished ask goes to the configured hooks.
+	hooks bool
+}
+
+// recordHistory wraps opts callbacks to capture the answer, sources, and
+// conversation ID. It returns nil when neither history nor usage is
+// recorded and no hook runs; finish is safe to call on nil.
+func recordHistory(providerName, model, query string, opts *provider.AskOptions) *historyRecorder {
+	usageOnly := globalCfg.History.Disabled || opts.Temporary
+	hooks := hooksConfigured() && !opts.Temporary
+	if usageOnly && globalCfg.Usage.Disabled && !hooks {
+		return nil
+	}
+
+	h := &historyRecorder{usageOnly: usageOnly, hooks: hooks, entry: history.Entry{
+		Provider:       providerName,
+		Model:          model,
+		Question:       query,
+		ConversationID: opts.ConversationID,
+		CreatedAt:      time.Now(),
+	}}
+
+	onText := opts.OnText
+	opts.OnText = func(text string) {
+		h.answer.WriteString(text)
+		if onText != nil {
+			onText(text)
+		}
+	}
+
+	onSource := opts.OnSource
+	opts.OnSource = func(name, url string) {
+		h.entry.Sources = append(h.entry.Sources, history.Source{Name: name, URL: url})
+		if onSource != nil {
+			onSource(name, url)
+		}
+	}
+
+	onConversation := opts.OnConversation
+	opts.OnConversation = func(conversationID, parentMessageID, responseID string) {
+		if conversationID != "" {
+			h.entry.ConversationID = conversationID
+		}
+		if onConversation != nil {
+			onConversation(conversationID, parentMessageID, responseID)
+		}
+	}
+
+	return h
+}
+
+// finish writes the captured exchange.
+func (h *historyRecorder) finish(askErr error) {
+	if h == nil {
+		return
+	}
+	h.entry.Answer = h.answer.String()
+	h.entry.CompletedAt = time.Now()
+	if askErr != nil {
+		h.entry.Error = askErr.Error()
+	}
+
+	addUsage(&h.entry)
+	if !h.usageOnly {
+		addHistoryEntry(&h.entry)
+	}
+	if h.hooks {
+		runHooks(&h.entry)
+	}
+}
+
+// addHistoryEntry writes e to the history database unless recording is
+// disabled. Failures are reported only in verbose mode so history never
+// breaks an ask.
+func addHistoryEntry(e *history.Entry) {
+	if globalCfg.History.Disabled {
+		return
+	}
+	store, err := history.Open()
+	if err == nil {
+		err = store.Add(e)
+		store.Close()
+	}
+	if err != nil {
+		slog.Debug("recording history failed", "err", err)
+	}
+}
+
+// addUsage records the metadata of the ask e describes unless usage
+// tracking is disabled. Like history, failures never break an ask.
+func addUsage(e *history.Entry) {
+	if globalCfg.Usage.Disabled {
+		return
+	}
+	u := history.Usage{
+		Provider:      e.Provider,
+		Model:         e.Model,
+		PromptChars:   utf8.RuneCountInString(e.Question),
+		ResponseChars: utf8.RuneCountInString(e.Answer),
+		Failed:        e.Error != "",
+		CreatedAt:     e.CreatedAt,
+	}
+	if !e.CompletedAt.IsZero() {
+		u.Duration = e.CompletedAt.Sub(e.CreatedAt)
+	}
+	store, err := history.Open()
+	if err == nil {
+		err = store.AddUsage(u)
+		store.Close()
+	}
+	if err != nil {
+		slog.Debug("recording usage failed", "err", err)
+	}
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/kyupark/ask/internal/history"
+	"github.com/kyupark/ask/internal/httpclient"
+)
+
+const (
+	// webhookTimeout bounds the webhook POST, which the ask waits for.
+	webhookTimeout = 10 * time.Second
+	// hookCommandTimeout bounds the hook command.
+	hookCommandTimeout = 30 * time.Second
+)
+
+// hookPayload is what the hooks receive about a finished ask.
+type hookPayload struct {
+	Provider       string           `json:"provider"`
+	Model          string           `json:"model,omitempty"`
+	ConversationID string           `json:"conversation_id,omitempty"`
+	Question       string           `json:"question"`
+	Answer         string           `json:"answer"`
+	Sources        []history.Source `json:"sources,omitempty"`
+	Error          string           `json:"error,omitempty"`
+	StartedAt      time.Time        `json:"started_at"`
+	DurationMs     int64            `json:"duration_ms"`
+}
+
+func hooksConfigured() bool {
+	return globalCfg.Hooks.Webhook != "" || globalCfg.Hooks.Command != ""
+}
+
+// runHooks sends a finished ask to the webhook and the hook command.
+// Failed asks are skipped unless hooks.on_error is set. A failing hook is
+// reported on stderr and never fails the ask.
+func runHooks(e *history.Entry) {
+	if !hooksConfigured() || (e.Error != "" && !globalCfg.Hooks.OnError) {
+		return
+	}
+	payload := hookPayload{
+		Provider:       e.Provider,
+		Model:          e.Model,
+		ConversationID: e.ConversationID,
+		Question:       e.Question,
+		Answer:         e.Answer,
+		Sources:        e.Sources,
+		Error:          e.Error,
+		StartedAt:      e.CreatedAt,
+	}
+	if !e.CompletedAt.IsZero() {
+		payload.DurationMs = e.CompletedAt.Sub(e.CreatedAt).Milliseconds()
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	if url := globalCfg.Hooks.Webhook; url != "" {
+		if err := postWebhook(url, data); err != nil {
+			fmt.Fprintf(os.Stderr, "[hook] webhook: %v\n", err)
+		}
+	}
+	if command := globalCfg.Hooks.Command; command != "" {
+		if err := runHookCommand(command, e, data); err != nil {
+			fmt.Fprintf(os.Stderr, "[hook] command: %v\n", err)
+		}
+	}
+}
+
+func postWebhook(url string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ask/"+Version)
+	resp, err := httpclient.NewWithProxy(0, globalCfg.Proxy).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// runHookCommand runs command in the shell with the payload on stdin and
+// the basics in ASK_* variables for commands that don't read JSON.
+func runHookCommand(command string, e *history.Entry, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookCommandTimeout)
+	defer cancel()
+	c := hookShellCommand(ctx, command)
+	c.Stdin = bytes.NewReader(data)
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(),
+		"ASK_PROVIDER="+e.Provider,
+		"ASK_MODEL="+e.Model,
+		"ASK_CONVERSATION_ID="+e.ConversationID,
+		"ASK_ERROR="+e.Error,
+	)
+	return c.Run()
+}
+
+// hookShellCommand runs a hook command through the platform shell.
+func hookShellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	howProvider string
+	howRun      bool
+)
+
+var howCmd = &cobra.Command{
+	Use:   "how [task]",
+	Short: "Suggest a shell command for a task, and run it if you agree",
+	Long: `Ask for a single shell command that does the task on this OS and shell,
+print it, and offer to run it. --run runs it without asking; when stdin
+is not a terminal the command is only printed.
+
+  ask how "resize all pngs in this folder to 50%"
+  ask how --run "list the 5 largest files under ~/Downloads"`,
+	Args: questionArgs,
+	RunE: runHow,
+}
+
+func init() {
+	howCmd.Flags().StringVarP(&howProvider, "provider", "p", "", "Provider that suggests the command (default: default_provider)")
+	howCmd.Flags().BoolVar(&howRun, "run", false, "Run the command without asking")
+	rootCmd.AddCommand(howCmd)
+}
+
+func runHow(cmd *cobra.Command, args []string) error {
+	name, err := toolProvider(howProvider)
+	if err != nil {
+		return err
+	}
+	task, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
+	osName, shell := detectOS(), detectShell()
+	answer, err := askOnce(cmd.Context(), name, howPrompt(task, osName, shell), nil)
+	if err != nil {
+		return err
+	}
+	command := cleanCommand(answer)
+	if command == "" {
+		return fmt.Errorf("%s suggested no command", name)
+	}
+	fmt.Println(command)
+
+	if !howRun {
+		fi, err := os.Stdin.Stat()
+		if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+			return nil
+		}
+		fmt.Fprint(os.Stderr, "Run it? [y/N] ")
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if reply := strings.ToLower(strings.TrimSpace(line)); reply != "y" && reply != "yes" {
+			return nil
+		}
+	}
+	c := shellCommand(shell, command)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// howPrompt asks for one command and nothing around it.
+func howPrompt(task, osName, shell string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Give a single %s command for %s that does this task:\n%s\n\n", shell, osName, task)
+	b.WriteString("Prefer tools that ship with the OS. Chain steps with && or pipes if needed, ")
+	b.WriteString("but keep it to one command line. Reply with the command only: ")
+	b.WriteString("no explanation, no code fences, no leading $.")
+	return b.String()
+}
+
+// cleanCommand strips the code fence, backticks and prompt sign a
+// provider may put around the command.
+func cleanCommand(s string) string {
+	s = cleanCommitMessage(s)
+	s = strings.Trim(s, "`")
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "$ ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// detectOS names the OS, with the distribution on Linux.
+func detectOS() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "macOS"
+	case "linux":
+		data, err := os.ReadFile("/etc/os-release")
+		if err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				if v, ok := strings.CutPrefix(line, "PRETTY_NAME="); ok {
+					return "Linux (" + strings.Trim(v, `"`) + ")"
+				}
+			}
+		}
+		return "Linux"
+	case "windows":
+		return "Windows"
+	}
+	return runtime.GOOS
+}
+
+// detectShell names the user's shell: $SHELL on Unix, PowerShell on
+// Windows unless ask runs under cmd.exe.
+func detectShell() string {
+	if runtime.GOOS == "windows" {
+		if os.Getenv("PSModulePath") == "" {
+			return "cmd"
+		}
+		return "powershell"
+	}
+	if sh := os.Getenv("SHELL"); sh != "" {
+		return filepath.Base(sh)
+	}
+	return "sh"
+}
+
+// shellCommand runs command in shell, which was named for the prompt.
+func shellCommand(shell, command string) *exec.Cmd {
+	switch shell {
+	case "cmd":
+		return exec.Command("cmd", "/C", command)
+	case "powershell":
+		return exec.Command("powershell", "-NoProfile", "-Command", command)
+	}
+	if sh := os.Getenv("SHELL"); sh != "" {
+		return exec.Command(sh, "-c", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
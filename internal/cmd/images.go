@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// imageOut is bound to --image-out on providers that can generate images
+// or return image search results.
+var imageOut string
+
+// downloadDir is bound to --download on providers whose answers can
+// include generated files and images.
+var downloadDir string
+
+// applyDownload points opts at --download: generated files are saved
+// there, and images too unless --image-out names another directory.
+func applyDownload(opts *provider.AskOptions) {
+	if downloadDir == "" {
+		return
+	}
+	opts.DownloadDir = downloadDir
+	if opts.ImageDir == "" {
+		opts.ImageDir = downloadDir
+	}
+}
+
+// printImages lists an answer's generated images after it: the saved file
+// when --image-out is set and the download worked, otherwise the URL.
+func printImages(images []provider.GeneratedImage) {
+	if len(images) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Images:")
+	for i, img := range images {
+		where := img.Path
+		if where == "" {
+			where = img.URL
+		}
+		fmt.Fprintf(os.Stderr, "  [%d] %s\n", i+1, where)
+		if img.Title != "" {
+			fmt.Fprintf(os.Stderr, "      %s\n", img.Title)
+		}
+	}
+	if imageOut == "" && downloadDir == "" {
+		flag := "--image-out"
+		if currentCmd != nil && currentCmd.Flags().Lookup(flag) == nil {
+			flag = "--download"
+		}
+		fmt.Fprintf(os.Stderr, "  (use %s <dir> to save them)\n", flag)
+	}
+}
+
+// printFiles lists the files generated for an answer after it: the saved
+// file when --download is set and the download worked, otherwise the URL.
+func printFiles(files []provider.GeneratedFile) {
+	if len(files) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Files:")
+	for i, f := range files {
+		where := f.Path
+		if where == "" {
+			where = f.URL
+		}
+		if where == "" {
+			where = "(not available)"
+		}
+		fmt.Fprintf(os.Stderr, "  [%d] %s: %s\n", i+1, f.Name, where)
+	}
+	if downloadDir == "" {
+		fmt.Fprintln(os.Stderr, "  (use --download <dir> to save them)")
+	}
+}
+
+// printMedia lists an answer's image and video results after it, with
+// the saved thumbnail when --image-out is set.
+func printMedia(media []provider.MediaResult) {
+	if len(media) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Media:")
+	for i, m := range media {
+		title := m.Title
+		if title == "" {
+			title = m.PageURL
+		}
+		fmt.Fprintf(os.Stderr, "  [%d] %s: %s\n", i+1, m.Kind, title)
+		fmt.Fprintf(os.Stderr, "      %s\n", m.URL)
+		if m.Path != "" {
+			fmt.Fprintf(os.Stderr, "      thumbnail: %s\n", m.Path)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	indexpkg "github.com/kyupark/ask/internal/index"
+)
+
+// contextExcerpts is how many passages --context puts in the prompt.
+const contextExcerpts = 6
+
+var (
+	indexName   string
+	flagContext string
+)
+
+// indexNameRE limits index names to what is safe as a file name.
+var indexNameRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var indexCmd = &cobra.Command{
+	Use:   "index <dir>",
+	Short: "Index a directory so --context can answer questions about it",
+	Long: `Build a keyword index of the text files in a directory. Asking with
+--context <name> then puts the passages most relevant to the question in
+the prompt, so any provider can answer questions about a private codebase
+or a folder of notes. Nothing leaves the machine until you ask.
+
+Hidden files, version control, dependency and build directories, binary
+files and files over 512 KB are skipped. Run the command again to pick up
+changes.
+
+  ask index ~/src/myapp
+  ask claude --context myapp "where are sessions refreshed?"
+
+Subcommands:
+  list    List the indexes
+  rm      Delete an index`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIndex,
+}
+
+var indexListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the indexes",
+	Args:  cobra.NoArgs,
+	RunE:  runIndexList,
+}
+
+var indexRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Delete an index",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := os.Remove(indexPath(args[0])); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no index named %q", args[0])
+			}
+			return err
+		}
+		fmt.Printf("Deleted index %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	indexCmd.Flags().StringVar(&indexName, "name", "", "Name for the index (default: the directory's name)")
+	indexCmd.AddCommand(indexListCmd, indexRmCmd)
+	rootCmd.AddCommand(indexCmd)
+	rootCmd.PersistentFlags().StringVar(&flagContext, "context", "", "Put passages from this index (see ask index) relevant to the question in the prompt")
+}
+
+func indexDir() string {
+	return filepath.Join(config.DataDir(), "indexes")
+}
+
+func indexPath(name string) string {
+	return filepath.Join(indexDir(), name+".json.gz")
+}
+
+func runIndex(cmd *cobra.Command, args []string) error {
+	info, err := os.Stat(args[0])
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", args[0])
+	}
+	idx, err := indexpkg.Build(args[0])
+	if err != nil {
+		return err
+	}
+	name := indexName
+	if name == "" {
+		name = filepath.Base(idx.Root)
+	}
+	if !indexNameRE.MatchString(name) {
+		return fmt.Errorf("invalid index name %q (use letters, digits, '.', '_' and '-'; set one with --name)", name)
+	}
+	if len(idx.Chunks) == 0 {
+		return fmt.Errorf("no text files found in %s", idx.Root)
+	}
+	if err := idx.Save(indexPath(name)); err != nil {
+		return err
+	}
+	fmt.Printf("Indexed %d files (%d passages) from %s as %q\n", idx.Files, len(idx.Chunks), idx.Root, name)
+	return nil
+}
+
+func runIndexList(cmd *cobra.Command, args []string) error {
+	paths, _ := filepath.Glob(filepath.Join(indexDir(), "*.json.gz"))
+	if len(paths) == 0 {
+		fmt.Println("No indexes. Build one with: ask index <dir>")
+		return nil
+	}
+	for _, p := range paths {
+		name := strings.TrimSuffix(filepath.Base(p), ".json.gz")
+		idx, err := indexpkg.Load(p)
+		if err != nil {
+			fmt.Printf("%-20s  (unreadable: %v)\n", name, err)
+			continue
+		}
+		fmt.Printf("%-20s  %5d files  %s  %s\n", name, idx.Files, formatTime(idx.Built), idx.Root)
+	}
+	return nil
+}
+
+// loadContextIndex opens the --context index, given by name or by the
+// directory it was built from.
+func loadContextIndex(ref string) (*indexpkg.Index, error) {
+	if indexNameRE.MatchString(ref) {
+		if idx, err := indexpkg.Load(indexPath(ref)); err == nil {
+			return idx, nil
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if abs, err := filepath.Abs(ref); err == nil {
+		paths, _ := filepath.Glob(filepath.Join(indexDir(), "*.json.gz"))
+		for _, p := range paths {
+			if idx, err := indexpkg.Load(p); err == nil && idx.Root == abs {
+				return idx, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no index named %q — build one with: ask index <dir>", ref)
+}
+
+// withContext puts the --context passages most relevant to the question
+// ahead of it.
+func withContext(query string) (string, error) {
+	if flagContext == "" {
+		return query, nil
+	}
+	idx, err := loadContextIndex(flagContext)
+	if err != nil {
+		return "", err
+	}
+	results := idx.Search(query, contextExcerpts)
+	if len(results) == 0 {
+		fmt.Fprintf(os.Stderr, "[context] nothing in %s matches the question\n", idx.Root)
+		return query, nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Excerpts from %s that may be relevant:\n\n", filepath.Base(idx.Root))
+	for _, r := range results {
+		if globalCfg.Verbose {
+			fmt.Fprintf(os.Stderr, "[context] %s:%d-%d (score %.2f)\n", r.Path, r.StartLine, r.EndLine, r.Score)
+		}
+		header := fmt.Sprintf("File: %s (lines %d-%d)", r.Path, r.StartLine, r.EndLine)
+		b.WriteString(fencedBlock(header, fenceLanguage(r.Path), r.Text))
+		b.WriteString("\n")
+	}
+	b.WriteString(query)
+	return b.String(), nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+// errInterrupted is returned by an ask stopped with Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+// interrupted is set once the first Ctrl-C has cancelled the command.
+var interrupted atomic.Bool
+
+// interruptContext returns a context cancelled by the first Ctrl-C (or
+// SIGTERM), so a streaming answer stops where it is and the command can
+// wrap up; a second one exits at once. stop releases the signals.
+func interruptContext() (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigs:
+		case <-done:
+			return
+		}
+		interrupted.Store(true)
+		cancel()
+		select {
+		case <-sigs:
+			fmt.Fprintln(os.Stderr)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sigs)
+		close(done)
+		cancel()
+	}
+}
+
+// stopInterrupted ends an answer cut short by Ctrl-C: the partial text is
+// closed off and the conversation hint printed, so it can be continued
+// with -c. It returns errInterrupted, which is reported by exit status
+// alone.
+func stopInterrupted(providerName, conversationID string, temporary bool) error {
+	emitStreamError(providerName, errInterrupted)
+	if !flagStreamJSON {
+		fmt.Println()
+	}
+	fmt.Fprintf(os.Stderr, "\n[%s] interrupted\n", providerName)
+	if conversationID != "" && !temporary {
+		printConversationHint(providerName, conversationID)
+	}
+	if currentCmd != nil {
+		currentCmd.SilenceErrors = true
+		currentCmd.SilenceUsage = true
+	}
+	return errInterrupted
+}
+
+// printConversationHint tells how to follow up in a conversation.
+func printConversationHint(providerName, conversationID string) {
+	fmt.Fprintf(os.Stderr, "\nConversation: %s\n", conversationID)
+	fmt.Fprintf(os.Stderr, "  ask %s -c %s \"follow up\"\n", providerName, conversationID)
+}
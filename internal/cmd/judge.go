@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var (
+	judgeProvider    string
+	judgeShowAnswers bool
+)
+
+var judgeCmd = &cobra.Command{
+	Use:   "judge [question]",
+	Short: "Ask every provider, then have one rank the answers",
+	Long: `Ask the question of every provider, as ask all does, then send the
+answers to the judge provider with the names removed and ask it to rank
+them and pick the best. The judge sees them as Answer A, B, C, ... in a
+shuffled order; which provider wrote each is printed after the verdict.
+
+  ask judge --judge claude "why is the sky blue?"`,
+	Args: questionArgs,
+	RunE: runJudge,
+}
+
+func init() {
+	judgeCmd.Flags().StringVar(&judgeProvider, "judge", "claude", "Provider that ranks the answers")
+	judgeCmd.Flags().BoolVar(&judgeShowAnswers, "show-answers", false, "Print every answer before the verdict")
+	rootCmd.AddCommand(judgeCmd)
+}
+
+// judgedAnswer is one anonymized answer put before the judge.
+type judgedAnswer struct {
+	label  string
+	result providerResult
+}
+
+func runJudge(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(providerNames, judgeProvider) {
+		return fmt.Errorf("unknown provider %q (use %s)", judgeProvider, strings.Join(providerNames, ", "))
+	}
+	if flagStreamJSON {
+		return fmt.Errorf("--stream-json is not supported by judge")
+	}
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
+	entries := askAllEntries()
+
+	var wgCookies sync.WaitGroup
+	for _, e := range entries {
+		wgCookies.Add(1)
+		go func(p provider.Provider) {
+			defer wgCookies.Done()
+			autoLoadCookies(cmd.Context(), p)
+		}(e.p)
+	}
+	wgCookies.Wait()
+
+	// 1. Every provider answers; progress goes to stderr.
+	fmt.Fprintf(os.Stderr, "[judge] asking %d providers...\n", len(entries))
+	results := make(chan providerResult, len(entries))
+	ctx, cancel := withProviderTimeout(cmd.Context())
+	for _, e := range entries {
+		go func(p provider.Provider, model string) {
+			results <- askAllOne(ctx, p, model, query, nil, nil, nil)
+		}(e.p, e.model)
+	}
+	var answers []judgedAnswer
+	for range entries {
+		r := <-results
+		if r.err != nil || strings.TrimSpace(r.output) == "" {
+			slog.Debug("judge: no answer", "provider", r.name, "err", r.err)
+			fmt.Fprintf(os.Stderr, "[judge] %s: no answer (%v)\n", r.name, r.err)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "[judge] %s answered in %s\n", r.name, r.elapsed.Round(100*time.Millisecond))
+		answers = append(answers, judgedAnswer{result: r})
+	}
+	cancel()
+	if len(answers) < 2 {
+		return fmt.Errorf("only %d provider(s) answered — judging needs at least two", len(answers))
+	}
+
+	// 2. Shuffle so neither the label nor the position gives a provider away.
+	rand.Shuffle(len(answers), func(i, j int) { answers[i], answers[j] = answers[j], answers[i] })
+	for i := range answers {
+		answers[i].label = string(rune('A' + i))
+	}
+
+	if judgeShowAnswers {
+		for _, a := range answers {
+			fmt.Printf("━━━ Answer %s ━━━\n\n%s\n\n", a.label, strings.TrimSpace(a.result.output))
+		}
+	}
+
+	// 3. The judge ranks them.
+	judge, model, err := newProviderByName(judgeProvider)
+	if err != nil {
+		return err
+	}
+	autoLoadCookies(cmd.Context(), judge)
+	fmt.Fprintf(os.Stderr, "[judge] %s is ranking %d answers...\n\n", judgeProvider, len(answers))
+
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: true,
+		OnText: func(text string) {
+			fmt.Print(text)
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", judgeProvider, "err", err)
+		},
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf(judgeProvider)
+	}
+	jctx, jcancel := withProviderTimeout(cmd.Context())
+	defer jcancel()
+	if err := sendPrompt(jctx, judge, judgePrompt(query, answers), opts); err != nil {
+		return fmt.Errorf("judge %s: %w", judgeProvider, err)
+	}
+	fmt.Println()
+
+	// 4. Reveal who wrote what.
+	fmt.Println()
+	for _, a := range answers {
+		who := a.result.name
+		if a.result.model != "" {
+			who += " (" + a.result.model + ")"
+		}
+		fmt.Printf("Answer %s = %s\n", a.label, who)
+	}
+	return nil
+}
+
+// judgePrompt builds the anonymized comparison request.
+func judgePrompt(question string, answers []judgedAnswer) string {
+	var b strings.Builder
+	b.WriteString("You are judging answers that different AI assistants gave to the same question. ")
+	b.WriteString("The assistants are anonymous; judge only the answers.\n\n")
+	b.WriteString("Question:\n")
+	b.WriteString(question)
+	b.WriteString("\n")
+	for _, a := range answers {
+		fmt.Fprintf(&b, "\n=== Answer %s ===\n%s\n", a.label, strings.TrimSpace(a.result.output))
+	}
+	b.WriteString("\nRank the answers from best to worst for accuracy, completeness and clarity, ")
+	b.WriteString("with a sentence or two on each. Point out any factual errors. ")
+	b.WriteString("End with a final line of the form \"Verdict: Answer X\" naming the best one.")
+	return b.String()
+}
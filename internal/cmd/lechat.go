@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+	lechatpkg "github.com/kyupark/ask/internal/provider/lechat"
+)
+
+var (
+	lechatModel        string
+	lechatResume       bool
+	lechatConversation string
+)
+
+var lechatCmd = &cobra.Command{
+	Use:   "lechat [question]",
+	Short: "Mistral Le Chat commands",
+	Long: `Interact with Mistral Le Chat (chat.mistral.ai) using browser cookies.
+  <question>      Ask a question (saves to history)
+  ask-incognito  Ask a question (no history)
+  list           List recent conversations
+  delete         Delete a conversation by ID
+  models         Show available models
+  alias          Name a conversation for use with -c`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !flagEdit {
+			return cmd.Help()
+		}
+		return runLeChatAsk(cmd, args, false)
+	},
+}
+
+var lechatAskIncognitoCmd = &cobra.Command{
+	Use:   "ask-incognito [question]",
+	Short: "Ask Le Chat (no history)",
+	Args:  questionArgs,
+	RunE:  func(cmd *cobra.Command, args []string) error { return runLeChatAsk(cmd, args, true) },
+}
+
+var lechatListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent Le Chat conversations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runList(cmd.Context(), newLeChatProvider(), listOptions())
+	},
+}
+
+var lechatDeleteCmd = &cobra.Command{
+	Use:   "delete <conversation-id>",
+	Short: "Delete a Le Chat conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDelete(cmd.Context(), newLeChatProvider(), args[0])
+	},
+}
+
+var lechatModelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Show available Le Chat models",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p := lechatpkg.New("", "", providerTimeout())
+		return runModels(p)
+	},
+}
+
+func init() {
+	lechatCmd.Flags().StringVarP(&lechatModel, "model", "m", "", "Model (e.g. 'mistral-large-latest', 'magistral-medium-latest')")
+	lechatCmd.Flags().BoolVarP(&lechatResume, "resume", "r", false, "Resume last conversation")
+	lechatCmd.Flags().StringVarP(&lechatConversation, "conversation", "c", "", "Continue a specific conversation by ID or alias")
+	lechatAskIncognitoCmd.Flags().StringVarP(&lechatModel, "model", "m", "", "Model (e.g. 'mistral-large-latest', 'magistral-medium-latest')")
+	lechatCmd.AddCommand(lechatAskIncognitoCmd)
+	addListFlags(lechatListCmd)
+	lechatCmd.AddCommand(lechatListCmd)
+	lechatCmd.AddCommand(lechatDeleteCmd)
+	lechatCmd.AddCommand(lechatModelsCmd)
+	lechatCmd.AddCommand(newAliasCmd("lechat"))
+	rootCmd.AddCommand(lechatCmd)
+}
+
+func runLeChatAsk(cmd *cobra.Command, args []string, temporary bool) error {
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
+
+	p := lechatpkg.New(
+		globalCfg.LeChat.BaseURL,
+		globalCfg.UserAgent,
+		providerTimeout(),
+	)
+	p.SetCookies(map[string]string{
+		lechatpkg.SessionCookieName: globalCfg.LeChat.SessionCookie,
+	})
+	p.SetProxy(providerProxy("lechat"))
+
+	loadAskCookies(cmd.Context(), p)
+
+	model := globalCfg.LeChat.Model
+	if lechatModel != "" {
+		model = lechatModel
+	}
+
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: temporary,
+		OnText: func(text string) {
+			fmt.Print(text)
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", "lechat", "err", err)
+		},
+	}
+
+	if !temporary {
+		if lechatConversation != "" {
+			opts.ConversationID = resolveConversationID("lechat", lechatConversation)
+		} else if lechatResume {
+			state := config.LoadState()
+			if conv := lastConversation(state, "lechat"); conv != nil {
+				opts.ConversationID = conv.ConversationID
+			} else {
+				fmt.Fprintln(os.Stderr, "No previous conversation found for lechat — starting new")
+			}
+		}
+	}
+
+	// Save conversation state and capture ID for hint.
+	var lastConvID string
+	if !temporary {
+		opts.OnConversation = func(convID, parentMsgID, respID string) {
+			lastConvID = convID
+			saveConversationState("lechat", query, &config.ConversationState{
+				ConversationID: convID,
+			})
+		}
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf("lechat")
+	}
+
+	applySystemPrompt("lechat", &opts)
+	applyStreamJSON("lechat", &opts)
+	rec := recordHistory("lechat", opts.Model, query, &opts)
+
+	err = askCached(cmd.Context(), p, query, opts)
+	rec.finish(err)
+	if err != nil {
+		if interrupted.Load() {
+			return stopInterrupted("lechat", lastConvID, temporary)
+		}
+		emitStreamError("lechat", err)
+		return err
+	}
+
+	finishAnswer("lechat")
+
+	if lastConvID != "" && !temporary {
+		printConversationHint("lechat", lastConvID)
+	}
+
+	return nil
+}
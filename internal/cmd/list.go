@@ -2,57 +2,173 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"os"
 	"time"
 
+	"github.com/spf13/cobra"
+
 	"github.com/kyupark/ask/internal/provider"
 )
 
+var (
+	listLimit  int
+	listOffset int
+	listAll    bool
+	listSearch string
+	listFormat string
+)
+
+// addListFlags adds the paging, search and output flags shared by every
+// provider's list command.
+func addListFlags(c *cobra.Command) {
+	c.Flags().IntVarP(&listLimit, "limit", "n", 20, "Maximum conversations to show")
+	c.Flags().IntVar(&listOffset, "offset", 0, "Skip this many of the most recent conversations")
+	c.Flags().BoolVar(&listAll, "all", false, "List every conversation, fetching page after page")
+	c.Flags().StringVarP(&listSearch, "search", "s", "", "Only conversations matching this term (ChatGPT and Perplexity also search their content)")
+	c.Flags().StringVar(&listFormat, "format", "table", "Output format: table, json or ids")
+	c.MarkFlagsMutuallyExclusive("all", "offset")
+}
+
+// listOptions returns the ListOptions the list flags ask for.
+func listOptions() provider.ListOptions {
+	return provider.ListOptions{Limit: listLimit, Offset: listOffset, Search: listSearch}
+}
+
+// listAllPage is the page size --all fetches when --limit is not given.
+const listAllPage = 100
+
 // runList is a shared helper that lists conversations for any provider
 // implementing the Lister interface.
-func runList(ctx context.Context, p provider.Provider, limit int) error {
+func runList(ctx context.Context, p provider.Provider, opts provider.ListOptions) error {
 	lister, ok := p.(provider.Lister)
 	if !ok {
 		return fmt.Errorf("%s does not support listing conversations", p.Name())
 	}
+	switch listFormat {
+	case "table", "json", "ids":
+	default:
+		return fmt.Errorf("invalid --format %q (use table, json or ids)", listFormat)
+	}
 
 	autoLoadCookies(ctx, p)
 
-	opts := provider.ListOptions{
-		Limit:   limit,
-		Verbose: globalCfg.Verbose,
-	}
+	opts.Verbose = globalCfg.Verbose
 	if globalCfg.Verbose {
-		opts.LogFunc = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format+"\n", args...)
+		opts.LogFunc = debugLogf(p.Name())
+	}
+
+	var conversations []provider.Conversation
+	if listAll {
+		var err error
+		conversations, err = listEvery(ctx, lister, opts)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		conversations, err = lister.ListConversations(ctx, opts)
+		if err != nil {
+			return err
 		}
 	}
 
-	conversations, err := lister.ListConversations(ctx, opts)
-	if err != nil {
-		return err
+	switch listFormat {
+	case "json":
+		return printConversationsJSON(conversations)
+	case "ids":
+		for _, c := range conversations {
+			fmt.Println(c.ID)
+		}
+		return nil
 	}
 
 	if len(conversations) == 0 {
 		fmt.Println("No conversations found.")
 		return nil
 	}
-
-	fmt.Printf("Found %d conversation(s):\n\n", len(conversations))
+	idWidth := 0
+	for _, c := range conversations {
+		idWidth = max(idWidth, len(c.ID))
+	}
 	for _, c := range conversations {
 		title := c.Title
 		if title == "" {
 			title = "(untitled)"
 		}
-		fmt.Printf("  %s\n", title)
-		fmt.Printf("    ID: %s\n", c.ID)
-		if !c.CreatedAt.IsZero() {
-			fmt.Printf("    %s\n", formatTime(c.CreatedAt))
+		when := c.UpdatedAt
+		if when.IsZero() {
+			when = c.CreatedAt
+		}
+		date := ""
+		if !when.IsZero() {
+			date = formatTime(when)
 		}
-		fmt.Println()
+		fmt.Printf("%-*s  %-20s  %s\n", idWidth, c.ID, date, truncateRunes(title, 80))
 	}
+	return nil
+}
 
+// listEvery pages through every conversation. It stops at a short page,
+// or at one that brings nothing new for providers that cannot page.
+func listEvery(ctx context.Context, lister provider.Lister, opts provider.ListOptions) ([]provider.Conversation, error) {
+	if !listFlagChanged("limit") {
+		opts.Limit = listAllPage
+	}
+	var all []provider.Conversation
+	seen := map[string]bool{}
+	for opts.Offset = 0; ; opts.Offset += opts.Limit {
+		page, err := lister.ListConversations(ctx, opts)
+		if err != nil {
+			return all, err
+		}
+		added := 0
+		for _, c := range page {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				all = append(all, c)
+				added++
+			}
+		}
+		if len(page) < opts.Limit || added == 0 {
+			return all, nil
+		}
+	}
+}
+
+// listFlagChanged reports whether the running list command was given flag.
+func listFlagChanged(name string) bool {
+	if currentCmd == nil {
+		return false
+	}
+	f := currentCmd.Flags().Lookup(name)
+	return f != nil && f.Changed
+}
+
+// printConversationsJSON prints conversations as a JSON array.
+func printConversationsJSON(conversations []provider.Conversation) error {
+	type conversationJSON struct {
+		ID        string     `json:"id"`
+		Title     string     `json:"title"`
+		CreatedAt *time.Time `json:"created_at,omitempty"`
+		UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	}
+	out := make([]conversationJSON, 0, len(conversations))
+	for _, c := range conversations {
+		j := conversationJSON{ID: c.ID, Title: c.Title}
+		if !c.CreatedAt.IsZero() {
+			j.CreatedAt = &c.CreatedAt
+		}
+		if !c.UpdatedAt.IsZero() {
+			j.UpdatedAt = &c.UpdatedAt
+		}
+		out = append(out, j)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
 	return nil
 }
 
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/spf13/cobra"
+
+	cfgpkg "github.com/kyupark/ask/internal/config"
+)
+
+var (
+	loginHeadless bool
+	loginTimeout  time.Duration
+)
+
+var loginCmd = &cobra.Command{
+	Use:   "login <provider>",
+	Short: "Log in through a browser window and store the session cookies",
+	Long: `Open a Chrome window at the provider's login page, wait until the
+session cookies appear, and store them in the config file.
+
+The browser uses its own profile under the ask data directory, so later
+logins usually only need a click. Requires Chrome or Chromium installed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogin,
+}
+
+func init() {
+	loginCmd.Flags().BoolVar(&loginHeadless, "headless", false, "Run without a visible window (only useful with a saved profile)")
+	loginCmd.Flags().DurationVar(&loginTimeout, "timeout", 5*time.Minute, "How long to wait for login to complete")
+	rootCmd.AddCommand(loginCmd)
+}
+
+func runLogin(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !slices.Contains(providerNames, name) {
+		return fmt.Errorf("unknown provider %q", name)
+	}
+	site := providerSites[name]
+
+	p, _, err := newProviderByName(name)
+	if err != nil {
+		return err
+	}
+	if len(p.CookieSpecs()) == 0 {
+		return fmt.Errorf("%s does not need a browser login", name)
+	}
+
+	// Cookie URLs cover both the bare and www hosts of every domain the
+	// provider reads cookies from.
+	var urls []string
+	wanted := make(map[string]bool)
+	for _, spec := range p.CookieSpecs() {
+		urls = append(urls, "https://"+spec.Domain+"/", "https://www."+spec.Domain+"/")
+		for _, n := range spec.Names {
+			wanted[n] = true
+		}
+	}
+
+	profileDir := filepath.Join(cfgpkg.DataDir(), "browser")
+	if err := os.MkdirAll(profileDir, 0o700); err != nil {
+		return fmt.Errorf("creating browser profile: %w", err)
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", loginHeadless),
+		chromedp.UserDataDir(profileDir),
+		chromedp.UserAgent(globalCfg.UserAgent),
+	)
+	ctx, cancel := context.WithTimeout(cmd.Context(), loginTimeout)
+	defer cancel()
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, opts...)
+	defer cancelAlloc()
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	defer cancelBrowser()
+
+	fmt.Fprintf(os.Stderr, "Opening %s — log in, then leave the window open until this command finishes.\n", site.login)
+	if err := chromedp.Run(browserCtx, chromedp.Navigate(site.login)); err != nil {
+		return fmt.Errorf("launching browser: %w", err)
+	}
+
+	for {
+		var found []*network.Cookie
+		err := chromedp.Run(browserCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			found, err = network.GetCookies().WithURLs(urls).Do(ctx)
+			return err
+		}))
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("login not completed within %s", loginTimeout)
+			}
+			return fmt.Errorf("reading browser cookies: %w", err)
+		}
+
+		values := make(map[string]string)
+		for _, c := range found {
+			if wanted[c.Name] && c.Value != "" {
+				values[c.Name] = c.Value
+			}
+		}
+		if hasCookies(values, site.required) {
+			stored := storeProviderCookies(name, values)
+			if err := cfgpkg.Save(globalCfg); err != nil {
+				return err
+			}
+			sort.Strings(stored)
+			fmt.Printf("Logged in to %s; stored %s\n", name, strings.Join(stored, ", "))
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("login not completed within %s", loginTimeout)
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func hasCookies(values map[string]string, names []string) bool {
+	for _, n := range names {
+		if values[n] == "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/mcp"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol server on stdio",
+	Long: `Run an MCP server over stdin/stdout so agents can call the
+cookie-authenticated providers as tools.
+
+Tools:
+  ask_<provider>       Ask chatgpt, claude, gemini, grok, or perplexity
+  list_conversations   List recent conversations for a provider
+
+Example client config:
+  {"mcpServers": {"ask": {"command": "ask", "args": ["mcp"]}}}`,
+	Args: cobra.NoArgs,
+	RunE: runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	s := mcp.NewServer("ask", Version)
+
+	for _, name := range providerNames {
+		s.AddTool(mcp.Tool{
+			Name:        "ask_" + name,
+			Description: fmt.Sprintf("Ask %s a question using the user's browser session and return the answer.", name),
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"prompt":          map[string]any{"type": "string", "description": "The question to ask"},
+					"model":           map[string]any{"type": "string", "description": "Optional model override"},
+					"conversation_id": map[string]any{"type": "string", "description": "Continue an existing conversation"},
+					"incognito":       map[string]any{"type": "boolean", "description": "Do not save to the provider's history"},
+				},
+				"required": []string{"prompt"},
+			},
+		}, mcpAskHandler(name))
+	}
+
+	s.AddTool(mcp.Tool{
+		Name:        "list_conversations",
+		Description: "List recent conversations for a provider.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"provider": map[string]any{"type": "string", "enum": providerNames},
+				"limit":    map[string]any{"type": "integer", "description": "Maximum conversations to return (default 20)"},
+			},
+			"required": []string{"provider"},
+		},
+	}, mcpListHandler)
+
+	slog.Info("serving on stdio", "component", "mcp")
+	return s.Serve(cmd.Context(), os.Stdin, os.Stdout)
+}
+
+func mcpAskHandler(name string) mcp.Handler {
+	return func(ctx context.Context, args map[string]any) (string, error) {
+		prompt := mcp.StringArg(args, "prompt")
+		if strings.TrimSpace(prompt) == "" {
+			return "", fmt.Errorf("prompt is required")
+		}
+		prompt, err := redactOutbound(prompt)
+		if err != nil {
+			return "", err
+		}
+
+		p, model, err := newProviderByName(name)
+		if err != nil {
+			return "", err
+		}
+		autoLoadCookies(ctx, p)
+		if m := mcp.StringArg(args, "model"); m != "" {
+			model = m
+		}
+
+		var out strings.Builder
+		var convID string
+		opts := provider.AskOptions{
+			Model:          model,
+			Verbose:        globalCfg.Verbose,
+			Temporary:      mcp.BoolArg(args, "incognito"),
+			ConversationID: resolveConversationID(name, mcp.StringArg(args, "conversation_id")),
+			OnText:         func(text string) { out.WriteString(text) },
+			OnSource: func(title, url string) {
+				if url != "" {
+					fmt.Fprintf(&out, "\n[source] %s %s", title, url)
+				}
+			},
+		}
+		if !opts.Temporary {
+			opts.OnConversation = func(conversationID, parentMessageID, responseID string) {
+				convID = conversationID
+				saveConversationState(name, prompt, &config.ConversationState{
+					ConversationID:  conversationID,
+					ParentMessageID: parentMessageID,
+					ResponseID:      responseID,
+				})
+			}
+		}
+		if globalCfg.Verbose {
+			opts.LogFunc = debugLogf(name)
+		}
+		applySystemPrompt(name, &opts)
+
+		if err := sendPrompt(ctx, p, prompt, opts); err != nil && out.Len() == 0 {
+			return "", err
+		}
+		if convID != "" {
+			fmt.Fprintf(&out, "\n\n(conversation_id: %s)", convID)
+		}
+		return out.String(), nil
+	}
+}
+
+func mcpListHandler(ctx context.Context, args map[string]any) (string, error) {
+	p, _, err := newProviderByName(mcp.StringArg(args, "provider"))
+	if err != nil {
+		return "", err
+	}
+	lister, ok := p.(provider.Lister)
+	if !ok {
+		return "", fmt.Errorf("%s does not support listing conversations", p.Name())
+	}
+	autoLoadCookies(ctx, p)
+
+	conversations, err := lister.ListConversations(ctx, provider.ListOptions{
+		Limit:   mcp.IntArg(args, "limit", 20),
+		Verbose: globalCfg.Verbose,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(conversations) == 0 {
+		return "No conversations found.", nil
+	}
+
+	var sb strings.Builder
+	for _, c := range conversations {
+		title := c.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		fmt.Fprintf(&sb, "%s\t%s", c.ID, title)
+		if !c.CreatedAt.IsZero() {
+			fmt.Fprintf(&sb, "\t%s", formatTime(c.CreatedAt))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
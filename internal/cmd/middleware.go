@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// middlewarePayload is what hooks.pre_send and hooks.post_receive get on
+// stdin. They may print it back with prompt, system_prompt (pre_send) or
+// answer (post_receive) changed; printing nothing keeps it as is.
+type middlewarePayload struct {
+	Stage          string `json:"stage"`
+	Provider       string `json:"provider"`
+	Model          string `json:"model,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	Prompt         string `json:"prompt"`
+	SystemPrompt   string `json:"system_prompt,omitempty"`
+	Answer         string `json:"answer,omitempty"`
+}
+
+// middlewareReply is a hook's output; fields left out keep their value.
+type middlewareReply struct {
+	Prompt       *string `json:"prompt"`
+	SystemPrompt *string `json:"system_prompt"`
+	Answer       *string `json:"answer"`
+}
+
+// runMiddleware runs a middleware hook with payload on stdin and decodes
+// what it prints. A failing hook's stderr is its error message.
+func runMiddleware(ctx context.Context, command string, payload middlewarePayload) (middlewareReply, error) {
+	var reply middlewareReply
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return reply, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, hookCommandTimeout)
+	defer cancel()
+	c := hookShellCommand(ctx, command)
+	c.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	c.Env = append(os.Environ(), "ASK_HOOK_STAGE="+payload.Stage, "ASK_PROVIDER="+payload.Provider, "ASK_MODEL="+payload.Model)
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return reply, fmt.Errorf("%w: %s", err, msg)
+		}
+		return reply, err
+	}
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 {
+		if err := json.Unmarshal(out, &reply); err != nil {
+			return reply, fmt.Errorf("reading its output: %w", err)
+		}
+	}
+	return reply, nil
+}
+
+// applyMiddleware runs hooks.pre_send on the prompt, which may rewrite it
+// or refuse it by failing, and, with hooks.post_receive set, holds the
+// answer back from opts.OnText for that hook. Call flush once the ask is
+// over to pass the answer on: rewritten after a successful ask, as it
+// arrived after a failed one or when the hook fails.
+func applyMiddleware(ctx context.Context, providerName string, query *string, opts *provider.AskOptions) (flush func(askErr error), err error) {
+	flush = func(error) {}
+	if command := globalCfg.Hooks.PreSend; command != "" {
+		reply, err := runMiddleware(ctx, command, middlewarePayload{
+			Stage:          "pre_send",
+			Provider:       providerName,
+			Model:          opts.Model,
+			ConversationID: opts.ConversationID,
+			Prompt:         *query,
+			SystemPrompt:   opts.SystemPrompt,
+		})
+		if err != nil {
+			return flush, fmt.Errorf("pre_send hook: %w", err)
+		}
+		if reply.Prompt != nil {
+			*query = *reply.Prompt
+		}
+		if reply.SystemPrompt != nil {
+			opts.SystemPrompt = *reply.SystemPrompt
+		}
+	}
+
+	command := globalCfg.Hooks.PostReceive
+	if command == "" || opts.OnText == nil {
+		return flush, nil
+	}
+	var (
+		mu             sync.Mutex
+		answer         strings.Builder
+		conversationID = opts.ConversationID
+	)
+	onText, onConversation := opts.OnText, opts.OnConversation
+	opts.OnText = func(text string) {
+		mu.Lock()
+		answer.WriteString(text)
+		mu.Unlock()
+	}
+	opts.OnConversation = func(c, m, r string) {
+		mu.Lock()
+		conversationID = c
+		mu.Unlock()
+		if onConversation != nil {
+			onConversation(c, m, r)
+		}
+	}
+	prompt := *query
+	flush = func(askErr error) {
+		mu.Lock()
+		text, convID := answer.String(), conversationID
+		mu.Unlock()
+		if askErr == nil && text != "" {
+			reply, err := runMiddleware(ctx, command, middlewarePayload{
+				Stage:          "post_receive",
+				Provider:       providerName,
+				Model:          opts.Model,
+				ConversationID: convID,
+				Prompt:         prompt,
+				Answer:         text,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[hook] post_receive: %v (showing the answer unchanged)\n", err)
+			} else if reply.Answer != nil {
+				text = *reply.Answer
+			}
+		}
+		if text != "" {
+			onText(text)
+		}
+	}
+	return flush, nil
+}
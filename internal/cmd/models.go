@@ -1,12 +1,110 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/spf13/cobra"
+
 	"github.com/kyupark/ask/internal/provider"
 )
 
+var modelsJSON bool
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List the models of every configured provider",
+	Long: `List the models of every provider ask all would ask, in one table.
+Ollama's are the models installed on its server. The model each provider
+uses when none is given, the configured one or else its own default, is
+marked with *.
+
+  ask models
+  ask models --json | jq -r '.[] | select(.provider == "claude") | .id'`,
+	Args: cobra.NoArgs,
+	RunE: runAllModels,
+}
+
+func init() {
+	modelsCmd.Flags().BoolVar(&modelsJSON, "json", false, "Print the models as JSON")
+	rootCmd.AddCommand(modelsCmd)
+}
+
+// modelRow is one model in the combined catalog.
+type modelRow struct {
+	Provider    string   `json:"provider"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Default     bool     `json:"default"`
+}
+
+func runAllModels(cmd *cobra.Command, args []string) error {
+	rows := []modelRow{}
+	for _, e := range askAllEntries() {
+		ml, ok := e.p.(provider.ModelLister)
+		if !ok {
+			continue
+		}
+		models := ml.ListModels().Models
+		// The configured model is the default when the catalog has it.
+		configured := false
+		for _, m := range models {
+			configured = configured || (e.model != "" && m.ID == e.model)
+		}
+		for _, m := range models {
+			def := m.Default
+			if configured {
+				def = m.ID == e.model
+			}
+			rows = append(rows, modelRow{
+				Provider:    e.p.Name(),
+				ID:          m.ID,
+				Name:        m.Name,
+				Description: m.Description,
+				Tags:        m.Tags,
+				Default:     def,
+			})
+		}
+	}
+
+	if modelsJSON {
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("No models found.")
+		return nil
+	}
+	pw, iw := len("provider"), len("model")
+	for _, r := range rows {
+		pw = max(pw, len(r.Provider))
+		iw = max(iw, len(r.ID))
+	}
+	fmt.Printf("  %-*s  %-*s  %s\n", pw, "provider", iw, "model", "name")
+	for _, r := range rows {
+		mark := "  "
+		if r.Default {
+			mark = "* "
+		}
+		name := r.Name
+		if len(r.Tags) > 0 {
+			name += " [" + strings.Join(r.Tags, ", ") + "]"
+		}
+		fmt.Printf("%s%-*s  %-*s  %s\n", mark, pw, r.Provider, iw, r.ID, name)
+	}
+	fmt.Println()
+	fmt.Println("(* = used when no model is given)")
+	return nil
+}
+
 // runModels prints the model catalog for a provider implementing ModelLister.
 func runModels(p provider.Provider) error {
 	ml, ok := p.(provider.ModelLister)
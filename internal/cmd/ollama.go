@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+	ollamapkg "github.com/kyupark/ask/internal/provider/ollama"
+)
+
+var (
+	ollamaModel        string
+	ollamaResume       bool
+	ollamaConversation string
+)
+
+var ollamaCmd = &cobra.Command{
+	Use:   "ollama [question]",
+	Short: "Local Ollama model commands",
+	Long: `Ask models served by a local Ollama server (no cookies, works offline).
+  <question>      Ask a question (saves to history)
+  ask-incognito  Ask a question (no history)
+  list           List saved conversations
+  show           Show a conversation transcript
+  export         Export a conversation
+  retry          Get a new answer to the last question
+  delete         Delete a conversation by ID
+  models         Show installed models
+  alias          Name a conversation for use with -c
+The server is OLLAMA_HOST or http://localhost:11434 unless ollama.base_url
+is set. Without -m or ollama.model the first installed model is used.
+Conversations are kept locally, since Ollama itself stores none.
+Set ollama.model to include Ollama in ask all.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !flagEdit {
+			return cmd.Help()
+		}
+		return runOllamaAsk(cmd, args, false)
+	},
+}
+
+var ollamaAskIncognitoCmd = &cobra.Command{
+	Use:   "ask-incognito [question]",
+	Short: "Ask Ollama (no history)",
+	Args:  questionArgs,
+	RunE:  func(cmd *cobra.Command, args []string) error { return runOllamaAsk(cmd, args, true) },
+}
+
+var ollamaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved Ollama conversations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runList(cmd.Context(), newOllamaProvider(), listOptions())
+	},
+}
+
+var ollamaDeleteCmd = &cobra.Command{
+	Use:   "delete <conversation-id>",
+	Short: "Delete an Ollama conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDelete(cmd.Context(), newOllamaProvider(), args[0])
+	},
+}
+
+var ollamaModelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Show installed Ollama models",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p := newOllamaProvider()
+		if _, err := p.(*ollamapkg.Provider).InstalledModels(cmd.Context()); err != nil {
+			return err
+		}
+		return runModels(p)
+	},
+}
+
+func init() {
+	ollamaCmd.Flags().StringVarP(&ollamaModel, "model", "m", "", "Model (e.g. 'llama3', 'qwen3:8b')")
+	ollamaCmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print a thinking model's reasoning before the answer")
+	ollamaCmd.Flags().BoolVarP(&ollamaResume, "resume", "r", false, "Resume last conversation")
+	ollamaCmd.Flags().StringVarP(&ollamaConversation, "conversation", "c", "", "Continue a specific conversation by ID or alias")
+	ollamaAskIncognitoCmd.Flags().StringVarP(&ollamaModel, "model", "m", "", "Model (e.g. 'llama3', 'qwen3:8b')")
+	ollamaAskIncognitoCmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print a thinking model's reasoning before the answer")
+	ollamaCmd.AddCommand(ollamaAskIncognitoCmd)
+	addListFlags(ollamaListCmd)
+	ollamaCmd.AddCommand(ollamaListCmd)
+	ollamaCmd.AddCommand(newShowCmd("ollama"))
+	ollamaCmd.AddCommand(newExportCmd("ollama"))
+	ollamaCmd.AddCommand(newRetryCmd("ollama", &ollamaResume, runOllamaAsk))
+	ollamaCmd.AddCommand(ollamaDeleteCmd)
+	ollamaCmd.AddCommand(ollamaModelsCmd)
+	ollamaCmd.AddCommand(newAliasCmd("ollama"))
+	rootCmd.AddCommand(ollamaCmd)
+}
+
+func runOllamaAsk(cmd *cobra.Command, args []string, temporary bool) error {
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
+
+	p := newOllamaProvider()
+
+	model := globalCfg.Ollama.Model
+	if ollamaModel != "" {
+		model = ollamaModel
+	}
+
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: temporary,
+		OnText: func(text string) {
+			fmt.Print(text)
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", "ollama", "err", err)
+		},
+	}
+
+	if !temporary {
+		if ollamaConversation != "" {
+			opts.ConversationID = resolveConversationID("ollama", ollamaConversation)
+		} else if ollamaResume {
+			state := config.LoadState()
+			if conv := lastConversation(state, "ollama"); conv != nil {
+				opts.ConversationID = conv.ConversationID
+			} else {
+				fmt.Fprintln(os.Stderr, "No previous conversation found for ollama — starting new")
+			}
+		}
+	}
+	opts.Retry = retryLast && opts.ConversationID != ""
+
+	// Save conversation state and capture ID for hint.
+	var lastConvID string
+	if !temporary {
+		opts.OnConversation = func(convID, parentMsgID, respID string) {
+			lastConvID = convID
+			saveConversationState("ollama", query, &config.ConversationState{
+				ConversationID: convID,
+			})
+		}
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf("ollama")
+	}
+
+	applySystemPrompt("ollama", &opts)
+	applyShowThinking(&opts)
+	applyStreamJSON("ollama", &opts)
+	rec := recordHistory("ollama", opts.Model, query, &opts)
+
+	err = askCached(cmd.Context(), p, query, opts)
+	rec.finish(err)
+	if err != nil {
+		if interrupted.Load() {
+			return stopInterrupted("ollama", lastConvID, temporary)
+		}
+		emitStreamError("ollama", err)
+		return err
+	}
+
+	finishAnswer("ollama")
+
+	if lastConvID != "" && !temporary {
+		printConversationHint("ollama", lastConvID)
+	}
+
+	return nil
+}
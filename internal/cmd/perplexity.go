@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -18,6 +18,10 @@ var (
 	perplexityFocus        string
 	perplexityResume       bool
 	perplexityConversation string
+	perplexityShowRelated  bool
+	perplexityShowMedia    bool
+	perplexityNoSources    bool
+	perplexitySourcesOnly  bool
 )
 
 var perplexityCmd = &cobra.Command{
@@ -30,10 +34,15 @@ Subcommands:
   ask-incognito  Ask a question (no history)
   list           List recent threads
 	delete         Delete a thread by ID
-	models         Show available models, modes, and search focuses`,
+	models         Show available models
+	alias          Name a conversation for use with -c, modes, and search focuses
+	show           Show a thread transcript
+	export         Export a transcript (md, json, html)
+	retry          Get a new answer to the last question
+	followup       Ask a related question by number (no number lists them)`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		if len(args) == 0 && !flagEdit {
 			return cmd.Help()
 		}
 		return runPerplexityAsk(cmd, args, false)
@@ -43,7 +52,7 @@ Subcommands:
 var perplexityAskIncognitoCmd = &cobra.Command{
 	Use:   "ask-incognito [question]",
 	Short: "Ask Perplexity (no history)",
-	Args:  cobra.MinimumNArgs(1),
+	Args:  questionArgs,
 	RunE:  func(cmd *cobra.Command, args []string) error { return runPerplexityAsk(cmd, args, true) },
 }
 
@@ -61,6 +70,29 @@ var perplexityDeleteCmd = &cobra.Command{
 	RunE:  runPerplexityDelete,
 }
 
+var perplexityFollowupCmd = &cobra.Command{
+	Use:   "followup [n]",
+	Short: "Ask a related question from the last Perplexity answer",
+	Long:  "Ask a related question from the last Perplexity answer in the same thread.\nWith no number, list the saved related questions.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			conv := lastConversation(config.LoadState(), "perplexity")
+			if conv == nil || len(conv.FollowUps) == 0 {
+				return fmt.Errorf("no related questions saved for perplexity")
+			}
+			printFollowUps("perplexity", conv.FollowUps)
+			return nil
+		}
+		q, err := followUpQuestion("perplexity", args[0])
+		if err != nil {
+			return err
+		}
+		perplexityResume = true
+		return runPerplexityAsk(cmd, []string{q}, false)
+	},
+}
+
 var perplexityModelsCmd = &cobra.Command{
 	Use:   "models",
 	Short: "Show available Perplexity models and modes",
@@ -76,19 +108,43 @@ func init() {
 	perplexityCmd.Flags().StringVar(&perplexityMode, "mode", "", "Mode (auto, pro, reasoning, deep research)")
 	perplexityCmd.Flags().StringVar(&perplexityFocus, "focus", "", "Search focus (internet, scholar, social, edgar, writing)")
 	perplexityCmd.Flags().BoolVarP(&perplexityResume, "resume", "r", false, "Resume last conversation")
-	perplexityCmd.Flags().StringVar(&perplexityConversation, "conversation", "", "Continue a specific conversation by ID")
+	perplexityCmd.Flags().StringVarP(&perplexityConversation, "conversation", "c", "", "Continue a specific conversation by ID or alias")
 	perplexityAskIncognitoCmd.Flags().StringVarP(&perplexityModel, "model", "m", "", "Model preference (e.g. 'pplx_reasoning', 'gpt52')")
 	perplexityAskIncognitoCmd.Flags().StringVar(&perplexityMode, "mode", "", "Mode (auto, pro, reasoning, deep research)")
 	perplexityAskIncognitoCmd.Flags().StringVar(&perplexityFocus, "focus", "", "Search focus (internet, scholar, social, edgar, writing)")
+	perplexityCmd.Flags().BoolVar(&perplexityShowRelated, "show-related", false, "List related questions after the answer")
+	perplexityAskIncognitoCmd.Flags().BoolVar(&perplexityShowRelated, "show-related", false, "List related questions after the answer")
+	perplexityFollowupCmd.Flags().BoolVar(&perplexityShowRelated, "show-related", false, "List related questions after the answer")
+	perplexityCmd.Flags().BoolVar(&perplexityShowMedia, "media", false, "List image and video results after the answer")
+	perplexityAskIncognitoCmd.Flags().BoolVar(&perplexityShowMedia, "media", false, "List image and video results after the answer")
+	for _, c := range []*cobra.Command{perplexityCmd, perplexityAskIncognitoCmd} {
+		c.Flags().BoolVar(&perplexityNoSources, "no-sources", false, "Leave out the source list and the [n] citations")
+		c.Flags().BoolVar(&perplexitySourcesOnly, "sources-only", false, "Print only the sources, not the answer")
+		c.MarkFlagsMutuallyExclusive("no-sources", "sources-only")
+	}
+	perplexityCmd.Flags().StringVar(&imageOut, "image-out", "", "Save image and video thumbnails to this directory (implies --media)")
+	perplexityAskIncognitoCmd.Flags().StringVar(&imageOut, "image-out", "", "Save image and video thumbnails to this directory (implies --media)")
 	perplexityCmd.AddCommand(perplexityAskIncognitoCmd)
+	addListFlags(perplexityListCmd)
 	perplexityCmd.AddCommand(perplexityListCmd)
 	perplexityCmd.AddCommand(perplexityDeleteCmd)
 	perplexityCmd.AddCommand(perplexityModelsCmd)
+	perplexityCmd.AddCommand(newAliasCmd("perplexity"))
+	perplexityCmd.AddCommand(newShowCmd("perplexity"))
+	perplexityCmd.AddCommand(newExportCmd("perplexity"))
+	perplexityCmd.AddCommand(newRetryCmd("perplexity", &perplexityResume, runPerplexityAsk))
+	perplexityCmd.AddCommand(perplexityFollowupCmd)
 	rootCmd.AddCommand(perplexityCmd)
 }
 
 func runPerplexityAsk(cmd *cobra.Command, args []string, temporary bool) error {
-	query := strings.Join(args, " ")
+	if perplexitySourcesOnly && flagStreamJSON {
+		return fmt.Errorf("--sources-only cannot be used with --stream-json")
+	}
+	query, send, err := prepareQuery(args)
+	if err != nil || !send {
+		return err
+	}
 
 	model := globalCfg.Perplexity.Model
 	if perplexityModel != "" {
@@ -115,8 +171,9 @@ func runPerplexityAsk(cmd *cobra.Command, args []string, temporary bool) error {
 		"cf_clearance":                     globalCfg.Perplexity.CfClearance,
 		"__Secure-next-auth.session-token": globalCfg.Perplexity.SessionCookie,
 	})
+	p.SetProxy(providerProxy("perplexity"))
 
-	autoLoadCookies(cmd.Context(), p)
+	loadAskCookies(cmd.Context(), p)
 
 	// Apply mode/focus overrides if set.
 	if mode != "" {
@@ -125,75 +182,113 @@ func runPerplexityAsk(cmd *cobra.Command, args []string, temporary bool) error {
 	if focus != "" {
 		p.SetSearchFocus(focus)
 	}
+	p.SetCitations(!perplexityNoSources)
 
-	var sources []struct{ name, url string }
+	var sources sourceList
 
 	opts := provider.AskOptions{
 		Model:     model,
 		Verbose:   globalCfg.Verbose,
 		Temporary: temporary,
+		ImageDir:  imageOut,
 		OnText: func(text string) {
 			fmt.Print(text)
 		},
 		OnSource: func(name, url string) {
-			sources = append(sources, struct{ name, url string }{name, url})
+			sources.add(name, url)
+		},
+		OnProgress: func(status string) {
+			fmt.Fprintf(os.Stderr, "[%s]\n", status)
 		},
 		OnError: func(err error) {
-			if globalCfg.Verbose {
-				fmt.Fprintf(os.Stderr, "[perplexity] parse error: %v\n", err)
-			}
+			slog.Debug("parse error", "provider", "perplexity", "err", err)
 		},
 	}
+	if perplexitySourcesOnly {
+		opts.OnText = func(string) {}
+	}
 
 	if !temporary {
 		if perplexityConversation != "" {
-			opts.ConversationID = perplexityConversation
+			opts.ConversationID = resolveConversationID("perplexity", perplexityConversation)
 		} else if perplexityResume {
 			state := config.LoadState()
-			if conv := state.GetConversation("perplexity"); conv != nil {
+			if conv := lastConversation(state, "perplexity"); conv != nil {
 				opts.ConversationID = conv.ConversationID
 			} else {
 				fmt.Fprintln(os.Stderr, "No previous conversation found for perplexity — starting new")
 			}
 		}
 	}
+	opts.Retry = retryLast && opts.ConversationID != ""
+
+	var media []provider.MediaResult
+	if perplexityShowMedia || imageOut != "" {
+		opts.OnMedia = func(m provider.MediaResult) {
+			media = append(media, m)
+		}
+	}
+
+	var followUps []string
+	opts.OnFollowUps = func(suggestions []string) {
+		followUps = suggestions
+	}
 
 	// Save conversation state and capture ID for hint.
 	var lastConvID string
 	if !temporary {
 		opts.OnConversation = func(convID, parentMsgID, respID string) {
 			lastConvID = convID
-			state := config.LoadState()
-			state.SetConversation("perplexity", &config.ConversationState{
+			saveConversationState("perplexity", query, &config.ConversationState{
 				ConversationID: convID,
 			})
-			_ = config.SaveState(state)
 		}
 	}
 	if globalCfg.Verbose {
-		opts.LogFunc = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format+"\n", args...)
-		}
+		opts.LogFunc = debugLogf("perplexity")
 	}
 
-	if err := p.Ask(cmd.Context(), query, opts); err != nil {
+	applySystemPrompt("perplexity", &opts)
+	applyStreamJSON("perplexity", &opts)
+	rec := recordHistory("perplexity", opts.Model, query, &opts)
+
+	err = askCached(cmd.Context(), p, query, opts)
+	rec.finish(err)
+	if err != nil {
+		if interrupted.Load() {
+			return stopInterrupted("perplexity", lastConvID, temporary)
+		}
+		emitStreamError("perplexity", err)
 		return err
 	}
 
-	fmt.Println()
+	// Follow-ups arrive after the conversation ID, so attach them afterwards.
+	if lastConvID != "" && len(followUps) > 0 {
+		saveFollowUps("perplexity", lastConvID, followUps)
+	}
 
-	if len(sources) > 0 {
-		fmt.Fprintln(os.Stderr)
-		fmt.Fprintln(os.Stderr, "Sources:")
-		for i, src := range sources {
-			fmt.Fprintf(os.Stderr, "  [%d] %s\n", i+1, src.name)
-			fmt.Fprintf(os.Stderr, "      %s\n", src.url)
+	switch {
+	case perplexitySourcesOnly:
+		for i, src := range sources.list {
+			fmt.Printf("[%d] %s\n    %s\n", i+1, src.name, src.url)
 		}
+	case perplexityNoSources:
+		finishAnswer("perplexity")
+	default:
+		finishAnswer("perplexity")
+		printSources(sources.list)
+	}
+
+	printMedia(media)
+
+	if perplexityShowRelated {
+		printFollowUps("perplexity", followUps)
+	} else if len(followUps) > 0 && !temporary {
+		fmt.Fprintf(os.Stderr, "\n%d related questions: ask perplexity followup (--show-related to list them here)\n", len(followUps))
 	}
 
 	if lastConvID != "" && !temporary {
-		fmt.Fprintf(os.Stderr, "\nConversation: %s\n", lastConvID)
-		fmt.Fprintf(os.Stderr, "  ask perplexity -c %s \"follow up\"\n", lastConvID)
+		printConversationHint("perplexity", lastConvID)
 	}
 
 	return nil
@@ -210,8 +305,9 @@ func runPerplexityList(cmd *cobra.Command, args []string) error {
 		"cf_clearance":                     globalCfg.Perplexity.CfClearance,
 		"__Secure-next-auth.session-token": globalCfg.Perplexity.SessionCookie,
 	})
+	p.SetProxy(providerProxy("perplexity"))
 
-	return runList(cmd.Context(), p, 20)
+	return runList(cmd.Context(), p, listOptions())
 }
 
 func runPerplexityDelete(cmd *cobra.Command, args []string) error {
@@ -225,6 +321,7 @@ func runPerplexityDelete(cmd *cobra.Command, args []string) error {
 		"cf_clearance":                     globalCfg.Perplexity.CfClearance,
 		"__Secure-next-auth.session-token": globalCfg.Perplexity.SessionCookie,
 	})
+	p.SetProxy(providerProxy("perplexity"))
 
 	return runDelete(cmd.Context(), p, args[0])
 }
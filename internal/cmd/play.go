@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/playbook"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var playOutput string
+
+var playCmd = &cobra.Command{
+	Use:   "play <playbook.yaml>",
+	Short: "Run a scripted multi-turn conversation from a YAML playbook",
+	Long: `Run each step of a playbook as one turn of a conversation, once per
+provider listed in the playbook, and print the answers as they arrive.
+
+  name: triage
+  providers: [chatgpt, claude]
+  models: {chatgpt: gpt-5-2}
+  system: Answer in at most three sentences.
+  steps:
+    - ask: What usually causes "too many open files" on Linux?
+    - ask: Summarize {{previous}} as a checklist.
+      only: [claude]
+    - ask: Which of those applies to containers?
+      prompts:
+        chatgpt: Which of those applies to Docker containers specifically?
+
+"only" limits a step to some providers, "prompts" replaces the prompt for
+one provider, and {{previous}} expands to that provider's previous answer.
+Use --output to save every turn as JSON.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPlay,
+}
+
+func init() {
+	playCmd.Flags().StringVarP(&playOutput, "output", "o", "", "Write the captured turns as JSON to this file")
+	rootCmd.AddCommand(playCmd)
+}
+
+// playTurn is one captured question and answer.
+type playTurn struct {
+	Step       int               `json:"step"`
+	Question   string            `json:"question"`
+	Answer     string            `json:"answer,omitempty"`
+	Sources    []provider.Source `json:"sources,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	DurationMs int64             `json:"duration_ms"`
+}
+
+// playRun is one provider's pass through the playbook.
+type playRun struct {
+	Provider       string     `json:"provider"`
+	Model          string     `json:"model,omitempty"`
+	ConversationID string     `json:"conversation_id,omitempty"`
+	Turns          []playTurn `json:"turns"`
+}
+
+func runPlay(cmd *cobra.Command, args []string) error {
+	pb, err := playbook.Load(args[0], providerNames)
+	if err != nil {
+		return err
+	}
+	if pb.System != "" && flagSystem == "" {
+		flagSystem = pb.System
+	}
+
+	var runs []*playRun
+	failed := 0
+	for i, name := range pb.Providers {
+		if i > 0 {
+			fmt.Println()
+		}
+		run, err := playProvider(cmd.Context(), pb, name)
+		if err != nil {
+			return err
+		}
+		for _, t := range run.Turns {
+			if t.Error != "" {
+				failed++
+			}
+		}
+		runs = append(runs, run)
+	}
+
+	if playOutput != "" {
+		data, err := json.MarshalIndent(map[string]any{"name": pb.Name, "runs": runs}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(playOutput, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "\nSaved %s\n", playOutput)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d turn(s) failed", failed)
+	}
+	return nil
+}
+
+// playProvider runs every applicable step as one conversation. A failed
+// turn ends the run, since later turns depend on it.
+func playProvider(ctx context.Context, pb *playbook.Playbook, name string) (*playRun, error) {
+	p, model, err := newProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if m := pb.Models[name]; m != "" {
+		model = m
+	}
+	autoLoadCookies(ctx, p)
+
+	run := &playRun{Provider: name, Model: model}
+	fmt.Printf("━━━ %s (%s) ━━━\n", name, model)
+
+	var parentMessageID, responseID, previous string
+	for i, step := range pb.Steps {
+		prompt, ok := step.Prompt(name, previous)
+		if !ok {
+			continue
+		}
+		query, err := redactOutbound(prompt)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Printf("\n▶ %s\n\n", prompt)
+		turn := playTurn{Step: i + 1, Question: prompt}
+		var answer strings.Builder
+		opts := provider.AskOptions{
+			Model:           model,
+			Verbose:         globalCfg.Verbose,
+			Temporary:       pb.Incognito,
+			ConversationID:  run.ConversationID,
+			ParentMessageID: parentMessageID,
+			ResponseID:      responseID,
+			OnText: func(text string) {
+				answer.WriteString(text)
+				fmt.Print(text)
+			},
+			OnSource: func(title, url string) {
+				turn.Sources = append(turn.Sources, provider.Source{Name: title, URL: url})
+			},
+			OnConversation: func(conversationID, parentMsgID, respID string) {
+				run.ConversationID = conversationID
+				parentMessageID = parentMsgID
+				responseID = respID
+			},
+		}
+		if globalCfg.Verbose {
+			opts.LogFunc = debugLogf(name)
+		}
+		applySystemPrompt(name, &opts)
+
+		start := time.Now()
+		qctx, cancel := withProviderTimeout(ctx)
+		err = sendPrompt(qctx, p, query, opts)
+		cancel()
+		fmt.Println()
+
+		turn.DurationMs = time.Since(start).Milliseconds()
+		turn.Answer = strings.TrimSpace(answer.String())
+		if err != nil && turn.Answer == "" {
+			turn.Error = err.Error()
+			run.Turns = append(run.Turns, turn)
+			fmt.Fprintf(os.Stderr, "[%s] step %d failed: %v\n", name, i+1, err)
+			return run, nil
+		}
+		run.Turns = append(run.Turns, turn)
+		previous = turn.Answer
+	}
+
+	if run.ConversationID != "" && !pb.Incognito {
+		saveConversationState(name, pb.Name, &config.ConversationState{
+			ConversationID:  run.ConversationID,
+			ParentMessageID: parentMessageID,
+			ResponseID:      responseID,
+		})
+	}
+	return run, nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// maxExecBytes caps the command output kept in the prompt.
+const maxExecBytes = 48 << 10
+
+var flagExec []string
+
+// ansiEscapeRE matches terminal color and cursor sequences, which only
+// waste the provider's context.
+var ansiEscapeRE = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+func init() {
+	rootCmd.PersistentFlags().StringArrayVar(&flagExec, "exec", nil, "Run a shell command and include its output in the prompt (repeatable)")
+}
+
+// withExec runs each --exec command and puts its combined output and exit
+// status ahead of the question. A failing command is not an error: its
+// output is usually what the question is about.
+func withExec(query string) (string, error) {
+	if len(flagExec) == 0 {
+		return query, nil
+	}
+	var b strings.Builder
+	for _, command := range flagExec {
+		fmt.Fprintf(os.Stderr, "[exec] %s\n", command)
+		var c *exec.Cmd
+		if runtime.GOOS == "windows" {
+			c = exec.Command("cmd", "/C", command)
+		} else {
+			c = exec.Command("sh", "-c", command)
+		}
+		var out bytes.Buffer
+		c.Stdout = &out
+		c.Stderr = &out
+		status := "exit status 0"
+		if err := c.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				return "", fmt.Errorf("--exec %q: %w", command, err)
+			}
+			status = exitErr.String()
+		}
+		text := ansiEscapeRE.ReplaceAllString(out.String(), "")
+		text = truncateMiddle(strings.ReplaceAll(text, "\r\n", "\n"), maxExecBytes)
+		b.WriteString(fencedBlock(fmt.Sprintf("Command: %s (%s)", command, status), "", text))
+		b.WriteString("\n")
+	}
+	b.WriteString(query)
+	return b.String(), nil
+}
+
+// truncateMiddle shortens text to about limit bytes by dropping whole
+// lines from the middle. The end gets the larger share, since that is
+// where failures and summaries are printed.
+func truncateMiddle(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	lines := strings.SplitAfter(text, "\n")
+	headBudget, tailBudget := limit/4, limit-limit/4
+
+	var head, tail int // lines kept from each end
+	for size := 0; head < len(lines) && size+len(lines[head]) <= headBudget; head++ {
+		size += len(lines[head])
+	}
+	for size := 0; tail < len(lines)-head && size+len(lines[len(lines)-1-tail]) <= tailBudget; tail++ {
+		size += len(lines[len(lines)-1-tail])
+	}
+	omitted := len(lines) - head - tail
+	if omitted == 0 {
+		return text
+	}
+	return strings.Join(lines[:head], "") +
+		fmt.Sprintf("[... %d lines omitted ...]\n", omitted) +
+		strings.Join(lines[len(lines)-tail:], "")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxPromptFileBytes caps each --file; larger files are usually logs
+	// or build output that no chat window would take anyway.
+	maxPromptFileBytes = 256 << 10
+	// maxPromptFilesBytes caps all --file contents together.
+	maxPromptFilesBytes = 1 << 20
+)
+
+var flagFiles []string
+
+func init() {
+	rootCmd.PersistentFlags().StringArrayVar(&flagFiles, "file", nil, "Include a file in the prompt as a fenced code block (repeatable)")
+}
+
+// fenceLanguages maps file extensions to code fence language tags where
+// the two differ or the extension alone is ambiguous.
+var fenceLanguages = map[string]string{
+	".bash": "bash", ".c": "c", ".cc": "cpp", ".cpp": "cpp", ".cs": "csharp",
+	".css": "css", ".go": "go", ".h": "c", ".hpp": "cpp", ".html": "html",
+	".java": "java", ".js": "javascript", ".json": "json", ".jsx": "jsx",
+	".kt": "kotlin", ".lua": "lua", ".md": "markdown", ".mjs": "javascript",
+	".php": "php", ".py": "python", ".rb": "ruby", ".rs": "rust",
+	".scala": "scala", ".sh": "bash", ".sql": "sql", ".swift": "swift",
+	".toml": "toml", ".ts": "typescript", ".tsx": "tsx", ".xml": "xml",
+	".yaml": "yaml", ".yml": "yaml", ".zsh": "zsh",
+}
+
+// fenceLanguage returns the code fence tag for path, or "" when unknown.
+func fenceLanguage(path string) string {
+	switch strings.ToLower(filepath.Base(path)) {
+	case "dockerfile":
+		return "dockerfile"
+	case "makefile":
+		return "makefile"
+	}
+	return fenceLanguages[strings.ToLower(filepath.Ext(path))]
+}
+
+// withFiles puts the --file contents ahead of the question, each in a
+// code fence under a header naming the file.
+func withFiles(query string) (string, error) {
+	if len(flagFiles) == 0 {
+		return query, nil
+	}
+	var b strings.Builder
+	total := 0
+	for _, path := range flagFiles {
+		data, err := readPromptFile(path)
+		if err != nil {
+			return "", err
+		}
+		total += len(data)
+		if total > maxPromptFilesBytes {
+			return "", fmt.Errorf("--file: files total more than %d KB", maxPromptFilesBytes>>10)
+		}
+		b.WriteString(fencedFile(path, data))
+		b.WriteString("\n")
+	}
+	b.WriteString(query)
+	return b.String(), nil
+}
+
+// readPromptFile reads a --file, refusing directories, oversized files
+// and binary content.
+func readPromptFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("--file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("--file: %s is a directory", path)
+	}
+	if info.Size() > maxPromptFileBytes {
+		return nil, fmt.Errorf("--file: %s is %d KB, more than the %d KB limit", path, info.Size()>>10, maxPromptFileBytes>>10)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("--file: %w", err)
+	}
+	if isBinary(data) {
+		return nil, fmt.Errorf("--file: %s looks binary (use --attach where the provider supports it)", path)
+	}
+	return data, nil
+}
+
+// isBinary reports whether data looks like something other than text:
+// a NUL byte in the first 8 KB, as git decides, or invalid UTF-8.
+func isBinary(data []byte) bool {
+	head := data[:min(len(data), 8000)]
+	return bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(data)
+}
+
+// fencedFile formats one file for the prompt.
+func fencedFile(path string, data []byte) string {
+	return fencedBlock("File: "+filepath.ToSlash(path), fenceLanguage(path), string(data))
+}
+
+// fencedBlock puts text in a code fence under a header line. The fence is
+// made longer than any backtick run in the text so it can't close early.
+func fencedBlock(header, lang, text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	text = strings.TrimRight(text, "\n")
+	return fmt.Sprintf("%s\n%s%s\n%s\n%s\n", header, fence, lang, text, fence)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/webpage"
+)
+
+const (
+	// urlFetchTimeout bounds fetching one --url page.
+	urlFetchTimeout = 30 * time.Second
+	// maxPageRunes caps the text kept from each page; past this a page is
+	// more boilerplate than answer and crowds out the question.
+	maxPageRunes = 60000
+)
+
+var flagURLs []string
+
+func init() {
+	rootCmd.PersistentFlags().StringArrayVar(&flagURLs, "url", nil, "Fetch a web page and include its text in the prompt (repeatable)")
+}
+
+// withURLs fetches each --url and puts its readable text ahead of the
+// question, so providers without browsing can answer about the page.
+func withURLs(query string) (string, error) {
+	if len(flagURLs) == 0 {
+		return query, nil
+	}
+	var b strings.Builder
+	for _, u := range flagURLs {
+		if !strings.Contains(u, "://") {
+			u = "https://" + u
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), urlFetchTimeout)
+		page, err := webpage.Fetch(ctx, u, globalCfg.UserAgent, globalCfg.Proxy, 0)
+		cancel()
+		if err != nil {
+			return "", fmt.Errorf("--url: %w", err)
+		}
+		text := page.Text
+		if text == "" {
+			return "", fmt.Errorf("--url: no readable text at %s", page.URL)
+		}
+		if truncated := truncateRunes(text, maxPageRunes); truncated != text {
+			fmt.Fprintf(os.Stderr, "[url] %s is long; only the first %d characters are included\n", page.URL, maxPageRunes)
+			text = truncated
+		}
+		header := "Page: " + page.URL
+		if page.Title != "" {
+			header += " (" + page.Title + ")"
+		}
+		b.WriteString(fencedBlock(header, "", text))
+		b.WriteString("\n")
+	}
+	b.WriteString(query)
+	return b.String(), nil
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/provider"
+	"github.com/kyupark/ask/internal/redact"
+)
+
+var (
+	flagRedact         bool
+	flagShowRedactions bool
+	flagSystem         string
+	flagEdit           bool
+	flagEditTemplate   string
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagRedact, "redact", false, "Redact secrets and personal data from the prompt, system prompt and attachments before sending")
+	rootCmd.PersistentFlags().BoolVar(&flagShowRedactions, "show-redactions", false, "Preview the redacted prompt, system prompt and attachments without sending them")
+	rootCmd.PersistentFlags().StringVar(&flagSystem, "system", "", "Instructions to follow in every answer (overrides system_prompt config)")
+	rootCmd.PersistentFlags().BoolVar(&flagEdit, "edit", false, "Write the prompt in $EDITOR before sending")
+	rootCmd.PersistentFlags().StringVar(&flagEditTemplate, "edit-template", "", "File to start the --edit buffer from (overrides edit_template config)")
+}
+
+// questionArgs requires a question unless --edit will supply one.
+func questionArgs(cmd *cobra.Command, args []string) error {
+	if flagEdit {
+		return nil
+	}
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
+
+// prepareQuery builds the outbound prompt from the question args, or from
+// the editor with --edit, adds any --context, --file, --exec and --url
+// contents, and applies redaction. send is false when the invocation was only a preview.
+// The system prompt and attachments are redacted as the ask is sent (see
+// redactOptions); the preview shows them too.
+func prepareQuery(args []string) (query string, send bool, err error) {
+	query = strings.Join(args, " ")
+	if flagEdit {
+		query, err = editPrompt(query)
+		if err != nil {
+			return "", false, err
+		}
+	}
+	query, err = withContext(query)
+	if err != nil {
+		return "", false, err
+	}
+	query, err = withFiles(query)
+	if err != nil {
+		return "", false, err
+	}
+	query, err = withExec(query)
+	if err != nil {
+		return "", false, err
+	}
+	query, err = withURLs(query)
+	if err != nil {
+		return "", false, err
+	}
+
+	query, err = redactOutbound(query)
+	if err != nil {
+		return "", false, err
+	}
+
+	if flagShowRedactions {
+		fmt.Println(query)
+		return query, false, previewRedactions()
+	}
+	return query, true, nil
+}
+
+// applySystemPrompt sets the standing instructions for a provider: --system
+// first, then the provider's system_prompt, then the global one.
+func applySystemPrompt(providerName string, opts *provider.AskOptions) {
+	sp := flagSystem
+	if sp == "" {
+		switch providerName {
+		case "anthropic-api":
+			sp = globalCfg.AnthropicAPI.SystemPrompt
+		case "chatgpt":
+			sp = globalCfg.ChatGPT.SystemPrompt
+		case "claude":
+			sp = globalCfg.Claude.SystemPrompt
+		case "deepseek":
+			sp = globalCfg.DeepSeek.SystemPrompt
+		case "gemini":
+			sp = globalCfg.Gemini.SystemPrompt
+		case "grok":
+			sp = globalCfg.Grok.SystemPrompt
+		case "lechat":
+			sp = globalCfg.LeChat.SystemPrompt
+		case "ollama":
+			sp = globalCfg.Ollama.SystemPrompt
+		case "perplexity":
+			sp = globalCfg.Perplexity.SystemPrompt
+		}
+	}
+	if sp == "" {
+		sp = globalCfg.SystemPrompt
+	}
+	opts.SystemPrompt = sp
+}
+
+// redactionEnabled reports whether outbound text is redacted, by config or
+// flag.
+func redactionEnabled() bool {
+	return globalCfg.Redact.Enabled || flagRedact || flagShowRedactions
+}
+
+// redactOutbound applies the configured redaction rules to text destined for
+// a provider. It is a no-op unless redaction is enabled in config or via flags.
+func redactOutbound(text string) (string, error) {
+	return redactLabeled("", text)
+}
+
+// redactLabeled is redactOutbound for a part of the ask other than the
+// prompt, such as the system prompt or an attachment, which label names
+// in the findings.
+func redactLabeled(label, text string) (string, error) {
+	if !redactionEnabled() {
+		return text, nil
+	}
+
+	r, err := redact.New(globalCfg.Redact.Rules, globalCfg.Redact.Patterns)
+	if err != nil {
+		return "", err
+	}
+
+	redacted, findings := r.Apply(text)
+	if flagShowRedactions || globalCfg.Verbose {
+		prefix := "[redact] "
+		if label != "" {
+			prefix += label + ": "
+		}
+		if len(findings) == 0 {
+			fmt.Fprintln(os.Stderr, prefix+"nothing to redact")
+		}
+		for _, f := range findings {
+			fmt.Fprintf(os.Stderr, "%s%s: %d match(es)\n", prefix, f.Rule, f.Count)
+		}
+	}
+	return redacted, nil
+}
+
+// redactOptions redacts what an ask sends besides the prompt: the system
+// prompt, and each attachment, which is swapped for a redacted copy in a
+// temporary directory that cleanup removes. Binary attachments can't be
+// redacted, so an ask with one is refused.
+func redactOptions(opts *provider.AskOptions) (cleanup func(), err error) {
+	cleanup = func() {}
+	if !redactionEnabled() {
+		return cleanup, nil
+	}
+	if opts.SystemPrompt, err = redactLabeled("system prompt", opts.SystemPrompt); err != nil {
+		return cleanup, err
+	}
+	if len(opts.Attachments) == 0 {
+		return cleanup, nil
+	}
+
+	dir, err := os.MkdirTemp("", "ask-redacted-")
+	if err != nil {
+		return cleanup, err
+	}
+	copies := make([]string, len(opts.Attachments))
+	for i, path := range opts.Attachments {
+		text, err := redactAttachment(path)
+		if err != nil {
+			os.RemoveAll(dir)
+			return cleanup, err
+		}
+		// One directory per file keeps the names the provider sees.
+		sub := filepath.Join(dir, strconv.Itoa(i))
+		copies[i] = filepath.Join(sub, filepath.Base(path))
+		if err := os.Mkdir(sub, 0o700); err == nil {
+			err = os.WriteFile(copies[i], []byte(text), 0o600)
+		}
+		if err != nil {
+			os.RemoveAll(dir)
+			return cleanup, err
+		}
+	}
+	opts.Attachments = copies
+	return func() { os.RemoveAll(dir) }, nil
+}
+
+// redactAttachment returns the redacted contents of a text attachment.
+func redactAttachment(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("--attach: %w", err)
+	}
+	if isBinary(data) {
+		return "", fmt.Errorf("--attach: %s looks binary and can't be redacted; leave it out or ask without redaction", path)
+	}
+	return redactLabeled(filepath.Base(path), string(data))
+}
+
+// previewRedactions prints, for --show-redactions, the system prompt and
+// --attach files the command would send, redacted.
+func previewRedactions() error {
+	var opts provider.AskOptions
+	applySystemPrompt(commandProvider(), &opts)
+	if currentCmd != nil && currentCmd.Flags().Lookup("attach") != nil {
+		opts.Attachments, _ = currentCmd.Flags().GetStringArray("attach")
+	}
+	if opts.SystemPrompt != "" {
+		sp, err := redactLabeled("system prompt", opts.SystemPrompt)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n--- system prompt ---\n%s\n", sp)
+	}
+	for _, path := range opts.Attachments {
+		text, err := redactAttachment(path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n--- attachment: %s ---\n%s\n", path, strings.TrimRight(text, "\n"))
+	}
+	return nil
+}
+
+// commandProvider returns the provider the running command belongs to,
+// or "" for commands outside one.
+func commandProvider() string {
+	for c := currentCmd; c != nil && c.HasParent(); c = c.Parent() {
+		if !c.Parent().HasParent() && slices.Contains(providerNames, c.Name()) {
+			return c.Name()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+func runRename(ctx context.Context, p provider.Provider, conversationID, title string) error {
+	conversationID = resolveConversationID(p.Name(), conversationID)
+	if conversationID == "" {
+		return fmt.Errorf("conversation ID is required")
+	}
+
+	renamer, ok := p.(provider.Renamer)
+	if !ok {
+		return fmt.Errorf("%s does not support renaming conversations", p.Name())
+	}
+
+	autoLoadCookies(ctx, p)
+
+	if err := renamer.RenameConversation(ctx, conversationID, title, updateOptions()); err != nil {
+		return err
+	}
+
+	fmt.Printf("Renamed conversation %s to %q\n", conversationID, strings.TrimSpace(title))
+	return nil
+}
+
+// updateOptions returns UpdateOptions honoring the global verbose flag.
+func updateOptions() provider.UpdateOptions {
+	opts := provider.UpdateOptions{Verbose: globalCfg.Verbose}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf("")
+	}
+	return opts
+}
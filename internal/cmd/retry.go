@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+)
+
+// retryLast is set by a retry subcommand so the provider's ask regenerates
+// the last answer instead of adding a turn.
+var retryLast bool
+
+// newRetryCmd builds "<provider> retry", which asks again for an answer to
+// the last question of the provider's saved conversation. ChatGPT and
+// Claude regenerate the answer in place, as their web UIs do; the others
+// send the question again as a new turn.
+func newRetryCmd(providerName string, resume *bool, ask func(*cobra.Command, []string, bool) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry",
+		Short: fmt.Sprintf("Get a new answer to the last %s question", providerName),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conv := lastConversation(config.LoadState(), providerName)
+			if conv == nil || conv.ConversationID == "" {
+				return fmt.Errorf("no %s conversation to retry", providerName)
+			}
+			if conv.Question == "" {
+				return fmt.Errorf("the last %s question was not saved — ask it again instead", providerName)
+			}
+			*resume = true
+			retryLast = true
+			return ask(cmd, []string{conv.Question}, false)
+		},
+	}
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// maxReviewChunkBytes is how much diff goes into one review request.
+// Bigger diffs are split between files; a file bigger than this is cut.
+const maxReviewChunkBytes = 48 << 10
+
+var (
+	reviewProvider string
+	reviewStaged   bool
+	reviewJSON     bool
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [ref]",
+	Short: "Review a git diff and list findings per file",
+	Long: `Send a git diff to a provider for code review and print what it finds,
+grouped by file. Without arguments the uncommitted changes are reviewed
+(git diff HEAD); --staged reviews only the staged ones, and a ref or range
+reviews the changes since it.
+
+Large diffs are split between files into several requests.
+
+  ask review
+  ask review --staged -p claude
+  ask review main...HEAD --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReview,
+}
+
+func init() {
+	reviewCmd.Flags().StringVarP(&reviewProvider, "provider", "p", "", "Provider that reviews (default: default_provider)")
+	reviewCmd.Flags().BoolVar(&reviewStaged, "staged", false, "Review the staged changes only")
+	reviewCmd.Flags().BoolVar(&reviewJSON, "json", false, "Print the findings as JSON")
+	rootCmd.AddCommand(reviewCmd)
+}
+
+// reviewFinding is one problem the reviewer reports.
+type reviewFinding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	if reviewStaged && len(args) > 0 {
+		return fmt.Errorf("--staged and a ref are mutually exclusive")
+	}
+	name, err := toolProvider(reviewProvider)
+	if err != nil {
+		return err
+	}
+	gitArgs := []string{"diff", "--no-color", "--no-ext-diff"}
+	switch {
+	case reviewStaged:
+		gitArgs = append(gitArgs, "--cached")
+	case len(args) == 1:
+		gitArgs = append(gitArgs, args[0])
+	default:
+		gitArgs = append(gitArgs, "HEAD")
+	}
+	diff, err := gitOutput(gitArgs...)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return fmt.Errorf("no changes to review")
+	}
+
+	chunks := chunkDiff(diff, maxReviewChunkBytes)
+	findings := []reviewFinding{}
+	for i, chunk := range chunks {
+		if len(chunks) > 1 {
+			fmt.Fprintf(os.Stderr, "[review] %s is reviewing part %d of %d...\n", name, i+1, len(chunks))
+		} else {
+			fmt.Fprintf(os.Stderr, "[review] %s is reviewing...\n", name)
+		}
+		prompt, err := redactOutbound(reviewPrompt(chunk))
+		if err != nil {
+			return err
+		}
+		answer, err := askOnce(cmd.Context(), name, prompt, nil)
+		if err != nil {
+			return err
+		}
+		found, err := parseFindings(answer)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		findings = append(findings, found...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+
+	if reviewJSON {
+		data, err := json.MarshalIndent(struct {
+			Provider string          `json:"provider"`
+			Findings []reviewFinding `json:"findings"`
+		}{name, findings}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	printFindings(findings)
+	return nil
+}
+
+// chunkDiff splits a unified diff between files into pieces of about
+// limit bytes. A single file over the limit is cut in the middle.
+func chunkDiff(diff string, limit int) []string {
+	var files []string
+	for _, part := range strings.SplitAfter(diff, "\ndiff --git ") {
+		if len(files) > 0 {
+			part = "diff --git " + part
+		}
+		files = append(files, strings.TrimSuffix(part, "diff --git "))
+	}
+	var chunks []string
+	var cur strings.Builder
+	for _, f := range files {
+		f = truncateMiddle(f, limit)
+		if cur.Len() > 0 && cur.Len()+len(f) > limit {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+		}
+		cur.WriteString(f)
+	}
+	if cur.Len() > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+// reviewPrompt asks for findings as a JSON array so they can be grouped.
+func reviewPrompt(diff string) string {
+	var b strings.Builder
+	b.WriteString("Review this code change as an experienced reviewer. Report bugs, security problems, ")
+	b.WriteString("race conditions, error handling gaps, and unclear or risky code. Skip style nits and ")
+	b.WriteString("praise; report nothing if the change is fine.\n\n")
+	b.WriteString("Reply with a JSON array only, no prose and no code fences, where each element is\n")
+	b.WriteString(`{"file": "path as in the diff", "line": line number in the new file or 0, "severity": "error" | "warning" | "info", "message": "what is wrong and how to fix it"}`)
+	b.WriteString("\nReply [] when there is nothing to report.\n\nDiff:\n")
+	b.WriteString(diff)
+	return b.String()
+}
+
+// parseFindings reads the JSON array out of an answer, tolerating the
+// prose or code fences some providers put around it.
+func parseFindings(answer string) ([]reviewFinding, error) {
+	start, end := strings.Index(answer, "["), strings.LastIndex(answer, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("review answer has no findings list:\n%s", answer)
+	}
+	var findings []reviewFinding
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &findings); err != nil {
+		return nil, fmt.Errorf("reading review findings: %w\n%s", err, answer)
+	}
+	for i := range findings {
+		findings[i].Severity = strings.ToLower(findings[i].Severity)
+		if findings[i].Severity == "" {
+			findings[i].Severity = "info"
+		}
+	}
+	return findings, nil
+}
+
+func printFindings(findings []reviewFinding) {
+	if len(findings) == 0 {
+		fmt.Println("No findings.")
+		return
+	}
+	file := "\x00"
+	for _, f := range findings {
+		if f.File != file {
+			if file != "\x00" {
+				fmt.Println()
+			}
+			file = f.File
+			if file == "" {
+				fmt.Println("(general)")
+			} else {
+				fmt.Println(file)
+			}
+		}
+		loc := "-"
+		if f.Line > 0 {
+			loc = fmt.Sprint(f.Line)
+		}
+		fmt.Printf("  %5s  %-7s  %s\n", loc, f.Severity, f.Message)
+	}
+}
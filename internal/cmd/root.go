@@ -3,24 +3,37 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/kyupark/ask/internal/config"
 	"github.com/kyupark/ask/internal/cookies"
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/logging"
 	"github.com/kyupark/ask/internal/provider"
 )
 
 var (
-	globalCfg   *config.Config
-	flagVerbose bool
+	globalCfg     *config.Config
+	flagVerbose   bool
+	flagLogLevel  string
+	flagLogFile   string
+	flagLogFormat string
+	flagTraceHAR  string
+	flagVCRRecord string
+	flagVCRReplay string
+
+	// closeLog closes the --log-file once the command is done.
+	closeLog = func() error { return nil }
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "ask",
-	Short: "Unified CLI for AI chatbots (Perplexity, ChatGPT, Gemini, Grok, Claude)",
+	Short: "Unified CLI for AI chatbots (Perplexity, ChatGPT, Gemini, Grok, Claude, DeepSeek, Le Chat, Ollama, Anthropic API)",
 	Long: `ask provides a single interface to multiple AI chatbots using
 browser cookie authentication. No API keys required.
 
@@ -30,28 +43,172 @@ Supported providers:
   gemini      — Google Gemini (batch RPC)
   grok        — Grok / X.com (NDJSON streaming)
   claude      — Claude.ai / Anthropic (SSE streaming)
+  deepseek    — DeepSeek (SSE streaming)
+  lechat      — Mistral Le Chat (streaming)
+  ollama      — local models via Ollama (NDJSON streaming, no cookies)
+  anthropic-api — Anthropic Messages API (SSE streaming, API key)
 Usage:
+  ask "your question"        (uses default_provider)
   ask perplexity "your question"
   ask chatgpt "your question"
   ask gemini "your question"
   ask all "compare providers"
   ask install-openclaw-skill
-Cookies are auto-extracted from Safari (preferred) or Chrome.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+Cookies are auto-extracted from Safari (preferred) or a Chromium-based
+browser (Chrome, Brave, Edge, Arc, Vivaldi, Chromium). Change the search
+order with: ask config set browsers chrome,brave
+
+Set the provider for bare questions with: ask config set default_provider chatgpt`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runDefaultProvider,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		currentCmd = cmd
 		globalCfg = config.Load()
 		if flagVerbose {
 			globalCfg.Verbose = true
 		}
+		if err := setupLogging(); err != nil {
+			return err
+		}
+		applyTimeouts()
+		if flagTraceHAR != "" {
+			httpclient.RecordHAR(Version)
+		}
+		return startCassette()
 	},
 }
 
 func init() {
-	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "Enable verbose output (same as --log-level debug)")
+	rootCmd.PersistentFlags().StringVar(&flagLogLevel, "log-level", "", "Log level: debug, info, warn, error (default warn)")
+	rootCmd.PersistentFlags().StringVar(&flagLogFile, "log-file", "", "Append logs to this file instead of stderr")
+	rootCmd.PersistentFlags().StringVar(&flagLogFormat, "log-format", "text", "Log format: text or json")
+	rootCmd.PersistentFlags().StringVar(&flagTraceHAR, "trace-har", "", "Record all HTTP traffic, secrets redacted, to this HAR file")
+	rootCmd.PersistentFlags().StringVar(&flagVCRRecord, "vcr-record", "", "Record sanitized HTTP exchanges to a replayable cassette")
+	rootCmd.PersistentFlags().StringVar(&flagVCRReplay, "vcr-replay", "", "Answer HTTP requests from a recorded cassette instead of the network")
+	_ = rootCmd.PersistentFlags().MarkHidden("vcr-record")
+	_ = rootCmd.PersistentFlags().MarkHidden("vcr-replay")
 }
 
 // Execute runs the root command.
 func Execute() error {
-	return rootCmd.Execute()
+	registerCompletions()
+	defer func() { closeLog() }()
+	ctx, stop := interruptContext()
+	defer stop()
+	err := rootCmd.ExecuteContext(ctx)
+	if err != nil {
+		printErrorHint(err)
+	}
+	printStats()
+	if herr := httpclient.StopCassette(); herr != nil {
+		slog.Warn("writing cassette failed", "path", flagVCRRecord, "err", herr)
+	}
+	if flagTraceHAR != "" {
+		if herr := httpclient.WriteHAR(flagTraceHAR); herr != nil {
+			slog.Warn("writing HAR file failed", "path", flagTraceHAR, "err", herr)
+		} else {
+			fmt.Fprintf(os.Stderr, "HTTP trace written to %s\n", flagTraceHAR)
+		}
+	}
+	return err
+}
+
+// startCassette turns on recording or replay for --vcr-record and
+// --vcr-replay, which capture provider exchanges for parser fixtures.
+func startCassette() error {
+	switch {
+	case flagVCRRecord != "" && flagVCRReplay != "":
+		return fmt.Errorf("--vcr-record and --vcr-replay are mutually exclusive")
+	case flagVCRRecord != "":
+		httpclient.RecordCassette(flagVCRRecord)
+	case flagVCRReplay != "":
+		return httpclient.ReplayCassette(flagVCRReplay)
+	}
+	return nil
+}
+
+// setupLogging installs the slog logger from the --log-* flags. Verbose
+// mode implies debug, and debug turns on verbose mode so provider logs
+// are collected.
+func setupLogging() error {
+	level := flagLogLevel
+	if level == "" && globalCfg.Verbose {
+		level = "debug"
+	}
+	closer, err := logging.Setup(logging.Options{Level: level, File: flagLogFile, Format: flagLogFormat})
+	if err != nil {
+		return err
+	}
+	closeLog = closer
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		globalCfg.Verbose = true
+	}
+	return nil
+}
+
+// debugLogf returns a provider log function that writes debug records
+// tagged with the provider name.
+func debugLogf(name string) func(string, ...any) {
+	logger := slog.Default()
+	if name != "" {
+		logger = logger.With("provider", name)
+	}
+	return logging.Logf(logger)
+}
+
+// runDefaultProvider answers `ask "question"` with the configured default
+// provider, as if `ask <provider> "question"` had been run.
+func runDefaultProvider(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !flagEdit {
+		return cmd.Help()
+	}
+	// A mistyped subcommand followed by a question is more likely than a
+	// question starting with a near-miss of one. Short words are left
+	// alone: "plan" is a question, not a typo of "play".
+	if len(args) > 1 && len(args[0]) >= 5 {
+		var suggestions []string
+		for _, c := range cmd.Commands() {
+			if c.IsAvailableCommand() && editDistance(strings.ToLower(args[0]), c.Name()) <= 2 {
+				suggestions = append(suggestions, c.Name())
+			}
+		}
+		if len(suggestions) > 0 {
+			return fmt.Errorf("unknown command %q for \"ask\"\n\nDid you mean this?\n\t%s", args[0], strings.Join(suggestions, "\n\t"))
+		}
+	}
+
+	name := globalCfg.DefaultProvider
+	if name == "" {
+		return fmt.Errorf("no default provider — run: ask config set default_provider chatgpt (or use ask <provider> \"question\")")
+	}
+	target, _, err := cmd.Find([]string{name})
+	if err != nil || target == cmd || target.RunE == nil {
+		return fmt.Errorf("default_provider %q is not a provider", name)
+	}
+	target.SetContext(cmd.Context())
+	return target.RunE(target, args)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
 }
 
 // autoLoadCookies extracts cookies for the provider from browsers if needed.
@@ -63,9 +220,7 @@ func autoLoadCookies(ctx context.Context, p provider.Provider) {
 
 	logf := func(string, ...any) {}
 	if globalCfg.Verbose {
-		logf = func(format string, args ...any) {
-			fmt.Fprintf(os.Stderr, format+"\n", args...)
-		}
+		logf = debugLogf(p.Name())
 	}
 
 	// Convert provider.CookieSpec to cookies.Spec.
@@ -77,27 +232,69 @@ func autoLoadCookies(ctx context.Context, p provider.Provider) {
 		})
 	}
 
-	result, err := cookies.ExtractMulti(ctx, cookieSpecs, logf)
+	result, err := cookies.ExtractMulti(ctx, cookieSpecs, globalCfg.Browsers, logf)
 	if err != nil {
-		if globalCfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[autoload] cookie extraction error: %v\n", err)
-		}
+		slog.Debug("cookie extraction failed", "provider", p.Name(), "err", err)
 		return
 	}
 
 	if len(result.Cookies) > 0 {
 		p.SetCookies(result.Cookies)
-		if globalCfg.Verbose {
-			fmt.Fprintf(os.Stderr, "[autoload] loaded %d cookies from %s\n", len(result.Cookies), result.Browser)
-		}
+		slog.Debug("loaded cookies", "provider", p.Name(), "count", len(result.Cookies), "browser", result.Browser)
 	}
 }
 
-// providerTimeout returns the configured timeout as time.Duration.
+// providerProxy returns the proxy URL for a provider: its own proxy key,
+// then the global one. Empty leaves the choice to the environment.
+func providerProxy(name string) string {
+	var proxy string
+	switch name {
+	case "chatgpt":
+		proxy = globalCfg.ChatGPT.Proxy
+	case "claude":
+		proxy = globalCfg.Claude.Proxy
+	case "deepseek":
+		proxy = globalCfg.DeepSeek.Proxy
+	case "gemini":
+		proxy = globalCfg.Gemini.Proxy
+	case "grok":
+		proxy = globalCfg.Grok.Proxy
+	case "lechat":
+		proxy = globalCfg.LeChat.Proxy
+	case "ollama":
+		proxy = globalCfg.Ollama.Proxy
+	case "anthropic-api":
+		proxy = globalCfg.AnthropicAPI.Proxy
+	case "perplexity":
+		proxy = globalCfg.Perplexity.Proxy
+	}
+	if proxy != "" {
+		return proxy
+	}
+	return globalCfg.Proxy
+}
+
+// providerTimeout is the overall cap on one question, or zero for none.
+// Stalls are caught by the per-phase timeouts applied in applyTimeouts.
 func providerTimeout() time.Duration {
-	timeout := time.Duration(globalCfg.Timeout) * time.Second
-	if timeout < 3*time.Minute {
-		return 3 * time.Minute
+	return time.Duration(globalCfg.Timeout) * time.Second
+}
+
+// withProviderTimeout bounds ctx by providerTimeout, if one is set.
+func withProviderTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout := providerTimeout(); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
 	}
-	return timeout
+	return context.WithCancel(ctx)
+}
+
+// applyTimeouts hands the connect, response-header and stream-idle
+// timeouts from config to the HTTP clients.
+func applyTimeouts() {
+	t := globalCfg.Timeouts
+	httpclient.SetTimeouts(httpclient.Timeouts{
+		Connect:        time.Duration(t.Connect) * time.Second,
+		ResponseHeader: time.Duration(t.ResponseHeader) * time.Second,
+		StreamIdle:     time.Duration(t.StreamIdle) * time.Second,
+	})
 }
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var (
+	serveAddr   string
+	serveAPIKey string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve an OpenAI-compatible chat completions API",
+	Long: `Start a local HTTP server exposing OpenAI-compatible endpoints backed by
+your browser sessions:
+
+  POST /v1/chat/completions   (streaming and non-streaming)
+  GET  /v1/models
+
+Select a backend with the model field, as "<provider>" or "<provider>:<model>":
+  chatgpt:gpt-5-2, claude:claude-opus-4-6, perplexity:pplx_pro, gemini, grok
+
+Requests are sent in incognito mode and do not touch local conversation state.`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveAPIKey, "api-key", "", "Require this bearer token on every request")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// serveBackend is a lazily initialised provider shared across requests.
+// Requests to the same provider are serialised because the web sessions
+// behind them are not safe for concurrent use.
+type serveBackend struct {
+	mu    sync.Mutex
+	p     provider.Provider
+	model string
+}
+
+type serveState struct {
+	mu       sync.Mutex
+	backends map[string]*serveBackend
+}
+
+type chatMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+type chatCompletionChoice struct {
+	Index        int             `json:"index"`
+	Message      *chatOutMessage `json:"message,omitempty"`
+	Delta        *chatOutMessage `json:"delta,omitempty"`
+	FinishReason *string         `json:"finish_reason"`
+}
+
+type chatOutMessage struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+type chatCompletionResponse struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int64                  `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []chatCompletionChoice `json:"choices"`
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	s := &serveState{backends: make(map[string]*serveBackend)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/chat/completions", s.handleChatCompletions)
+	mux.HandleFunc("GET /v1/models", s.handleModels)
+
+	var handler http.Handler = mux
+	if serveAPIKey != "" {
+		handler = requireBearer(serveAPIKey, mux)
+	}
+
+	fmt.Fprintf(os.Stderr, "Listening on http://%s/v1\n", serveAddr)
+	srv := &http.Server{Addr: serveAddr, Handler: handler}
+	go func() {
+		<-cmd.Context().Done()
+		_ = srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func requireBearer(key string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+key {
+			writeAPIError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// backend returns the shared provider for name, creating it and loading
+// browser cookies on first use.
+func (s *serveState) backend(ctx context.Context, name string) (*serveBackend, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b := s.backends[name]; b != nil {
+		return b, nil
+	}
+	p, model, err := newProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	autoLoadCookies(ctx, p)
+	b := &serveBackend{p: p, model: model}
+	s.backends[name] = b
+	return b, nil
+}
+
+func (s *serveState) handleModels(w http.ResponseWriter, r *http.Request) {
+	type modelEntry struct {
+		ID      string `json:"id"`
+		Object  string `json:"object"`
+		OwnedBy string `json:"owned_by"`
+	}
+	var data []modelEntry
+	for _, e := range askAllEntries() {
+		name := e.p.Name()
+		data = append(data, modelEntry{ID: name, Object: "model", OwnedBy: name})
+		if ml, ok := e.p.(provider.ModelLister); ok {
+			for _, m := range ml.ListModels().Models {
+				data = append(data, modelEntry{ID: name + ":" + m.ID, Object: "model", OwnedBy: name})
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": data})
+}
+
+func (s *serveState) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	var req chatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	name, model, _ := strings.Cut(req.Model, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	b, err := s.backend(r.Context(), name)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if model == "" {
+		model = b.model
+	}
+
+	query := flattenMessages(req.Messages)
+	if strings.TrimSpace(query) == "" {
+		writeAPIError(w, http.StatusBadRequest, "messages must contain text content")
+		return
+	}
+	query, err = redactOutbound(query)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := chatCompletionResponse{
+		ID:      fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano()),
+		Created: time.Now().Unix(),
+		Model:   name + ":" + model,
+	}
+
+	opts := provider.AskOptions{
+		Model:     model,
+		Verbose:   globalCfg.Verbose,
+		Temporary: true,
+	}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf(name)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !req.Stream {
+		var text strings.Builder
+		opts.OnText = func(t string) { text.WriteString(t) }
+		err := sendPrompt(r.Context(), b.p, query, opts)
+		if err != nil && text.Len() == 0 {
+			writeAPIError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		// An answer cut short by an error must not pass for a whole one.
+		finish := "stop"
+		if err != nil {
+			slog.Warn("answer cut short", "provider", name, "err", err)
+			finish = "length"
+		}
+		resp.Object = "chat.completion"
+		resp.Choices = []chatCompletionChoice{{
+			Message:      &chatOutMessage{Role: "assistant", Content: text.String()},
+			FinishReason: &finish,
+		}}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	resp.Object = "chat.completion.chunk"
+	writeChunk := func(choice chatCompletionChoice) {
+		resp.Choices = []chatCompletionChoice{choice}
+		data, _ := json.Marshal(resp)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	writeChunk(chatCompletionChoice{Delta: &chatOutMessage{Role: "assistant"}})
+	opts.OnText = func(t string) {
+		writeChunk(chatCompletionChoice{Delta: &chatOutMessage{Content: t}})
+	}
+	finish := "stop"
+	if err := sendPrompt(r.Context(), b.p, query, opts); err != nil {
+		// Headers are already sent; report the failure in-band.
+		slog.Warn("answer cut short", "provider", name, "err", err)
+		writeChunk(chatCompletionChoice{Delta: &chatOutMessage{Content: "\n[error] " + err.Error()}})
+		finish = "length"
+	}
+	writeChunk(chatCompletionChoice{Delta: &chatOutMessage{}, FinishReason: &finish})
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// flattenMessages turns an OpenAI message list into a single prompt. The web
+// providers keep no server-side state for these requests, so earlier turns
+// are replayed as a labelled transcript.
+func flattenMessages(msgs []chatMessage) string {
+	if len(msgs) == 1 {
+		return messageText(msgs[0].Content)
+	}
+	var sb strings.Builder
+	for _, m := range msgs {
+		text := messageText(m.Content)
+		if text == "" {
+			continue
+		}
+		role := m.Role
+		if role == "" {
+			role = "user"
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(strings.ToUpper(role[:1]) + role[1:] + ": " + text)
+	}
+	return sb.String()
+}
+
+// messageText extracts text from a message content field, which may be a
+// plain string or an array of typed content parts.
+func messageText(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return ""
+	}
+	var texts []string
+	for _, p := range parts {
+		if p.Type == "text" && p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]string{"message": msg, "type": "invalid_request_error"},
+	})
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Manage locally tracked conversation sessions",
+}
+
+var sessionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the resumable session for each provider",
+	Args:  cobra.NoArgs,
+	RunE:  runSessionsList,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsListCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
+
+func runSessionsList(cmd *cobra.Command, args []string) error {
+	state := config.LoadState()
+	if len(state.LastConversation) == 0 {
+		fmt.Println("No sessions found.")
+		return nil
+	}
+
+	names := make([]string, 0, len(state.LastConversation))
+	for name, conv := range state.LastConversation {
+		if conv != nil && conv.ConversationID != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := state.LastConversation[names[i]], state.LastConversation[names[j]]
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.After(b.UpdatedAt)
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Printf("Found %d session(s):\n\n", len(names))
+	for _, name := range names {
+		conv := state.LastConversation[name]
+		title := conv.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		fmt.Printf("  [%s] %s\n", name, title)
+		fmt.Printf("    ID: %s\n", conv.ConversationID)
+		if !conv.UpdatedAt.IsZero() {
+			fmt.Printf("    %s\n", formatTime(conv.UpdatedAt))
+		}
+		fmt.Printf("    Resume: ask %s -r \"follow up\"\n", name)
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// saveConversationState persists continuation context for a provider. The
+// session keeps its existing title when the conversation is unchanged;
+// otherwise a title is derived from the prompt that started it.
+func saveConversationState(providerName, query string, cs *config.ConversationState) {
+	_ = config.UpdateState(func(state *config.State) {
+		if cs.Title == "" {
+			if prev := state.GetConversation(providerName); prev != nil && prev.ConversationID == cs.ConversationID && prev.Title != "" {
+				cs.Title = prev.Title
+			} else {
+				cs.Title = sessionTitle(query)
+			}
+		}
+		if cs.Question == "" {
+			cs.Question = query
+		}
+		cs.UpdatedAt = time.Now()
+		state.SetConversation(providerName, cs)
+		if dir := resumeDir(); dir != "" {
+			state.SetDirConversation(dir, providerName, cs)
+		}
+	})
+}
+
+// saveFollowUps attaches suggested next questions to the saved state of
+// the conversation they were offered in.
+func saveFollowUps(providerName, conversationID string, followUps []string) {
+	_ = config.UpdateState(func(state *config.State) {
+		for _, conv := range []*config.ConversationState{
+			state.GetConversation(providerName),
+			state.GetDirConversation(resumeDir(), providerName),
+		} {
+			if conv != nil && conv.ConversationID == conversationID {
+				conv.FollowUps = followUps
+			}
+		}
+	})
+}
+
+// resumeDir is the directory --resume is scoped to when
+// resume_per_directory is set, and "" otherwise.
+func resumeDir() string {
+	if !globalCfg.ResumePerDirectory {
+		return ""
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// lastConversation returns the conversation --resume continues for a
+// provider: with resume_per_directory, the one last used in the working
+// directory or its nearest parent that has one; otherwise the last one
+// anywhere.
+func lastConversation(state *config.State, providerName string) *config.ConversationState {
+	dir := resumeDir()
+	if dir == "" {
+		return state.GetConversation(providerName)
+	}
+	for {
+		if conv := state.GetDirConversation(dir, providerName); conv != nil {
+			return conv
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// sessionTitle derives a short single-line title from a prompt.
+func sessionTitle(query string) string {
+	title := strings.Join(strings.Fields(query), " ")
+	const maxRunes = 60
+	r := []rune(title)
+	if len(r) <= maxRunes {
+		return title
+	}
+	return strings.TrimSpace(string(r[:maxRunes])) + "..."
+}
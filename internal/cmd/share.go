@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+func runShare(ctx context.Context, p provider.Provider, conversationID string) error {
+	conversationID = resolveConversationID(p.Name(), conversationID)
+	if conversationID == "" {
+		return fmt.Errorf("conversation ID is required")
+	}
+
+	sharer, ok := p.(provider.Sharer)
+	if !ok {
+		return fmt.Errorf("%s does not support sharing conversations", p.Name())
+	}
+
+	autoLoadCookies(ctx, p)
+
+	url, err := sharer.ShareConversation(ctx, conversationID, updateOptions())
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(url)
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// newShowCmd builds the show subcommand for a provider.
+func newShowCmd(providerName string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <conversation-id>",
+		Short: fmt.Sprintf("Show a %s conversation transcript", providerName),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, _, err := newProviderByName(providerName)
+			if err != nil {
+				return err
+			}
+			return runShow(cmd.Context(), p, args[0])
+		},
+	}
+}
+
+func runShow(ctx context.Context, p provider.Provider, conversationID string) error {
+	t, err := fetchTranscript(ctx, p, conversationID)
+	if err != nil {
+		return err
+	}
+	printTranscript(t)
+	return nil
+}
+
+// printTranscript writes a readable transcript to stdout.
+func printTranscript(t *provider.Transcript) {
+	title := t.Title
+	if title == "" {
+		title = "(untitled)"
+	}
+	fmt.Println(title)
+	fmt.Printf("ID: %s\n", t.ID)
+	if !t.CreatedAt.IsZero() {
+		fmt.Printf("Created: %s\n", formatTime(t.CreatedAt))
+	}
+
+	if len(t.Messages) == 0 {
+		fmt.Println()
+		fmt.Println("No messages found.")
+		return
+	}
+
+	for _, m := range t.Messages {
+		fmt.Println()
+		label := "You"
+		if m.Role != "user" {
+			label = "Assistant"
+			if m.Model != "" {
+				label += " (" + m.Model + ")"
+			}
+		}
+		if !m.CreatedAt.IsZero() {
+			label += " · " + formatTime(m.CreatedAt)
+		}
+		fmt.Printf("── %s ──\n", label)
+		fmt.Println(strings.TrimSpace(m.Text))
+		if len(m.Sources) > 0 {
+			fmt.Println()
+			fmt.Println("Sources:")
+			for i, src := range m.Sources {
+				name := src.Name
+				if name == "" {
+					name = src.URL
+				}
+				fmt.Printf("  [%d] %s\n", i+1, name)
+				fmt.Printf("      %s\n", src.URL)
+			}
+		}
+	}
+}
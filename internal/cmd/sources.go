@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// answerSource is a citation collected while an answer streams.
+type answerSource struct{ name, url string }
+
+// sourceList collects an answer's citations in the order they arrive,
+// once per page: URLs differing only in scheme, a leading "www.", a
+// trailing slash, a fragment or utm_ tracking parameters are the same.
+type sourceList struct {
+	list []answerSource
+	seen map[string]bool
+}
+
+func (s *sourceList) add(name, rawURL string) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return
+	}
+	key := sourceKey(rawURL)
+	if s.seen[key] {
+		return
+	}
+	if s.seen == nil {
+		s.seen = map[string]bool{}
+	}
+	s.seen[key] = true
+	name = strings.TrimSpace(name)
+	if name == "" || name == rawURL {
+		name = sourceDomain(rawURL)
+	}
+	s.list = append(s.list, answerSource{name, rawURL})
+}
+
+// sourceKey normalizes a URL for de-duplication.
+func sourceKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	q := u.Query()
+	for k := range q {
+		if strings.HasPrefix(k, "utm_") {
+			q.Del(k)
+		}
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	key := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if enc := q.Encode(); enc != "" {
+		key += "?" + enc
+	}
+	return key
+}
+
+// sourceDomain names a source by its host when it has no title.
+func sourceDomain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// printSources lists an answer's citations after it.
+func printSources(sources []answerSource) {
+	if len(sources) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Sources:")
+	for i, src := range sources {
+		fmt.Fprintf(os.Stderr, "  [%d] %s\n", i+1, src.name)
+		fmt.Fprintf(os.Stderr, "      %s\n", src.url)
+	}
+}
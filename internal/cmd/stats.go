@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var flagStats bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagStats, "stats", false, "Print time to first token, duration, chunk count and words after the answer")
+}
+
+// streamStats measures one answer as it streams.
+type streamStats struct {
+	provider string
+	start    time.Time
+	first    time.Time
+	end      time.Time
+	chunks   int
+	text     strings.Builder
+}
+
+var (
+	statsMu sync.Mutex
+	// answerStats holds every answer measured by this run, printed once
+	// the command is done.
+	answerStats []*streamStats
+)
+
+// trackStats wraps opts.OnText to measure the answer when --stats is set.
+// It returns nil otherwise; stop is safe to call on nil.
+func trackStats(providerName string, opts *provider.AskOptions) *streamStats {
+	if !flagStats {
+		return nil
+	}
+	s := &streamStats{provider: providerName, start: time.Now()}
+	onText := opts.OnText
+	opts.OnText = func(text string) {
+		statsMu.Lock()
+		if s.chunks == 0 {
+			s.first = time.Now()
+		}
+		s.chunks++
+		s.text.WriteString(text)
+		statsMu.Unlock()
+		if onText != nil {
+			onText(text)
+		}
+	}
+	statsMu.Lock()
+	answerStats = append(answerStats, s)
+	statsMu.Unlock()
+	return s
+}
+
+func (s *streamStats) stop() {
+	if s == nil {
+		return
+	}
+	statsMu.Lock()
+	s.end = time.Now()
+	statsMu.Unlock()
+}
+
+// printStats writes one line per measured answer to stderr.
+func printStats() {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	if len(answerStats) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr)
+	for _, s := range answerStats {
+		end := s.end
+		if end.IsZero() {
+			end = time.Now()
+		}
+		first := "-"
+		if !s.first.IsZero() {
+			first = formatStatsDuration(s.first.Sub(s.start))
+		}
+		words := len(strings.Fields(s.text.String()))
+		line := fmt.Sprintf("[%s] first token %s · total %s · %d chunks · ~%d words",
+			s.provider, first, formatStatsDuration(end.Sub(s.start)), s.chunks, words)
+		// A rate over a burst shorter than this says nothing.
+		if streamed := end.Sub(s.first); !s.first.IsZero() && streamed >= 100*time.Millisecond {
+			line += fmt.Sprintf(" (%.0f words/s)", float64(words)/streamed.Seconds())
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+}
+
+func formatStatsDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var flagStreamJSON bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagStreamJSON, "stream-json", false, "Write the response as newline-delimited JSON events on stdout")
+}
+
+// streamEvent is one line of --stream-json output.
+type streamEvent struct {
+	Type           string   `json:"type"` // text, thinking, source, image, file, media, progress, follow_ups, conversation, error, done
+	Provider       string   `json:"provider"`
+	Text           string   `json:"text,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	URL            string   `json:"url,omitempty"`
+	Path           string   `json:"path,omitempty"`
+	Suggestions    []string `json:"suggestions,omitempty"`
+	ConversationID string   `json:"conversation_id,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+var streamMu sync.Mutex
+
+func emitStreamEvent(ev streamEvent) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	streamMu.Lock()
+	defer streamMu.Unlock()
+	fmt.Fprintf(os.Stdout, "%s\n", data)
+}
+
+// applyStreamJSON rewires opts so that, with --stream-json, every callback
+// also emits an event. Text is emitted instead of being passed on, so the
+// command's own stdout printing is suppressed.
+func applyStreamJSON(providerName string, opts *provider.AskOptions) {
+	if !flagStreamJSON {
+		return
+	}
+
+	opts.OnText = func(text string) {
+		emitStreamEvent(streamEvent{Type: "text", Provider: providerName, Text: text})
+	}
+
+	if opts.OnThinking != nil {
+		opts.OnThinking = func(text string) {
+			emitStreamEvent(streamEvent{Type: "thinking", Provider: providerName, Text: text})
+		}
+	}
+
+	onSource := opts.OnSource
+	opts.OnSource = func(name, url string) {
+		emitStreamEvent(streamEvent{Type: "source", Provider: providerName, Name: name, URL: url})
+		if onSource != nil {
+			onSource(name, url)
+		}
+	}
+
+	onImage := opts.OnImage
+	opts.OnImage = func(img provider.GeneratedImage) {
+		emitStreamEvent(streamEvent{Type: "image", Provider: providerName, Name: img.Title, URL: img.URL, Path: img.Path})
+		if onImage != nil {
+			onImage(img)
+		}
+	}
+
+	onMedia := opts.OnMedia
+	opts.OnMedia = func(m provider.MediaResult) {
+		emitStreamEvent(streamEvent{Type: "media", Provider: providerName, Name: m.Title, URL: m.URL, Path: m.Path})
+		if onMedia != nil {
+			onMedia(m)
+		}
+	}
+
+	onFile := opts.OnFile
+	opts.OnFile = func(f provider.GeneratedFile) {
+		emitStreamEvent(streamEvent{Type: "file", Provider: providerName, Name: f.Name, URL: f.URL, Path: f.Path})
+		if onFile != nil {
+			onFile(f)
+		}
+	}
+
+	onProgress := opts.OnProgress
+	opts.OnProgress = func(status string) {
+		emitStreamEvent(streamEvent{Type: "progress", Provider: providerName, Text: status})
+		if onProgress != nil {
+			onProgress(status)
+		}
+	}
+
+	onFollowUps := opts.OnFollowUps
+	opts.OnFollowUps = func(suggestions []string) {
+		emitStreamEvent(streamEvent{Type: "follow_ups", Provider: providerName, Suggestions: suggestions})
+		if onFollowUps != nil {
+			onFollowUps(suggestions)
+		}
+	}
+
+	onConversation := opts.OnConversation
+	opts.OnConversation = func(conversationID, parentMessageID, responseID string) {
+		emitStreamEvent(streamEvent{Type: "conversation", Provider: providerName, ConversationID: conversationID})
+		if onConversation != nil {
+			onConversation(conversationID, parentMessageID, responseID)
+		}
+	}
+
+	onError := opts.OnError
+	opts.OnError = func(err error) {
+		emitStreamEvent(streamEvent{Type: "error", Provider: providerName, Error: err.Error()})
+		if onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// emitStreamError reports a failed ask when --stream-json is active.
+func emitStreamError(providerName string, err error) {
+	if flagStreamJSON && err != nil {
+		emitStreamEvent(streamEvent{Type: "error", Provider: providerName, Error: err.Error()})
+	}
+}
+
+// finishAnswer ends the answer on stdout: a trailing newline for plain
+// output or a done event for --stream-json.
+func finishAnswer(providerName string) {
+	if flagStreamJSON {
+		emitStreamEvent(streamEvent{Type: "done", Provider: providerName})
+		return
+	}
+	fmt.Println()
+}
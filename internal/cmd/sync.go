@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/history"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var (
+	syncProviders []string
+	syncFull      bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Mirror conversation lists into the local history database",
+	Long: `Copy the ID, title and timestamps of every remote conversation into the
+local history database, so ` + "`ask history search`" + ` finds them offline:
+
+  ask sync
+  ask sync -p chatgpt -p claude
+
+Each run pages through the newest conversations only until it reaches a
+page it has already mirrored unchanged; providers that don't report update
+times (grok) are listed in full. --full lists everything again and
+forgets conversations deleted on the provider's side.`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().StringSliceVarP(&syncProviders, "provider", "p", nil, "Provider to sync (repeatable; default: every provider in ask all)")
+	syncCmd.Flags().BoolVar(&syncFull, "full", false, "List every conversation and drop deleted ones")
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	var providers []provider.Provider
+	if len(syncProviders) > 0 {
+		for _, name := range syncProviders {
+			if !slices.Contains(providerNames, name) {
+				return fmt.Errorf("unknown provider %q (use %s)", name, strings.Join(providerNames, ", "))
+			}
+			p, _, err := newProviderByName(name)
+			if err != nil {
+				return err
+			}
+			providers = append(providers, p)
+		}
+	} else {
+		for _, e := range askAllEntries() {
+			if _, ok := e.p.(provider.Lister); ok {
+				providers = append(providers, e.p)
+			}
+		}
+	}
+
+	store, err := history.Open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var errs []error
+	for _, p := range providers {
+		if err := syncProvider(cmd.Context(), store, p); err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] %v\n", p.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		}
+		if cmd.Context().Err() != nil {
+			break
+		}
+	}
+	// Without -p a provider the user is not signed in to is expected to
+	// fail; only report failure when nothing synced at all.
+	if len(syncProviders) == 0 && len(errs) < len(providers) {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// syncProvider mirrors p's conversation list, newest first, stopping at
+// the first page that holds nothing new unless --full is set or the
+// provider doesn't report update times.
+func syncProvider(ctx context.Context, store *history.Store, p provider.Provider) error {
+	lister, ok := p.(provider.Lister)
+	if !ok {
+		return fmt.Errorf("%s does not support listing conversations", p.Name())
+	}
+	known, err := store.MirroredConversations(p.Name())
+	if err != nil {
+		return err
+	}
+
+	autoLoadCookies(ctx, p)
+	opts := provider.ListOptions{Limit: listAllPage, Verbose: globalCfg.Verbose}
+	if globalCfg.Verbose {
+		opts.LogFunc = debugLogf(p.Name())
+	}
+
+	seen := map[string]bool{}
+	var added, updated int
+	for opts.Offset = 0; ; opts.Offset += opts.Limit {
+		page, err := lister.ListConversations(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("listing conversations: %w", err)
+		}
+		now := time.Now()
+		var changed []history.Conversation
+		fresh := 0
+		// A page with conversations lacking update times (Grok's) can't
+		// show that older pages are unchanged too.
+		undated := false
+		for _, c := range page {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			fresh++
+			undated = undated || c.UpdatedAt.IsZero()
+			prev, ok := known[c.ID]
+			if ok && syncUnchanged(c, prev) {
+				continue
+			}
+			if ok {
+				updated++
+			} else {
+				added++
+			}
+			changed = append(changed, history.Conversation{
+				Provider:  p.Name(),
+				ID:        c.ID,
+				Title:     c.Title,
+				CreatedAt: c.CreatedAt,
+				UpdatedAt: c.UpdatedAt,
+				SyncedAt:  now,
+			})
+		}
+		if err := store.SaveConversations(changed); err != nil {
+			return err
+		}
+		if len(page) < opts.Limit || fresh == 0 || (len(changed) == 0 && !syncFull && !undated) {
+			break
+		}
+	}
+
+	summary := fmt.Sprintf("%d new, %d updated", added, updated)
+	if syncFull {
+		removed, err := store.PruneConversations(p.Name(), seen)
+		if err != nil {
+			return err
+		}
+		summary += fmt.Sprintf(", %d removed", removed)
+	}
+	fmt.Fprintf(os.Stderr, "[%s] %s\n", p.Name(), summary)
+	return nil
+}
+
+// syncUnchanged reports whether c matches its mirrored copy prev. Times
+// are compared to the millisecond the database keeps; the title catches
+// renames in listings without update times.
+func syncUnchanged(c provider.Conversation, prev history.Conversation) bool {
+	return c.Title == prev.Title &&
+		c.UpdatedAt.Truncate(time.Millisecond).Equal(prev.UpdatedAt) &&
+		c.CreatedAt.Truncate(time.Millisecond).Equal(prev.CreatedAt)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/history"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+func openTestHistory(t *testing.T) *history.Store {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store, err := history.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// fakeConversations returns n conversations, newest first, dated from
+// updated when it is set.
+func fakeConversations(n int, updated time.Time) []provider.Conversation {
+	var cs []provider.Conversation
+	for i := range n {
+		c := provider.Conversation{ID: fmt.Sprintf("c%d", i), Title: fmt.Sprintf("Title %d", i)}
+		if !updated.IsZero() {
+			c.UpdatedAt = updated.Add(-time.Duration(i) * time.Minute)
+		}
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+func TestSyncUndatedRenamed(t *testing.T) {
+	globalCfg = &config.Config{}
+	store := openTestHistory(t)
+	ctx := context.Background()
+
+	// Two pages of conversations without update times, as Grok lists them.
+	p := &fakeLister{conversations: fakeConversations(listAllPage+50, time.Time{})}
+	if err := syncProvider(ctx, store, p); err != nil {
+		t.Fatal(err)
+	}
+
+	p.conversations[listAllPage+10].Title = "Renamed"
+	p.pages = 0
+	if err := syncProvider(ctx, store, p); err != nil {
+		t.Fatal(err)
+	}
+	if p.pages != 2 {
+		t.Errorf("listed %d pages, want 2: an unchanged first page says nothing about undated ones", p.pages)
+	}
+	mirrored, err := store.MirroredConversations("fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mirrored[p.conversations[listAllPage+10].ID].Title; got != "Renamed" {
+		t.Errorf("mirrored title = %q, want the new one", got)
+	}
+}
+
+func TestSyncDatedStopsEarly(t *testing.T) {
+	globalCfg = &config.Config{}
+	store := openTestHistory(t)
+	ctx := context.Background()
+
+	updated := time.Date(2026, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	p := &fakeLister{conversations: fakeConversations(listAllPage+50, updated)}
+	if err := syncProvider(ctx, store, p); err != nil {
+		t.Fatal(err)
+	}
+	p.pages = 0
+	if err := syncProvider(ctx, store, p); err != nil {
+		t.Fatal(err)
+	}
+	if p.pages != 1 {
+		t.Errorf("listed %d pages, want 1 when the first page is unchanged", p.pages)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package cmd
+
+// stdoutWidth reports no terminal size; terminalWidth falls back to
+// COLUMNS.
+func stdoutWidth() int { return 0 }
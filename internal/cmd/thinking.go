@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// showThinking is bound to --show-thinking on providers that stream
+// reasoning summaries.
+var showThinking bool
+
+// applyShowThinking prints reasoning text to stderr ahead of the answer
+// when --show-thinking is set. Each line is prefixed with "│ " (dimmed
+// on a terminal) so it reads apart from the answer; the block is closed
+// when the first answer text arrives.
+func applyShowThinking(opts *provider.AskOptions) {
+	if !showThinking {
+		return
+	}
+
+	dim, reset := "", ""
+	if fi, err := os.Stderr.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		dim, reset = "\x1b[2m", "\x1b[0m"
+	}
+
+	open, midLine := false, false
+	opts.OnThinking = func(text string) {
+		lines := strings.SplitAfter(text, "\n")
+		for _, line := range lines {
+			if line == "" {
+				continue
+			}
+			if !midLine {
+				fmt.Fprint(os.Stderr, dim+"│ ")
+			}
+			fmt.Fprint(os.Stderr, line)
+			midLine = !strings.HasSuffix(line, "\n")
+			if !midLine {
+				fmt.Fprint(os.Stderr, reset)
+			}
+		}
+		open = true
+	}
+
+	onText := opts.OnText
+	opts.OnText = func(text string) {
+		if open {
+			if midLine {
+				fmt.Fprintln(os.Stderr, reset)
+			}
+			fmt.Fprintln(os.Stderr)
+			open, midLine = false, false
+		}
+		if onText != nil {
+			onText(text)
+		}
+	}
+}
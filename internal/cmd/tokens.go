@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var flagForce bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagForce, "force", false, "Send prompts estimated to exceed the model's context anyway")
+}
+
+// contextWindows is each provider's context size in tokens for its usual
+// models. The web apps do not publish theirs; these are the models'
+// documented sizes, which their servers enforce with opaque errors.
+// Ollama is left out: its size depends on the model and server setting,
+// and it truncates instead of failing.
+var contextWindows = map[string]int{
+	"anthropic-api": 200_000,
+	"chatgpt":       128_000,
+	"claude":        200_000,
+	"deepseek":      128_000,
+	"gemini":        1_000_000,
+	"grok":          128_000,
+	"lechat":        128_000,
+	"perplexity":    32_000,
+}
+
+// tokenScale corrects the estimate for tokenizers that split text finer
+// than OpenAI's.
+var tokenScale = map[string]float64{
+	"anthropic-api": 1.15,
+	"claude":        1.15,
+	"lechat":        1.1,
+}
+
+// imageTokens approximates what an attached image costs.
+const imageTokens = 1_500
+
+// documentTokens approximates what an attached PDF or other binary file
+// costs. Providers extract its text on their side, so its size in bytes
+// says nothing about the context it takes.
+const documentTokens = 2_000
+
+// promptWarnShare is the share of the context past which a prompt gets a
+// warning: the answer and any earlier turns need room too.
+const promptWarnShare = 0.9
+
+// contextWindow returns the context size to check prompts for model of
+// providerName against, or 0 when it is unknown.
+func contextWindow(providerName, model string) int {
+	if globalCfg.MaxPromptTokens > 0 {
+		return globalCfg.MaxPromptTokens
+	}
+	model = strings.ToLower(model)
+	switch {
+	case providerName == "chatgpt" && strings.Contains(model, "think"):
+		return 196_000
+	case providerName == "grok" && strings.HasPrefix(model, "grok-4"):
+		return 256_000
+	}
+	return contextWindows[providerName]
+}
+
+// estimateTokens approximates how many tokens text is for a BPE
+// tokenizer: a word is one token per four letters or digits, punctuation
+// is a token each, and CJK characters are a token each. Spaces ride along
+// with the word after them.
+func estimateTokens(text string) int {
+	tokens, word := 0, 0
+	endWord := func() {
+		tokens += (word + 3) / 4
+		word = 0
+	}
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana):
+			endWord()
+			tokens++
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word++
+			// Letters outside ASCII are often split further.
+			if r > unicode.MaxLatin1 {
+				word++
+			}
+		case unicode.IsSpace(r):
+			endWord()
+		default:
+			endWord()
+			tokens++
+		}
+	}
+	endWord()
+	return tokens
+}
+
+// estimateAttachment approximates the tokens of an attached file: text by
+// its content, images and other binary files at a flat rate.
+func estimateAttachment(path string) int {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".heic":
+		return imageTokens
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// The provider reports unreadable files itself.
+		return 0
+	}
+	if utf8.Valid(data) {
+		return estimateTokens(string(data))
+	}
+	return documentTokens
+}
+
+// estimatePrompt approximates the tokens query and its attachments are
+// for providerName.
+func estimatePrompt(providerName, query string, opts provider.AskOptions) int {
+	n := estimateTokens(query) + estimateTokens(opts.SystemPrompt)
+	for _, a := range opts.Attachments {
+		n += estimateAttachment(a)
+	}
+	if scale, ok := tokenScale[providerName]; ok {
+		n = int(float64(n) * scale)
+	}
+	return n
+}
+
+// checkPromptSize refuses a prompt estimated to exceed the model's
+// context, unless --force is set, and warns when it comes close. Earlier
+// turns of a continued conversation are not counted.
+func checkPromptSize(providerName, query string, opts provider.AskOptions) error {
+	limit := contextWindow(providerName, opts.Model)
+	if limit <= 0 {
+		return nil
+	}
+	n := estimatePrompt(providerName, query, opts)
+	switch {
+	case n > limit && !flagForce:
+		return fmt.Errorf("prompt is about %s tokens (estimated), more than the %s %s accepts; shorten it or pass --force to send it anyway",
+			formatTokens(n), formatTokens(limit), providerName)
+	case n > limit:
+		fmt.Fprintf(os.Stderr, "[%s] warning: prompt is about %s tokens, more than the %s it accepts; sending anyway\n",
+			providerName, formatTokens(n), formatTokens(limit))
+	case float64(n) > float64(limit)*promptWarnShare:
+		fmt.Fprintf(os.Stderr, "[%s] warning: prompt is about %s of the %s tokens it accepts; the answer may be cut short\n",
+			providerName, formatTokens(n), formatTokens(limit))
+	}
+	return nil
+}
+
+// formatTokens prints a token count compactly: 950, 12.3k, 1.2M.
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 10_000:
+		return fmt.Sprintf("%dk", n/1000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	}
+	return fmt.Sprint(n)
+}
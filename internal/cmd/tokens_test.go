@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+func TestCheckPromptSizeBinaryAttachment(t *testing.T) {
+	globalCfg = &config.Config{}
+	pdf := filepath.Join(t.TempDir(), "report.pdf")
+	data := append([]byte("%PDF-1.7\n"), bytes.Repeat([]byte{0xff, 0xfe, 0x00, 0x81}, 150_000)...)
+	if err := os.WriteFile(pdf, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := provider.AskOptions{Attachments: []string{pdf}}
+	if err := checkPromptSize("chatgpt", "Summarize this report.", opts); err != nil {
+		t.Errorf("a %d KB PDF was refused: %v", len(data)>>10, err)
+	}
+	if n := estimateAttachment(pdf); n != documentTokens {
+		t.Errorf("estimateAttachment = %d, want %d", n, documentTokens)
+	}
+}
+
+func TestEstimateAttachmentText(t *testing.T) {
+	txt := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(txt, bytes.Repeat([]byte("word "), 1000), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if n := estimateAttachment(txt); n != 1000 {
+		t.Errorf("estimateAttachment = %d, want 1000", n)
+	}
+}
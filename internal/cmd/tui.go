@@ -0,0 +1,690 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/history"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen interface for chatting with every provider",
+	Long: `Open a full-screen chat: pick a provider on the left, type below, and
+watch the answer stream in the main pane. Follow-up questions continue the
+conversation. The history sidebar lists recorded conversations to resume,
+copy from, or delete.
+
+Keys:
+  Tab / Shift-Tab   move between the input, providers, and history
+  Enter             send (input), choose (providers), resume (history)
+  ↑ ↓               select in a list; scroll the answers from the input
+  PgUp / PgDn       scroll the answers
+  Ctrl-N            start a new conversation with the current provider
+  Ctrl-Y / y        copy the last answer (or the selected history answer)
+  d                 delete the selected history conversation
+  Ctrl-C            stop the answer being streamed, or quit`,
+	Args: cobra.NoArgs,
+	RunE: runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+type tuiFocus int
+
+const (
+	focusInput tuiFocus = iota
+	focusProviders
+	focusHistory
+	focusCount
+)
+
+// historyLoadLimit is how many recorded exchanges the sidebar reads;
+// exchanges of the same conversation collapse into one row.
+const historyLoadLimit = 200
+
+// Messages sent from the goroutines that ask and delete.
+type (
+	tuiTextMsg    struct{ text string }
+	tuiDoneMsg    struct{ err error }
+	tuiConvMsg    struct{ state *config.ConversationState }
+	tuiDeletedMsg struct {
+		entry history.Entry
+		err   error
+	}
+)
+
+var (
+	tuiTitleStyle    = lipgloss.NewStyle().Reverse(true)
+	tuiHeaderStyle   = lipgloss.NewStyle().Bold(true)
+	tuiFocusedStyle  = lipgloss.NewStyle().Bold(true).Underline(true)
+	tuiSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	tuiFaintStyle    = lipgloss.NewStyle().Faint(true)
+	tuiYouStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	tuiErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// tuiTurn is one question and its answer in the main pane.
+type tuiTurn struct {
+	provider string
+	question string
+	answer   strings.Builder
+	err      error
+	pending  bool
+}
+
+// tuiModel is the bubbletea model behind ask tui.
+type tuiModel struct {
+	ctx context.Context
+	// events carries stream and delete results into Update.
+	events chan tea.Msg
+
+	width, height int
+	focus         tuiFocus
+
+	providers []string
+	provIdx   int
+	// newProvider builds the provider a question goes to.
+	newProvider func(name string) (provider.Provider, string, error)
+	models      map[string]string
+	// conv holds the conversation each provider continues.
+	conv map[string]*config.ConversationState
+
+	history    []history.Entry
+	histIdx    int
+	histNote   string
+	confirming bool
+
+	input  textinput.Model
+	answer viewport.Model
+	turns  []*tuiTurn
+	status string
+
+	streaming bool
+	cancel    context.CancelFunc
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		if fi, err := f.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+			return fmt.Errorf("ask tui needs an interactive terminal")
+		}
+	}
+	m := newTUIModel(cmd.Context())
+	_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(cmd.Context())).Run()
+	if m.cancel != nil {
+		m.cancel()
+	}
+	return err
+}
+
+func newTUIModel(ctx context.Context) *tuiModel {
+	m := &tuiModel{
+		ctx:         ctx,
+		events:      make(chan tea.Msg, 64),
+		providers:   providerNames,
+		newProvider: newProviderByName,
+		models:      make(map[string]string),
+		conv:        make(map[string]*config.ConversationState),
+		input:       textinput.New(),
+		answer:      viewport.New(0, 0),
+	}
+	m.input.Prompt = "› "
+	m.input.PromptStyle = lipgloss.NewStyle().Bold(true)
+	m.input.Focus()
+	if i := slices.Index(m.providers, globalCfg.DefaultProvider); i >= 0 {
+		m.provIdx = i
+	} else if i := slices.Index(m.providers, "claude"); i >= 0 {
+		m.provIdx = i
+	}
+	m.loadHistory()
+	return m
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.listen())
+}
+
+// listen waits for the next stream or delete result.
+func (m *tuiModel) listen() tea.Cmd {
+	return func() tea.Msg { return <-m.events }
+}
+
+// loadHistory reads recorded conversations for the sidebar, newest first
+// and one row per conversation.
+func (m *tuiModel) loadHistory() {
+	m.history = nil
+	if globalCfg.History.Disabled {
+		m.histNote = "(history is disabled)"
+		return
+	}
+	store, err := history.Open()
+	if err != nil {
+		m.histNote = "(history unavailable)"
+		return
+	}
+	defer store.Close()
+	entries, err := store.List(history.ListOptions{Limit: historyLoadLimit})
+	if err != nil {
+		m.histNote = "(history unavailable)"
+		return
+	}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		key := e.Provider + "/" + e.ConversationID
+		if e.ConversationID == "" || e.Error != "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		m.history = append(m.history, e)
+	}
+	m.histNote = ""
+	if len(m.history) == 0 {
+		m.histNote = "(no conversations yet)"
+	}
+	m.histIdx = min(m.histIdx, max(0, len(m.history)-1))
+}
+
+func (m *tuiModel) provider() string { return m.providers[m.provIdx] }
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = max(msg.Width, 40), max(msg.Height, 10)
+		m.layout()
+	case tuiTextMsg:
+		if t := m.lastTurn(); t != nil {
+			t.answer.WriteString(msg.text)
+		}
+		m.refresh()
+		return m, m.listen()
+	case tuiConvMsg:
+		if t := m.lastTurn(); t != nil {
+			m.conv[t.provider] = msg.state
+		}
+		return m, m.listen()
+	case tuiDoneMsg:
+		m.streaming, m.cancel = false, nil
+		if t := m.lastTurn(); t != nil {
+			t.pending = false
+			t.err = msg.err
+			if msg.err == nil {
+				if cs := m.conv[t.provider]; cs != nil {
+					saveConversationState(t.provider, t.question, cs)
+				}
+				m.status = ""
+			}
+		}
+		m.loadHistory()
+		m.refresh()
+		return m, m.listen()
+	case tuiDeletedMsg:
+		m.deleted(msg)
+		return m, m.listen()
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *tuiModel) handleKey(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirming {
+		m.confirming = false
+		m.status = ""
+		if r := keyRune(k); r == 'y' || r == 'Y' {
+			m.deleteSelected()
+		}
+		return m, nil
+	}
+
+	switch k.Type {
+	case tea.KeyCtrlC:
+		if m.streaming {
+			m.cancel()
+			m.status = "stopped"
+			return m, nil
+		}
+		return m, tea.Quit
+	case tea.KeyTab:
+		m.setFocus((m.focus + 1) % focusCount)
+		return m, nil
+	case tea.KeyShiftTab:
+		m.setFocus((m.focus + focusCount - 1) % focusCount)
+		return m, nil
+	case tea.KeyEsc:
+		m.setFocus(focusInput)
+		return m, nil
+	case tea.KeyPgUp:
+		m.answer.PageUp()
+		return m, nil
+	case tea.KeyPgDown:
+		m.answer.PageDown()
+		return m, nil
+	case tea.KeyCtrlN:
+		if !m.streaming {
+			delete(m.conv, m.provider())
+			m.turns = nil
+			m.status = "New " + m.provider() + " conversation"
+			m.refresh()
+		}
+		return m, nil
+	case tea.KeyCtrlY:
+		return m, m.copySelected()
+	}
+
+	switch m.focus {
+	case focusInput:
+		switch k.Type {
+		case tea.KeyUp:
+			m.answer.ScrollUp(1)
+		case tea.KeyDown:
+			m.answer.ScrollDown(1)
+		case tea.KeyEnter:
+			m.send()
+		default:
+			return m, m.typeKey(k)
+		}
+	case focusProviders:
+		switch k.Type {
+		case tea.KeyUp:
+			m.selectProvider(m.provIdx - 1)
+		case tea.KeyDown:
+			m.selectProvider(m.provIdx + 1)
+		case tea.KeyEnter:
+			m.setFocus(focusInput)
+		}
+	case focusHistory:
+		switch r := keyRune(k); {
+		case k.Type == tea.KeyUp:
+			m.histIdx = max(0, m.histIdx-1)
+		case k.Type == tea.KeyDown:
+			m.histIdx = min(max(0, len(m.history)-1), m.histIdx+1)
+		case k.Type == tea.KeyEnter || r == 'r':
+			m.resumeSelected()
+		case k.Type == tea.KeyDelete || r == 'd':
+			if len(m.history) > 0 && !m.streaming {
+				m.confirming = true
+				m.status = fmt.Sprintf("Delete %s conversation %s? (y/n)", m.history[m.histIdx].Provider, m.history[m.histIdx].ConversationID)
+			}
+		case r == 'y':
+			return m, m.copySelected()
+		}
+	}
+	return m, nil
+}
+
+// keyRune returns the character of a single printable key press, or 0
+// for anything else, typed-ahead or pasted text included.
+func keyRune(k tea.KeyMsg) rune {
+	if k.Type != tea.KeyRunes || len(k.Runes) != 1 || k.Paste {
+		return 0
+	}
+	return k.Runes[0]
+}
+
+// typeKey passes a key press to the input. Typed-ahead text arrives a
+// word at a time, and textinput matches its bindings by name, so a word
+// like "up" or "end" is passed one rune at a time.
+func (m *tuiModel) typeKey(k tea.KeyMsg) tea.Cmd {
+	if k.Type != tea.KeyRunes || k.Paste || len(k.Runes) < 2 {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(k)
+		return cmd
+	}
+	var cmds []tea.Cmd
+	for _, r := range k.Runes {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: k.Alt})
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *tuiModel) setFocus(f tuiFocus) {
+	m.focus = f
+	if f == focusInput {
+		m.input.Focus()
+	} else {
+		m.input.Blur()
+	}
+}
+
+func (m *tuiModel) selectProvider(i int) {
+	if m.streaming || i < 0 || i >= len(m.providers) {
+		return
+	}
+	m.provIdx = i
+	m.status = ""
+	m.refresh()
+}
+
+func (m *tuiModel) lastTurn() *tuiTurn {
+	if len(m.turns) == 0 {
+		return nil
+	}
+	return m.turns[len(m.turns)-1]
+}
+
+// send asks the current provider the typed question, continuing its
+// conversation, and streams the answer into the main pane.
+func (m *tuiModel) send() {
+	query := strings.TrimSpace(m.input.Value())
+	if query == "" || m.streaming {
+		return
+	}
+	name := m.provider()
+	p, model, err := m.newProvider(name)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	// Questions typed here leave under the same rules as those from the
+	// command line.
+	query, err = redactOutbound(query)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+
+	events := m.events
+	opts := provider.AskOptions{
+		Model:   model,
+		Verbose: globalCfg.Verbose,
+		OnText: func(text string) {
+			events <- tuiTextMsg{text}
+		},
+		OnConversation: func(conversationID, parentMessageID, responseID string) {
+			events <- tuiConvMsg{&config.ConversationState{
+				ConversationID:  conversationID,
+				ParentMessageID: parentMessageID,
+				ResponseID:      responseID,
+			}}
+		},
+		OnError: func(err error) {
+			slog.Debug("stream error", "provider", name, "err", err)
+		},
+	}
+	if cs := m.conv[name]; cs != nil {
+		opts.ConversationID = cs.ConversationID
+		opts.ParentMessageID = cs.ParentMessageID
+		opts.ResponseID = cs.ResponseID
+	}
+	applySystemPrompt(name, &opts)
+	if err := checkPromptSize(name, query, opts); err != nil {
+		m.status = err.Error()
+		return
+	}
+
+	m.models[name] = model
+	m.input.Reset()
+	m.status = ""
+	m.turns = append(m.turns, &tuiTurn{provider: name, question: query, pending: true})
+	m.refresh()
+	m.answer.GotoBottom()
+	rec := recordHistory(name, model, query, &opts)
+
+	ctx, cancel := withProviderTimeout(m.ctx)
+	m.streaming, m.cancel = true, cancel
+	go func() {
+		defer cancel()
+		autoLoadCookies(ctx, p)
+		err := sendPrompt(ctx, p, query, opts)
+		rec.finish(err)
+		events <- tuiDoneMsg{err}
+	}()
+}
+
+// resumeSelected continues the selected history conversation and shows
+// its last exchange.
+func (m *tuiModel) resumeSelected() {
+	if len(m.history) == 0 || m.streaming {
+		return
+	}
+	e := m.history[m.histIdx]
+	i := slices.Index(m.providers, e.Provider)
+	if i < 0 {
+		m.status = "unknown provider " + e.Provider
+		return
+	}
+	m.provIdx = i
+	cs := &config.ConversationState{ConversationID: e.ConversationID}
+	// The saved session knows the message to continue from, which some
+	// providers need.
+	if saved := config.LoadState().GetConversation(e.Provider); saved != nil && saved.ConversationID == e.ConversationID {
+		cs = saved
+	}
+	m.conv[e.Provider] = cs
+
+	t := &tuiTurn{provider: e.Provider, question: e.Question}
+	t.answer.WriteString(e.Answer)
+	m.turns = []*tuiTurn{t}
+	m.setFocus(focusInput)
+	m.status = "Resumed " + e.Provider + " conversation " + e.ConversationID
+	m.refresh()
+	m.answer.GotoBottom()
+}
+
+func (m *tuiModel) deleteSelected() {
+	if len(m.history) == 0 {
+		return
+	}
+	e := m.history[m.histIdx]
+	p, _, err := newProviderByName(e.Provider)
+	if err != nil {
+		m.status = err.Error()
+		return
+	}
+	deleter, ok := p.(provider.Deleter)
+	if !ok {
+		m.status = e.Provider + " does not support deleting conversations"
+		return
+	}
+	m.status = "Deleting " + e.ConversationID + "..."
+	events := m.events
+	go func() {
+		ctx, cancel := withProviderTimeout(m.ctx)
+		defer cancel()
+		autoLoadCookies(ctx, p)
+		err := deleter.DeleteConversation(ctx, e.ConversationID, provider.DeleteOptions{})
+		events <- tuiDeletedMsg{entry: e, err: err}
+	}()
+}
+
+func (m *tuiModel) deleted(msg tuiDeletedMsg) {
+	if msg.err != nil {
+		m.status = "delete failed: " + msg.err.Error()
+		return
+	}
+	clearConversationState(msg.entry.Provider, msg.entry.ConversationID)
+	if cs := m.conv[msg.entry.Provider]; cs != nil && cs.ConversationID == msg.entry.ConversationID {
+		delete(m.conv, msg.entry.Provider)
+	}
+	m.history = slices.DeleteFunc(m.history, func(e history.Entry) bool {
+		return e.Provider == msg.entry.Provider && e.ConversationID == msg.entry.ConversationID
+	})
+	m.histIdx = min(m.histIdx, max(0, len(m.history)-1))
+	m.status = "Deleted " + msg.entry.ConversationID
+}
+
+// copySelected copies an answer to the clipboard with an OSC 52 escape,
+// which terminals forward to the system clipboard, over SSH too.
+func (m *tuiModel) copySelected() tea.Cmd {
+	var text string
+	switch {
+	case m.focus == focusHistory && len(m.history) > 0:
+		text = m.history[m.histIdx].Answer
+	case m.lastTurn() != nil:
+		text = m.lastTurn().answer.String()
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		m.status = "nothing to copy"
+		return nil
+	}
+	m.status = fmt.Sprintf("Copied %d characters", utf8.RuneCountInString(text))
+	return func() tea.Msg {
+		termenv.Copy(text)
+		return nil
+	}
+}
+
+// --- Rendering ---
+
+func (m *tuiModel) sidebarWidth() int { return min(32, max(20, m.width/4)) }
+
+func (m *tuiModel) bodyHeight() int { return m.height - 3 }
+
+// layout sizes the answer pane and input to the window.
+func (m *tuiModel) layout() {
+	m.answer.Width = m.width - m.sidebarWidth() - 3
+	m.answer.Height = m.bodyHeight()
+	m.input.Width = m.width - lipgloss.Width(m.input.Prompt) - 1
+	m.refresh()
+}
+
+// refresh re-renders the conversation into the answer pane, keeping it
+// at the bottom unless the user scrolled up.
+func (m *tuiModel) refresh() {
+	follow := m.answer.AtBottom()
+	m.answer.SetContent(m.conversationView(m.answer.Width))
+	if follow {
+		m.answer.GotoBottom()
+	}
+}
+
+// View draws the whole screen: a title bar, the sidebar and answers, the
+// input line, and a status line.
+func (m *tuiModel) View() string {
+	if m.width == 0 {
+		return ""
+	}
+
+	title := " ask · " + m.provider()
+	if model := m.models[m.provider()]; model != "" {
+		title += " (" + model + ")"
+	}
+	if cs := m.conv[m.provider()]; cs != nil && cs.ConversationID != "" {
+		title += " · " + cs.ConversationID
+	} else {
+		title += " · new conversation"
+	}
+	if m.streaming {
+		title += " · answering…"
+	}
+
+	sep := tuiFaintStyle.Render(strings.Repeat(" │ \n", m.bodyHeight()-1) + " │ ")
+	body := lipgloss.JoinHorizontal(lipgloss.Top, m.sidebarView(m.sidebarWidth(), m.bodyHeight()), sep, m.answer.View())
+
+	status := m.status
+	if status == "" {
+		switch m.focus {
+		case focusInput:
+			status = "Enter send · Tab providers/history · PgUp/PgDn scroll · ^N new · ^Y copy · ^C quit"
+		case focusProviders:
+			status = "↑↓ choose provider · Enter back to input · Tab next"
+		case focusHistory:
+			status = "↑↓ select · Enter resume · y copy · d delete · Tab next"
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		tuiTitleStyle.Render(padRight(truncateRunes(title, m.width), m.width)),
+		body,
+		m.input.View(),
+		tuiFaintStyle.Render(truncateRunes(status, m.width)),
+	)
+}
+
+// sidebarView renders the provider selector and the history list as
+// exactly height lines, each padded to width.
+func (m *tuiModel) sidebarView(width, height int) string {
+	var lines []string
+	item := func(text string, selected, focused bool) string {
+		marker := "  "
+		if selected {
+			marker = "▸ "
+		}
+		s := padRight(truncateRunes(marker+text, width), width)
+		if selected && focused {
+			return tuiSelectedStyle.Render(s)
+		}
+		return s
+	}
+	header := func(text string, focused bool) string {
+		s := padRight(truncateRunes(text, width), width)
+		if focused {
+			return tuiFocusedStyle.Render(s)
+		}
+		return tuiHeaderStyle.Render(s)
+	}
+
+	lines = append(lines, header("Providers", m.focus == focusProviders))
+	for i, name := range m.providers {
+		lines = append(lines, item(name, i == m.provIdx, m.focus == focusProviders))
+	}
+	lines = append(lines, padRight("", width), header("History", m.focus == focusHistory))
+
+	room := height - len(lines)
+	if m.histNote != "" {
+		lines = append(lines, tuiFaintStyle.Render(padRight(truncateRunes(m.histNote, width), width)))
+	} else if room > 0 {
+		// Scroll the list so the selection stays visible.
+		start := max(0, m.histIdx-room+1)
+		for i := start; i < len(m.history) && i < start+room; i++ {
+			e := m.history[i]
+			lines = append(lines, item(e.Provider+" · "+sessionTitle(e.Question), i == m.histIdx, m.focus == focusHistory))
+		}
+	}
+
+	for len(lines) < height {
+		lines = append(lines, padRight("", width))
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+// tuiPlain expands tabs and drops carriage returns, which would
+// otherwise break the column arithmetic.
+var tuiPlain = strings.NewReplacer("\t", "    ", "\r", "")
+
+// conversationView renders every turn, wrapped to width.
+func (m *tuiModel) conversationView(width int) string {
+	wrap := lipgloss.NewStyle().Width(width)
+	if len(m.turns) == 0 {
+		return tuiFaintStyle.Width(width).Render("Ask " + m.provider() + " anything. Tab moves to the provider and history lists.")
+	}
+	var parts []string
+	for _, t := range m.turns {
+		parts = append(parts, tuiYouStyle.Render("you → "+t.provider), wrap.Render(tuiPlain.Replace(t.question)), "")
+		answer := tuiPlain.Replace(strings.TrimSpace(t.answer.String()))
+		switch {
+		case answer != "":
+			parts = append(parts, wrap.Render(answer))
+		case t.pending:
+			parts = append(parts, tuiFaintStyle.Render("…"))
+		}
+		if t.err != nil {
+			parts = append(parts, tuiErrorStyle.Width(width).Render("error: "+t.err.Error()))
+		}
+		parts = append(parts, "")
+	}
+	return strings.Join(parts, "\n")
+}
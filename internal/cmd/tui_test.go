@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kyupark/ask/internal/config"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// fakeAsker records the questions it is asked.
+type fakeAsker struct {
+	queries []string
+}
+
+func (f *fakeAsker) Name() string                       { return "fake" }
+func (f *fakeAsker) CookieSpecs() []provider.CookieSpec { return nil }
+func (f *fakeAsker) SetCookies(map[string]string)       {}
+
+func (f *fakeAsker) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
+	f.queries = append(f.queries, query)
+	return nil
+}
+
+// newTestTUI returns a model whose only provider, name, is p.
+func newTestTUI(t *testing.T, name string, p provider.Provider) *tuiModel {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	m := newTUIModel(context.Background())
+	m.providers, m.provIdx = []string{name}, 0
+	m.newProvider = func(string) (provider.Provider, string, error) { return p, "", nil }
+	return m
+}
+
+func TestTUISendRedacts(t *testing.T) {
+	globalCfg = &config.Config{Redact: config.RedactConfig{
+		Enabled:  true,
+		Patterns: map[string]string{"ticket": `TICKET-[0-9]+`},
+	}}
+	p := &fakeAsker{}
+	m := newTestTUI(t, "fake", p)
+
+	m.input.SetValue("why does TICKET-4242 keep failing?")
+	m.send()
+	if m.status != "" {
+		t.Fatalf("status = %q", m.status)
+	}
+	if msg, ok := (<-m.events).(tuiDoneMsg); !ok || msg.err != nil {
+		t.Fatalf("got %#v, want a clean finish", msg)
+	}
+
+	if len(p.queries) != 1 {
+		t.Fatalf("provider asked %d times, want 1", len(p.queries))
+	}
+	if strings.Contains(p.queries[0], "TICKET-4242") {
+		t.Errorf("provider got %q, want the ticket redacted", p.queries[0])
+	}
+	if q := m.turns[0].question; strings.Contains(q, "TICKET-4242") {
+		t.Errorf("transcript shows %q, want what was sent", q)
+	}
+}
+
+func TestTUISendTooLarge(t *testing.T) {
+	globalCfg = &config.Config{}
+	p := &fakeAsker{}
+	m := newTestTUI(t, "chatgpt", p)
+
+	query := strings.Repeat("word ", 2*contextWindow("chatgpt", ""))
+	m.input.SetValue(query)
+	m.send()
+	if !strings.Contains(m.status, "tokens") {
+		t.Errorf("status = %q, want the prompt refused", m.status)
+	}
+	if m.streaming || len(m.turns) != 0 {
+		t.Error("refused prompt was sent")
+	}
+	if m.input.Value() != query {
+		t.Error("refused prompt was cleared from the input")
+	}
+}
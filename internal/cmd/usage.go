@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kyupark/ask/internal/history"
+)
+
+var (
+	usageSince string
+	usageBy    string
+	usageJSON  bool
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Summarize how much each provider has been used",
+	Long: `Summarize recorded asks by provider, model, or day: how many, how many
+failed, the characters sent and received, and the time spent waiting.
+
+Every ask records its provider, model, prompt and answer sizes, and
+duration, but not its text, so usage is kept even for incognito asks and
+with history disabled. Stop recording with: ask config set usage.disabled true
+
+  ask usage --since 7d
+  ask usage --since 2026-01-01 --by model
+  ask usage --by day --json`,
+	Args: cobra.NoArgs,
+	RunE: runUsage,
+}
+
+func init() {
+	usageCmd.Flags().StringVar(&usageSince, "since", "7d", "Start of the period: a duration such as 12h, 7d or 4w, or a date (YYYY-MM-DD)")
+	usageCmd.Flags().StringVar(&usageBy, "by", "provider", "Group by provider, model, or day")
+	usageCmd.Flags().BoolVar(&usageJSON, "json", false, "Print the summary as JSON")
+	rootCmd.AddCommand(usageCmd)
+}
+
+// usageRow is the usage of one group.
+type usageRow struct {
+	Key           string `json:"key"`
+	Asks          int    `json:"asks"`
+	Failed        int    `json:"failed"`
+	PromptChars   int    `json:"prompt_chars"`
+	ResponseChars int    `json:"response_chars"`
+	DurationMs    int64  `json:"duration_ms"`
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	since, err := parseSince(usageSince, time.Now())
+	if err != nil {
+		return err
+	}
+	var keyOf func(history.Usage) string
+	switch usageBy {
+	case "provider":
+		keyOf = func(u history.Usage) string { return u.Provider }
+	case "model":
+		keyOf = func(u history.Usage) string {
+			if u.Model == "" {
+				return u.Provider + " (default)"
+			}
+			return u.Provider + " " + u.Model
+		}
+	case "day":
+		keyOf = func(u history.Usage) string { return u.CreatedAt.Format("2006-01-02") }
+	default:
+		return fmt.Errorf("invalid --by %q (use provider, model, or day)", usageBy)
+	}
+
+	store, err := history.Open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	records, err := store.UsageSince(since)
+	if err != nil {
+		return err
+	}
+
+	rows, total := summarizeUsage(records, keyOf)
+	if usageBy == "day" {
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Key < rows[j].Key })
+	} else {
+		sort.Slice(rows, func(i, j int) bool {
+			if rows[i].Asks != rows[j].Asks {
+				return rows[i].Asks > rows[j].Asks
+			}
+			return rows[i].Key < rows[j].Key
+		})
+	}
+
+	if usageJSON {
+		data, err := json.MarshalIndent(struct {
+			Since time.Time  `json:"since"`
+			By    string     `json:"by"`
+			Rows  []usageRow `json:"rows"`
+			Total usageRow   `json:"total"`
+		}{since, usageBy, rows, total}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(rows) == 0 {
+		fmt.Printf("No usage recorded since %s.\n", formatTime(since))
+		return nil
+	}
+	fmt.Printf("Usage since %s:\n\n", formatTime(since))
+	width := len(usageBy)
+	for _, r := range rows {
+		width = max(width, len(r.Key))
+	}
+	fmt.Printf("%-*s  %6s  %6s  %10s  %10s  %10s\n", width, usageBy, "asks", "failed", "sent", "received", "time")
+	for _, r := range append(rows, total) {
+		fmt.Printf("%-*s  %6d  %6d  %10s  %10s  %10s\n", width, r.Key, r.Asks, r.Failed,
+			formatChars(r.PromptChars), formatChars(r.ResponseChars), formatUsageDuration(r.DurationMs))
+	}
+	return nil
+}
+
+// summarizeUsage groups records by keyOf and totals them.
+func summarizeUsage(records []history.Usage, keyOf func(history.Usage) string) ([]usageRow, usageRow) {
+	groups := make(map[string]*usageRow)
+	total := usageRow{Key: "total"}
+	for _, u := range records {
+		key := keyOf(u)
+		row := groups[key]
+		if row == nil {
+			row = &usageRow{Key: key}
+			groups[key] = row
+		}
+		for _, r := range []*usageRow{row, &total} {
+			r.Asks++
+			if u.Failed {
+				r.Failed++
+			}
+			r.PromptChars += u.PromptChars
+			r.ResponseChars += u.ResponseChars
+			r.DurationMs += u.Duration.Milliseconds()
+		}
+	}
+	rows := make([]usageRow, 0, len(groups))
+	for _, r := range groups {
+		rows = append(rows, *r)
+	}
+	return rows, total
+}
+
+// parseSince turns --since into a start time: a Go duration, a number of
+// days ("7d") or weeks ("4w"), or a date.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		if count, err := strconv.Atoi(s[:n-1]); err == nil && count >= 0 {
+			days := count
+			if s[n-1] == 'w' {
+				days *= 7
+			}
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (use e.g. 12h, 7d, 4w, or 2026-01-31)", s)
+}
+
+// formatChars shortens large character counts: 950, 12.3k, 4.1M.
+func formatChars(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 10_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+func formatUsageDuration(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d >= time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -12,20 +12,117 @@ const (
 	configFile = "config.json"
 
 	DefaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36"
-	DefaultTimeout   = 180
 )
 
 // Config is the top-level configuration.
 type Config struct {
 	UserAgent string `json:"user_agent,omitempty"`
-	Timeout   int    `json:"timeout,omitempty"`
-	Verbose   bool   `json:"verbose,omitempty"`
+	// Timeout caps a whole question in seconds; zero means no cap.
+	Timeout int `json:"timeout,omitempty"`
+	// Timeouts bounds each phase of a request.
+	Timeouts TimeoutConfig `json:"timeouts,omitempty"`
+	Verbose  bool          `json:"verbose,omitempty"`
+	// Browsers is the cookie search order (e.g. ["brave", "safari"]);
+	// empty uses the built-in order.
+	Browsers []string `json:"browsers,omitempty"`
+	// SecretStore is where session tokens and cookies are kept: "keychain"
+	// for the OS credential store, "file" or empty for this file.
+	SecretStore string `json:"secret_store,omitempty"`
+	// StoredSecrets lists the keys currently held in the credential store.
+	StoredSecrets []string `json:"stored_secrets,omitempty"`
+	// SystemPrompt is sent with every question unless the provider's own
+	// system_prompt or --system overrides it.
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	// EditTemplate is the file --edit starts the prompt buffer from.
+	EditTemplate string `json:"edit_template,omitempty"`
+	// DefaultProvider answers bare `ask "question"` invocations.
+	DefaultProvider string `json:"default_provider,omitempty"`
+	// Failover is the provider order tried by `ask failover`.
+	Failover []string `json:"failover,omitempty"`
+	// Proxy is the proxy URL (http://, https://, socks5://) for every
+	// provider; empty uses HTTPS_PROXY/ALL_PROXY from the environment.
+	Proxy string `json:"proxy,omitempty"`
+	// ResumePerDirectory makes --resume continue the conversation last
+	// used in the working directory (or its nearest parent that has one)
+	// instead of the last one anywhere.
+	ResumePerDirectory bool `json:"resume_per_directory,omitempty"`
+	// MaxPromptTokens overrides the estimated context size prompts are
+	// checked against; 0 uses each model's own.
+	MaxPromptTokens int `json:"max_prompt_tokens,omitempty"`
 
 	Perplexity PerplexityConfig `json:"perplexity,omitempty"`
 	ChatGPT    ChatGPTConfig    `json:"chatgpt,omitempty"`
 	Gemini     GeminiConfig     `json:"gemini,omitempty"`
 	Grok       GrokConfig       `json:"grok,omitempty"`
 	Claude     ClaudeConfig     `json:"claude,omitempty"`
+	DeepSeek   DeepSeekConfig   `json:"deepseek,omitempty"`
+	LeChat     LeChatConfig     `json:"lechat,omitempty"`
+	Ollama     OllamaConfig     `json:"ollama,omitempty"`
+
+	AnthropicAPI AnthropicAPIConfig `json:"anthropic_api,omitempty"`
+
+	Redact  RedactConfig  `json:"redact,omitempty"`
+	History HistoryConfig `json:"history,omitempty"`
+	Usage   UsageConfig   `json:"usage,omitempty"`
+	Hooks   HooksConfig   `json:"hooks,omitempty"`
+	Audit   AuditConfig   `json:"audit,omitempty"`
+
+	// unreadSecrets are stored secrets the credential store refused to
+	// return on Load.
+	unreadSecrets map[string]bool
+}
+
+// TimeoutConfig holds per-phase timeouts in seconds. Zero uses the
+// built-in default.
+type TimeoutConfig struct {
+	Connect        int `json:"connect,omitempty"`
+	ResponseHeader int `json:"response_header,omitempty"`
+	StreamIdle     int `json:"stream_idle,omitempty"`
+	// AskAll is how long each provider gets in `ask all` before it is
+	// left out; zero falls back to Timeout.
+	AskAll int `json:"ask_all,omitempty"`
+}
+
+// HistoryConfig controls the local question/answer history database.
+type HistoryConfig struct {
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// UsageConfig controls the per-ask usage log behind `ask usage`.
+type UsageConfig struct {
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// HooksConfig names what is told about every finished ask.
+type HooksConfig struct {
+	// Webhook receives the result as a JSON POST.
+	Webhook string `json:"webhook,omitempty"`
+	// Command is run by the shell with the result as JSON on stdin.
+	Command string `json:"command,omitempty"`
+	// OnError runs the hooks for failed asks too.
+	OnError bool `json:"on_error,omitempty"`
+	// PreSend is run with each prompt as JSON on stdin before it is sent
+	// and may print it back changed.
+	PreSend string `json:"pre_send,omitempty"`
+	// PostReceive is run with each answer as JSON on stdin and may print
+	// it back changed.
+	PostReceive string `json:"post_receive,omitempty"`
+}
+
+// AuditConfig controls the append-only log of every prompt sent.
+type AuditConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Path is the log file; empty uses audit.jsonl in the data directory.
+	Path string `json:"path,omitempty"`
+}
+
+// RedactConfig controls outbound redaction of prompts.
+type RedactConfig struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Rules lists built-in rule names to apply; empty means all built-ins.
+	Rules []string `json:"rules,omitempty"`
+	// Patterns maps custom rule names to regular expressions.
+	Patterns map[string]string `json:"patterns,omitempty"`
 }
 
 // PerplexityConfig holds Perplexity-specific settings.
@@ -34,8 +131,10 @@ type PerplexityConfig struct {
 	SessionCookie string `json:"session_cookie,omitempty"`
 	BaseURL       string `json:"base_url,omitempty"`
 	Model         string `json:"model,omitempty"`
+	SystemPrompt  string `json:"system_prompt,omitempty"`
 	Mode          string `json:"mode,omitempty"`
 	SearchFocus   string `json:"search_focus,omitempty"`
+	Proxy         string `json:"proxy,omitempty"`
 }
 
 // ChatGPTConfig holds ChatGPT-specific settings.
@@ -45,39 +144,124 @@ type ChatGPTConfig struct {
 	PUID         string `json:"puid,omitempty"`
 	BaseURL      string `json:"base_url,omitempty"`
 	Model        string `json:"model,omitempty"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
 	Effort       string `json:"effort,omitempty"`
+	Proxy        string `json:"proxy,omitempty"`
+	// Timezone is an IANA name (e.g. "Europe/Berlin"); empty uses the host timezone.
+	Timezone string `json:"timezone,omitempty"`
+	// Locale is a BCP 47 tag (e.g. "de-DE") sent as the request language;
+	// empty uses the host locale (LANG and friends).
+	Locale string `json:"locale,omitempty"`
+	// Account is the ChatGPT-Account-Id of the workspace to use; empty uses
+	// the personal account.
+	Account string `json:"account,omitempty"`
+	// Fingerprint overrides individual browser fingerprint fields.
+	Fingerprint FingerprintConfig `json:"fingerprint,omitempty"`
+}
+
+// FingerprintConfig describes the browser environment reported to ChatGPT.
+// Zero fields use the per-install profile stored in state.
+type FingerprintConfig struct {
+	ScreenWidth  int    `json:"screen_width,omitempty"`
+	ScreenHeight int    `json:"screen_height,omitempty"`
+	PageWidth    int    `json:"page_width,omitempty"`
+	PageHeight   int    `json:"page_height,omitempty"`
+	PixelRatio   int    `json:"pixel_ratio,omitempty"`
+	Cores        int    `json:"cores,omitempty"`
+	ScriptSrc    string `json:"script_src,omitempty"`
+	DPL          string `json:"dpl,omitempty"`
 }
 
 // GeminiConfig holds Gemini-specific settings.
 type GeminiConfig struct {
-	PSID   string `json:"psid,omitempty"`
-	PSIDTS string `json:"psidts,omitempty"`
-	PSIDCC string `json:"psidcc,omitempty"`
-	Model  string `json:"model,omitempty"`
+	PSID         string `json:"psid,omitempty"`
+	PSIDTS       string `json:"psidts,omitempty"`
+	PSIDCC       string `json:"psidcc,omitempty"`
+	Model        string `json:"model,omitempty"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	Proxy        string `json:"proxy,omitempty"`
 }
 
 // GrokConfig holds Grok (X.com) specific settings.
 type GrokConfig struct {
-	AuthToken  string `json:"auth_token,omitempty"`
-	CT0        string `json:"ct0,omitempty"`
-	Model      string `json:"model,omitempty"`
-	DeepSearch bool   `json:"deepsearch,omitempty"`
-	Reasoning  bool   `json:"reasoning,omitempty"`
+	AuthToken    string `json:"auth_token,omitempty"`
+	CT0          string `json:"ct0,omitempty"`
+	Model        string `json:"model,omitempty"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	DeepSearch   bool   `json:"deepsearch,omitempty"`
+	Reasoning    bool   `json:"reasoning,omitempty"`
+	Persona      string `json:"persona,omitempty"`
+	Proxy        string `json:"proxy,omitempty"`
 }
 
 // ClaudeConfig holds Claude.ai specific settings.
 type ClaudeConfig struct {
 	SessionKey string `json:"session_key,omitempty"`
 	BaseURL    string `json:"base_url,omitempty"`
-	Model      string `json:"model,omitempty"`
-	Effort     string `json:"effort,omitempty"`
+	// APIFallback asks the Anthropic API when claude.ai fails before
+	// answering.
+	APIFallback  bool   `json:"api_fallback,omitempty"`
+	Model        string `json:"model,omitempty"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	Effort       string `json:"effort,omitempty"`
+	// ThinkingBudget caps extended thinking in tokens (0 = claude.ai default).
+	ThinkingBudget int    `json:"thinking_budget,omitempty"`
+	Proxy          string `json:"proxy,omitempty"`
+}
+
+// DeepSeekConfig holds chat.deepseek.com specific settings.
+type DeepSeekConfig struct {
+	SessionID string `json:"session_id,omitempty"`
+	// UserToken is the web app's bearer token; empty looks it up with
+	// the session cookie.
+	UserToken    string `json:"user_token,omitempty"`
+	BaseURL      string `json:"base_url,omitempty"`
+	Model        string `json:"model,omitempty"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	Search       bool   `json:"search,omitempty"`
+	Proxy        string `json:"proxy,omitempty"`
+}
+
+// LeChatConfig holds Mistral Le Chat (chat.mistral.ai) settings.
+type LeChatConfig struct {
+	SessionCookie string `json:"session_cookie,omitempty"`
+	BaseURL       string `json:"base_url,omitempty"`
+	Model         string `json:"model,omitempty"`
+	SystemPrompt  string `json:"system_prompt,omitempty"`
+	Proxy         string `json:"proxy,omitempty"`
+}
+
+// OllamaConfig holds settings for a local Ollama server.
+type OllamaConfig struct {
+	// BaseURL is the server URL; empty uses OLLAMA_HOST, then
+	// http://localhost:11434.
+	BaseURL string `json:"base_url,omitempty"`
+	// Model is the default model. Setting it also includes Ollama in
+	// `ask all`.
+	Model        string `json:"model,omitempty"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	Proxy        string `json:"proxy,omitempty"`
+}
+
+// AnthropicAPIConfig holds settings for the Anthropic Messages API, the
+// API-key alternative to the cookie-based Claude provider.
+type AnthropicAPIConfig struct {
+	// APIKey is the API key; empty uses ANTHROPIC_API_KEY. Setting either
+	// also includes the API in `ask all`.
+	APIKey       string `json:"api_key,omitempty"`
+	BaseURL      string `json:"base_url,omitempty"`
+	Model        string `json:"model,omitempty"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	MaxTokens    int    `json:"max_tokens,omitempty"`
+	// ThinkingBudget turns on extended thinking with this many tokens.
+	ThinkingBudget int    `json:"thinking_budget,omitempty"`
+	Proxy          string `json:"proxy,omitempty"`
 }
 
 // Load reads config from the XDG config file, applying defaults.
 func Load() *Config {
 	cfg := &Config{
 		UserAgent: DefaultUserAgent,
-		Timeout:   DefaultTimeout,
 	}
 
 	path := FilePath()
@@ -87,19 +271,23 @@ func Load() *Config {
 	}
 
 	_ = json.Unmarshal(data, cfg)
+	loadSecrets(cfg)
 
 	if cfg.UserAgent == "" {
 		cfg.UserAgent = DefaultUserAgent
 	}
-	if cfg.Timeout == 0 {
-		cfg.Timeout = DefaultTimeout
-	}
-
 	return cfg
 }
 
-// Save writes the config to the XDG config file.
+// Save writes the config to the XDG config file. With the keychain secret
+// store, secrets go to the OS credential store instead; any it rejects are
+// written to the file.
 func Save(cfg *Config) error {
+	out, _ := storeSecrets(cfg)
+	return writeConfig(out)
+}
+
+func writeConfig(cfg *Config) error {
 	path := FilePath()
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o700); err != nil {
@@ -123,6 +311,19 @@ func filePathForApp(app, file string) string {
 	return filepath.Join(configBaseDir(), app, file)
 }
 
+// DataDir returns the XDG data directory for ask (e.g. ~/.local/share/ask).
+func DataDir() string {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "."
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, appName)
+}
+
 func configBaseDir() string {
 	dir := os.Getenv("XDG_CONFIG_HOME")
 	if dir == "" {
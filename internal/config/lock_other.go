@@ -0,0 +1,11 @@
+//go:build !unix
+
+package config
+
+import "os"
+
+// lockFile does not lock; state writes are still atomic, but concurrent
+// updates may lose one another's changes.
+func lockFile(f *os.File) error { return nil }
+
+func unlockFile(f *os.File) error { return nil }
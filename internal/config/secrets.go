@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Secret store names accepted in Config.SecretStore.
+const (
+	SecretStoreFile     = "file"
+	SecretStoreKeychain = "keychain"
+)
+
+// keychainService is the service name secrets are filed under in the OS
+// credential store (Keychain on macOS, the Secret Service via libsecret on
+// Linux, Credential Manager on Windows).
+const keychainService = "ask"
+
+// SecretBackend stores secrets outside the config file.
+type SecretBackend interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// keyringBackend keeps each secret as its own credential store item.
+type keyringBackend struct{}
+
+func (keyringBackend) Get(key string) (string, error) {
+	return keyring.Get(keychainService, key)
+}
+
+func (keyringBackend) Set(key, value string) error {
+	return keyring.Set(keychainService, key, value)
+}
+
+func (keyringBackend) Delete(key string) error {
+	err := keyring.Delete(keychainService, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// secretBackend is the backend used when SecretStore is "keychain".
+var secretBackend SecretBackend = keyringBackend{}
+
+// SecretFields maps the config key of every session token and cookie to
+// its field.
+func (c *Config) SecretFields() map[string]*string {
+	return map[string]*string{
+		"perplexity.cf_clearance":   &c.Perplexity.CfClearance,
+		"perplexity.session_cookie": &c.Perplexity.SessionCookie,
+		"chatgpt.session_token":     &c.ChatGPT.SessionToken,
+		"chatgpt.cf_clearance":      &c.ChatGPT.CfClearance,
+		"chatgpt.puid":              &c.ChatGPT.PUID,
+		"gemini.psid":               &c.Gemini.PSID,
+		"gemini.psidts":             &c.Gemini.PSIDTS,
+		"gemini.psidcc":             &c.Gemini.PSIDCC,
+		"grok.auth_token":           &c.Grok.AuthToken,
+		"grok.ct0":                  &c.Grok.CT0,
+		"claude.session_key":        &c.Claude.SessionKey,
+		"deepseek.session_id":       &c.DeepSeek.SessionID,
+		"deepseek.user_token":       &c.DeepSeek.UserToken,
+		"lechat.session_cookie":     &c.LeChat.SessionCookie,
+		"anthropic_api.api_key":     &c.AnthropicAPI.APIKey,
+	}
+}
+
+// MigrateSecrets switches cfg to the named secret store and saves it,
+// moving every secret across. Secrets the keychain rejects stay in the
+// file and are reported in the error.
+func MigrateSecrets(cfg *Config, store string) error {
+	switch store {
+	case SecretStoreKeychain:
+		if err := checkSecretStore(); err != nil {
+			return err
+		}
+	case SecretStoreFile:
+	default:
+		return fmt.Errorf("unknown secret store %q (use %s or %s)", store, SecretStoreKeychain, SecretStoreFile)
+	}
+	cfg.SecretStore = store
+	out, fallback := storeSecrets(cfg)
+	if err := writeConfig(out); err != nil {
+		return err
+	}
+	return fallback
+}
+
+// checkSecretStore verifies the OS credential store is usable by writing
+// and removing a probe item.
+func checkSecretStore() error {
+	const probe = "ask-probe"
+	if err := secretBackend.Set(probe, "ok"); err != nil {
+		return fmt.Errorf("OS keychain unavailable: %w", err)
+	}
+	return secretBackend.Delete(probe)
+}
+
+// loadSecrets fills empty secret fields from the credential store. Keys
+// that can't be read (a locked keychain, a dismissed prompt) are recorded
+// so a later Save doesn't mistake them for deleted secrets.
+func loadSecrets(cfg *Config) {
+	fields := cfg.SecretFields()
+	for _, key := range cfg.StoredSecrets {
+		field, ok := fields[key]
+		if !ok || *field != "" {
+			continue
+		}
+		v, err := secretBackend.Get(key)
+		if err != nil {
+			if cfg.unreadSecrets == nil {
+				cfg.unreadSecrets = make(map[string]bool)
+			}
+			cfg.unreadSecrets[key] = true
+			continue
+		}
+		*field = v
+	}
+}
+
+// storeSecrets returns the copy of cfg to write to disk. With the keychain
+// store, each non-empty secret is moved into the credential store and
+// blanked in the copy; a secret the store rejects (for example one over
+// the platform's size limit) stays in the file. With the file store, any
+// secrets left in the credential store from before are removed. The
+// returned error lists secrets that fell back to the file.
+func storeSecrets(cfg *Config) (*Config, error) {
+	out := *cfg
+	if cfg.SecretStore != SecretStoreKeychain && len(cfg.StoredSecrets) == 0 {
+		return &out, nil
+	}
+
+	var stored, failed []string
+	for key, field := range out.SecretFields() {
+		wasStored := slices.Contains(cfg.StoredSecrets, key)
+		switch {
+		case *field == "" && cfg.unreadSecrets[key]:
+			// Never loaded, so neither changed nor deleted: leave it be.
+			stored = append(stored, key)
+		case cfg.SecretStore != SecretStoreKeychain || *field == "":
+			if wasStored {
+				_ = secretBackend.Delete(key)
+			}
+		case secretBackend.Set(key, *field) == nil:
+			stored = append(stored, key)
+			*field = ""
+		default:
+			failed = append(failed, key)
+			if wasStored {
+				_ = secretBackend.Delete(key)
+			}
+		}
+	}
+	sort.Strings(stored)
+	cfg.StoredSecrets = stored
+	out.StoredSecrets = stored
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return &out, fmt.Errorf("kept %v in the config file: keychain write failed", failed)
+	}
+	return &out, nil
+}
@@ -16,6 +16,13 @@ type ConversationState struct {
 	ParentMessageID string            `json:"parent_message_id,omitempty"`
 	ResponseID      string            `json:"response_id,omitempty"`
 	Extra           map[string]string `json:"extra,omitempty"`
+	// Title is a short human-readable label shown by `ask sessions list`.
+	Title     string    `json:"title,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// Question is the last question asked, kept for `ask <provider> retry`.
+	Question string `json:"question,omitempty"`
+	// FollowUps holds the provider's suggested next questions, if any.
+	FollowUps []string `json:"follow_ups,omitempty"`
 }
 
 type AskAllConversationState struct {
@@ -29,6 +36,45 @@ type State struct {
 	LastConversation map[string]*ConversationState       `json:"last_conversation"`
 	AskAll           map[string]*AskAllConversationState `json:"ask_all,omitempty"`
 	LastAskAllID     string                              `json:"last_ask_all_id,omitempty"`
+	// DirConversations maps a working directory to the last conversation
+	// per provider used in it, for resume_per_directory.
+	DirConversations map[string]map[string]*ConversationState `json:"dir_conversations,omitempty"`
+	// ChatGPTFingerprint is the browser profile generated on first use.
+	ChatGPTFingerprint *FingerprintConfig `json:"chatgpt_fingerprint,omitempty"`
+	// GeminiCookiesRotatedAt is when the Gemini session cookies were last
+	// rotated, so runs in quick succession don't rotate again.
+	GeminiCookiesRotatedAt time.Time `json:"gemini_cookies_rotated_at,omitempty"`
+	// Aliases maps provider → friendly name → conversation ID.
+	Aliases map[string]map[string]string `json:"aliases,omitempty"`
+	// ChatGPTLimits are the quotas ChatGPT last reported, so asks can warn
+	// before a cap is hit.
+	ChatGPTLimits []ChatGPTLimitState `json:"chatgpt_limits,omitempty"`
+	// ChatGPTSession is the device ID, access token and prefetched
+	// sentinel handshake of the last run, reused while still valid.
+	ChatGPTSession *ChatGPTSessionState `json:"chatgpt_session,omitempty"`
+}
+
+// ChatGPTSessionState is ChatGPT client state kept between runs. The file
+// is readable only by its owner, like the config holding the session
+// cookie the token is derived from.
+type ChatGPTSessionState struct {
+	DeviceID    string    `json:"device_id"`
+	AccessToken string    `json:"access_token,omitempty"`
+	TokenExpiry time.Time `json:"token_expiry,omitempty"`
+	Account     string    `json:"account,omitempty"`
+	ChatToken   string    `json:"chat_token,omitempty"`
+	ProofToken  string    `json:"proof_token,omitempty"`
+	SentinelAt  time.Time `json:"sentinel_at,omitempty"`
+}
+
+// ChatGPTLimitState is one model or feature quota as last reported.
+type ChatGPTLimitState struct {
+	Name string `json:"name"`
+	// Remaining is -1 when only exhaustion is known.
+	Remaining  int       `json:"remaining"`
+	Exhausted  bool      `json:"exhausted,omitempty"`
+	ResetsAt   time.Time `json:"resets_at,omitempty"`
+	ObservedAt time.Time `json:"observed_at"`
 }
 
 // LoadState reads state from the XDG config directory, returning empty state if not found.
@@ -52,20 +98,73 @@ func LoadState() *State {
 	return s
 }
 
-// SaveState writes state to the XDG config directory.
+// SaveState writes state to the XDG config directory, replacing the file
+// atomically so a concurrent LoadState never sees half of it. To change
+// state saved by other runs in the meantime, use UpdateState.
 func SaveState(s *State) error {
+	unlock, err := lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeState(s)
+}
+
+// UpdateState applies fn to the saved state and saves the result, holding
+// the state lock throughout so concurrent runs don't lose each other's
+// changes.
+func UpdateState(fn func(*State)) error {
+	unlock, err := lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	s := LoadState()
+	fn(s)
+	return writeState(s)
+}
+
+// lockState takes the lock serializing state writes across processes and
+// returns the function releasing it.
+func lockState() (func(), error) {
 	path := StatePath()
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o700); err != nil {
-		return fmt.Errorf("creating state dir: %w", err)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating state dir: %w", err)
+	}
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening state lock: %w", err)
 	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("locking state: %w", err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
 
+func writeState(s *State) error {
+	path := StatePath()
 	data, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshalling state: %w", err)
 	}
 
-	return os.WriteFile(path, data, 0o600)
+	tmp, err := os.CreateTemp(filepath.Dir(path), stateFile+".*")
+	if err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
 // SetConversation stores conversation state for a provider.
@@ -76,6 +175,43 @@ func (s *State) SetConversation(provider string, cs *ConversationState) {
 	s.LastConversation[provider] = cs
 }
 
+// maxDirConversations bounds how many directories keep conversations;
+// the least recently used are dropped first.
+const maxDirConversations = 200
+
+// SetDirConversation stores conversation state for a provider in dir.
+func (s *State) SetDirConversation(dir, provider string, cs *ConversationState) {
+	if s.DirConversations == nil {
+		s.DirConversations = make(map[string]map[string]*ConversationState)
+	}
+	if s.DirConversations[dir] == nil {
+		s.DirConversations[dir] = make(map[string]*ConversationState)
+	}
+	s.DirConversations[dir][provider] = cs
+
+	for len(s.DirConversations) > maxDirConversations {
+		oldest, oldestAt := "", time.Time{}
+		for d, convs := range s.DirConversations {
+			var last time.Time
+			for _, c := range convs {
+				if c != nil && c.UpdatedAt.After(last) {
+					last = c.UpdatedAt
+				}
+			}
+			if oldest == "" || last.Before(oldestAt) {
+				oldest, oldestAt = d, last
+			}
+		}
+		delete(s.DirConversations, oldest)
+	}
+}
+
+// GetDirConversation returns the last conversation state for a provider
+// in dir, or nil.
+func (s *State) GetDirConversation(dir, provider string) *ConversationState {
+	return s.DirConversations[dir][provider]
+}
+
 func (s *State) SetAskAllConversation(id, question string, providers map[string]*ConversationState) {
 	if id == "" {
 		return
@@ -102,6 +238,50 @@ func (s *State) GetConversation(provider string) *ConversationState {
 	return s.LastConversation[provider]
 }
 
+// SetAlias names a provider conversation, replacing any previous target.
+func (s *State) SetAlias(provider, name, conversationID string) {
+	if s.Aliases == nil {
+		s.Aliases = make(map[string]map[string]string)
+	}
+	if s.Aliases[provider] == nil {
+		s.Aliases[provider] = make(map[string]string)
+	}
+	s.Aliases[provider][name] = conversationID
+}
+
+// RemoveAlias deletes a conversation alias and reports whether it existed.
+func (s *State) RemoveAlias(provider, name string) bool {
+	if _, ok := s.Aliases[provider][name]; !ok {
+		return false
+	}
+	delete(s.Aliases[provider], name)
+	if len(s.Aliases[provider]) == 0 {
+		delete(s.Aliases, provider)
+	}
+	return true
+}
+
+// RemoveAliasesTo deletes every alias of a provider that points at
+// conversationID and reports whether any existed.
+func (s *State) RemoveAliasesTo(provider, conversationID string) bool {
+	removed := false
+	for name, id := range s.Aliases[provider] {
+		if id == conversationID {
+			removed = s.RemoveAlias(provider, name) || removed
+		}
+	}
+	return removed
+}
+
+// ResolveAlias returns the conversation ID ref names for a provider, or
+// ref unchanged when it is not an alias.
+func (s *State) ResolveAlias(provider, ref string) string {
+	if id, ok := s.Aliases[provider][ref]; ok {
+		return id
+	}
+	return ref
+}
+
 // StatePath returns the path to the state file.
 func StatePath() string {
 	return filePathForApp(appName, stateFile)
@@ -0,0 +1,226 @@
+// Package cookies provides generic browser cookie extraction.
+//
+// chromium.go reads cookies from Chromium-based browsers on macOS.
+// Every Chromium derivative stores cookies in the same SQLite schema and
+// encrypts values with AES-128-CBC using a key derived from a per-browser
+// Keychain item ("<Browser> Safe Storage"). Only the profile directory and
+// Keychain names differ, so each browser is a row in chromiumBrowsers.
+package cookies
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// chromiumBrowser describes where a Chromium-based browser keeps its
+// cookies and how to unlock them.
+type chromiumBrowser struct {
+	Name        string   // config name, e.g. "brave"
+	Label       string   // display name
+	Dir         []string // user data dir under ~/Library/Application Support
+	SafeStorage string   // Keychain service holding the encryption password
+	Account     string   // Keychain account for SafeStorage
+}
+
+var chromiumBrowsers = []chromiumBrowser{
+	{Name: "chrome", Label: "Chrome", Dir: []string{"Google", "Chrome"}, SafeStorage: "Chrome Safe Storage", Account: "Chrome"},
+	{Name: "brave", Label: "Brave", Dir: []string{"BraveSoftware", "Brave-Browser"}, SafeStorage: "Brave Safe Storage", Account: "Brave"},
+	{Name: "edge", Label: "Edge", Dir: []string{"Microsoft Edge"}, SafeStorage: "Microsoft Edge Safe Storage", Account: "Microsoft Edge"},
+	{Name: "arc", Label: "Arc", Dir: []string{"Arc", "User Data"}, SafeStorage: "Arc Safe Storage", Account: "Arc"},
+	{Name: "vivaldi", Label: "Vivaldi", Dir: []string{"Vivaldi"}, SafeStorage: "Vivaldi Safe Storage", Account: "Vivaldi"},
+	{Name: "chromium", Label: "Chromium", Dir: []string{"Chromium"}, SafeStorage: "Chromium Safe Storage", Account: "Chromium"},
+}
+
+func findChromiumBrowser(name string) (chromiumBrowser, bool) {
+	for _, b := range chromiumBrowsers {
+		if b.Name == name {
+			return b, true
+		}
+	}
+	return chromiumBrowser{}, false
+}
+
+func extractChromium(ctx context.Context, b chromiumBrowser, domain string, nameSet map[string]bool, result *Result, logf func(string, ...any)) error {
+	paths, err := chromiumCookiePaths(b)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("%s cookie file not found", b.Label)
+	}
+
+	var key []byte
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		logf("  Searching %s cookies at %s ...", b.Label, path)
+
+		rows, version, err := readChromiumCookies(path, domain, nameSet)
+		if err != nil {
+			logf("    %v", err)
+			continue
+		}
+		for _, row := range rows {
+			if result.Cookies[row.name] != "" {
+				continue
+			}
+			value := row.value
+			if value == "" && len(row.encrypted) > 0 {
+				// Unlock lazily so browsers without matching cookies
+				// never trigger a Keychain prompt.
+				if key == nil {
+					if key, err = chromiumKey(b); err != nil {
+						return fmt.Errorf("unlocking %s: %w", b.SafeStorage, err)
+					}
+				}
+				value, err = decryptChromiumValue(key, row.encrypted, version)
+				if err != nil {
+					logf("    %s: %v", row.name, err)
+					continue
+				}
+			}
+			if value == "" {
+				continue
+			}
+			result.Cookies[row.name] = value
+			if result.Browser == "" {
+				result.Browser = b.Name
+			}
+			logf("    Found %s (domain=%s, browser=%s)", row.name, row.host, b.Name)
+		}
+	}
+
+	return nil
+}
+
+// chromiumCookiePaths returns the cookie databases for every profile of b,
+// Default first.
+func chromiumCookiePaths(b chromiumBrowser) ([]string, error) {
+	if runtime.GOOS != "darwin" {
+		return nil, fmt.Errorf("unsupported OS %q — only macOS is currently supported", runtime.GOOS)
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Join(append([]string{dir}, b.Dir...)...)
+
+	profiles := []string{filepath.Join(root, "Default")}
+	extra, _ := filepath.Glob(filepath.Join(root, "Profile *"))
+	profiles = append(profiles, extra...)
+
+	var paths []string
+	for _, profile := range profiles {
+		for _, name := range []string{filepath.Join("Network", "Cookies"), "Cookies"} {
+			path := filepath.Join(profile, name)
+			if _, err := os.Stat(path); err == nil {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	return paths, nil
+}
+
+type chromiumCookie struct {
+	host      string
+	name      string
+	value     string
+	encrypted []byte
+}
+
+// readChromiumCookies loads matching rows and the database schema version.
+// The file is opened immutable so a running browser's lock doesn't block it.
+func readChromiumCookies(path, domain string, nameSet map[string]bool) ([]chromiumCookie, int, error) {
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() + "?mode=ro&immutable=1"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer db.Close()
+
+	version := 0
+	var raw string
+	if err := db.QueryRow(`SELECT value FROM meta WHERE key = 'version'`).Scan(&raw); err == nil {
+		version, _ = strconv.Atoi(raw)
+	}
+
+	rows, err := db.Query(`SELECT host_key, name, value, encrypted_value FROM cookies
+		WHERE host_key = ? OR host_key LIKE ? ORDER BY expires_utc DESC`,
+		domain, "%."+strings.TrimPrefix(domain, "."))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var out []chromiumCookie
+	for rows.Next() {
+		var c chromiumCookie
+		if err := rows.Scan(&c.host, &c.name, &c.value, &c.encrypted); err != nil {
+			return nil, 0, err
+		}
+		if len(nameSet) > 0 && !nameSet[c.name] {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, version, rows.Err()
+}
+
+// chromiumKey derives the AES key from the browser's Keychain password.
+func chromiumKey(b chromiumBrowser) ([]byte, error) {
+	out, err := exec.Command("/usr/bin/security", "find-generic-password",
+		"-w", "-s", b.SafeStorage, "-a", b.Account).Output()
+	if err != nil {
+		return nil, err
+	}
+	password := strings.TrimSpace(string(out))
+	return pbkdf2.Key(sha1.New, password, []byte("saltysalt"), 1003, 16)
+}
+
+// decryptChromiumValue decrypts a "v10" cookie value. Databases from
+// schema version 24 on prefix the plaintext with a SHA-256 of the host.
+func decryptChromiumValue(key, encrypted []byte, version int) (string, error) {
+	if !bytes.HasPrefix(encrypted, []byte("v10")) {
+		return "", fmt.Errorf("unsupported encryption prefix %q", encrypted[:min(3, len(encrypted))])
+	}
+	data := encrypted[3:]
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return "", fmt.Errorf("invalid ciphertext length %d", len(data))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	iv := bytes.Repeat([]byte{' '}, aes.BlockSize)
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	pad := int(plain[len(plain)-1])
+	if pad == 0 || pad > aes.BlockSize || pad > len(plain) {
+		return "", fmt.Errorf("invalid padding")
+	}
+	plain = plain[:len(plain)-pad]
+
+	if version >= 24 && len(plain) >= 32 {
+		plain = plain[32:]
+	}
+	return string(plain), nil
+}
@@ -1,5 +1,6 @@
-// Package cookies provides generic browser cookie extraction via kooky.
-// Safari-first, Chrome as fallback.
+// Package cookies provides generic browser cookie extraction.
+// Safari is read via kooky; Chromium-based browsers (Chrome, Brave, Edge,
+// Arc, Vivaldi, Chromium) share one reader. Browsers are tried in order.
 package cookies
 
 import (
@@ -10,10 +11,22 @@ import (
 	"runtime"
 
 	"github.com/browserutils/kooky"
-	"github.com/browserutils/kooky/browser/chrome"
 	"github.com/browserutils/kooky/browser/safari"
 )
 
+// DefaultBrowsers is the search order used when none is configured. Safari
+// comes first because its cookies are plaintext (no Keychain prompt).
+var DefaultBrowsers = []string{"safari", "chrome", "brave", "edge", "arc", "vivaldi", "chromium"}
+
+// ValidBrowser reports whether name is a supported browser.
+func ValidBrowser(name string) bool {
+	if name == "safari" {
+		return true
+	}
+	_, ok := findChromiumBrowser(name)
+	return ok
+}
+
 // Spec describes which cookies to extract for a given domain.
 type Spec struct {
 	Domain string   // domain suffix to match (e.g. "perplexity.ai")
@@ -39,13 +52,16 @@ func (r *Result) HasAll(names []string) bool {
 	return true
 }
 
-// Extract reads cookies matching the spec from browsers.
-// Order: Safari first, then Chrome (Safari cookies are plaintext on macOS,
-// Chrome requires Keychain access).
-func Extract(ctx context.Context, spec Spec, logf func(string, ...any)) (*Result, error) {
+// Extract reads cookies matching the spec from browsers, trying each in
+// order until every requested name is found. A nil order uses
+// DefaultBrowsers.
+func Extract(ctx context.Context, spec Spec, order []string, logf func(string, ...any)) (*Result, error) {
 	if logf == nil {
 		logf = func(string, ...any) {}
 	}
+	if len(order) == 0 {
+		order = DefaultBrowsers
+	}
 
 	result := &Result{Cookies: make(map[string]string)}
 	nameSet := make(map[string]bool, len(spec.Names))
@@ -53,17 +69,24 @@ func Extract(ctx context.Context, spec Spec, logf func(string, ...any)) (*Result
 		nameSet[n] = true
 	}
 
-	// Safari first (no Keychain prompt).
-	if err := extractSafari(ctx, spec.Domain, nameSet, result, logf); err != nil {
-		logf("  Safari: %v", err)
-	}
-	if result.HasAll(spec.Names) {
-		return result, nil
-	}
-
-	// Chrome fallback.
-	if err := extractChrome(ctx, spec.Domain, nameSet, result, logf); err != nil {
-		logf("  Chrome: %v", err)
+	for _, name := range order {
+		if result.HasAll(spec.Names) {
+			break
+		}
+		if name == "safari" {
+			if err := extractSafari(ctx, spec.Domain, nameSet, result, logf); err != nil {
+				logf("  Safari: %v", err)
+			}
+			continue
+		}
+		b, ok := findChromiumBrowser(name)
+		if !ok {
+			logf("  %s: unsupported browser", name)
+			continue
+		}
+		if err := extractChromium(ctx, b, spec.Domain, nameSet, result, logf); err != nil {
+			logf("  %s: %v", b.Label, err)
+		}
 	}
 
 	return result, nil
@@ -71,7 +94,7 @@ func Extract(ctx context.Context, spec Spec, logf func(string, ...any)) (*Result
 
 // ExtractMulti extracts cookies for multiple specs at once.
 // It stops searching additional specs once all unique cookie names are found.
-func ExtractMulti(ctx context.Context, specs []Spec, logf func(string, ...any)) (*Result, error) {
+func ExtractMulti(ctx context.Context, specs []Spec, order []string, logf func(string, ...any)) (*Result, error) {
 	if logf == nil {
 		logf = func(string, ...any) {}
 	}
@@ -96,7 +119,7 @@ func ExtractMulti(ctx context.Context, specs []Spec, logf func(string, ...any))
 		if haveAll {
 			break
 		}
-		r, err := Extract(ctx, spec, logf)
+		r, err := Extract(ctx, spec, order, logf)
 		if err != nil {
 			logf("  %s: %v", spec.Domain, err)
 			continue
@@ -157,61 +180,6 @@ func extractSafari(ctx context.Context, domain string, nameSet map[string]bool,
 	return nil
 }
 
-func extractChrome(ctx context.Context, domain string, nameSet map[string]bool, result *Result, logf func(string, ...any)) error {
-	path, err := chromeCookiePath()
-	if err != nil {
-		return err
-	}
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return fmt.Errorf("Chrome cookie file not found at %s", path)
-	}
-
-	logf("  Searching Chrome cookies at %s ...", path)
-
-	seq := chrome.TraverseCookies(path,
-		kooky.DomainHasSuffix(domain),
-	).OnlyCookies()
-
-	for cookie := range seq {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		if cookie == nil || cookie.Value == "" {
-			continue
-		}
-		if len(nameSet) > 0 && !nameSet[cookie.Name] {
-			continue
-		}
-		if existing := result.Cookies[cookie.Name]; existing != "" {
-			continue
-		}
-		result.Cookies[cookie.Name] = cookie.Value
-		if result.Browser == "" {
-			result.Browser = "chrome"
-		}
-		logf("    Found %s (domain=%s, browser=chrome)", cookie.Name, cookie.Domain)
-	}
-
-	return nil
-}
-
-func chromeCookiePath() (string, error) {
-	if runtime.GOOS != "darwin" {
-		return "", fmt.Errorf("unsupported OS %q — only macOS is currently supported", runtime.GOOS)
-	}
-	dir, err := os.UserConfigDir()
-	if err != nil {
-		return "", err
-	}
-	networkPath := filepath.Join(dir, "Google", "Chrome", "Default", "Network", "Cookies")
-	if _, err := os.Stat(networkPath); err == nil {
-		return networkPath, nil
-	}
-	return filepath.Join(dir, "Google", "Chrome", "Default", "Cookies"), nil
-}
-
 func safariCookiePaths() ([]string, error) {
 	if runtime.GOOS != "darwin" {
 		return nil, fmt.Errorf("unsupported OS %q — only macOS is currently supported", runtime.GOOS)
@@ -0,0 +1,135 @@
+package cookies
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cookie is a single cookie read from an export file.
+type Cookie struct {
+	Domain  string
+	Name    string
+	Value   string
+	Expires time.Time // zero for session cookies
+}
+
+// ReadFile parses a Netscape cookies.txt file or a JSON export (an array
+// of objects with domain, name, value, and an optional expirationDate or
+// expires, as written by most browser extensions). The format is detected
+// from the content.
+func ReadFile(path string) ([]Cookie, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return parseJSON(trimmed)
+	}
+	return parseNetscape(data)
+}
+
+// parseNetscape reads the tab-separated cookies.txt format:
+// domain, include-subdomains, path, secure, expires, name, value.
+func parseNetscape(data []byte) ([]Cookie, error) {
+	var out []Cookie
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// curl marks HttpOnly cookies with this prefix on otherwise
+		// normal lines.
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("line %d: expected 7 tab-separated fields, got %d", n, len(fields))
+		}
+		c := Cookie{Domain: fields[0], Name: fields[5], Value: fields[6]}
+		if sec, err := strconv.ParseInt(fields[4], 10, 64); err == nil && sec > 0 {
+			c.Expires = time.Unix(sec, 0)
+		}
+		out = append(out, c)
+	}
+	return out, scanner.Err()
+}
+
+type jsonCookie struct {
+	Domain         string  `json:"domain"`
+	Name           string  `json:"name"`
+	Value          string  `json:"value"`
+	ExpirationDate float64 `json:"expirationDate"`
+	Expires        float64 `json:"expires"`
+}
+
+func parseJSON(data []byte) ([]Cookie, error) {
+	var raw []jsonCookie
+	if err := json.Unmarshal(data, &raw); err != nil {
+		// Some tools wrap the list: {"cookies": [...]}.
+		var wrapped struct {
+			Cookies []jsonCookie `json:"cookies"`
+		}
+		if err2 := json.Unmarshal(data, &wrapped); err2 != nil || wrapped.Cookies == nil {
+			return nil, fmt.Errorf("parsing JSON cookies: %w", err)
+		}
+		raw = wrapped.Cookies
+	}
+
+	out := make([]Cookie, 0, len(raw))
+	for _, r := range raw {
+		c := Cookie{Domain: r.Domain, Name: r.Name, Value: r.Value}
+		exp := r.ExpirationDate
+		if exp == 0 {
+			exp = r.Expires
+		}
+		if exp > 0 {
+			c.Expires = time.Unix(int64(exp), 0)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// Match returns the cookies from list that satisfy spec. Expired cookies
+// are skipped; when a name appears more than once the latest-expiring
+// value wins.
+func Match(list []Cookie, spec Spec) map[string]string {
+	nameSet := make(map[string]bool, len(spec.Names))
+	for _, n := range spec.Names {
+		nameSet[n] = true
+	}
+	suffix := strings.TrimPrefix(spec.Domain, ".")
+
+	now := time.Now()
+	best := make(map[string]Cookie)
+	for _, c := range list {
+		domain := strings.TrimPrefix(c.Domain, ".")
+		if domain != suffix && !strings.HasSuffix(domain, "."+suffix) {
+			continue
+		}
+		if c.Value == "" || (len(nameSet) > 0 && !nameSet[c.Name]) {
+			continue
+		}
+		if !c.Expires.IsZero() && c.Expires.Before(now) {
+			continue
+		}
+		if prev, ok := best[c.Name]; ok && !prev.Expires.IsZero() && (c.Expires.IsZero() || !c.Expires.After(prev.Expires)) {
+			continue
+		}
+		best[c.Name] = c
+	}
+
+	out := make(map[string]string, len(best))
+	for name, c := range best {
+		out[name] = c.Value
+	}
+	return out
+}
@@ -0,0 +1,218 @@
+// Package export renders conversation transcripts as Markdown, JSON, or
+// standalone HTML.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{"md", "json", "html"}
+
+// Write renders t in the given format to w.
+func Write(w io.Writer, format, providerName string, t *provider.Transcript) error {
+	switch strings.ToLower(format) {
+	case "md", "markdown":
+		return Markdown(w, providerName, t)
+	case "json":
+		return JSON(w, providerName, t)
+	case "html":
+		return HTML(w, providerName, t)
+	}
+	return fmt.Errorf("unsupported format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+// Extension returns the file extension for a format.
+func Extension(format string) string {
+	switch strings.ToLower(format) {
+	case "markdown":
+		return "md"
+	}
+	return strings.ToLower(format)
+}
+
+// Markdown writes t as a Markdown document.
+func Markdown(w io.Writer, providerName string, t *provider.Transcript) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", title(t))
+	fmt.Fprintf(&sb, "- Provider: %s\n", providerName)
+	fmt.Fprintf(&sb, "- Conversation: `%s`\n", t.ID)
+	if !t.CreatedAt.IsZero() {
+		fmt.Fprintf(&sb, "- Created: %s\n", stamp(t.CreatedAt))
+	}
+	if !t.UpdatedAt.IsZero() {
+		fmt.Fprintf(&sb, "- Updated: %s\n", stamp(t.UpdatedAt))
+	}
+
+	for _, m := range t.Messages {
+		fmt.Fprintf(&sb, "\n## %s", roleLabel(m.Role))
+		if m.Model != "" {
+			fmt.Fprintf(&sb, " (%s)", m.Model)
+		}
+		sb.WriteString("\n\n")
+		if !m.CreatedAt.IsZero() {
+			fmt.Fprintf(&sb, "_%s_\n\n", stamp(m.CreatedAt))
+		}
+		sb.WriteString(strings.TrimSpace(m.Text))
+		sb.WriteString("\n")
+		if len(m.Sources) > 0 {
+			sb.WriteString("\n**Sources**\n\n")
+			for i, src := range m.Sources {
+				fmt.Fprintf(&sb, "%d. [%s](%s)\n", i+1, sourceName(src), src.URL)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+type jsonSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonMessage struct {
+	ID        string       `json:"id,omitempty"`
+	Role      string       `json:"role"`
+	Text      string       `json:"text"`
+	Model     string       `json:"model,omitempty"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+	Sources   []jsonSource `json:"sources,omitempty"`
+}
+
+type jsonTranscript struct {
+	Provider  string        `json:"provider"`
+	ID        string        `json:"id"`
+	Title     string        `json:"title,omitempty"`
+	CreatedAt *time.Time    `json:"created_at,omitempty"`
+	UpdatedAt *time.Time    `json:"updated_at,omitempty"`
+	Messages  []jsonMessage `json:"messages"`
+}
+
+// JSON writes t as an indented JSON document.
+func JSON(w io.Writer, providerName string, t *provider.Transcript) error {
+	out := jsonTranscript{
+		Provider:  providerName,
+		ID:        t.ID,
+		Title:     t.Title,
+		CreatedAt: timePtr(t.CreatedAt),
+		UpdatedAt: timePtr(t.UpdatedAt),
+		Messages:  make([]jsonMessage, 0, len(t.Messages)),
+	}
+	for _, m := range t.Messages {
+		jm := jsonMessage{ID: m.ID, Role: m.Role, Text: m.Text, Model: m.Model, CreatedAt: timePtr(m.CreatedAt)}
+		for _, src := range m.Sources {
+			jm.Sources = append(jm.Sources, jsonSource{Name: src.Name, URL: src.URL})
+		}
+		out.Messages = append(out.Messages, jm)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+const htmlHead = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 50rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #222; }
+header { border-bottom: 1px solid #ddd; margin-bottom: 1.5rem; }
+.meta { color: #666; font-size: 0.9rem; }
+.msg { margin: 1rem 0; padding: 0.75rem 1rem; border-radius: 8px; }
+.user { background: #eef4ff; }
+.assistant { background: #f6f6f6; }
+.role { font-weight: 600; }
+.text { white-space: pre-wrap; }
+.sources { font-size: 0.9rem; }
+</style>
+</head>
+<body>
+`
+
+// HTML writes t as a standalone HTML page.
+func HTML(w io.Writer, providerName string, t *provider.Transcript) error {
+	var sb strings.Builder
+	esc := html.EscapeString
+
+	fmt.Fprintf(&sb, htmlHead, esc(title(t)))
+	fmt.Fprintf(&sb, "<header>\n<h1>%s</h1>\n<p class=\"meta\">%s · <code>%s</code>", esc(title(t)), esc(providerName), esc(t.ID))
+	if !t.CreatedAt.IsZero() {
+		fmt.Fprintf(&sb, " · %s", esc(stamp(t.CreatedAt)))
+	}
+	sb.WriteString("</p>\n</header>\n")
+
+	for _, m := range t.Messages {
+		role := "assistant"
+		if m.Role == "user" {
+			role = "user"
+		}
+		fmt.Fprintf(&sb, "<section class=\"msg %s\">\n<div class=\"role\">%s", role, esc(roleLabel(m.Role)))
+		if m.Model != "" {
+			fmt.Fprintf(&sb, " <span class=\"meta\">(%s)</span>", esc(m.Model))
+		}
+		if !m.CreatedAt.IsZero() {
+			fmt.Fprintf(&sb, " <span class=\"meta\">%s</span>", esc(stamp(m.CreatedAt)))
+		}
+		fmt.Fprintf(&sb, "</div>\n<div class=\"text\">%s</div>\n", esc(strings.TrimSpace(m.Text)))
+		if len(m.Sources) > 0 {
+			sb.WriteString("<ol class=\"sources\">\n")
+			for _, src := range m.Sources {
+				fmt.Fprintf(&sb, "<li><a href=\"%s\">%s</a></li>\n", esc(src.URL), esc(sourceName(src)))
+			}
+			sb.WriteString("</ol>\n")
+		}
+		sb.WriteString("</section>\n")
+	}
+	sb.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func title(t *provider.Transcript) string {
+	if strings.TrimSpace(t.Title) != "" {
+		return t.Title
+	}
+	return "Untitled conversation"
+}
+
+func roleLabel(role string) string {
+	switch role {
+	case "user":
+		return "User"
+	case "assistant":
+		return "Assistant"
+	case "":
+		return "Unknown"
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
+
+func sourceName(src provider.Source) string {
+	if src.Name != "" {
+		return src.Name
+	}
+	return src.URL
+}
+
+func stamp(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04 MST")
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
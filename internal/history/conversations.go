@@ -0,0 +1,187 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Conversation is the metadata of one remote conversation mirrored by
+// ask sync: enough to find it offline, not its messages.
+type Conversation struct {
+	Provider  string
+	ID        string
+	Title     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	SyncedAt  time.Time
+}
+
+// MirroredConversations returns the mirrored conversations of provider,
+// keyed by conversation ID.
+func (s *Store) MirroredConversations(provider string) (map[string]Conversation, error) {
+	rows, err := s.db.Query(`SELECT id, title, created_at, updated_at, synced_at FROM conversations WHERE provider = ?`, provider)
+	if err != nil {
+		return nil, fmt.Errorf("reading conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := map[string]Conversation{}
+	for rows.Next() {
+		var (
+			c                        = Conversation{Provider: provider}
+			created, updated, synced int64
+		)
+		if err := rows.Scan(&c.ID, &c.Title, &created, &updated, &synced); err != nil {
+			return nil, err
+		}
+		c.CreatedAt = fromUnixMilli(created)
+		c.UpdatedAt = fromUnixMilli(updated)
+		c.SyncedAt = fromUnixMilli(synced)
+		conversations[c.ID] = c
+	}
+	return conversations, rows.Err()
+}
+
+// SaveConversations inserts conversations or updates their title and
+// timestamps.
+func (s *Store) SaveConversations(conversations []Conversation) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("saving conversations: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, c := range conversations {
+		synced := c.SyncedAt
+		if synced.IsZero() {
+			synced = now
+		}
+		_, err := tx.Exec(
+			`INSERT INTO conversations (provider, id, title, created_at, updated_at, synced_at)
+			 VALUES (?, ?, ?, ?, ?, ?)
+			 ON CONFLICT (provider, id) DO UPDATE SET
+			   title = excluded.title, created_at = excluded.created_at,
+			   updated_at = excluded.updated_at, synced_at = excluded.synced_at`,
+			c.Provider, c.ID, c.Title, unixMilli(c.CreatedAt), unixMilli(c.UpdatedAt), synced.UnixMilli(),
+		)
+		if err != nil {
+			return fmt.Errorf("saving conversations: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// PruneConversations removes the mirrored conversations of provider whose
+// IDs are not in keep, and returns how many it removed.
+func (s *Store) PruneConversations(provider string, keep map[string]bool) (int, error) {
+	mirrored, err := s.MirroredConversations(provider)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for id := range mirrored {
+		if keep[id] {
+			continue
+		}
+		if _, err := s.db.Exec(`DELETE FROM conversations WHERE provider = ? AND id = ?`, provider, id); err != nil {
+			return removed, fmt.Errorf("pruning conversations: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// SearchOptions filters Search and SearchConversations results.
+type SearchOptions struct {
+	Limit    int
+	Provider string
+}
+
+// SearchConversations returns the mirrored conversations whose title
+// contains term (case-insensitively), most recently updated first.
+func (s *Store) SearchConversations(term string, opts SearchOptions) ([]Conversation, error) {
+	query := `SELECT provider, id, title, created_at, updated_at, synced_at FROM conversations
+		WHERE title LIKE ? ESCAPE '\'`
+	args := []any{likePattern(term)}
+	if opts.Provider != "" {
+		query += ` AND provider = ?`
+		args = append(args, opts.Provider)
+	}
+	query += ` ORDER BY MAX(updated_at, created_at) DESC LIMIT ?`
+	args = append(args, searchLimit(opts.Limit))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("searching conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var conversations []Conversation
+	for rows.Next() {
+		var (
+			c                        Conversation
+			created, updated, synced int64
+		)
+		if err := rows.Scan(&c.Provider, &c.ID, &c.Title, &created, &updated, &synced); err != nil {
+			return nil, err
+		}
+		c.CreatedAt = fromUnixMilli(created)
+		c.UpdatedAt = fromUnixMilli(updated)
+		c.SyncedAt = fromUnixMilli(synced)
+		conversations = append(conversations, c)
+	}
+	return conversations, rows.Err()
+}
+
+// Search returns the recorded entries whose question or answer contains
+// term (case-insensitively), most recent first.
+func (s *Store) Search(term string, opts SearchOptions) ([]Entry, error) {
+	query := `SELECT ` + columns + ` FROM entries
+		WHERE (question LIKE ? ESCAPE '\' OR answer LIKE ? ESCAPE '\')`
+	pattern := likePattern(term)
+	args := []any{pattern, pattern}
+	if opts.Provider != "" {
+		query += ` AND provider = ?`
+		args = append(args, opts.Provider)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, searchLimit(opts.Limit))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("searching history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+// likePattern matches term anywhere, taking its % and _ literally.
+func likePattern(term string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(term) + "%"
+}
+
+func searchLimit(limit int) int {
+	if limit <= 0 {
+		return 20
+	}
+	return limit
+}
+
+func fromUnixMilli(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,214 @@
+// Package history records every question and answer in a local SQLite
+// database under the XDG data directory.
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+
+	"github.com/kyupark/ask/internal/config"
+)
+
+const dbFile = "history.db"
+
+// ErrNotFound is returned when an entry does not exist.
+var ErrNotFound = errors.New("history entry not found")
+
+// Source is a citation attached to an answer.
+type Source struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Entry is a single recorded question/answer exchange.
+type Entry struct {
+	ID             int64
+	Provider       string
+	Model          string
+	ConversationID string
+	Question       string
+	Answer         string
+	Sources        []Source
+	Error          string
+	CreatedAt      time.Time
+	CompletedAt    time.Time
+}
+
+// Store is an open history database.
+type Store struct {
+	db *sql.DB
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS entries (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	provider        TEXT NOT NULL,
+	model           TEXT NOT NULL DEFAULT '',
+	conversation_id TEXT NOT NULL DEFAULT '',
+	question        TEXT NOT NULL,
+	answer          TEXT NOT NULL DEFAULT '',
+	sources         TEXT NOT NULL DEFAULT '[]',
+	error           TEXT NOT NULL DEFAULT '',
+	created_at      INTEGER NOT NULL,
+	completed_at    INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS entries_created_at ON entries(created_at);
+CREATE INDEX IF NOT EXISTS entries_conversation ON entries(provider, conversation_id);
+CREATE TABLE IF NOT EXISTS usage (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	provider       TEXT NOT NULL,
+	model          TEXT NOT NULL DEFAULT '',
+	prompt_chars   INTEGER NOT NULL DEFAULT 0,
+	response_chars INTEGER NOT NULL DEFAULT 0,
+	duration_ms    INTEGER NOT NULL DEFAULT 0,
+	failed         INTEGER NOT NULL DEFAULT 0,
+	created_at     INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS usage_created_at ON usage(created_at);
+CREATE TABLE IF NOT EXISTS conversations (
+	provider   TEXT NOT NULL,
+	id         TEXT NOT NULL,
+	title      TEXT NOT NULL DEFAULT '',
+	created_at INTEGER NOT NULL DEFAULT 0,
+	updated_at INTEGER NOT NULL DEFAULT 0,
+	synced_at  INTEGER NOT NULL,
+	PRIMARY KEY (provider, id)
+);
+`
+
+// Path returns the location of the history database.
+func Path() string {
+	return filepath.Join(config.DataDir(), dbFile)
+}
+
+// Open opens (creating if needed) the history database.
+func Open() (*Store, error) {
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating data dir: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("initializing history: %w", err)
+	}
+	_ = os.Chmod(path, 0o600)
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error { return s.db.Close() }
+
+// Add inserts e and sets its ID.
+func (s *Store) Add(e *Entry) error {
+	sources, _ := json.Marshal(e.Sources)
+	if e.Sources == nil {
+		sources = []byte("[]")
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	res, err := s.db.Exec(
+		`INSERT INTO entries (provider, model, conversation_id, question, answer, sources, error, created_at, completed_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.Provider, e.Model, e.ConversationID, e.Question, e.Answer, string(sources), e.Error,
+		e.CreatedAt.UnixMilli(), unixMilli(e.CompletedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("recording history: %w", err)
+	}
+	e.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// ListOptions filters List results.
+type ListOptions struct {
+	Limit    int
+	Provider string
+}
+
+// List returns the most recent entries first.
+func (s *Store) List(opts ListOptions) ([]Entry, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	query := `SELECT ` + columns + ` FROM entries`
+	var args []any
+	if opts.Provider != "" {
+		query += ` WHERE provider = ?`
+		args = append(args, opts.Provider)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+// Get returns the entry with the given ID.
+func (s *Store) Get(id int64) (*Entry, error) {
+	row := s.db.QueryRow(`SELECT `+columns+` FROM entries WHERE id = ?`, id)
+	e, err := scanEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return e, err
+}
+
+const columns = `id, provider, model, conversation_id, question, answer, sources, error, created_at, completed_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntry(row scanner) (*Entry, error) {
+	var (
+		e                    Entry
+		sources              string
+		created, completedAt int64
+	)
+	err := row.Scan(&e.ID, &e.Provider, &e.Model, &e.ConversationID, &e.Question, &e.Answer,
+		&sources, &e.Error, &created, &completedAt)
+	if err != nil {
+		return nil, err
+	}
+	_ = json.Unmarshal([]byte(sources), &e.Sources)
+	e.CreatedAt = time.UnixMilli(created)
+	if completedAt > 0 {
+		e.CompletedAt = time.UnixMilli(completedAt)
+	}
+	return &e, nil
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
@@ -0,0 +1,70 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// Usage is the metadata of one ask. It holds no question or answer text,
+// so it is recorded even when history is disabled or the ask is
+// incognito.
+type Usage struct {
+	Provider      string
+	Model         string
+	PromptChars   int
+	ResponseChars int
+	Duration      time.Duration
+	Failed        bool
+	CreatedAt     time.Time
+}
+
+// AddUsage records u.
+func (s *Store) AddUsage(u Usage) error {
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = time.Now()
+	}
+	failed := 0
+	if u.Failed {
+		failed = 1
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO usage (provider, model, prompt_chars, response_chars, duration_ms, failed, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		u.Provider, u.Model, u.PromptChars, u.ResponseChars, u.Duration.Milliseconds(), failed,
+		u.CreatedAt.UnixMilli(),
+	)
+	if err != nil {
+		return fmt.Errorf("recording usage: %w", err)
+	}
+	return nil
+}
+
+// UsageSince returns the usage recorded at or after since, oldest first.
+func (s *Store) UsageSince(since time.Time) ([]Usage, error) {
+	rows, err := s.db.Query(
+		`SELECT provider, model, prompt_chars, response_chars, duration_ms, failed, created_at
+		 FROM usage WHERE created_at >= ? ORDER BY created_at, id`,
+		unixMilli(since),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reading usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []Usage
+	for rows.Next() {
+		var (
+			u                   Usage
+			durationMs, created int64
+			failed              int
+		)
+		if err := rows.Scan(&u.Provider, &u.Model, &u.PromptChars, &u.ResponseChars, &durationMs, &failed, &created); err != nil {
+			return nil, err
+		}
+		u.Duration = time.Duration(durationMs) * time.Millisecond
+		u.Failed = failed != 0
+		u.CreatedAt = time.UnixMilli(created)
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
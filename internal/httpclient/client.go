@@ -5,25 +5,48 @@ package httpclient
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"net/url"
 	"time"
 
 	utls "github.com/refraction-networking/utls"
-	"golang.org/x/net/http2"
 )
 
 // New returns an *http.Client whose TLS handshake looks like Chrome.
-// Every HTTPS request gets a fresh TLS connection (no pooling) which is
-// fine for CLI workloads that make only a handful of requests.
+// HTTPS connections are pooled per origin across every client New
+// returns and closed after idleConnTimeout of disuse.
+// Requests go through the proxy named by HTTPS_PROXY or ALL_PROXY, if any.
 func New(timeout time.Duration) *http.Client {
+	return NewWithProxy(timeout, "")
+}
+
+// NewWithProxy is like New but sends requests through proxyURL (http://,
+// https://, socks5:// or socks5h://) instead of the environment's proxy.
+// An empty proxyURL behaves like New.
+//
+// timeout caps the whole request including reading the body; zero means
+// no cap, leaving the connect, response-header and stream-idle limits
+// from SetTimeouts in charge.
+func NewWithProxy(timeout time.Duration, proxyURL string) *http.Client {
+	dialer := &net.Dialer{KeepAlive: 30 * time.Second}
+	cfg := proxyConfig(proxyURL)
 	return &http.Client{
 		Timeout: timeout,
-		Transport: &chromeTransport{dialer: &net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
+		Transport: &timeoutTransport{base: &chromeTransport{
+			dialer: dialer,
+			proxy:  cfg.ProxyFunc(),
+			plain: &http.Transport{
+				Proxy: proxyFunc(proxyURL),
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					ctx, cancel := context.WithTimeout(ctx, currentTimeouts().Connect)
+					defer cancel()
+					return dialer.DialContext(ctx, network, addr)
+				},
+				DisableKeepAlives: true,
+			},
 		}},
 	}
 }
@@ -31,52 +54,98 @@ func New(timeout time.Duration) *http.Client {
 // chromeTransport implements http.RoundTripper with uTLS Chrome fingerprint.
 type chromeTransport struct {
 	dialer *net.Dialer
+	proxy  func(*url.URL) (*url.URL, error)
+	// plain carries non-HTTPS requests.
+	plain *http.Transport
 }
 
 func (t *chromeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if req.URL.Scheme != "https" {
-		return http.DefaultTransport.RoundTrip(req)
+		return t.plain.RoundTrip(req)
 	}
 
 	host := req.URL.Hostname()
-	port := portFromURL(req.URL)
-	addr := net.JoinHostPort(host, port)
-
-	rawConn, err := t.dialer.DialContext(req.Context(), "tcp", addr)
+	addr := net.JoinHostPort(host, portFromURL(req.URL))
+	proxyURL, err := t.proxy(req.URL)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid proxy: %w", err)
 	}
+	hc := hostConnsFor(addr, proxyURL)
 
-	tlsConn := utls.UClient(rawConn, &utls.Config{
-		ServerName: host,
-		NextProtos: []string{"h2", "http/1.1"},
-	}, utls.HelloChrome_Auto)
+	if cc := hc.pooledH2(); cc != nil {
+		resp, err := cc.RoundTrip(req)
+		if err == nil || !isReplayable(req) || req.Context().Err() != nil {
+			return resp, err
+		}
+		// The pooled connection died under us; retry on a fresh one.
+	}
+	if h1t := hc.pooledH1(); h1t != nil {
+		return h1t.RoundTrip(req)
+	}
 
-	if err := tlsConn.Handshake(); err != nil {
-		rawConn.Close()
+	tlsConn, err := t.dialTLS(req.Context(), host, addr, proxyURL, "h2", "http/1.1")
+	if err != nil {
 		return nil, err
 	}
 
 	// Cloudflare strongly prefers HTTP/2.
 	if tlsConn.ConnectionState().NegotiatedProtocol == "h2" {
-		h2t := &http2.Transport{
-			DialTLSContext: func(_ context.Context, _, _ string, _ *tls.Config) (net.Conn, error) {
-				return tlsConn, nil
-			},
+		cc, err := h2Transport.NewClientConn(tlsConn)
+		if err != nil {
+			tlsConn.Close()
+			return nil, err
 		}
-		return h2t.RoundTrip(req)
+		hc.addH2(cc)
+		return cc.RoundTrip(req)
 	}
 
-	// Fallback to HTTP/1.1.
-	h1t := &http.Transport{
-		DialTLSContext: func(_ context.Context, _, _ string) (net.Conn, error) {
-			return tlsConn, nil
-		},
-		DisableKeepAlives: true,
-	}
+	// Fallback to HTTP/1.1 with keep-alive. Later connections to this
+	// origin offer only http/1.1 so the transport never gets an h2 conn.
+	h1t := hc.h1Transport(func(ctx context.Context) (*utls.UConn, error) {
+		return t.dialTLS(ctx, host, addr, proxyURL, "http/1.1")
+	}, tlsConn)
 	return h1t.RoundTrip(req)
 }
 
+// dialTLS connects to addr, through proxyURL if set, and performs a
+// Chrome-fingerprinted TLS handshake offering protos via ALPN.
+func (t *chromeTransport) dialTLS(ctx context.Context, host, addr string, proxyURL *url.URL, protos ...string) (*utls.UConn, error) {
+	connect := currentTimeouts().Connect
+	ctx, cancel := context.WithTimeout(ctx, connect)
+	defer cancel()
+
+	var rawConn net.Conn
+	var err error
+	if proxyURL != nil {
+		rawConn, err = t.dialProxy(ctx, proxyURL, addr)
+	} else {
+		rawConn, err = t.dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, connectError(ctx, connect, err)
+	}
+
+	tlsConn := utls.UClient(rawConn, &utls.Config{
+		ServerName: host,
+		NextProtos: protos,
+	}, utls.HelloChrome_Auto)
+
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		rawConn.Close()
+		return nil, connectError(ctx, connect, err)
+	}
+	return tlsConn, nil
+}
+
+// connectError reports err as a connect timeout when the connect deadline,
+// not the caller, ended the attempt.
+func connectError(ctx context.Context, connect time.Duration, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{Phase: "connect", After: connect}
+	}
+	return err
+}
+
 func portFromURL(u *url.URL) string {
 	if p := u.Port(); p != "" {
 		return p
@@ -0,0 +1,301 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// harBodyLimit caps how much of each body a HAR entry keeps.
+const harBodyLimit = 1 << 20
+
+// redactedValue replaces secrets in recorded traffic.
+const redactedValue = "REDACTED"
+
+// harSecretHeaders are replaced with redactedValue in recordings.
+var harSecretHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-csrf-token":        true,
+	"x-api-key":           true,
+}
+
+// harSecretFields matches JSON string fields holding tokens.
+var harSecretFields = regexp.MustCompile(`"(accessToken|access_token|sessionToken|session_token|refresh_token|id_token|token)"(\s*:\s*)"[^"]*"`)
+
+// harSecretForm matches the Google "at" anti-forgery token in form bodies.
+var harSecretForm = regexp.MustCompile(`(^|&)(at)=[^&]*`)
+
+// har collects traffic while recording is on.
+var har struct {
+	sync.Mutex
+	on      bool
+	creator string
+	records []*harRecord
+}
+
+// RecordHAR starts recording every request made through this package.
+// creatorVersion is written into the HAR file's creator block.
+func RecordHAR(creatorVersion string) {
+	har.Lock()
+	har.on = true
+	har.creator = creatorVersion
+	har.Unlock()
+}
+
+// WriteHAR saves the recorded traffic to path as a HAR 1.2 file. Cookies,
+// authorization headers and token fields are redacted.
+func WriteHAR(path string) error {
+	har.Lock()
+	entries := make([]harEntry, len(har.records))
+	for i, r := range har.records {
+		r.mu.Lock()
+		entries[i] = r.entry
+		r.mu.Unlock()
+	}
+	creator := har.creator
+	har.Unlock()
+
+	doc := map[string]any{
+		"log": map[string]any{
+			"version": "1.2",
+			"creator": map[string]string{"name": "ask", "version": creator},
+			"entries": entries,
+		},
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func harRecording() bool {
+	har.Lock()
+	defer har.Unlock()
+	return har.on
+}
+
+// harRecord is an entry still being filled in as the response streams.
+type harRecord struct {
+	mu    sync.Mutex
+	entry harEntry
+	start time.Time
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// startHAREntry records req, buffering its body so it can still be sent.
+// req must be the transport's own copy of the request.
+func startHAREntry(req *http.Request) *harRecord {
+	start := time.Now()
+	r := &harRecord{start: start}
+	e := &r.entry
+	*e = harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         redactURL(req),
+			HTTPVersion: "HTTP/1.1",
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Response: harResponse{Headers: []harNameValue{}, Cookies: []harNameValue{}, HeadersSize: -1, BodySize: -1},
+	}
+	for name, values := range req.URL.Query() {
+		for _, v := range values {
+			e.Request.QueryString = append(e.Request.QueryString, harNameValue{Name: name, Value: v})
+		}
+	}
+
+	if body := bufferRequestBody(req); body != nil {
+		e.Request.BodySize = len(body)
+		e.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     harText(body),
+		}
+	}
+
+	har.Lock()
+	har.records = append(har.records, r)
+	har.Unlock()
+	return r
+}
+
+// finishHeaders records the response status line and headers.
+func (r *harRecord) finishHeaders(resp *http.Response, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := &r.entry
+	e.Timings.Wait = msSince(r.start)
+	e.Time = e.Timings.Wait
+	if err != nil {
+		e.Comment = err.Error()
+		return
+	}
+	e.Request.HTTPVersion = resp.Proto
+	e.Response.Status = resp.StatusCode
+	e.Response.StatusText = http.StatusText(resp.StatusCode)
+	e.Response.HTTPVersion = resp.Proto
+	e.Response.Headers = harHeaders(resp.Header)
+	e.Response.RedirectURL = resp.Header.Get("Location")
+	e.Response.Content.MimeType = resp.Header.Get("Content-Type")
+}
+
+// finishBody records the response body once it is fully read or closed.
+func (r *harRecord) finishBody(body []byte, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := &r.entry
+	e.Time = msSince(r.start)
+	e.Timings.Receive = e.Time - e.Timings.Wait
+	e.Response.BodySize = len(body)
+	e.Response.Content.Size = len(body)
+	e.Response.Content.Text = harText(body)
+	if err != nil && err != io.EOF {
+		e.Comment = err.Error()
+	}
+}
+
+// harBody tees a response body into its HAR entry.
+type harBody struct {
+	body   io.ReadCloser
+	record *harRecord
+	buf    bytes.Buffer
+	once   sync.Once
+}
+
+func (b *harBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if room := harBodyLimit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(n, room)])
+	}
+	if err != nil {
+		b.once.Do(func() { b.record.finishBody(b.buf.Bytes(), err) })
+	}
+	return n, err
+}
+
+func (b *harBody) Close() error {
+	b.once.Do(func() { b.record.finishBody(b.buf.Bytes(), nil) })
+	return b.body.Close()
+}
+
+func harHeaders(h http.Header) []harNameValue {
+	out := []harNameValue{}
+	for name, values := range h {
+		for _, v := range values {
+			if harSecretHeaders[strings.ToLower(name)] {
+				v = redactedValue
+			}
+			out = append(out, harNameValue{Name: name, Value: v})
+		}
+	}
+	return out
+}
+
+// harText returns body as recordable text with token fields redacted.
+func harText(body []byte) string {
+	if len(body) > harBodyLimit {
+		body = body[:harBodyLimit]
+	}
+	return redactBody(body)
+}
+
+// redactBody returns body as text with token fields redacted.
+func redactBody(body []byte) string {
+	text := harSecretFields.ReplaceAllString(string(body), `"$1"$2"`+redactedValue+`"`)
+	return harSecretForm.ReplaceAllString(text, "${1}${2}="+redactedValue)
+}
+
+// bufferRequestBody reads req's body and puts back a replayable copy. It
+// returns nil when there is no body or it can't be read.
+func bufferRequestBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	if err != nil {
+		return nil
+	}
+	return body
+}
+
+// redactURL drops userinfo from the request URL.
+func redactURL(req *http.Request) string {
+	u := *req.URL
+	u.User = nil
+	return u.String()
+}
+
+func msSince(t time.Time) float64 {
+	return float64(time.Since(t).Microseconds()) / 1000
+}
@@ -0,0 +1,119 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	utls "github.com/refraction-networking/utls"
+	"golang.org/x/net/http2"
+)
+
+// idleConnTimeout closes pooled connections nobody has used for a while.
+const idleConnTimeout = 90 * time.Second
+
+// h2Transport creates the pooled HTTP/2 client connections. Its own pool
+// is unused: conns below are handed straight to NewClientConn.
+var h2Transport = &http2.Transport{IdleConnTimeout: idleConnTimeout}
+
+// conns holds one hostConns per origin and proxy. It is shared by every
+// client New returns, since providers create a client per call.
+var conns = struct {
+	sync.Mutex
+	m map[string]*hostConns
+}{m: make(map[string]*hostConns)}
+
+// hostConns is the pooled state for one origin. An origin speaks either
+// HTTP/2 (one multiplexed connection) or HTTP/1.1 (a keep-alive
+// transport), decided by ALPN on the first handshake.
+type hostConns struct {
+	mu sync.Mutex
+	h2 *http2.ClientConn
+	h1 *http.Transport
+	// spare is a handshaken HTTP/1.1 connection waiting for h1 to dial.
+	spare chan net.Conn
+}
+
+func hostConnsFor(addr string, proxyURL *url.URL) *hostConns {
+	key := addr
+	if proxyURL != nil {
+		key += " via " + proxyURL.String()
+	}
+	conns.Lock()
+	defer conns.Unlock()
+	hc, ok := conns.m[key]
+	if !ok {
+		hc = &hostConns{spare: make(chan net.Conn, 1)}
+		conns.m[key] = hc
+	}
+	return hc
+}
+
+// pooledH2 returns the origin's HTTP/2 connection if it can take another
+// request.
+func (hc *hostConns) pooledH2() *http2.ClientConn {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	if hc.h2 != nil && !hc.h2.CanTakeNewRequest() {
+		hc.h2 = nil
+	}
+	return hc.h2
+}
+
+// addH2 pools cc unless a usable connection got there first.
+func (hc *hostConns) addH2(cc *http2.ClientConn) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	if hc.h2 == nil || !hc.h2.CanTakeNewRequest() {
+		hc.h2 = cc
+	}
+}
+
+// pooledH1 returns the origin's HTTP/1.1 transport, if it has one.
+func (hc *hostConns) pooledH1() *http.Transport {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	return hc.h1
+}
+
+// h1Transport returns the origin's HTTP/1.1 transport, creating it with
+// dial for new connections. A connection passed in as spare is used
+// before dialing.
+func (hc *hostConns) h1Transport(dial func(ctx context.Context) (*utls.UConn, error), spare net.Conn) *http.Transport {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	if spare != nil {
+		select {
+		case hc.spare <- spare:
+		default:
+			spare.Close()
+		}
+	}
+	if hc.h1 == nil {
+		hc.h1 = &http.Transport{
+			DialTLSContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				select {
+				case c := <-hc.spare:
+					return c, nil
+				default:
+				}
+				return dial(ctx)
+			},
+			IdleConnTimeout: idleConnTimeout,
+		}
+	}
+	return hc.h1
+}
+
+// isReplayable reports whether a request can be resent after a pooled
+// connection turned out to be dead.
+func isReplayable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package httpclient
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// proxyConfig resolves the proxy for a request. An explicit proxy URL
+// overrides HTTPS_PROXY/HTTP_PROXY; ALL_PROXY fills in when neither is set.
+// NO_PROXY is honoured either way.
+func proxyConfig(proxyURL string) *httpproxy.Config {
+	cfg := httpproxy.FromEnvironment()
+	all := getEnvAny("ALL_PROXY", "all_proxy")
+	if proxyURL != "" {
+		all = proxyURL
+		cfg.HTTPProxy, cfg.HTTPSProxy = "", ""
+	}
+	if cfg.HTTPSProxy == "" {
+		cfg.HTTPSProxy = all
+	}
+	if cfg.HTTPProxy == "" {
+		cfg.HTTPProxy = all
+	}
+	return cfg
+}
+
+// proxyFunc returns an http.Transport Proxy function for proxyURL (empty
+// uses the environment).
+func proxyFunc(proxyURL string) func(*http.Request) (*url.URL, error) {
+	resolve := proxyConfig(proxyURL).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return resolve(req.URL)
+	}
+}
+
+func getEnvAny(names ...string) string {
+	for _, n := range names {
+		if v := os.Getenv(n); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// dialProxy opens a connection to addr through proxyURL: a CONNECT tunnel
+// for http and https proxies, or a SOCKS5 handshake for socks5/socks5h.
+// The returned connection is ready for the TLS handshake with addr.
+func (t *chromeTransport) dialProxy(ctx context.Context, proxyURL *url.URL, addr string) (net.Conn, error) {
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		d, err := proxy.FromURL(proxyURL, t.dialer)
+		if err != nil {
+			return nil, err
+		}
+		if cd, ok := d.(proxy.ContextDialer); ok {
+			return cd.DialContext(ctx, "tcp", addr)
+		}
+		return d.Dial("tcp", addr)
+	case "http", "https":
+		return t.dialConnect(ctx, proxyURL, addr)
+	}
+	return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+}
+
+// dialConnect asks an HTTP proxy to open a tunnel to addr.
+func (t *chromeTransport) dialConnect(ctx context.Context, proxyURL *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := net.JoinHostPort(proxyURL.Hostname(), portFromURL(proxyURL))
+	conn, err := t.dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("proxy %s: %w", proxyAddr, err)
+	}
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy %s: %w", proxyAddr, err)
+		}
+		conn = tlsConn
+	}
+
+	// Give up on the CONNECT exchange when the request is cancelled.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if u := proxyURL.User; u != nil {
+		pass, _ := u.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.Username() + ":" + pass))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", proxyAddr, err)
+	}
+
+	// The proxy sends nothing after its reply until the client speaks, so
+	// the reader can't swallow bytes belonging to the tunnel.
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("proxy %s: %w", proxyAddr, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: CONNECT %s: %s", proxyAddr, addr, strings.TrimSpace(resp.Status))
+	}
+	if !stop() {
+		return nil, ctx.Err()
+	}
+	return conn, nil
+}
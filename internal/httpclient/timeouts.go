@@ -0,0 +1,184 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Timeouts bounds the phases of a request. Unlike a client-wide timeout
+// they let a long answer keep streaming for as long as data keeps
+// arriving.
+type Timeouts struct {
+	// Connect covers the TCP dial, proxy handshake and TLS handshake.
+	Connect time.Duration
+	// ResponseHeader is how long to wait for the response headers once
+	// the request is sent.
+	ResponseHeader time.Duration
+	// StreamIdle is how long a response body may go without data.
+	StreamIdle time.Duration
+}
+
+// DefaultTimeouts apply to any phase SetTimeouts leaves at zero.
+var DefaultTimeouts = Timeouts{
+	Connect:        30 * time.Second,
+	ResponseHeader: 2 * time.Minute,
+	StreamIdle:     3 * time.Minute,
+}
+
+var (
+	timeoutsMu sync.RWMutex
+	timeouts   = DefaultTimeouts
+)
+
+// SetTimeouts replaces the timeouts used by every client from this
+// package. Zero fields keep their defaults.
+func SetTimeouts(t Timeouts) {
+	if t.Connect <= 0 {
+		t.Connect = DefaultTimeouts.Connect
+	}
+	if t.ResponseHeader <= 0 {
+		t.ResponseHeader = DefaultTimeouts.ResponseHeader
+	}
+	if t.StreamIdle <= 0 {
+		t.StreamIdle = DefaultTimeouts.StreamIdle
+	}
+	timeoutsMu.Lock()
+	timeouts = t
+	timeoutsMu.Unlock()
+}
+
+func currentTimeouts() Timeouts {
+	timeoutsMu.RLock()
+	defer timeoutsMu.RUnlock()
+	return timeouts
+}
+
+// TimeoutError reports which phase of a request ran out of time.
+type TimeoutError struct {
+	Phase string
+	After time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timeout after %s", e.Phase, e.After)
+}
+
+// Timeout lets callers treat it like a net.Error.
+func (e *TimeoutError) Timeout() bool { return true }
+
+// PlainTransport returns a standard net/http transport that honours
+// proxyURL (or the environment) and the package timeouts. Use it for
+// requests that don't need the Chrome TLS fingerprint.
+func PlainTransport(proxyURL string) http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxyFunc(proxyURL)
+	return &timeoutTransport{base: t}
+}
+
+// timeoutTransport enforces the response-header and stream-idle timeouts
+// around base.
+type timeoutTransport struct {
+	base http.RoundTripper
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if resp, ok, err := replayFromCassette(req); ok {
+		return resp, err
+	}
+
+	limits := currentTimeouts()
+	ctx, cancel := context.WithCancelCause(req.Context())
+
+	var record *harRecord
+	var taped *Interaction
+	if harRecording() || cassetteRecording() {
+		req = req.Clone(req.Context())
+		if harRecording() {
+			record = startHAREntry(req)
+		}
+		if cassetteRecording() {
+			taped = startInteraction(req)
+		}
+	}
+
+	start := time.Now()
+	headerTimer := time.AfterFunc(limits.ResponseHeader, func() {
+		cancel(&TimeoutError{Phase: "response header", After: limits.ResponseHeader})
+	})
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	headerTimer.Stop()
+	if err != nil {
+		cause := context.Cause(ctx)
+		cancel(nil)
+		var te *TimeoutError
+		if errors.As(cause, &te) {
+			err = te
+		}
+		slog.Debug("http request failed", "method", req.Method, "host", req.URL.Host, "path", req.URL.Path,
+			"duration", time.Since(start), "err", err)
+		if record != nil {
+			record.finishHeaders(nil, err)
+		}
+		if taped != nil {
+			dropInteraction(taped)
+		}
+		return nil, err
+	}
+	slog.Debug("http request", "method", req.Method, "host", req.URL.Host, "path", req.URL.Path,
+		"status", resp.StatusCode, "proto", resp.Proto, "duration", time.Since(start))
+
+	resp.Body = newIdleBody(ctx, resp.Body, limits.StreamIdle, cancel)
+	if record != nil {
+		record.finishHeaders(resp, nil)
+		resp.Body = &harBody{body: resp.Body, record: record}
+	}
+	if taped != nil {
+		recordResponse(taped, resp)
+	}
+	return resp, nil
+}
+
+// idleBody cancels the request when no data arrives for idle, so a stalled
+// stream fails instead of hanging.
+type idleBody struct {
+	ctx    context.Context
+	body   io.ReadCloser
+	timer  *time.Timer
+	idle   time.Duration
+	cancel context.CancelCauseFunc
+}
+
+func newIdleBody(ctx context.Context, body io.ReadCloser, idle time.Duration, cancel context.CancelCauseFunc) *idleBody {
+	b := &idleBody{ctx: ctx, body: body, idle: idle, cancel: cancel}
+	b.timer = time.AfterFunc(idle, func() {
+		cancel(&TimeoutError{Phase: "stream idle", After: idle})
+	})
+	return b
+}
+
+func (b *idleBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 {
+		b.timer.Reset(b.idle)
+	}
+	if err != nil && err != io.EOF {
+		var te *TimeoutError
+		if errors.As(context.Cause(b.ctx), &te) {
+			return n, te
+		}
+	}
+	return n, err
+}
+
+func (b *idleBody) Close() error {
+	b.timer.Stop()
+	err := b.body.Close()
+	b.cancel(nil)
+	return err
+}
@@ -0,0 +1,229 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// A cassette is a recorded set of HTTP exchanges that can be replayed in
+// place of the network, so provider parsers can be exercised against
+// real response shapes without cookies or connectivity.
+//
+// Recording sanitizes like the HAR trace: secret headers and token fields
+// are replaced with REDACTED. Replay matches requests by method, host and
+// path, in recorded order, ignoring query strings and bodies.
+
+// Cassette is the on-disk recording format.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is the sanitized request side of an Interaction.
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// CassetteResponse is the sanitized response side of an Interaction.
+type CassetteResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// cassette is the active recording or replay, if any.
+var cassette struct {
+	sync.Mutex
+	recording bool
+	replaying bool
+	path      string
+	tape      Cassette
+	used      []bool
+}
+
+// RecordCassette starts capturing every exchange made through this
+// package. StopCassette writes them to path.
+func RecordCassette(path string) {
+	cassette.Lock()
+	defer cassette.Unlock()
+	cassette.recording, cassette.replaying = true, false
+	cassette.path = path
+	cassette.tape = Cassette{}
+}
+
+// ReplayCassette serves every request made through this package from the
+// cassette at path instead of the network. Requests with no matching
+// interaction fail.
+func ReplayCassette(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var tape Cassette
+	if err := json.Unmarshal(data, &tape); err != nil {
+		return fmt.Errorf("parsing cassette %s: %w", path, err)
+	}
+
+	cassette.Lock()
+	defer cassette.Unlock()
+	cassette.recording, cassette.replaying = false, true
+	cassette.path = path
+	cassette.tape = tape
+	cassette.used = make([]bool, len(tape.Interactions))
+	return nil
+}
+
+// StopCassette ends recording or replay. A recording is written to the
+// path given to RecordCassette.
+func StopCassette() error {
+	cassette.Lock()
+	defer cassette.Unlock()
+	recording, path := cassette.recording, cassette.path
+	cassette.recording, cassette.replaying = false, false
+	if !recording {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(cassette.tape, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func cassetteRecording() bool {
+	cassette.Lock()
+	defer cassette.Unlock()
+	return cassette.recording
+}
+
+// replayFromCassette answers req from the cassette. ok is false when no
+// cassette is being replayed.
+func replayFromCassette(req *http.Request) (resp *http.Response, ok bool, err error) {
+	cassette.Lock()
+	defer cassette.Unlock()
+	if !cassette.replaying {
+		return nil, false, nil
+	}
+
+	for i, in := range cassette.tape.Interactions {
+		if cassette.used[i] || !sameEndpoint(in.Request, req) {
+			continue
+		}
+		cassette.used[i] = true
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		// Redaction may have changed the body's length.
+		header.Del("Content-Length")
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, true, nil
+	}
+	return nil, true, fmt.Errorf("cassette %s: no recorded response for %s %s", cassette.path, req.Method, req.URL.Redacted())
+}
+
+func sameEndpoint(recorded CassetteRequest, req *http.Request) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+	u, err := req.URL.Parse(recorded.URL)
+	return err == nil && u.Host == req.URL.Host && u.Path == req.URL.Path
+}
+
+// startInteraction records the request side of an exchange. req must be
+// the transport's own copy of the request.
+func startInteraction(req *http.Request) *Interaction {
+	in := &Interaction{Request: CassetteRequest{
+		Method: req.Method,
+		URL:    redactURL(req),
+		Header: redactHeader(req.Header),
+		Body:   redactBody(bufferRequestBody(req)),
+	}}
+	cassette.Lock()
+	cassette.tape.Interactions = append(cassette.tape.Interactions, in)
+	cassette.Unlock()
+	return in
+}
+
+// dropInteraction removes an exchange that never got a response.
+func dropInteraction(in *Interaction) {
+	cassette.Lock()
+	defer cassette.Unlock()
+	for i, recorded := range cassette.tape.Interactions {
+		if recorded == in {
+			cassette.tape.Interactions = append(cassette.tape.Interactions[:i], cassette.tape.Interactions[i+1:]...)
+			return
+		}
+	}
+}
+
+// tapeBody captures the full response body into its interaction.
+type tapeBody struct {
+	body io.ReadCloser
+	in   *Interaction
+	buf  bytes.Buffer
+	once sync.Once
+}
+
+func (b *tapeBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.buf.Write(p[:n])
+	if err != nil {
+		b.once.Do(b.finish)
+	}
+	return n, err
+}
+
+func (b *tapeBody) Close() error {
+	b.once.Do(b.finish)
+	return b.body.Close()
+}
+
+func (b *tapeBody) finish() {
+	cassette.Lock()
+	b.in.Response.Body = redactBody(b.buf.Bytes())
+	cassette.Unlock()
+}
+
+// recordResponse fills in the response side and tees the body into it.
+func recordResponse(in *Interaction, resp *http.Response) {
+	cassette.Lock()
+	in.Response.Status = resp.StatusCode
+	in.Response.Header = redactHeader(resp.Header)
+	cassette.Unlock()
+	resp.Body = &tapeBody{body: resp.Body, in: in}
+}
+
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for name := range out {
+		if harSecretHeaders[strings.ToLower(name)] {
+			out[name] = []string{redactedValue}
+		}
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		io.WriteString(w, `{"accessToken":"secret","answer":"`+r.URL.Path+`"}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "tape.json")
+	get := func(p string) string {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+p, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := New(0).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	RecordCassette(path)
+	get("/one")
+	get("/two")
+	if err := StopCassette(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("cassette keeps a secret:\n%s", data)
+	}
+
+	srv.Close()
+	if err := ReplayCassette(path); err != nil {
+		t.Fatal(err)
+	}
+	defer StopCassette()
+	if got, want := get("/two"), `{"accessToken":"REDACTED","answer":"/two"}`; got != want {
+		t.Errorf("replayed /two = %s, want %s", got, want)
+	}
+	if got, want := get("/one"), `{"accessToken":"REDACTED","answer":"/one"}`; got != want {
+		t.Errorf("replayed /one = %s, want %s", got, want)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/one", nil)
+	if _, err := New(0).Do(req); err == nil {
+		t.Error("replaying an exchange twice succeeded, want an error")
+	}
+}
@@ -0,0 +1,299 @@
+// Package index builds and searches a keyword index over the text files
+// of a directory, so the most relevant passages can be put in a prompt.
+//
+// Passages are ranked with BM25. The index keeps the passages themselves
+// and computes term statistics when it is loaded, which keeps the file
+// small and is fast enough for a codebase or a folder of notes.
+package index
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// chunkLines is the length of a passage, and chunkStep how far apart
+	// passages start; the difference overlaps them so a match near a
+	// boundary is still seen with its surroundings.
+	chunkLines = 50
+	chunkStep  = 40
+	// maxFileBytes skips generated and data files too big to be useful.
+	maxFileBytes = 512 << 10
+
+	// BM25 parameters, at their usual values.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// skipDirs are never indexed: version control, dependencies and build
+// output.
+var skipDirs = map[string]bool{
+	".git": true, ".hg": true, ".svn": true, "node_modules": true,
+	"vendor": true, "dist": true, "build": true, "target": true,
+	"__pycache__": true, ".venv": true, "venv": true,
+}
+
+// Chunk is one passage of a file.
+type Chunk struct {
+	Path      string `json:"path"` // relative to the index root, slash separated
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Text      string `json:"text"`
+}
+
+// Index is the searchable set of passages under Root.
+type Index struct {
+	Root   string    `json:"root"`
+	Built  time.Time `json:"built"`
+	Files  int       `json:"files"`
+	Chunks []Chunk   `json:"chunks"`
+
+	terms  []map[string]int // term counts per chunk
+	lens   []int            // terms per chunk
+	df     map[string]int   // chunks containing each term
+	avgLen float64
+}
+
+// Result is a passage matching a query.
+type Result struct {
+	Chunk
+	Score float64
+}
+
+// Build walks root and splits every text file into passages. Hidden
+// directories, dependency and build directories, large files and binary
+// files are skipped.
+func Build(root string) (*Index, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	idx := &Index{Root: root, Built: time.Now()}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (skipDirs[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(name, ".") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxFileBytes || info.Size() == 0 {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || isBinary(data) {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		idx.Files++
+		idx.Chunks = append(idx.Chunks, chunkFile(filepath.ToSlash(rel), string(data))...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	idx.prepare()
+	return idx, nil
+}
+
+// chunkFile splits a file into overlapping passages of chunkLines lines.
+func chunkFile(path, text string) []Chunk {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	var chunks []Chunk
+	for start := 0; start < len(lines); start += chunkStep {
+		end := min(start+chunkLines, len(lines))
+		body := strings.Join(lines[start:end], "\n")
+		if strings.TrimSpace(body) != "" {
+			chunks = append(chunks, Chunk{Path: path, StartLine: start + 1, EndLine: end, Text: body})
+		}
+		if end == len(lines) {
+			break
+		}
+	}
+	return chunks
+}
+
+// isBinary reports whether data looks like something other than text.
+func isBinary(data []byte) bool {
+	head := data[:min(len(data), 8000)]
+	return bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(data)
+}
+
+// Save writes the index to path as gzipped JSON.
+func (idx *Index) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(idx); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// Load reads an index written by Save.
+func Load(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading index %s: %w", path, err)
+	}
+	var idx Index
+	if err := json.NewDecoder(zr).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("reading index %s: %w", path, err)
+	}
+	idx.prepare()
+	return &idx, nil
+}
+
+// prepare counts the terms of every passage for searching.
+func (idx *Index) prepare() {
+	idx.terms = make([]map[string]int, len(idx.Chunks))
+	idx.lens = make([]int, len(idx.Chunks))
+	idx.df = map[string]int{}
+	total := 0
+	for i, c := range idx.Chunks {
+		counts := map[string]int{}
+		// The path counts too: a question naming a file should find it.
+		for _, t := range Tokenize(c.Path + "\n" + c.Text) {
+			counts[t]++
+			idx.lens[i]++
+		}
+		for t := range counts {
+			idx.df[t]++
+		}
+		idx.terms[i] = counts
+		total += idx.lens[i]
+	}
+	if len(idx.Chunks) > 0 {
+		idx.avgLen = float64(total) / float64(len(idx.Chunks))
+	}
+}
+
+// Search returns up to k passages ranked by BM25 relevance to query.
+func (idx *Index) Search(query string, k int) []Result {
+	seen := map[string]bool{}
+	var qterms []string
+	for _, t := range Tokenize(query) {
+		if !seen[t] && idx.df[t] > 0 {
+			seen[t] = true
+			qterms = append(qterms, t)
+		}
+	}
+	if len(qterms) == 0 {
+		return nil
+	}
+	n := float64(len(idx.Chunks))
+	var results []Result
+	for i, counts := range idx.terms {
+		var score float64
+		for _, t := range qterms {
+			tf := float64(counts[t])
+			if tf == 0 {
+				continue
+			}
+			df := float64(idx.df[t])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := 1 - bm25B + bm25B*float64(idx.lens[i])/idx.avgLen
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+		if score > 0 {
+			results = append(results, Result{Chunk: idx.Chunks[i], Score: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+
+	// Passages overlap, so a hit usually shows up twice; keep the better.
+	var top []Result
+	for _, r := range results {
+		if len(top) == k {
+			break
+		}
+		if !slices.ContainsFunc(top, r.overlaps) {
+			top = append(top, r)
+		}
+	}
+	return top
+}
+
+func (r Result) overlaps(o Result) bool {
+	return r.Path == o.Path && r.StartLine <= o.EndLine && o.StartLine <= r.EndLine
+}
+
+// stopWords are too common to say anything about relevance.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true,
+	"not": true, "you": true, "all": true, "any": true, "can": true,
+	"how": true, "what": true, "why": true, "does": true, "this": true,
+	"that": true, "with": true, "from": true, "into": true, "where": true,
+	"when": true, "which": true, "is": true, "in": true, "of": true,
+	"to": true, "it": true, "an": true, "on": true, "be": true, "do": true,
+	"or": true, "if": true, "as": true, "at": true, "by": true,
+}
+
+// Tokenize splits text into lowercase terms. Identifiers are split at
+// case changes as well, so "parseConfig" yields "parseconfig", "parse"
+// and "config".
+func Tokenize(text string) []string {
+	var terms []string
+	add := func(t string) {
+		t = strings.ToLower(t)
+		if len(t) > 1 && !stopWords[t] {
+			terms = append(terms, t)
+		}
+	}
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		add(w)
+		parts := splitCamel(w)
+		if len(parts) > 1 {
+			for _, p := range parts {
+				add(p)
+			}
+		}
+	}
+	return terms
+}
+
+// splitCamel splits an identifier at lower-to-upper case changes.
+func splitCamel(w string) []string {
+	var parts []string
+	start := 0
+	runes := []rune(w)
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1]) {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
@@ -0,0 +1,97 @@
+// Package locale detects the host timezone and language so provider
+// requests match the user's real environment instead of a hard-coded US
+// Pacific, English default.
+package locale
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Timezone returns the IANA name of the host timezone, or "UTC" if it
+// cannot be determined.
+func Timezone() string {
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); tz != "" {
+		if _, err := time.LoadLocation(tz); err == nil {
+			return tz
+		}
+	}
+
+	// /etc/localtime is a symlink into the zoneinfo tree on macOS and most
+	// Linux distributions, e.g. /var/db/timezone/zoneinfo/Europe/Berlin.
+	if target, err := filepath.EvalSymlinks("/etc/localtime"); err == nil {
+		if _, name, ok := strings.Cut(filepath.ToSlash(target), "zoneinfo/"); ok {
+			if _, err := time.LoadLocation(name); err == nil {
+				return name
+			}
+		}
+	}
+
+	if name := time.Local.String(); name != "" && name != "Local" {
+		return name
+	}
+	return "UTC"
+}
+
+// Load resolves an IANA timezone name, falling back to the host timezone
+// when name is empty or invalid. It returns the location and its name.
+func Load(name string) (*time.Location, string) {
+	if name = strings.TrimSpace(name); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc, name
+		}
+	}
+	name = Timezone()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC, "UTC"
+	}
+	return loc, name
+}
+
+// OffsetMinutes returns the current UTC offset of loc in minutes
+// (e.g. -480 for America/Los_Angeles in winter).
+func OffsetMinutes(loc *time.Location) int {
+	_, offset := time.Now().In(loc).Zone()
+	return offset / 60
+}
+
+// Language returns the host language as a BCP 47 tag (e.g. "de-DE"), from
+// LC_ALL, LC_MESSAGES or LANG, then the macOS user setting; "en-US" if none
+// is set.
+func Language() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if tag := posixToBCP47(os.Getenv(env)); tag != "" {
+			return tag
+		}
+	}
+	if runtime.GOOS == "darwin" {
+		out, err := exec.Command("defaults", "read", "-g", "AppleLocale").Output()
+		if err == nil {
+			if tag := posixToBCP47(string(out)); tag != "" {
+				return tag
+			}
+		}
+	}
+	return "en-US"
+}
+
+// posixToBCP47 turns a POSIX locale name such as "de_DE.UTF-8@euro" into
+// "de-DE". It returns "" for C, POSIX and empty names.
+func posixToBCP47(name string) string {
+	name = strings.TrimSpace(name)
+	name, _, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, "@")
+	if name == "" || name == "C" || name == "POSIX" {
+		return ""
+	}
+	lang, region, _ := strings.Cut(strings.ReplaceAll(name, "_", "-"), "-")
+	if region == "" {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
@@ -0,0 +1,85 @@
+// Package logging configures the process-wide slog logger and adapts it
+// to the printf-style log functions providers and cookie extraction take.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Options selects where logs go and how much is written.
+type Options struct {
+	// Level is debug, info, warn or error.
+	Level string
+	// File receives the logs instead of stderr; it is appended to.
+	File string
+	// Format is text or json.
+	Format string
+}
+
+// Setup installs the default slog logger. The returned function closes
+// the log file, if any.
+func Setup(opts Options) (func() error, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var w io.Writer = os.Stderr
+	closer := func() error { return nil }
+	if opts.File != "" {
+		f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("opening log file: %w", err)
+		}
+		w, closer = f, f.Close
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", "text":
+		h = slog.NewTextHandler(w, handlerOpts)
+	case "json":
+		h = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		closer()
+		return nil, fmt.Errorf("unsupported log format %q (use text or json)", opts.Format)
+	}
+	slog.SetDefault(slog.New(h))
+	return closer, nil
+}
+
+// ParseLevel parses a level name; empty means warn.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "", "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unsupported log level %q (use debug, info, warn or error)", s)
+}
+
+// Logf returns a printf-style function that writes debug records to
+// logger. A leading "[component]" tag, as providers write, becomes a
+// component attribute.
+func Logf(logger *slog.Logger) func(format string, args ...any) {
+	return func(format string, args ...any) {
+		msg := strings.TrimSpace(fmt.Sprintf(format, args...))
+		if rest, ok := strings.CutPrefix(msg, "["); ok {
+			if tag, text, ok := strings.Cut(rest, "] "); ok && !strings.ContainsAny(tag, " []") {
+				logger.Debug(text, "component", tag)
+				return
+			}
+		}
+		logger.Debug(msg)
+	}
+}
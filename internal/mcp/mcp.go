@@ -0,0 +1,189 @@
+// Package mcp implements a minimal Model Context Protocol server over stdio
+// (newline-delimited JSON-RPC 2.0) that exposes tools to MCP clients.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const protocolVersion = "2024-11-05"
+
+// Tool describes a callable tool and its JSON Schema input.
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+// Handler runs a tool with its decoded arguments and returns text output.
+type Handler func(ctx context.Context, args map[string]any) (string, error)
+
+// Server dispatches MCP requests to registered tools.
+type Server struct {
+	name     string
+	version  string
+	tools    []Tool
+	handlers map[string]Handler
+}
+
+// NewServer creates a server that identifies itself with name and version.
+func NewServer(name, version string) *Server {
+	return &Server{name: name, version: version, handlers: make(map[string]Handler)}
+}
+
+// AddTool registers a tool.
+func (s *Server) AddTool(t Tool, h Handler) {
+	s.tools = append(s.tools, t)
+	s.handlers[t.Name] = h
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Serve reads requests from r and writes responses to w until r is
+// exhausted or ctx is cancelled. Tool calls run concurrently; everything
+// else is answered in order.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	enc := json.NewEncoder(w)
+	send := func(resp response) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = enc.Encode(resp)
+	}
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			break
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			send(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+			continue
+		}
+		// Notifications carry no ID and get no response.
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		handle := func(req request) {
+			result, rerr := s.dispatch(ctx, req)
+			resp := response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr}
+			if rerr == nil && result == nil {
+				resp.Result = struct{}{}
+			}
+			send(resp)
+		}
+		if req.Method != "tools/call" {
+			handle(req)
+			continue
+		}
+		wg.Add(1)
+		go func(req request) {
+			defer wg.Done()
+			handle(req)
+		}(req)
+	}
+	wg.Wait()
+	return scanner.Err()
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": protocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return nil, nil
+	case "tools/list":
+		return map[string]any{"tools": s.tools}, nil
+	case "tools/call":
+		var p struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		h, ok := s.handlers[p.Name]
+		if !ok {
+			return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool %q", p.Name)}
+		}
+		text, err := h(ctx, p.Arguments)
+		if err != nil {
+			// Tool failures are reported in the result so the model can see them.
+			return toolResult(err.Error(), true), nil
+		}
+		return toolResult(text, false), nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func toolResult(text string, isError bool) map[string]any {
+	return map[string]any{
+		"content": []map[string]string{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+// StringArg returns a string argument, or "" if absent.
+func StringArg(args map[string]any, key string) string {
+	s, _ := args[key].(string)
+	return s
+}
+
+// IntArg returns a numeric argument as an int, or def if absent.
+func IntArg(args map[string]any, key string, def int) int {
+	if f, ok := args[key].(float64); ok {
+		return int(f)
+	}
+	return def
+}
+
+// BoolArg returns a boolean argument, or false if absent.
+func BoolArg(args map[string]any, key string) bool {
+	b, _ := args[key].(bool)
+	return b
+}
@@ -0,0 +1,117 @@
+// Package playbook loads scripted multi-turn conversations from YAML.
+//
+// A playbook is an ordered list of steps run as one conversation per
+// provider:
+//
+//	name: triage
+//	providers: [chatgpt, claude]
+//	system: Answer in at most three sentences.
+//	steps:
+//	  - ask: What usually causes "too many open files" on Linux?
+//	  - ask: How do I check the current limit for a running process?
+//	  - ask: Summarize {{previous}} as a checklist.
+//	    only: [claude]
+//	  - ask: Which of those applies to containers?
+//	    prompts:
+//	      chatgpt: Which of those applies to Docker containers specifically?
+//
+// Steps may restrict themselves to some providers (only) or replace the
+// prompt for one provider (prompts), which lets a flow branch per
+// provider. {{previous}} expands to that provider's previous answer.
+package playbook
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Playbook is a parsed playbook file.
+type Playbook struct {
+	Name      string            `yaml:"name"`
+	Providers []string          `yaml:"providers"`
+	Provider  string            `yaml:"provider"` // shorthand for a single provider
+	Models    map[string]string `yaml:"models"`   // provider → model
+	System    string            `yaml:"system"`
+	Incognito bool              `yaml:"incognito"`
+	Steps     []Step            `yaml:"steps"`
+}
+
+// Step is a single turn.
+type Step struct {
+	Ask     string            `yaml:"ask"`
+	Only    []string          `yaml:"only"`
+	Prompts map[string]string `yaml:"prompts"`
+}
+
+// previousPlaceholder expands to the provider's previous answer.
+const previousPlaceholder = "{{previous}}"
+
+// Load reads and validates a playbook. known lists the accepted provider
+// names.
+func Load(path string, known []string) (*Playbook, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pb Playbook
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&pb); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if pb.Provider != "" {
+		pb.Providers = append([]string{pb.Provider}, pb.Providers...)
+		pb.Provider = ""
+	}
+	if len(pb.Providers) == 0 {
+		return nil, fmt.Errorf("%s: no providers", path)
+	}
+	isKnown := func(name string) bool { return slices.Contains(known, name) }
+	for _, name := range pb.Providers {
+		if !isKnown(name) {
+			return nil, fmt.Errorf("%s: unknown provider %q", path, name)
+		}
+	}
+	if len(pb.Steps) == 0 {
+		return nil, fmt.Errorf("%s: no steps", path)
+	}
+	for i, s := range pb.Steps {
+		if strings.TrimSpace(s.Ask) == "" && len(s.Prompts) == 0 {
+			return nil, fmt.Errorf("%s: step %d has no prompt", path, i+1)
+		}
+		for _, name := range s.Only {
+			if !isKnown(name) {
+				return nil, fmt.Errorf("%s: step %d: unknown provider %q", path, i+1, name)
+			}
+		}
+		for name := range s.Prompts {
+			if !isKnown(name) {
+				return nil, fmt.Errorf("%s: step %d: unknown provider %q", path, i+1, name)
+			}
+		}
+	}
+	return &pb, nil
+}
+
+// Prompt returns the step's prompt for a provider with {{previous}}
+// expanded. ok is false when the step doesn't apply to the provider.
+func (s Step) Prompt(providerName, previous string) (prompt string, ok bool) {
+	if len(s.Only) > 0 && !slices.Contains(s.Only, providerName) {
+		return "", false
+	}
+	prompt = s.Ask
+	if p, ok := s.Prompts[providerName]; ok {
+		prompt = p
+	}
+	if strings.TrimSpace(prompt) == "" {
+		return "", false
+	}
+	return strings.ReplaceAll(prompt, previousPlaceholder, previous), true
+}
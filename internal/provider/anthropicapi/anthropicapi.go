@@ -0,0 +1,335 @@
+// Package anthropicapi implements a provider for the Anthropic Messages
+// API, authenticated with an API key instead of claude.ai cookies.
+//
+// It answers when the cookie-based claude provider cannot (an expired
+// session, a blocked browser) at the price of pay-as-you-go billing. The
+// API keeps no conversations, so they are saved locally with localchat.
+package anthropicapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+	"github.com/kyupark/ask/internal/provider/localchat"
+	"github.com/kyupark/ask/internal/sse"
+)
+
+const (
+	defaultBaseURL   = "https://api.anthropic.com"
+	messagesPath     = "/v1/messages"
+	apiVersion       = "2023-06-01"
+	defaultModel     = "claude-sonnet-4-6"
+	defaultMaxTokens = 8192
+
+	// APIKeyEnv is read when no key is configured, as the Anthropic SDKs
+	// do.
+	APIKeyEnv = "ANTHROPIC_API_KEY"
+
+	conversationPrefix = "api-"
+)
+
+// --- Request/Response types ---
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type thinkingConfig struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+type messagesRequest struct {
+	Model     string          `json:"model"`
+	MaxTokens int             `json:"max_tokens"`
+	System    string          `json:"system,omitempty"`
+	Messages  []message       `json:"messages"`
+	Stream    bool            `json:"stream"`
+	Thinking  *thinkingConfig `json:"thinking,omitempty"`
+}
+
+// streamEvent is the data of one SSE event. Only the fields of the event
+// types handled in readStream are decoded.
+type streamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		Thinking   string `json:"thinking"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Error *apiError `json:"error"`
+}
+
+type apiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Provider implements the Anthropic Messages API backend.
+type Provider struct {
+	baseURL        string
+	apiKey         string
+	timeout        time.Duration
+	proxy          string
+	maxTokens      int
+	thinkingBudget int
+	store          *localchat.Store
+}
+
+// New creates an Anthropic API provider. An empty apiKey falls back to
+// ANTHROPIC_API_KEY.
+func New(baseURL, apiKey string, timeout time.Duration) *Provider {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	if apiKey == "" {
+		apiKey = os.Getenv(APIKeyEnv)
+	}
+	return &Provider{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		apiKey:    apiKey,
+		timeout:   timeout,
+		maxTokens: defaultMaxTokens,
+		store:     localchat.New("", conversationPrefix),
+	}
+}
+
+func (p *Provider) Name() string { return "anthropic-api" }
+
+// CookieSpecs returns nothing: the API authenticates with a key.
+func (p *Provider) CookieSpecs() []provider.CookieSpec { return nil }
+
+func (p *Provider) SetCookies(map[string]string) {}
+
+// SetProxy routes requests through proxyURL instead of the environment's
+// HTTPS_PROXY/ALL_PROXY.
+func (p *Provider) SetProxy(proxyURL string) { p.proxy = proxyURL }
+
+// SetDataDir sets where conversations are saved. Without it nothing is
+// saved and every question starts a new conversation.
+func (p *Provider) SetDataDir(dir string) { p.store = localchat.New(dir, conversationPrefix) }
+
+// BaseURL returns the API URL requests are sent to.
+func (p *Provider) BaseURL() string { return p.baseURL }
+
+// SetMaxTokens caps the answer length; zero keeps the default.
+func (p *Provider) SetMaxTokens(n int) {
+	if n > 0 {
+		p.maxTokens = n
+	}
+}
+
+// SetThinkingBudget enables extended thinking with a budget of n tokens;
+// zero leaves it off.
+func (p *Provider) SetThinkingBudget(n int) { p.thinkingBudget = n }
+
+func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
+	if p.apiKey == "" {
+		return fmt.Errorf("no API key — run: ask config set anthropic_api.api_key <key> (or set %s)", APIKeyEnv)
+	}
+
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	// 1. Load the conversation being continued, or start a new one.
+	var conv *localchat.Conversation
+	if opts.ConversationID != "" {
+		c, err := p.store.Load(opts.ConversationID)
+		if err != nil {
+			return err
+		}
+		conv = c
+		if opts.Retry {
+			conv.DropLastTurn()
+		}
+	} else {
+		conv = p.store.Create(query)
+		if sp := strings.TrimSpace(opts.SystemPrompt); sp != "" {
+			conv.Messages = append(conv.Messages, localchat.Message{Role: "system", Content: sp})
+		}
+	}
+
+	model := opts.Model
+	if model == "" && opts.ConversationID != "" {
+		model = conv.Model
+	}
+	if model == "" {
+		model = defaultModel
+	}
+	logf("[anthropic-api] model=%s conversation=%s", model, conv.ID)
+
+	// 2. Send the whole conversation plus the new question. The system
+	// prompt is a request field, not a message.
+	conv.Messages = append(conv.Messages, localchat.Message{Role: "user", Content: query, CreatedAt: time.Now()})
+	req := messagesRequest{Model: model, MaxTokens: p.maxTokens, Stream: true}
+	for _, m := range conv.Messages {
+		if m.Role == "system" {
+			req.System = m.Content
+			continue
+		}
+		req.Messages = append(req.Messages, message{Role: m.Role, Content: m.Content})
+	}
+	if p.thinkingBudget > 0 {
+		req.Thinking = &thinkingConfig{Type: "enabled", BudgetTokens: p.thinkingBudget}
+		// max_tokens counts the thinking too and must exceed the budget.
+		if req.MaxTokens <= p.thinkingBudget {
+			req.MaxTokens = p.thinkingBudget + defaultMaxTokens
+		}
+	}
+
+	var answer strings.Builder
+	streamOpts := opts
+	streamOpts.OnText = func(text string) {
+		answer.WriteString(text)
+		if opts.OnText != nil {
+			opts.OnText(text)
+		}
+	}
+	// An answer stopped part way by cancelling ctx is kept, as web chats
+	// keep a stopped reply, so the conversation can go on from it.
+	streamErr := p.streamMessages(ctx, req, streamOpts, logf)
+	if streamErr != nil && (ctx.Err() == nil || answer.Len() == 0) {
+		return streamErr
+	}
+
+	// 3. Save the answered turn.
+	if opts.Temporary || !p.store.Enabled() {
+		return streamErr
+	}
+	conv.Model = model
+	conv.Messages = append(conv.Messages, localchat.Message{Role: "assistant", Content: answer.String(), Model: model, CreatedAt: time.Now()})
+	if err := p.store.Save(conv); err != nil {
+		return err
+	}
+	if opts.OnConversation != nil {
+		opts.OnConversation(conv.ID, "", "")
+	}
+	return streamErr
+}
+
+// streamMessages posts to the Messages API and streams the answer.
+func (p *Provider) streamMessages(ctx context.Context, body messagesRequest, opts provider.AskOptions, logf func(string, ...any)) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshalling request: %w", err)
+	}
+
+	u := p.baseURL + messagesPath
+	logf("[anthropic-api] POST %s (%d messages)", u, len(body.Messages))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", apiVersion)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		var env struct {
+			Error *apiError `json:"error"`
+		}
+		if json.Unmarshal(b, &env) == nil && env.Error != nil {
+			kind := provider.StatusKind(resp.StatusCode, env.Error.Type+" "+env.Error.Message)
+			return provider.Errorf(kind, "anthropic-api: %s: %s (HTTP %d)", env.Error.Type, env.Error.Message, resp.StatusCode)
+		}
+		return provider.HTTPError(resp.StatusCode, b)
+	}
+	return readStream(resp.Body, opts, logf)
+}
+
+// readStream reads the Messages API event stream: text and thinking
+// arrive as content_block_delta events, and an error event ends the
+// answer early.
+func readStream(r io.Reader, opts provider.AskOptions, logf func(string, ...any)) error {
+	err := sse.Read(r, func(e sse.Event) error {
+		var ev streamEvent
+		if err := json.Unmarshal([]byte(e.Data), &ev); err != nil {
+			if opts.OnError != nil {
+				opts.OnError(fmt.Errorf("parsing event: %w", err))
+			}
+			return nil
+		}
+		switch ev.Type {
+		case "content_block_delta":
+			switch ev.Delta.Type {
+			case "text_delta":
+				if ev.Delta.Text != "" && opts.OnText != nil {
+					opts.OnText(ev.Delta.Text)
+				}
+			case "thinking_delta":
+				if ev.Delta.Thinking != "" && opts.OnThinking != nil {
+					opts.OnThinking(ev.Delta.Thinking)
+				}
+			}
+		case "message_delta":
+			if ev.Delta.StopReason != "" {
+				logf("[anthropic-api] stop_reason=%s", ev.Delta.StopReason)
+			}
+			if ev.Delta.StopReason == "max_tokens" && opts.OnTruncated != nil {
+				opts.OnTruncated()
+			}
+		case "error":
+			if ev.Error != nil {
+				return fmt.Errorf("anthropic-api: %s: %s", ev.Error.Type, ev.Error.Message)
+			}
+			return fmt.Errorf("anthropic-api: stream error")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.OnDone != nil {
+		opts.OnDone()
+	}
+	return nil
+}
+
+// ListConversations lists saved conversations, most recent first.
+func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
+	return p.store.List(opts)
+}
+
+// DeleteConversation removes a saved conversation.
+func (p *Provider) DeleteConversation(ctx context.Context, conversationID string, opts provider.DeleteOptions) error {
+	return p.store.Delete(conversationID)
+}
+
+// FetchTranscript returns a saved conversation.
+func (p *Provider) FetchTranscript(ctx context.Context, conversationID string, opts provider.TranscriptOptions) (*provider.Transcript, error) {
+	return p.store.Transcript(conversationID)
+}
+
+func (p *Provider) ListModels() provider.ProviderModels {
+	return provider.ProviderModels{
+		Provider: "anthropic-api",
+		Models: []provider.ModelInfo{
+			{ID: "claude-opus-4-6", Name: "Claude Opus 4.6", Description: "Smartest — best for complex tasks", Default: false, Tags: []string{"flagship", "reasoning"}},
+			{ID: "claude-sonnet-4-6", Name: "Claude Sonnet 4.6", Description: "Best speed/intelligence balance", Default: true, Tags: []string{"balanced"}},
+			{ID: "claude-haiku-4-5-20251001", Name: "Claude Haiku 4.5", Description: "Fastest — lightweight tasks", Default: false, Tags: []string{"fast"}},
+		},
+	}
+}
@@ -0,0 +1,124 @@
+// Package chatgpt — account.go selects a team or enterprise workspace.
+//
+// A login can belong to several accounts: the personal one and any
+// workspaces. Requests act in the workspace named by the ChatGPT-Account-Id
+// header, and in the personal account without it. The accounts come from
+// GET /backend-api/accounts/check and from the session payload, which
+// names the account the session was opened in.
+package chatgpt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+const accountsCheckPath = "/backend-api/accounts/check/v4-2023-04-27"
+
+// Account is a personal account or workspace the login belongs to.
+type Account struct {
+	ID   string
+	Name string
+	// Plan is the subscription, e.g. "plus" or "team".
+	Plan string
+	// Structure is "personal" or "workspace".
+	Structure string
+	Default   bool
+}
+
+// sessionAccount is the account in the /api/auth/session payload.
+type sessionAccount struct {
+	ID        string `json:"id"`
+	PlanType  string `json:"planType"`
+	Structure string `json:"structure"`
+}
+
+type accountsCheckResponse struct {
+	Accounts map[string]struct {
+		Account struct {
+			AccountID string `json:"account_id"`
+			Name      string `json:"name"`
+			PlanType  string `json:"plan_type"`
+			Structure string `json:"structure"`
+			IsDefault bool   `json:"is_default"`
+		} `json:"account"`
+	} `json:"accounts"`
+	AccountOrdering []string `json:"account_ordering"`
+}
+
+// SetAccount sends requests as the account or workspace with the given
+// ID; empty uses the login's personal account.
+func (p *Provider) SetAccount(id string) { p.accountID = strings.TrimSpace(id) }
+
+func (p *Provider) setAccount(req *http.Request) {
+	if p.accountID != "" {
+		req.Header.Set("ChatGPT-Account-Id", p.accountID)
+	}
+}
+
+// ListAccounts returns the accounts the login can act as, in the order
+// the web app shows them.
+func (p *Provider) ListAccounts(ctx context.Context, logf func(string, ...any)) ([]Account, error) {
+	if p.sessionToken == "" {
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chatgpt.com in your browser")
+	}
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	token, err := p.getAccessToken(ctx, logf)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	u := p.baseURL + accountsCheckPath
+	logf("[chatgpt] GET %s", u)
+
+	var data accountsCheckResponse
+	checkErr := p.doJSON(ctx, httpclient.NewWithProxy(p.timeout, p.proxy), token, http.MethodGet, u, nil, &data)
+
+	var accounts []Account
+	seen := map[string]bool{}
+	add := func(a Account) {
+		if a.ID == "" || seen[a.ID] {
+			return
+		}
+		seen[a.ID] = true
+		accounts = append(accounts, a)
+	}
+	// Entries are keyed by account ID, plus a "default" duplicate of one.
+	byID := map[string]Account{}
+	var ids []string
+	for _, entry := range data.Accounts {
+		a := entry.Account
+		if _, ok := byID[a.AccountID]; !ok {
+			ids = append(ids, a.AccountID)
+		}
+		byID[a.AccountID] = Account{ID: a.AccountID, Name: a.Name, Plan: a.PlanType, Structure: a.Structure, Default: a.IsDefault}
+	}
+	slices.Sort(ids)
+	for _, id := range append(data.AccountOrdering, ids...) {
+		if a, ok := byID[id]; ok {
+			add(a)
+		}
+	}
+	if s := p.sessionAccount; s != nil {
+		add(Account{ID: s.ID, Plan: s.PlanType, Structure: s.Structure})
+	}
+
+	if len(accounts) == 0 {
+		if checkErr != nil {
+			return nil, checkErr
+		}
+		return nil, fmt.Errorf("no accounts in the response")
+	}
+	if checkErr != nil {
+		logf("[chatgpt] accounts check failed: %v (using the session's account)", checkErr)
+	}
+	return accounts, nil
+}
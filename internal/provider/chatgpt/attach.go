@@ -0,0 +1,239 @@
+// Package chatgpt — attach.go uploads local files through the
+// /backend-api/files flow so they can be referenced from a message.
+//
+// Flow:
+//  1. POST /backend-api/files with name/size/use_case → upload_url + file_id
+//  2. PUT the raw bytes to upload_url (Azure blob storage)
+//  3. POST /backend-api/files/{file_id}/uploaded to finalize
+//  4. Reference file_id in the message (image asset pointer or attachment)
+package chatgpt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"  // register decoder for image dimensions
+	_ "image/jpeg" // register decoder for image dimensions
+	_ "image/png"  // register decoder for image dimensions
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+const (
+	filesPath = "/backend-api/files"
+
+	// maxAttachmentSize mirrors the ChatGPT web upload limit.
+	maxAttachmentSize = 512 << 20
+)
+
+type createFileReq struct {
+	FileName string `json:"file_name"`
+	FileSize int    `json:"file_size"`
+	UseCase  string `json:"use_case"`
+}
+
+type createFileResp struct {
+	Status    string `json:"status"`
+	UploadURL string `json:"upload_url"`
+	FileID    string `json:"file_id"`
+}
+
+// attachmentMetadata is the per-file entry in message.metadata.attachments.
+type attachmentMetadata struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Size     int    `json:"size"`
+	MimeType string `json:"mime_type"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+}
+
+// imageAssetPointer is a multimodal_text part referencing an uploaded image.
+type imageAssetPointer struct {
+	ContentType  string `json:"content_type"`
+	AssetPointer string `json:"asset_pointer"`
+	SizeBytes    int    `json:"size_bytes"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+type uploadedFile struct {
+	id       string
+	name     string
+	size     int
+	mimeType string
+	width    int
+	height   int
+}
+
+func (f *uploadedFile) isImage() bool {
+	return strings.HasPrefix(f.mimeType, "image/") && f.width > 0
+}
+
+// uploadFile uploads a local file and returns its server-side reference.
+func (p *Provider) uploadFile(ctx context.Context, client *http.Client, token, path string, logf func(string, ...any)) (*uploadedFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if len(data) > maxAttachmentSize {
+		return nil, fmt.Errorf("file is %d bytes, limit is %d", len(data), maxAttachmentSize)
+	}
+
+	f := &uploadedFile{
+		name:     filepath.Base(path),
+		size:     len(data),
+		mimeType: detectMimeType(path, data),
+	}
+	if strings.HasPrefix(f.mimeType, "image/") {
+		if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			f.width, f.height = cfg.Width, cfg.Height
+		}
+	}
+
+	useCase := "my_files"
+	if f.isImage() {
+		useCase = "multimodal"
+	}
+
+	// 1. Reserve an upload slot.
+	body, _ := json.Marshal(createFileReq{FileName: f.name, FileSize: f.size, UseCase: useCase})
+	url := p.baseURL + filesPath
+	logf("[chatgpt] POST %s (%s, %d bytes, %s)", url, f.name, f.size, f.mimeType)
+
+	var created createFileResp
+	if err := p.doJSON(ctx, client, token, http.MethodPost, url, body, &created); err != nil {
+		return nil, err
+	}
+	if created.FileID == "" || created.UploadURL == "" {
+		return nil, fmt.Errorf("files endpoint returned no upload URL (status %q)", created.Status)
+	}
+	f.id = created.FileID
+
+	// 2. Upload the bytes directly to blob storage.
+	logf("[chatgpt] PUT upload for %s", f.id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, created.UploadURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("creating upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", f.mimeType)
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("x-ms-version", "2020-04-08")
+	req.Header.Set("Origin", "https://chatgpt.com")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("upload failed: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, fmt.Errorf("upload %w", provider.HTTPError(resp.StatusCode, b))
+	}
+	resp.Body.Close()
+
+	// 3. Mark the upload complete so the file is processed.
+	url = fmt.Sprintf("%s%s/%s/uploaded", p.baseURL, filesPath, f.id)
+	logf("[chatgpt] POST %s", url)
+	if err := p.doJSON(ctx, client, token, http.MethodPost, url, []byte("{}"), nil); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// doJSON sends an authenticated JSON request to the backend API and
+// decodes the response into out when non-nil.
+func (p *Provider) doJSON(ctx context.Context, client *http.Client, token, method, url string, body []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("Accept-Language", p.acceptLanguage())
+	req.Header.Set("OAI-Device-Id", p.deviceID)
+	req.Header.Set("OAI-Language", p.language())
+	req.Header.Set("Origin", "https://chatgpt.com")
+	req.Header.Set("Referer", "https://chatgpt.com/")
+	p.setCookies(req)
+	p.setAccount(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return provider.HTTPError(resp.StatusCode, b)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// buildMessageContent returns the message content and attachment metadata
+// for query with the given uploads. Images are inlined as asset pointers in
+// a multimodal_text message; other files are referenced as attachments only.
+func buildMessageContent(query string, uploads []*uploadedFile) (requestContent, []attachmentMetadata) {
+	if len(uploads) == 0 {
+		return requestContent{ContentType: "text", Parts: []any{query}}, nil
+	}
+
+	var parts []any
+	var attachments []attachmentMetadata
+	for _, f := range uploads {
+		attachments = append(attachments, attachmentMetadata{
+			ID:       f.id,
+			Name:     f.name,
+			Size:     f.size,
+			MimeType: f.mimeType,
+			Width:    f.width,
+			Height:   f.height,
+		})
+		if f.isImage() {
+			parts = append(parts, imageAssetPointer{
+				ContentType:  "image_asset_pointer",
+				AssetPointer: "file-service://" + f.id,
+				SizeBytes:    f.size,
+				Width:        f.width,
+				Height:       f.height,
+			})
+		}
+	}
+
+	if len(parts) == 0 {
+		return requestContent{ContentType: "text", Parts: []any{query}}, attachments
+	}
+	parts = append(parts, query)
+	return requestContent{ContentType: "multimodal_text", Parts: parts}, attachments
+}
+
+// detectMimeType guesses a file's MIME type from its extension, falling back
+// to content sniffing.
+func detectMimeType(path string, data []byte) string {
+	if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(path))); t != "" {
+		t, _, _ = strings.Cut(t, ";")
+		return t
+	}
+	t, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	return t
+}
@@ -10,10 +10,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/locale"
 	"github.com/kyupark/ask/internal/provider"
 	"io"
 	"math/big"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -45,8 +47,16 @@ type content struct {
 	Parts       []string `json:"parts"`
 }
 
+// requestContent is the outbound message content. Parts hold plain text
+// or, for multimodal messages, image asset pointers followed by the text.
+type requestContent struct {
+	ContentType string `json:"content_type"`
+	Parts       []any  `json:"parts"`
+}
+
 type messageMetadata struct {
 	SerializationMetadata serializationMetadata `json:"serialization_metadata"`
+	Attachments           []attachmentMetadata  `json:"attachments,omitempty"`
 }
 
 type serializationMetadata struct {
@@ -55,12 +65,13 @@ type serializationMetadata struct {
 type message struct {
 	ID         string          `json:"id"`
 	Author     author          `json:"author"`
-	Content    content         `json:"content"`
+	Content    requestContent  `json:"content"`
 	CreateTime float64         `json:"create_time"`
 	Metadata   messageMetadata `json:"metadata"`
 }
 type conversationMode struct {
-	Kind string `json:"kind"`
+	Kind    string `json:"kind"`
+	GizmoID string `json:"gizmo_id,omitempty"`
 }
 
 type clientContextualInfo struct {
@@ -103,7 +114,8 @@ type conversationResponse struct {
 }
 
 type sessionResponse struct {
-	AccessToken string `json:"accessToken"`
+	AccessToken string          `json:"accessToken"`
+	Account     *sessionAccount `json:"account"`
 }
 
 // Provider implements the ChatGPT web API backend.
@@ -113,13 +125,30 @@ type Provider struct {
 	model          string
 	userAgent      string
 	timeout        time.Duration
+	proxy          string
 	sessionToken   string
 	cfClearance    string
 	puid           string
 	deviceID       string
-	// Cached auth state.
-	accessToken string
-	tokenExpiry time.Time
+	timezone       string
+	locale         string
+	webSearch      bool
+	deepResearch   bool
+	gizmoID        string
+	accountID      string
+	// sessionAccount is the account named by the last session payload.
+	sessionAccount *sessionAccount
+	fingerprint    Fingerprint
+	onLimits       func([]Limit)
+	onSession      func(Session)
+	// Cached auth state; tokenAccount is the account() it was issued for.
+	accessToken  string
+	tokenExpiry  time.Time
+	tokenAccount string
+	// warmSentinel is a sentinel handshake done ahead of the next question
+	// by Warm, taken at warmSentinelAt.
+	warmSentinel   *sentinelResult
+	warmSentinelAt time.Time
 }
 
 // New creates a ChatGPT provider.
@@ -131,11 +160,12 @@ func New(baseURL, model, userAgent string, timeout time.Duration) *Provider {
 		model = defaultModel
 	}
 	return &Provider{
-		baseURL:   baseURL,
-		model:     model,
-		userAgent: userAgent,
-		timeout:   timeout,
-		deviceID:  newUUID(),
+		baseURL:     baseURL,
+		model:       model,
+		userAgent:   userAgent,
+		timeout:     timeout,
+		deviceID:    newUUID(),
+		fingerprint: defaultFingerprint,
 	}
 }
 
@@ -148,6 +178,10 @@ func (p *Provider) CookieSpecs() []provider.CookieSpec {
 	}
 }
 
+// SetProxy routes requests through proxyURL instead of the environment's
+// HTTPS_PROXY/ALL_PROXY.
+func (p *Provider) SetProxy(proxyURL string) { p.proxy = proxyURL }
+
 func (p *Provider) SetCookies(cookies map[string]string) {
 	if v := cookies[cookieSessionToken]; v != "" {
 		p.sessionToken = v
@@ -163,14 +197,56 @@ func (p *Provider) SetCookies(cookies map[string]string) {
 // SetThinkingEffort sets the thinking effort level (none, low, medium, high, xhigh).
 func (p *Provider) SetThinkingEffort(effort string) { p.thinkingEffort = effort }
 
+// SetTimezone overrides the IANA timezone sent with requests (default: host timezone).
+func (p *Provider) SetTimezone(tz string) { p.timezone = tz }
+
+// SetLocale overrides the BCP 47 locale sent with requests (default: host locale).
+func (p *Provider) SetLocale(l string) { p.locale = l }
+
+// SetWebSearch asks ChatGPT to browse the web before answering.
+func (p *Provider) SetWebSearch(on bool) { p.webSearch = on }
+
+// SetDeepResearch runs the question as a deep research task. The answer
+// is a report written after the task finishes, which can take many
+// minutes.
+func (p *Provider) SetDeepResearch(on bool) { p.deepResearch = on }
+
+func (p *Provider) language() string {
+	if l := strings.TrimSpace(p.locale); l != "" {
+		return l
+	}
+	return locale.Language()
+}
+
+// acceptLanguage builds an Accept-Language value for the configured locale.
+func (p *Provider) acceptLanguage() string {
+	lang := p.language()
+	base, _, _ := strings.Cut(lang, "-")
+	if base == lang {
+		return lang + ";q=0.9"
+	}
+	return fmt.Sprintf("%s,%s;q=0.9", lang, base)
+}
+
+// languages is the navigator.languages list for the configured locale.
+func (p *Provider) languages() string {
+	lang := p.language()
+	base, _, _ := strings.Cut(lang, "-")
+	if base == lang {
+		return lang
+	}
+	return lang + "," + base
+}
+
 func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
 	if p.sessionToken == "" {
-		return fmt.Errorf("no session cookie — log in to chatgpt.com in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chatgpt.com in your browser")
 	}
 	logf := opts.LogFunc
 	if logf == nil {
 		logf = func(string, ...any) {}
 	}
+	query = provider.PrependSystemPrompt(query, opts)
 	// Refresh access token if needed.
 	token, err := p.getAccessToken(ctx, logf)
 	if err != nil {
@@ -178,7 +254,7 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 	}
 
 	// Acquire sentinel tokens (chat-requirements + PoW).
-	sentinel, err := p.acquireSentinel(ctx, logf)
+	sentinel, err := p.sentinel(ctx, logf)
 	if err != nil {
 		logf("[chatgpt] sentinel failed: %v (proceeding without)", err)
 		// Non-fatal: try the request anyway; some sessions may not require it.
@@ -195,43 +271,55 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 		modelCandidates = []string{defaultModel}
 	}
 
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+
+	var uploads []*uploadedFile
+	for _, path := range opts.Attachments {
+		f, err := p.uploadFile(ctx, client, token, path, logf)
+		if err != nil {
+			return fmt.Errorf("attaching %s: %w", path, err)
+		}
+		uploads = append(uploads, f)
+	}
+	msgContent, attachments := buildMessageContent(query, uploads)
+
 	tsl, _ := rand.Int(rand.Reader, big.NewInt(481))
+	loc, tzName := locale.Load(p.timezone)
+	logf("[chatgpt] timezone=%s locale=%s", tzName, p.language())
 	baseReqBody := conversationRequest{
 		Action: "next",
 		Messages: []message{
 			{
-				ID:     newUUID(),
-				Author: author{Role: "user"},
-				Content: content{
-					ContentType: "text",
-					Parts:       []string{query},
-				},
+				ID:         newUUID(),
+				Author:     author{Role: "user"},
+				Content:    msgContent,
 				CreateTime: float64(time.Now().Unix()),
 				Metadata: messageMetadata{
 					SerializationMetadata: serializationMetadata{
 						CustomSymbolOffsets: []interface{}{},
 					},
+					Attachments: attachments,
 				},
 			},
 		},
 		ParentMessageID:            newUUID(),
 		Model:                      requestedModel,
-		TimezoneOffsetMin:          -480,
-		Timezone:                   "America/Los_Angeles",
+		TimezoneOffsetMin:          locale.OffsetMinutes(loc),
+		Timezone:                   tzName,
 		HistoryAndTrainingDisabled: opts.Temporary,
-		ConversationMode:           conversationMode{Kind: "primary_assistant"},
+		ConversationMode:           p.conversationMode(),
 		EnableMessageFollowups:     true,
-		SystemHints:                []string{},
+		SystemHints:                p.systemHints(),
 		// Don't send supported_encodings/supports_buffering — v1 delta encoding
 		// uses a completely different response format we don't parse yet.
 		ClientContextualInfo: clientContextualInfo{
 			IsDarkMode:      false,
 			TimeSinceLoaded: int(tsl.Int64()) + 20,
-			PageHeight:      578,
-			PageWidth:       1850,
-			PixelRatio:      1,
-			ScreenHeight:    1080,
-			ScreenWidth:     1920,
+			PageHeight:      p.fingerprint.PageHeight,
+			PageWidth:       p.fingerprint.PageWidth,
+			PixelRatio:      p.fingerprint.PixelRatio,
+			ScreenHeight:    p.fingerprint.ScreenHeight,
+			ScreenWidth:     p.fingerprint.ScreenWidth,
 		},
 		ParagenCotSummaryDisplayOverride: "allow",
 	}
@@ -239,8 +327,12 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 		baseReqBody.ConversationID = opts.ConversationID
 		baseReqBody.ParentMessageID = opts.ParentMessageID
 	}
+	if opts.Retry && opts.ConversationID != "" {
+		if err := p.prepareVariant(ctx, client, token, &baseReqBody, logf); err != nil {
+			return fmt.Errorf("retry: %w", err)
+		}
+	}
 	url := p.baseURL + conversationPath
-	client := httpclient.New(p.timeout)
 	var lastErr error
 
 	for i, candidate := range modelCandidates {
@@ -272,9 +364,9 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 		req.Header.Set("Authorization", "Bearer "+token)
 		req.Header.Set("User-Agent", p.userAgent)
 		req.Header.Set("Accept", "text/event-stream")
-		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+		req.Header.Set("Accept-Language", p.acceptLanguage())
 		req.Header.Set("OAI-Device-Id", p.deviceID)
-		req.Header.Set("OAI-Language", "en-US")
+		req.Header.Set("OAI-Language", p.language())
 		req.Header.Set("Origin", "https://chatgpt.com")
 		req.Header.Set("Referer", "https://chatgpt.com/")
 
@@ -286,6 +378,7 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 		}
 
 		p.setCookies(req)
+		p.setAccount(req)
 
 		resp, err := client.Do(req)
 		if err != nil {
@@ -300,7 +393,17 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
 			_ = resp.Body.Close()
-			lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+			if resp.StatusCode == http.StatusTooManyRequests {
+				lerr := limitResponseError(candidate, resp.Header, body)
+				p.reportLimits([]Limit{{Name: candidate, Remaining: 0, Exhausted: true, ResetsAt: lerr.ResetsAt}})
+				return lerr
+			}
+			if resp.StatusCode == http.StatusUnauthorized {
+				// Don't reuse a token the server rejected in later runs.
+				p.accessToken = ""
+				p.saveSession()
+			}
+			lastErr = provider.HTTPError(resp.StatusCode, body)
 			if i < len(modelCandidates)-1 && isModelFallbackError(resp.StatusCode, string(body)) {
 				logf("[chatgpt] model %q rejected, trying fallback model", candidate)
 				continue
@@ -323,6 +426,10 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 			logf("[chatgpt] requested model=%s, resolved model=%s", candidate, streamMeta.resolvedModel)
 		}
 
+		if streamMeta.asyncTaskID != "" {
+			return p.awaitResearch(ctx, client, token, streamMeta, opts, logf)
+		}
+		p.deliverFiles(ctx, client, token, streamMeta, opts, logf)
 		return nil
 	}
 
@@ -333,7 +440,7 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 }
 
 func (p *Provider) getAccessToken(ctx context.Context, logf func(string, ...any)) (string, error) {
-	if p.accessToken != "" && time.Now().Before(p.tokenExpiry) {
+	if p.accessToken != "" && time.Now().Before(p.tokenExpiry) && p.tokenAccount == p.account() {
 		logf("[chatgpt] using cached access token")
 		return p.accessToken, nil
 	}
@@ -354,10 +461,10 @@ func (p *Provider) getAccessToken(ctx context.Context, logf func(string, ...any)
 
 		req.Header.Set("User-Agent", p.userAgent)
 		req.Header.Set("Accept", "application/json")
-		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+		req.Header.Set("Accept-Language", p.acceptLanguage())
 		p.setCookies(req)
 
-		client := httpclient.New(p.timeout)
+		client := httpclient.NewWithProxy(p.timeout, p.proxy)
 		resp, err := client.Do(req)
 		if err != nil {
 			lastErr = err
@@ -392,7 +499,10 @@ func (p *Provider) getAccessToken(ctx context.Context, logf func(string, ...any)
 		}
 
 		p.accessToken = session.AccessToken
+		p.sessionAccount = session.Account
 		p.tokenExpiry = time.Now().Add(55 * time.Minute)
+		p.tokenAccount = p.account()
+		p.saveSession()
 		logf("[chatgpt] access token obtained")
 		return p.accessToken, nil
 	}
@@ -415,7 +525,16 @@ func (p *Provider) setCookies(req *http.Request) {
 var errModelFallbackNeeded = errors.New("chatgpt stream indicates model fallback needed")
 
 type streamMetadata struct {
-	resolvedModel string
+	resolvedModel  string
+	conversationID string
+	// asyncTaskID is set when the answer is produced by a background task
+	// (deep research) rather than in the stream.
+	asyncTaskID string
+	// messageID is the answer's message, which its sandbox files belong to.
+	messageID string
+	// images and sandboxPaths are what the answer generated.
+	images       []imageAsset
+	sandboxPaths []string
 }
 
 func (p *Provider) readStream(r io.Reader, opts provider.AskOptions, requestedModel string) (streamMetadata, error) {
@@ -426,6 +545,12 @@ func (p *Provider) readStream(r io.Reader, opts provider.AskOptions, requestedMo
 	var lastConversationID string
 	var lastMessageID string
 	meta := streamMetadata{}
+	seenSources := map[string]bool{}
+	thoughts := thoughtStream{emitted: map[string]int{}}
+	truncated := false
+	// Image messages repeat as they fill in; the last frame of each wins.
+	images := map[string][]imageAsset{}
+	var imageMessages []string
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -456,6 +581,55 @@ func (p *Provider) readStream(r io.Reader, opts provider.AskOptions, requestedMo
 			if hasModelSwitcherDeny(raw) && fullText == "" {
 				return meta, errModelFallbackNeeded
 			}
+			if t, _ := raw["type"].(string); t == "conversation_detail_metadata" {
+				p.reportLimits(parseLimits(raw))
+			}
+			if msg, ok := raw["error"].(string); ok && isLimitMessage(msg) {
+				p.reportLimits([]Limit{{Name: requestedModel, Remaining: 0, Exhausted: true}})
+				return meta, &LimitError{Model: requestedModel, Message: msg}
+			}
+			if v, ok := findStringByKey(raw, "async_task_id"); ok && v != "" && meta.asyncTaskID == "" {
+				meta.asyncTaskID = v
+				if title, ok := findStringByKey(raw, "async_task_title"); ok && title != "" && opts.OnProgress != nil {
+					opts.OnProgress("Research started: " + title)
+				}
+			}
+			if opts.OnTruncated != nil && !truncated && stoppedAtLimit(raw) {
+				truncated = true
+				opts.OnTruncated()
+			}
+			if suggestions, ok := findStringsByKey(raw, "follow_up_suggestions"); ok && opts.OnFollowUps != nil {
+				opts.OnFollowUps(suggestions)
+			}
+			if opts.OnThinking != nil {
+				if msg, ok := raw["message"].(map[string]any); ok {
+					thoughts.emit(msg, opts.OnThinking)
+				}
+			}
+			if msg, ok := raw["message"].(map[string]any); ok {
+				if assets := messageImages(msg); len(assets) > 0 {
+					id, _ := msg["id"].(string)
+					if _, seen := images[id]; !seen {
+						imageMessages = append(imageMessages, id)
+					}
+					images[id] = assets
+				}
+			}
+			if opts.OnSource != nil {
+				if msg, ok := raw["message"].(map[string]any); ok {
+					metadata, _ := msg["metadata"].(map[string]any)
+					for _, src := range messageSources(metadata) {
+						if seenSources[src.URL] {
+							continue
+						}
+						seenSources[src.URL] = true
+						if src.Name == "" {
+							src.Name = src.URL
+						}
+						opts.OnSource(src.Name, src.URL)
+					}
+				}
+			}
 		}
 
 		var frame conversationResponse
@@ -475,7 +649,7 @@ func (p *Provider) readStream(r io.Reader, opts provider.AskOptions, requestedMo
 		}
 
 		if len(frame.Message.Content.Parts) > 0 {
-			current := frame.Message.Content.Parts[len(frame.Message.Content.Parts)-1]
+			current := stripCitationMarkers(frame.Message.Content.Parts[len(frame.Message.Content.Parts)-1])
 			if len(current) > len(fullText) {
 				delta := current[len(fullText):]
 				fullText = current
@@ -490,6 +664,12 @@ func (p *Provider) readStream(r io.Reader, opts provider.AskOptions, requestedMo
 		return meta, fmt.Errorf("reading stream: %w", err)
 	}
 
+	meta.conversationID = lastConversationID
+	meta.messageID = lastMessageID
+	for _, id := range imageMessages {
+		meta.images = append(meta.images, images[id]...)
+	}
+	meta.sandboxPaths = sandboxPaths(fullText)
 	if opts.OnConversation != nil && (lastConversationID != "" || lastMessageID != "") {
 		opts.OnConversation(lastConversationID, lastMessageID, "")
 	}
@@ -501,6 +681,88 @@ func (p *Provider) readStream(r io.Reader, opts provider.AskOptions, requestedMo
 	return meta, nil
 }
 
+// thoughtStream turns the cumulative "thoughts" messages of a reasoning
+// model into incremental text. Each thought is a summary heading and a
+// body; the frames repeat everything so far, so it remembers how much of
+// each thought has been emitted.
+type thoughtStream struct {
+	emitted map[string]int // "messageID/index" → body bytes emitted
+}
+
+func (t *thoughtStream) emit(msg map[string]any, onThinking func(string)) {
+	content, _ := msg["content"].(map[string]any)
+	if ct, _ := content["content_type"].(string); ct != "thoughts" {
+		return
+	}
+	items, _ := content["thoughts"].([]any)
+	msgID, _ := msg["id"].(string)
+	for i, item := range items {
+		thought, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("%s/%d", msgID, i)
+		n, seen := t.emitted[key]
+		if !seen {
+			if len(t.emitted) > 0 {
+				onThinking("\n\n")
+			}
+			if summary, _ := thought["summary"].(string); strings.TrimSpace(summary) != "" {
+				onThinking(strings.TrimSpace(summary) + "\n")
+			}
+		}
+		body, _ := thought["content"].(string)
+		if len(body) > n {
+			onThinking(body[n:])
+			n = len(body)
+		}
+		t.emitted[key] = n
+	}
+}
+
+// systemHints selects the conversation tools for a request.
+func (p *Provider) systemHints() []string {
+	if p.deepResearch {
+		return []string{"research"}
+	}
+	if p.webSearch {
+		return []string{"search"}
+	}
+	return []string{}
+}
+
+// Search answers embed citation markers as private-use runes, e.g.
+// "\ue200cite\ue202turn0search1\ue201"; the sources arrive separately in
+// the message metadata.
+const (
+	citationStart = "\ue200"
+	citationEnd   = "\ue201"
+)
+
+// stripCitationMarkers removes complete citation markers from text and
+// drops a trailing marker that hasn't finished streaming, so the cleaned
+// text only ever grows as the stream does.
+func stripCitationMarkers(text string) string {
+	if !strings.Contains(text, citationStart) {
+		return text
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(text, citationStart)
+		if start < 0 {
+			b.WriteString(text)
+			break
+		}
+		b.WriteString(text[:start])
+		end := strings.Index(text[start:], citationEnd)
+		if end < 0 {
+			break
+		}
+		text = text[start+end+len(citationEnd):]
+	}
+	return b.String()
+}
+
 func buildChatGPTModelCandidates(primary string) []string {
 	seen := map[string]struct{}{}
 	candidates := []string{}
@@ -555,6 +817,46 @@ func findStringByKey(v any, key string) (string, bool) {
 	return "", false
 }
 
+// findStringsByKey returns the first non-empty string array stored under key.
+func findStringsByKey(v any, key string) ([]string, bool) {
+	switch t := v.(type) {
+	case map[string]any:
+		if raw, ok := t[key].([]any); ok {
+			var out []string
+			for _, item := range raw {
+				if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+					out = append(out, s)
+				}
+			}
+			if len(out) > 0 {
+				return out, true
+			}
+		}
+		for _, child := range t {
+			if out, ok := findStringsByKey(child, key); ok {
+				return out, true
+			}
+		}
+	case []any:
+		for _, child := range t {
+			if out, ok := findStringsByKey(child, key); ok {
+				return out, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// stoppedAtLimit reports whether a frame's message ended at the output
+// length limit, which the web app answers with a "Continue generating" button.
+func stoppedAtLimit(raw map[string]any) bool {
+	msg, _ := raw["message"].(map[string]any)
+	metadata, _ := msg["metadata"].(map[string]any)
+	details, _ := metadata["finish_details"].(map[string]any)
+	kind, _ := details["type"].(string)
+	return kind == "max_tokens"
+}
+
 func hasModelSwitcherDeny(v any) bool {
 	switch t := v.(type) {
 	case map[string]any:
@@ -601,15 +903,52 @@ func newUUID() string {
 // --- List conversations ---
 
 func (p *Provider) DeleteConversation(ctx context.Context, conversationID string, opts provider.DeleteOptions) error {
+	if err := p.patchConversation(ctx, conversationID, map[string]any{"is_visible": false}, opts.LogFunc); err != nil {
+		return err
+	}
+	if opts.LogFunc != nil {
+		opts.LogFunc("[chatgpt] conversation deleted")
+	}
+	return nil
+}
+
+// RenameConversation sets a conversation's title.
+func (p *Provider) RenameConversation(ctx context.Context, conversationID, title string, opts provider.UpdateOptions) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if err := p.patchConversation(ctx, conversationID, map[string]any{"title": title}, opts.LogFunc); err != nil {
+		return err
+	}
+	if opts.LogFunc != nil {
+		opts.LogFunc("[chatgpt] conversation renamed")
+	}
+	return nil
+}
+
+// ArchiveConversation archives or unarchives a conversation.
+func (p *Provider) ArchiveConversation(ctx context.Context, conversationID string, archived bool, opts provider.UpdateOptions) error {
+	if err := p.patchConversation(ctx, conversationID, map[string]any{"is_archived": archived}, opts.LogFunc); err != nil {
+		return err
+	}
+	if opts.LogFunc != nil {
+		opts.LogFunc("[chatgpt] conversation archived=%v", archived)
+	}
+	return nil
+}
+
+// patchConversation applies fields to a conversation via the PATCH
+// endpoint used for delete, rename, and archive.
+func (p *Provider) patchConversation(ctx context.Context, conversationID string, fields map[string]any, logf func(string, ...any)) error {
 	conversationID = strings.TrimSpace(conversationID)
 	if conversationID == "" {
 		return fmt.Errorf("conversation ID is required")
 	}
 	if p.sessionToken == "" {
-		return fmt.Errorf("no session cookie — log in to chatgpt.com in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chatgpt.com in your browser")
 	}
 
-	logf := opts.LogFunc
 	if logf == nil {
 		logf = func(string, ...any) {}
 	}
@@ -619,7 +958,7 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 		return fmt.Errorf("auth: %w", err)
 	}
 
-	payload, err := json.Marshal(map[string]any{"is_visible": false})
+	payload, err := json.Marshal(fields)
 	if err != nil {
 		return fmt.Errorf("marshalling request: %w", err)
 	}
@@ -638,8 +977,9 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 	req.Header.Set("Origin", "https://chatgpt.com")
 	req.Header.Set("Referer", "https://chatgpt.com/")
 	p.setCookies(req)
+	p.setAccount(req)
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
@@ -648,14 +988,76 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return provider.HTTPError(resp.StatusCode, body)
 	}
-
-	logf("[chatgpt] conversation deleted")
 	return nil
 }
 
-const conversationsPath = "/backend-api/conversations"
+const (
+	conversationsPath       = "/backend-api/conversations"
+	conversationsSearchPath = "/backend-api/conversations/search"
+)
+
+// searchConversations finds conversations by title and content with the
+// web app's search, then applies the offset and limit to the matches.
+func (p *Provider) searchConversations(ctx context.Context, token string, opts provider.ListOptions, limit int, logf func(string, ...any)) ([]provider.Conversation, error) {
+	q := url.Values{}
+	q.Set("query", opts.Search)
+	u := p.baseURL + conversationsSearchPath + "?" + q.Encode()
+	logf("[chatgpt] GET %s", u)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("Accept", "application/json")
+	p.setCookies(req)
+	p.setAccount(req)
+
+	resp, err := httpclient.NewWithProxy(p.timeout, p.proxy).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, provider.HTTPError(resp.StatusCode, body)
+	}
+
+	var data struct {
+		Items []struct {
+			ConversationID string   `json:"conversation_id"`
+			Title          string   `json:"title"`
+			CreateTime     flexTime `json:"create_time"`
+			UpdateTime     flexTime `json:"update_time"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	// A conversation matches once per matching message.
+	seen := map[string]bool{}
+	var result []provider.Conversation
+	for _, item := range data.Items {
+		if item.ConversationID == "" || seen[item.ConversationID] {
+			continue
+		}
+		seen[item.ConversationID] = true
+		c := provider.Conversation{ID: item.ConversationID, Title: item.Title}
+		if item.CreateTime.Valid {
+			c.CreatedAt = item.CreateTime.Time
+		}
+		if item.UpdateTime.Valid {
+			c.UpdatedAt = item.UpdateTime.Time
+		}
+		result = append(result, c)
+	}
+	logf("[chatgpt] search matched %d conversations", len(result))
+	return provider.Page(result, provider.ListOptions{Offset: opts.Offset, Limit: limit}), nil
+}
 
 type conversationsResponse struct {
 	Items  []conversationItem `json:"items"`
@@ -706,7 +1108,7 @@ type conversationItem struct {
 // ListConversations fetches recent conversations from the ChatGPT web API.
 func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
 	if p.sessionToken == "" {
-		return nil, fmt.Errorf("no session cookie — log in to chatgpt.com in your browser")
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chatgpt.com in your browser")
 	}
 
 	logf := opts.LogFunc
@@ -723,8 +1125,14 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 	if limit <= 0 {
 		limit = 20
 	}
+	if opts.Search != "" {
+		return p.searchConversations(ctx, token, opts, limit, logf)
+	}
 
-	u := fmt.Sprintf("%s%s?offset=0&limit=%d&order=updated", p.baseURL, conversationsPath, limit)
+	u := fmt.Sprintf("%s%s?offset=%d&limit=%d&order=updated", p.baseURL, conversationsPath, max(opts.Offset, 0), limit)
+	if opts.Archived {
+		u += "&is_archived=true"
+	}
 	logf("[chatgpt] GET %s", u)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
@@ -735,8 +1143,9 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 	req.Header.Set("User-Agent", p.userAgent)
 	req.Header.Set("Accept", "application/json")
 	p.setCookies(req)
+	p.setAccount(req)
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -745,7 +1154,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, provider.HTTPError(resp.StatusCode, body)
 	}
 
 	var data conversationsResponse
@@ -830,8 +1239,9 @@ func (p *Provider) FetchAvailableModels(ctx context.Context, logf func(string, .
 	req.Header.Set("User-Agent", p.userAgent)
 	req.Header.Set("Accept", "application/json")
 	p.setCookies(req)
+	p.setAccount(req)
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -840,7 +1250,7 @@ func (p *Provider) FetchAvailableModels(ctx context.Context, logf func(string, .
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, provider.HTTPError(resp.StatusCode, body)
 	}
 
 	var data backendModelsResponse
@@ -0,0 +1,74 @@
+package chatgpt
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// replay serves the provider's requests from a cassette in testdata.
+func replay(t *testing.T, name string) {
+	t.Helper()
+	if err := httpclient.ReplayCassette(filepath.Join("testdata", name)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = httpclient.StopCassette() })
+}
+
+func TestAskReplay(t *testing.T) {
+	replay(t, "ask.json")
+
+	p := New("", "", "test-agent", 0)
+	p.SetCookies(map[string]string{cookieSessionToken: "test-session"})
+
+	var text strings.Builder
+	var sources []provider.Source
+	var convID, msgID string
+	done := false
+	err := p.Ask(context.Background(), "What is the capital of France?", provider.AskOptions{
+		OnText:   func(s string) { text.WriteString(s) },
+		OnSource: func(name, url string) { sources = append(sources, provider.Source{Name: name, URL: url}) },
+		OnConversation: func(id, parent, _ string) {
+			convID, msgID = id, parent
+		},
+		OnDone: func() { done = true },
+	})
+	if err != nil {
+		t.Fatalf("Ask: %v", err)
+	}
+
+	if want := "Paris is the capital of France. \n\nIt sits on the Seine."; text.String() != want {
+		t.Errorf("text = %q, want %q", text.String(), want)
+	}
+	wantSources := []provider.Source{{Name: "France - The World Factbook", URL: "https://www.cia.gov/the-world-factbook/countries/france/"}}
+	if len(sources) != len(wantSources) || sources[0] != wantSources[0] {
+		t.Errorf("sources = %v, want %v", sources, wantSources)
+	}
+	if convID != "6820c6f1-0000-4000-8000-000000000001" {
+		t.Errorf("conversation = %q", convID)
+	}
+	if msgID != "bbbbbbbb-0000-4000-8000-000000000003" {
+		t.Errorf("message = %q", msgID)
+	}
+	if !done {
+		t.Error("OnDone not called")
+	}
+}
+
+func TestStripCitationMarkers(t *testing.T) {
+	marker := citationStart + "cite\ue202turn0search0" + citationEnd
+	tests := []struct{ in, want string }{
+		{"plain", "plain"},
+		{"a " + marker + " b", "a  b"},
+		{"a " + marker[:len(marker)-len(citationEnd)], "a "},
+	}
+	for _, tt := range tests {
+		if got := stripCitationMarkers(tt.in); got != tt.want {
+			t.Errorf("stripCitationMarkers(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package chatgpt — files.go saves what an answer generated.
+//
+// Generated images (DALL·E and image generation) arrive as asset pointers
+// in tool messages: file-service://file-… for older images and
+// sediment://file_… for newer ones. Code interpreter outputs are linked
+// from the answer text as sandbox:/mnt/data/… paths. Each resolves to a
+// signed download URL through its own endpoint:
+//
+//	file-service: GET /backend-api/files/{id}/download
+//	sediment:     GET /backend-api/files/download/{id}?conversation_id=…
+//	sandbox:      GET /backend-api/conversation/{id}/interpreter/download?message_id=…&sandbox_path=…
+package chatgpt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// sandboxLinkRE matches a code interpreter output path in answer text.
+var sandboxLinkRE = regexp.MustCompile(`sandbox:(/mnt/data/[^)\s"'\]]+)`)
+
+// imageAsset is an image a tool message produced.
+type imageAsset struct {
+	pointer string
+	prompt  string
+}
+
+// downloadResponse is the reply of the download endpoints.
+type downloadResponse struct {
+	Status      string `json:"status"`
+	DownloadURL string `json:"download_url"`
+	FileName    string `json:"file_name"`
+	ErrorCode   string `json:"error_code"`
+}
+
+// messageImages returns the image asset pointers in a streamed message
+// that is not the user's own.
+func messageImages(msg map[string]any) []imageAsset {
+	if a, _ := msg["author"].(map[string]any); a["role"] == "user" {
+		return nil
+	}
+	content, _ := msg["content"].(map[string]any)
+	if ct, _ := content["content_type"].(string); ct != "multimodal_text" {
+		return nil
+	}
+	parts, _ := content["parts"].([]any)
+	var assets []imageAsset
+	for _, part := range parts {
+		m, ok := part.(map[string]any)
+		if !ok || m["content_type"] != "image_asset_pointer" {
+			continue
+		}
+		pointer, _ := m["asset_pointer"].(string)
+		if pointer == "" {
+			continue
+		}
+		a := imageAsset{pointer: pointer}
+		a.prompt, _ = findStringByKey(m["metadata"], "prompt")
+		assets = append(assets, a)
+	}
+	return assets
+}
+
+// sandboxPaths returns the distinct code interpreter paths linked from
+// text, in order.
+func sandboxPaths(text string) []string {
+	var paths []string
+	seen := map[string]bool{}
+	for _, m := range sandboxLinkRE.FindAllStringSubmatch(text, -1) {
+		if p := strings.TrimRight(m[1], ".,;:"); !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// deliverFiles resolves the answer's images and files, downloads them to
+// opts.ImageDir and opts.DownloadDir when set, and reports each through
+// opts.OnImage and opts.OnFile. A failed download is logged and the item
+// is still reported.
+func (p *Provider) deliverFiles(ctx context.Context, client *http.Client, token string, meta streamMetadata, opts provider.AskOptions, logf func(string, ...any)) {
+	if opts.ImageDir != "" || opts.OnImage != nil {
+		stamp := time.Now().Format("20060102-150405")
+		for i, a := range meta.images {
+			img := provider.GeneratedImage{Title: a.prompt}
+			dl, err := p.resolveAsset(ctx, client, token, a.pointer, meta.conversationID, logf)
+			if err != nil {
+				logf("[chatgpt] warning: could not resolve image %d: %v", i+1, err)
+			} else {
+				img.URL = dl.DownloadURL
+				if opts.ImageDir != "" {
+					resp, err := p.fetchDownload(ctx, client, token, dl.DownloadURL, logf)
+					if err == nil {
+						img.Path, err = provider.SaveImage(resp, opts.ImageDir, fmt.Sprintf("chatgpt-%s-%d", stamp, i+1))
+					}
+					if err != nil {
+						logf("[chatgpt] warning: could not save image %d: %v", i+1, err)
+					}
+				}
+			}
+			if opts.OnImage != nil {
+				opts.OnImage(img)
+			}
+		}
+	}
+
+	if opts.DownloadDir != "" || opts.OnFile != nil {
+		for _, sandboxPath := range meta.sandboxPaths {
+			f := provider.GeneratedFile{Name: path.Base(sandboxPath)}
+			dl, err := p.resolveSandboxFile(ctx, client, token, meta.conversationID, meta.messageID, sandboxPath, logf)
+			if err != nil {
+				logf("[chatgpt] warning: could not resolve %s: %v", sandboxPath, err)
+			} else {
+				f.URL = dl.DownloadURL
+				if dl.FileName != "" {
+					f.Name = dl.FileName
+				}
+				if opts.DownloadDir != "" {
+					resp, err := p.fetchDownload(ctx, client, token, dl.DownloadURL, logf)
+					if err == nil {
+						f.Path, err = provider.SaveFile(resp, opts.DownloadDir, f.Name)
+					}
+					if err != nil {
+						logf("[chatgpt] warning: could not save %s: %v", f.Name, err)
+					}
+				}
+			}
+			if opts.OnFile != nil {
+				opts.OnFile(f)
+			}
+		}
+	}
+}
+
+// resolveAsset turns an image asset pointer into a download URL.
+func (p *Provider) resolveAsset(ctx context.Context, client *http.Client, token, pointer, conversationID string, logf func(string, ...any)) (*downloadResponse, error) {
+	var u string
+	switch {
+	case strings.HasPrefix(pointer, "file-service://"):
+		u = fmt.Sprintf("%s%s/%s/download", p.baseURL, filesPath, url.PathEscape(strings.TrimPrefix(pointer, "file-service://")))
+	case strings.HasPrefix(pointer, "sediment://"):
+		q := url.Values{"conversation_id": {conversationID}, "inline": {"false"}}
+		u = fmt.Sprintf("%s%s/download/%s?%s", p.baseURL, filesPath, url.PathEscape(strings.TrimPrefix(pointer, "sediment://")), q.Encode())
+	default:
+		return nil, fmt.Errorf("unknown asset pointer %q", pointer)
+	}
+	return p.resolveDownload(ctx, client, token, u, logf)
+}
+
+// resolveSandboxFile turns a code interpreter path into a download URL.
+func (p *Provider) resolveSandboxFile(ctx context.Context, client *http.Client, token, conversationID, messageID, sandboxPath string, logf func(string, ...any)) (*downloadResponse, error) {
+	if conversationID == "" || messageID == "" {
+		return nil, fmt.Errorf("no conversation to download from")
+	}
+	q := url.Values{"message_id": {messageID}, "sandbox_path": {sandboxPath}}
+	u := fmt.Sprintf("%s%s/%s/interpreter/download?%s", p.baseURL, conversationPath, url.PathEscape(conversationID), q.Encode())
+	return p.resolveDownload(ctx, client, token, u, logf)
+}
+
+func (p *Provider) resolveDownload(ctx context.Context, client *http.Client, token, u string, logf func(string, ...any)) (*downloadResponse, error) {
+	logf("[chatgpt] GET %s", u)
+	var dl downloadResponse
+	if err := p.doJSON(ctx, client, token, http.MethodGet, u, nil, &dl); err != nil {
+		return nil, err
+	}
+	if dl.DownloadURL == "" {
+		return nil, fmt.Errorf("no download URL (status %q %s)", dl.Status, dl.ErrorCode)
+	}
+	if strings.HasPrefix(dl.DownloadURL, "/") {
+		dl.DownloadURL = p.baseURL + dl.DownloadURL
+	}
+	return &dl, nil
+}
+
+// fetchDownload GETs a resolved download URL. URLs on ChatGPT itself need
+// the session; signed storage URLs must not get it.
+func (p *Provider) fetchDownload(ctx context.Context, client *http.Client, token, u string, logf func(string, ...any)) (*http.Response, error) {
+	logf("[chatgpt] GET %s", u)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+	if strings.HasPrefix(u, p.baseURL+"/") {
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("OAI-Device-Id", p.deviceID)
+		p.setCookies(req)
+		p.setAccount(req)
+	}
+	return client.Do(req)
+}
@@ -0,0 +1,86 @@
+// Package chatgpt — fingerprint.go describes the browser environment that
+// is reported to ChatGPT in conversation requests and the sentinel config.
+package chatgpt
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Fingerprint holds the browser properties reported to ChatGPT. Zero
+// fields fall back to the built-in defaults.
+type Fingerprint struct {
+	ScreenWidth  int
+	ScreenHeight int
+	PageWidth    int
+	PageHeight   int
+	PixelRatio   int
+	Cores        int
+	// ScriptSrc and DPL identify the ChatGPT web deployment in the sentinel config.
+	ScriptSrc string
+	DPL       string
+}
+
+var defaultFingerprint = Fingerprint{
+	ScreenWidth:  1920,
+	ScreenHeight: 1080,
+	PageWidth:    1850,
+	PageHeight:   578,
+	PixelRatio:   1,
+	Cores:        8,
+	ScriptSrc:    defaultScript,
+	DPL:          defaultDPL,
+}
+
+// commonDisplays are popular desktop resolutions with their device pixel ratio.
+var commonDisplays = []struct{ w, h, ratio int }{
+	{1920, 1080, 1},
+	{2560, 1440, 1},
+	{1366, 768, 1},
+	{1536, 864, 1},
+	{1440, 900, 2},
+	{1512, 982, 2},
+	{1728, 1117, 2},
+	{1680, 1050, 2},
+}
+
+// RandomFingerprint returns a plausible desktop browser fingerprint. It is
+// meant to be generated once per install and reused, not per request.
+func RandomFingerprint() Fingerprint {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	d := commonDisplays[rng.Intn(len(commonDisplays))]
+	return Fingerprint{
+		ScreenWidth:  d.w,
+		ScreenHeight: d.h,
+		// Leave room for a scrollbar and the browser toolbar/tab strip.
+		PageWidth:  d.w - rng.Intn(24),
+		PageHeight: d.h - 110 - rng.Intn(150),
+		PixelRatio: d.ratio,
+		Cores:      []int{4, 8, 10, 12, 16}[rng.Intn(5)],
+	}
+}
+
+// SetFingerprint overrides the reported browser fingerprint. Zero fields
+// keep their current values.
+func (p *Provider) SetFingerprint(fp Fingerprint) {
+	if fp.ScreenWidth > 0 && fp.ScreenHeight > 0 {
+		p.fingerprint.ScreenWidth = fp.ScreenWidth
+		p.fingerprint.ScreenHeight = fp.ScreenHeight
+	}
+	if fp.PageWidth > 0 && fp.PageHeight > 0 {
+		p.fingerprint.PageWidth = fp.PageWidth
+		p.fingerprint.PageHeight = fp.PageHeight
+	}
+	if fp.PixelRatio > 0 {
+		p.fingerprint.PixelRatio = fp.PixelRatio
+	}
+	if fp.Cores > 0 {
+		p.fingerprint.Cores = fp.Cores
+	}
+	if fp.ScriptSrc != "" {
+		p.fingerprint.ScriptSrc = fp.ScriptSrc
+	}
+	if fp.DPL != "" {
+		p.fingerprint.DPL = fp.DPL
+	}
+}
@@ -0,0 +1,86 @@
+// Package chatgpt — gizmo.go talks to custom GPTs ("gizmos").
+//
+// A question is routed to a custom GPT by sending conversation_mode
+// {kind: "gizmo_interaction", gizmo_id: "g-…"} instead of the default
+// primary_assistant mode. The GPTs pinned in the sidebar come from
+// GET /backend-api/gizmos/bootstrap.
+package chatgpt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+const gizmosBootstrapPath = "/backend-api/gizmos/bootstrap"
+
+// GPT is a custom GPT available to the account.
+type GPT struct {
+	ID          string
+	Name        string
+	Description string
+}
+
+type gizmosBootstrapResponse struct {
+	Gizmos []struct {
+		Resource struct {
+			Gizmo struct {
+				ID      string `json:"id"`
+				Display struct {
+					Name        string `json:"name"`
+					Description string `json:"description"`
+				} `json:"display"`
+			} `json:"gizmo"`
+		} `json:"resource"`
+	} `json:"gizmos"`
+}
+
+// SetGizmo sends questions to the custom GPT with the given ID (g-…)
+// instead of the default assistant.
+func (p *Provider) SetGizmo(id string) { p.gizmoID = strings.TrimSpace(id) }
+
+// conversationMode selects the default assistant or the configured GPT.
+func (p *Provider) conversationMode() conversationMode {
+	if p.gizmoID != "" {
+		return conversationMode{Kind: "gizmo_interaction", GizmoID: p.gizmoID}
+	}
+	return conversationMode{Kind: "primary_assistant"}
+}
+
+// ListGPTs returns the custom GPTs pinned to the account's sidebar.
+func (p *Provider) ListGPTs(ctx context.Context, logf func(string, ...any)) ([]GPT, error) {
+	if p.sessionToken == "" {
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chatgpt.com in your browser")
+	}
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	token, err := p.getAccessToken(ctx, logf)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	u := p.baseURL + gizmosBootstrapPath + "?limit=50"
+	logf("[chatgpt] GET %s", u)
+
+	var data gizmosBootstrapResponse
+	if err := p.doJSON(ctx, httpclient.NewWithProxy(p.timeout, p.proxy), token, http.MethodGet, u, nil, &data); err != nil {
+		return nil, err
+	}
+
+	gpts := make([]GPT, 0, len(data.Gizmos))
+	for _, g := range data.Gizmos {
+		gizmo := g.Resource.Gizmo
+		if gizmo.ID == "" {
+			continue
+		}
+		gpts = append(gpts, GPT{ID: gizmo.ID, Name: gizmo.Display.Name, Description: gizmo.Display.Description})
+	}
+	logf("[chatgpt] fetched %d pinned GPT(s)", len(gpts))
+	return gpts, nil
+}
@@ -0,0 +1,227 @@
+package chatgpt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/locale"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+const conversationInitPath = "/backend-api/conversation/init"
+
+// Limit is the quota ChatGPT reports on a model or a feature such as
+// deep_research.
+type Limit struct {
+	// Name is a model slug or a feature name.
+	Name string
+	// Remaining is the number of uses left, or -1 when ChatGPT only says
+	// whether the cap is hit.
+	Remaining int
+	// Exhausted is set once the cap is hit; ChatGPT then answers with
+	// another model, or not at all, until ResetsAt.
+	Exhausted bool
+	ResetsAt  time.Time
+}
+
+// LimitError is returned when ChatGPT refuses a question because a usage
+// cap is hit.
+type LimitError struct {
+	Model    string
+	ResetsAt time.Time
+	Message  string
+}
+
+func (e *LimitError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = "usage limit reached"
+		if e.Model != "" {
+			msg = "usage limit reached for " + e.Model
+		}
+	}
+	if !e.ResetsAt.IsZero() {
+		msg += fmt.Sprintf(" (resets %s)", e.ResetsAt.Local().Format("Jan 2 15:04"))
+	}
+	return msg
+}
+
+// Unwrap makes a LimitError match provider.ErrRateLimited.
+func (e *LimitError) Unwrap() error { return provider.ErrRateLimited }
+
+// SetLimitsHandler sets the function called with every quota ChatGPT
+// reports, from FetchLimits or in passing while answering, so the caller
+// can keep them for warnings.
+func (p *Provider) SetLimitsHandler(fn func([]Limit)) { p.onLimits = fn }
+
+func (p *Provider) reportLimits(limits []Limit) {
+	if len(limits) > 0 && p.onLimits != nil {
+		p.onLimits(limits)
+	}
+}
+
+// FetchLimits returns the account's current quotas, as the web app loads
+// them when a new chat opens.
+func (p *Provider) FetchLimits(ctx context.Context, logf func(string, ...any)) ([]Limit, error) {
+	if p.sessionToken == "" {
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chatgpt.com in your browser")
+	}
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	token, err := p.getAccessToken(ctx, logf)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	loc, _ := locale.Load(p.timezone)
+	payload, _ := json.Marshal(map[string]any{
+		"gizmo_id":                nil,
+		"requested_default_model": nil,
+		"conversation_id":         nil,
+		"timezone_offset_min":     locale.OffsetMinutes(loc),
+	})
+	u := p.baseURL + conversationInitPath
+	logf("[chatgpt] POST %s", u)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("OAI-Device-Id", p.deviceID)
+	req.Header.Set("Origin", "https://chatgpt.com")
+	req.Header.Set("Referer", "https://chatgpt.com/")
+	p.setCookies(req)
+	p.setAccount(req)
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, provider.HTTPError(resp.StatusCode, body)
+	}
+
+	var raw map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	limits := parseLimits(raw)
+	logf("[chatgpt] %d limits reported", len(limits))
+	p.reportLimits(limits)
+	return limits, nil
+}
+
+// parseLimits reads the quotas in a conversation_detail_metadata object:
+// limits_progress counts down per feature, model_limits lists the models
+// whose cap is hit, and blocked_features the features that are off.
+func parseLimits(raw map[string]any) []Limit {
+	var limits []Limit
+	if items, ok := raw["limits_progress"].([]any); ok {
+		for _, item := range items {
+			m, _ := item.(map[string]any)
+			name, _ := m["feature_name"].(string)
+			if name == "" {
+				continue
+			}
+			l := Limit{Name: name, Remaining: -1, ResetsAt: parseLimitTime(m["reset_after"])}
+			if n, ok := m["remaining"].(float64); ok {
+				l.Remaining = int(n)
+				l.Exhausted = n <= 0
+			}
+			limits = append(limits, l)
+		}
+	}
+	if items, ok := raw["model_limits"].([]any); ok {
+		for _, item := range items {
+			m, _ := item.(map[string]any)
+			slug, _ := m["model_slug"].(string)
+			if slug == "" {
+				continue
+			}
+			limits = append(limits, Limit{Name: slug, Remaining: 0, Exhausted: true, ResetsAt: parseLimitTime(m["resets_after"])})
+		}
+	}
+	if items, ok := raw["blocked_features"].([]any); ok {
+		for _, item := range items {
+			var name string
+			switch v := item.(type) {
+			case string:
+				name = v
+			case map[string]any:
+				name, _ = v["name"].(string)
+			}
+			if name != "" {
+				limits = append(limits, Limit{Name: name, Remaining: 0, Exhausted: true})
+			}
+		}
+	}
+	return limits
+}
+
+// parseLimitTime accepts the RFC 3339 strings and Unix seconds ChatGPT
+// uses for reset times.
+func parseLimitTime(v any) time.Time {
+	switch t := v.(type) {
+	case string:
+		if ts, err := time.Parse(time.RFC3339, t); err == nil {
+			return ts
+		}
+	case float64:
+		if t > 0 {
+			return time.Unix(int64(t), 0)
+		}
+	}
+	return time.Time{}
+}
+
+// limitResponseError turns a 429 answer into a LimitError: the body
+// carries a model_cap_exceeded detail with clears_in seconds, and
+// Retry-After says the same in the header.
+func limitResponseError(model string, header http.Header, body []byte) *LimitError {
+	e := &LimitError{Model: model}
+	var env struct {
+		Detail any `json:"detail"`
+	}
+	if json.Unmarshal(body, &env) == nil {
+		switch d := env.Detail.(type) {
+		case string:
+			e.Message = d
+		case map[string]any:
+			e.Message, _ = d["message"].(string)
+			if secs, ok := d["clears_in"].(float64); ok && secs > 0 {
+				e.ResetsAt = time.Now().Add(time.Duration(secs) * time.Second)
+			}
+		}
+	}
+	if e.ResetsAt.IsZero() {
+		if secs, err := strconv.Atoi(strings.TrimSpace(header.Get("Retry-After"))); err == nil && secs > 0 {
+			e.ResetsAt = time.Now().Add(time.Duration(secs) * time.Second)
+		}
+	}
+	return e
+}
+
+// isLimitMessage reports whether a stream error text is a usage cap
+// notice, such as "You've hit your GPT-5 limit" or "You've reached our
+// limit of messages per hour".
+func isLimitMessage(s string) bool {
+	lower := strings.ToLower(s)
+	return (strings.Contains(lower, "you've hit") || strings.Contains(lower, "you've reached") ||
+		strings.Contains(lower, "you have reached") || strings.Contains(lower, "usage cap")) &&
+		(strings.Contains(lower, "limit") || strings.Contains(lower, "cap"))
+}
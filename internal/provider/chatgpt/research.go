@@ -0,0 +1,98 @@
+// Package chatgpt — research.go waits for a deep research task to finish.
+//
+// A deep research turn streams only a short kickoff message; the report is
+// written by a background task and appended to the conversation when it
+// completes. The stream carries the task's async_task_id, after which the
+// conversation is polled until a message marked as the task result shows up
+// on the current branch.
+package chatgpt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// researchPollInterval is how often a running research task is checked.
+const researchPollInterval = 15 * time.Second
+
+// researchMaxPollErrors is how many failed polls in a row abandon the wait.
+const researchMaxPollErrors = 3
+
+// awaitResearch polls the conversation until the research report arrives
+// and delivers it through opts like a streamed answer.
+func (p *Provider) awaitResearch(ctx context.Context, client *http.Client, token string, meta streamMetadata, opts provider.AskOptions, logf func(string, ...any)) error {
+	if meta.conversationID == "" {
+		return fmt.Errorf("deep research started without a conversation ID")
+	}
+	logf("[chatgpt] research task %s started, polling conversation %s", meta.asyncTaskID, meta.conversationID)
+
+	start := time.Now()
+	ticker := time.NewTicker(researchPollInterval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		detail, err := p.fetchConversation(ctx, client, token, meta.conversationID, logf)
+		if err != nil {
+			failures++
+			logf("[chatgpt] research poll failed (%d/%d): %v", failures, researchMaxPollErrors, err)
+			if failures >= researchMaxPollErrors {
+				return fmt.Errorf("waiting for deep research: %w", err)
+			}
+			continue
+		}
+		failures = 0
+
+		if node, ok := researchResult(detail); ok {
+			msg, _ := transcriptMessage(node.Message)
+			if opts.OnText != nil {
+				opts.OnText("\n\n" + msg.Text)
+			}
+			if opts.OnSource != nil {
+				for _, src := range msg.Sources {
+					if src.Name == "" {
+						src.Name = src.URL
+					}
+					opts.OnSource(src.Name, src.URL)
+				}
+			}
+			if opts.OnConversation != nil {
+				opts.OnConversation(meta.conversationID, node.ID, "")
+			}
+			logf("[chatgpt] research finished after %s", time.Since(start).Round(time.Second))
+			return nil
+		}
+
+		if opts.OnProgress != nil {
+			opts.OnProgress(fmt.Sprintf("Researching… %s elapsed", time.Since(start).Round(time.Second)))
+		}
+	}
+}
+
+// researchResult returns the task's report if it is on the current branch.
+func researchResult(detail *conversationDetail) (mappingNode, bool) {
+	branch := currentBranch(detail.Mapping, detail.CurrentNode)
+	for i := len(branch) - 1; i >= 0; i-- {
+		node := branch[i]
+		if node.Message == nil {
+			continue
+		}
+		if done, _ := node.Message.Metadata["is_async_task_result_message"].(bool); !done {
+			continue
+		}
+		if _, ok := transcriptMessage(node.Message); ok {
+			return node, true
+		}
+	}
+	return mappingNode{}, false
+}
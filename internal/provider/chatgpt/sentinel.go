@@ -17,11 +17,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math/rand"
 	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/locale"
+	"github.com/kyupark/ask/internal/provider"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -39,11 +43,6 @@ const (
 	defaultPerfVal = 885.6999999880791
 )
 
-var (
-	cores   = []int{1, 2, 4, 8, 12, 16, 24}
-	screens = []int{3000, 4000, 6000}
-)
-
 // sentinelResult holds everything needed to set sentinel headers on the
 // conversation request.
 type sentinelResult struct {
@@ -67,10 +66,52 @@ type chatRequirementsResp struct {
 	ForceLogin bool `json:"force_login"`
 }
 
+// warmSentinelTTL is how long a prefetched sentinel handshake is used;
+// chat-requirements tokens are short-lived.
+const warmSentinelTTL = 2 * time.Minute
+
+// Warm fetches the access token and does the sentinel handshake, proof of
+// work included, so the next Ask can skip both.
+func (p *Provider) Warm(ctx context.Context, logf func(string, ...any)) error {
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	if p.sessionToken == "" {
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chatgpt.com in your browser")
+	}
+	if _, err := p.getAccessToken(ctx, logf); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	s, err := p.acquireSentinel(ctx, logf)
+	if err != nil {
+		return err
+	}
+	p.warmSentinel, p.warmSentinelAt = s, time.Now()
+	p.saveSession()
+	return nil
+}
+
+// sentinel returns the handshake prefetched by Warm, in this run or a
+// saved earlier one, while it is fresh, once, and does a new one
+// otherwise.
+func (p *Provider) sentinel(ctx context.Context, logf func(string, ...any)) (*sentinelResult, error) {
+	s := p.warmSentinel
+	p.warmSentinel = nil
+	if s != nil {
+		p.saveSession()
+	}
+	if s != nil && time.Since(p.warmSentinelAt) < warmSentinelTTL {
+		logf("[chatgpt] using prefetched sentinel")
+		return s, nil
+	}
+	return p.acquireSentinel(ctx, logf)
+}
+
 // acquireSentinel performs the full sentinel handshake:
 // fetch chat-requirements → solve PoW if needed → return tokens.
 func (p *Provider) acquireSentinel(ctx context.Context, logf func(string, ...any)) (*sentinelResult, error) {
-	config := buildConfig(p.userAgent)
+	loc, _ := locale.Load(p.timezone)
+	config := buildConfig(p.userAgent, p.fingerprint, loc, p.language(), p.languages())
 
 	// Build a simple "p" value.  The referenced implementations send either
 	// a static string or a light token; a random UUID-ish string works.
@@ -88,10 +129,10 @@ func (p *Provider) acquireSentinel(ctx context.Context, logf func(string, ...any
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "*/*")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("Accept-Language", p.acceptLanguage())
 	req.Header.Set("User-Agent", p.userAgent)
 	req.Header.Set("OAI-Device-Id", p.deviceID)
-	req.Header.Set("OAI-Language", "en-US")
+	req.Header.Set("OAI-Language", p.language())
 	req.Header.Set("Origin", "https://chatgpt.com")
 	req.Header.Set("Referer", "https://chatgpt.com/")
 
@@ -99,8 +140,9 @@ func (p *Provider) acquireSentinel(ctx context.Context, logf func(string, ...any
 		req.Header.Set("Authorization", "Bearer "+p.accessToken)
 	}
 	p.setCookies(req)
+	p.setAccount(req)
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("sentinel request failed: %w", err)
@@ -109,7 +151,7 @@ func (p *Provider) acquireSentinel(ctx context.Context, logf func(string, ...any
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return nil, fmt.Errorf("sentinel HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("sentinel %w", provider.HTTPError(resp.StatusCode, body))
 	}
 
 	var cresp chatRequirementsResp
@@ -118,7 +160,7 @@ func (p *Provider) acquireSentinel(ctx context.Context, logf func(string, ...any
 	}
 
 	if cresp.ForceLogin {
-		return nil, fmt.Errorf("ChatGPT requires login — session may be expired")
+		return nil, provider.Errorf(provider.ErrAuthExpired, "ChatGPT requires login — session may be expired")
 	}
 
 	if cresp.Token == "" {
@@ -147,21 +189,19 @@ func (p *Provider) acquireSentinel(ctx context.Context, logf func(string, ...any
 
 // buildConfig creates the browser-fingerprint config array that gets
 // JSON-serialized and base64-encoded in the PoW loop.
-func buildConfig(userAgent string) []interface{} {
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	core := cores[rng.Intn(len(cores))]
-	screen := screens[rng.Intn(len(screens))]
+func buildConfig(userAgent string, fp Fingerprint, loc *time.Location, lang, langs string) []interface{} {
+	screen := fp.Cores + fp.ScreenWidth + fp.ScreenHeight
 
 	return []interface{}{
-		core + screen,     // 0: cores + screen resolution bucket
-		getParseTime(),    // 1: formatted timestamp
+		screen,            // 0: cores + screen width + height
+		getParseTime(loc), // 1: formatted timestamp
 		int64(4294705152), // 2: magic constant (WebGL renderer hash)
 		0,                 // 3: iteration counter (mutated in loop)
 		userAgent,         // 4: User-Agent
-		defaultScript,     // 5: script source URL
-		defaultDPL,        // 6: deployment hash
-		"en-US",           // 7: language
-		"en-US,en",        // 8: languages
+		fp.ScriptSrc,      // 5: script source URL
+		fp.DPL,            // 6: deployment hash
+		lang,              // 7: language
+		langs,             // 8: languages
 		0,                 // 9: elapsed time in ms (mutated in loop)
 		navigatorKey,      // 10: navigator property fingerprint
 		documentKey,       // 11: document property key
@@ -170,18 +210,18 @@ func buildConfig(userAgent string) []interface{} {
 	}
 }
 
-// getParseTime returns a timestamp string mimicking a US-timezone browser.
-func getParseTime() string {
-	loc, err := time.LoadLocation("America/Los_Angeles")
-	if err != nil {
-		loc = time.UTC
-	}
+// getParseTime returns a timestamp string as a browser in loc formats
+// new Date().toString().
+func getParseTime(loc *time.Location) string {
 	now := time.Now().In(loc)
-	return now.Format(timeLayout) + " GMT-0800 (Pacific Time)"
+	zone, _ := now.Zone()
+	return now.Format(timeLayout) + now.Format(" GMT-0700") + " (" + zone + ")"
 }
 
 // solveProofOfWork brute-forces a nonce such that
 // SHA3-512(seed || base64(config_with_nonce)) has a hex prefix ≤ difficulty.
+// The nonce space is split across GOMAXPROCS workers, which stop as soon
+// as any of them finds a solution.
 //
 // Returns ("gAAAAAB" + base64_solution, true) on success, or a fallback
 // error token on exhaustion.
@@ -191,11 +231,49 @@ func solveProofOfWork(config []interface{}, seed, diff string) (string, bool) {
 		diffLen = 1
 	}
 
+	workers := runtime.GOMAXPROCS(0)
+	startTime := time.Now()
+	var (
+		found    atomic.Bool
+		once     sync.Once
+		solution string
+		wg       sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			if b64, ok := searchNonces(config, seed, diff, diffLen, w, workers, startTime, &found); ok {
+				once.Do(func() {
+					solution = b64
+					found.Store(true)
+				})
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if found.Load() {
+		return resultPrefix + solution, true
+	}
+
+	// Fallback: send an error token so the request at least proceeds.
+	fallback := errorPrefix + base64.StdEncoding.EncodeToString([]byte(`"`+seed+`"`))
+	return fallback, false
+}
+
+// searchNonces tries every stride-th nonce from first on its own copy of
+// config, giving up once another worker has set found. It returns the
+// base64 config of the first solution it finds.
+func searchNonces(config []interface{}, seed, diff string, diffLen, first, stride int, startTime time.Time, found *atomic.Bool) (string, bool) {
+	config = append([]interface{}(nil), config...)
 	hasher := sha3.New512()
 	seedBytes := []byte(seed)
-	startTime := time.Now()
 
-	for i := 0; i < maxIterations; i++ {
+	for i := first; i < maxIterations; i += stride {
+		if found.Load() {
+			return "", false
+		}
 		config[3] = i
 		config[9] = time.Since(startTime).Milliseconds()
 
@@ -208,11 +286,8 @@ func solveProofOfWork(config []interface{}, seed, diff string) (string, bool) {
 		hasher.Reset()
 
 		if hex.EncodeToString(hash[:diffLen]) <= diff {
-			return resultPrefix + b64, true
+			return b64, true
 		}
 	}
-
-	// Fallback: send an error token so the request at least proceeds.
-	fallback := errorPrefix + base64.StdEncoding.EncodeToString([]byte(`"`+seed+`"`))
-	return fallback, false
+	return "", false
 }
@@ -0,0 +1,67 @@
+package chatgpt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Session is the client state worth keeping between runs: the device ID
+// ChatGPT knows this install by, the access token, and a sentinel
+// handshake done ahead by Warm. Restoring it lets a new run skip the
+// session fetch and the proof of work, and keeps one device ID instead of
+// a fresh one per run.
+type Session struct {
+	DeviceID    string
+	AccessToken string
+	TokenExpiry time.Time
+	// Account identifies the session cookie the access token was issued
+	// for; the token is not reused under another one.
+	Account    string
+	ChatToken  string
+	ProofToken string
+	SentinelAt time.Time
+}
+
+// SetSession restores state saved from an earlier run. Fields left empty
+// keep their defaults.
+func (p *Provider) SetSession(s Session) {
+	if s.DeviceID != "" {
+		p.deviceID = s.DeviceID
+	}
+	if s.AccessToken != "" {
+		p.accessToken, p.tokenExpiry, p.tokenAccount = s.AccessToken, s.TokenExpiry, s.Account
+	}
+	if s.ChatToken != "" {
+		p.warmSentinel = &sentinelResult{ChatToken: s.ChatToken, ProofToken: s.ProofToken}
+		p.warmSentinelAt = s.SentinelAt
+	}
+}
+
+// SetSessionHandler sets the function called with the session whenever
+// it changes, so the caller can save it for the next run.
+func (p *Provider) SetSessionHandler(fn func(Session)) { p.onSession = fn }
+
+func (p *Provider) saveSession() {
+	if p.onSession == nil {
+		return
+	}
+	s := Session{
+		DeviceID:    p.deviceID,
+		AccessToken: p.accessToken,
+		TokenExpiry: p.tokenExpiry,
+		Account:     p.tokenAccount,
+	}
+	if p.warmSentinel != nil {
+		s.ChatToken, s.ProofToken = p.warmSentinel.ChatToken, p.warmSentinel.ProofToken
+		s.SentinelAt = p.warmSentinelAt
+	}
+	p.onSession(s)
+}
+
+// account returns a short digest of the session cookie, so a saved access
+// token can be matched to it without storing the cookie twice.
+func (p *Provider) account() string {
+	sum := sha256.Sum256([]byte(p.sessionToken))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,108 @@
+// Package chatgpt — share.go publishes a conversation as a public,
+// read-only link.
+//
+// Flow:
+//  1. GET /backend-api/conversation/{id} → current_node
+//  2. POST /backend-api/share/create → share_id + share_url
+//  3. PATCH /backend-api/share/{share_id} with is_public=true
+package chatgpt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+const sharePath = "/backend-api/share"
+
+type shareCreateResponse struct {
+	ShareID       string `json:"share_id"`
+	ShareURL      string `json:"share_url"`
+	Title         string `json:"title"`
+	IsPublic      bool   `json:"is_public"`
+	AlreadyExists bool   `json:"already_exists"`
+}
+
+// ShareConversation creates (or refreshes) a public share link for the
+// current branch of a conversation and returns its URL.
+func (p *Provider) ShareConversation(ctx context.Context, conversationID string, opts provider.UpdateOptions) (string, error) {
+	conversationID = strings.TrimSpace(conversationID)
+	if conversationID == "" {
+		return "", fmt.Errorf("conversation ID is required")
+	}
+	if p.sessionToken == "" {
+		return "", provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chatgpt.com in your browser")
+	}
+
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	token, err := p.getAccessToken(ctx, logf)
+	if err != nil {
+		return "", fmt.Errorf("auth: %w", err)
+	}
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	detail, err := p.fetchConversation(ctx, client, token, conversationID, logf)
+	if err != nil {
+		return "", err
+	}
+	if detail.CurrentNode == "" {
+		return "", fmt.Errorf("conversation %s has no messages to share", conversationID)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"conversation_id": conversationID,
+		"current_node_id": detail.CurrentNode,
+		"is_anonymous":    true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshalling request: %w", err)
+	}
+
+	u := p.baseURL + sharePath + "/create"
+	logf("[chatgpt] POST %s", u)
+	var share shareCreateResponse
+	if err := p.doJSON(ctx, client, token, "POST", u, body, &share); err != nil {
+		return "", fmt.Errorf("creating share: %w", err)
+	}
+	if share.ShareID == "" {
+		return "", fmt.Errorf("share response did not include a share ID")
+	}
+
+	if !share.IsPublic {
+		title := share.Title
+		if title == "" {
+			title = detail.Title
+		}
+		body, err = json.Marshal(map[string]any{
+			"share_id":               share.ShareID,
+			"highlighted_message_id": nil,
+			"title":                  title,
+			"is_public":              true,
+			"is_visible":             true,
+			"is_anonymous":           true,
+		})
+		if err != nil {
+			return "", fmt.Errorf("marshalling request: %w", err)
+		}
+
+		u = fmt.Sprintf("%s%s/%s", p.baseURL, sharePath, share.ShareID)
+		logf("[chatgpt] PATCH %s", u)
+		if err := p.doJSON(ctx, client, token, "PATCH", u, body, nil); err != nil {
+			return "", fmt.Errorf("publishing share: %w", err)
+		}
+	}
+
+	if share.ShareURL == "" {
+		share.ShareURL = "https://chatgpt.com/share/" + share.ShareID
+	}
+	logf("[chatgpt] share %s (already_exists=%v)", share.ShareID, share.AlreadyExists)
+	return share.ShareURL, nil
+}
@@ -0,0 +1,275 @@
+// Package chatgpt — transcript.go fetches a stored conversation and
+// flattens its message tree into a linear transcript.
+//
+// The backend returns every message as a node in a "mapping" keyed by
+// node ID, with edits and regenerations forming sibling branches. The
+// branch the web UI shows is recovered by walking parent links from
+// current_node back to the root.
+package chatgpt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+type conversationDetail struct {
+	ConversationID string                 `json:"conversation_id"`
+	Title          string                 `json:"title"`
+	CreateTime     flexTime               `json:"create_time"`
+	UpdateTime     flexTime               `json:"update_time"`
+	CurrentNode    string                 `json:"current_node"`
+	Mapping        map[string]mappingNode `json:"mapping"`
+}
+
+type mappingNode struct {
+	ID      string          `json:"id"`
+	Parent  string          `json:"parent"`
+	Message *mappingMessage `json:"message"`
+}
+
+type mappingMessage struct {
+	Author struct {
+		Role string `json:"role"`
+	} `json:"author"`
+	CreateTime flexTime `json:"create_time"`
+	Content    struct {
+		ContentType string `json:"content_type"`
+		Parts       []any  `json:"parts"`
+	} `json:"content"`
+	Recipient string         `json:"recipient"`
+	Metadata  map[string]any `json:"metadata"`
+}
+
+// FetchTranscript fetches a conversation and returns the messages on its
+// current branch.
+func (p *Provider) FetchTranscript(ctx context.Context, conversationID string, opts provider.TranscriptOptions) (*provider.Transcript, error) {
+	conversationID = strings.TrimSpace(conversationID)
+	if conversationID == "" {
+		return nil, fmt.Errorf("conversation ID is required")
+	}
+	if p.sessionToken == "" {
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chatgpt.com in your browser")
+	}
+
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	token, err := p.getAccessToken(ctx, logf)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	detail, err := p.fetchConversation(ctx, httpclient.NewWithProxy(p.timeout, p.proxy), token, conversationID, logf)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &provider.Transcript{ID: detail.ConversationID, Title: detail.Title}
+	if t.ID == "" {
+		t.ID = conversationID
+	}
+	if detail.CreateTime.Valid {
+		t.CreatedAt = detail.CreateTime.Time
+	}
+	if detail.UpdateTime.Valid {
+		t.UpdatedAt = detail.UpdateTime.Time
+	}
+
+	for _, node := range currentBranch(detail.Mapping, detail.CurrentNode) {
+		msg, ok := transcriptMessage(node.Message)
+		if !ok {
+			continue
+		}
+		msg.ID = node.ID
+		// Tool calls split one assistant turn into several nodes; fold them
+		// back together so the transcript reads like the web UI.
+		if n := len(t.Messages); n > 0 && t.Messages[n-1].Role == msg.Role && msg.Role == "assistant" {
+			prev := &t.Messages[n-1]
+			prev.ID = msg.ID
+			prev.Text = strings.TrimSpace(prev.Text + "\n\n" + msg.Text)
+			prev.Sources = append(prev.Sources, msg.Sources...)
+			if prev.Model == "" {
+				prev.Model = msg.Model
+			}
+			continue
+		}
+		t.Messages = append(t.Messages, msg)
+	}
+
+	logf("[chatgpt] transcript has %d message(s)", len(t.Messages))
+	return t, nil
+}
+
+// fetchConversation loads the raw conversation tree.
+func (p *Provider) fetchConversation(ctx context.Context, client *http.Client, token, conversationID string, logf func(string, ...any)) (*conversationDetail, error) {
+	u := fmt.Sprintf("%s%s/%s", p.baseURL, conversationPath, conversationID)
+	logf("[chatgpt] GET %s", u)
+
+	var detail conversationDetail
+	if err := p.doJSON(ctx, client, token, http.MethodGet, u, nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// currentBranch returns the nodes from the root down to current, following
+// parent links. A cycle or missing node ends the walk.
+func currentBranch(mapping map[string]mappingNode, current string) []mappingNode {
+	var branch []mappingNode
+	seen := make(map[string]bool)
+	for id := current; id != "" && !seen[id]; {
+		node, ok := mapping[id]
+		if !ok {
+			break
+		}
+		seen[id] = true
+		branch = append(branch, node)
+		id = node.Parent
+	}
+	for i, j := 0, len(branch)-1; i < j; i, j = i+1, j-1 {
+		branch[i], branch[j] = branch[j], branch[i]
+	}
+	return branch
+}
+
+// transcriptMessage converts a visible user or assistant message. System
+// prompts, tool output, hidden messages, and reasoning traces are skipped.
+func transcriptMessage(m *mappingMessage) (provider.Message, bool) {
+	if m == nil {
+		return provider.Message{}, false
+	}
+	role := m.Author.Role
+	if role != "user" && role != "assistant" {
+		return provider.Message{}, false
+	}
+	if m.Recipient != "" && m.Recipient != "all" {
+		return provider.Message{}, false
+	}
+	if hidden, _ := m.Metadata["is_visually_hidden_from_conversation"].(bool); hidden {
+		return provider.Message{}, false
+	}
+	switch m.Content.ContentType {
+	case "text", "multimodal_text":
+	default:
+		return provider.Message{}, false
+	}
+
+	var parts []string
+	for _, part := range m.Content.Parts {
+		switch v := part.(type) {
+		case string:
+			if v != "" {
+				parts = append(parts, v)
+			}
+		case map[string]any:
+			if ct, _ := v["content_type"].(string); ct == "image_asset_pointer" {
+				parts = append(parts, "[image]")
+			}
+		}
+	}
+	text := strings.TrimSpace(strings.Join(parts, "\n"))
+	if text == "" {
+		return provider.Message{}, false
+	}
+
+	msg := provider.Message{Role: role, Text: text}
+	if m.CreateTime.Valid {
+		msg.CreatedAt = m.CreateTime.Time
+	}
+	if role == "assistant" {
+		msg.Model, _ = m.Metadata["model_slug"].(string)
+		msg.Sources = messageSources(m.Metadata)
+	}
+	return msg, true
+}
+
+// messageSources collects web citations from message metadata. Both the
+// older "citations" list and the newer "content_references" groups are
+// read; duplicate URLs are dropped.
+func messageSources(metadata map[string]any) []provider.Source {
+	var sources []provider.Source
+	seen := make(map[string]bool)
+	add := func(v any) {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return
+		}
+		url, _ := m["url"].(string)
+		if url == "" || seen[url] {
+			return
+		}
+		seen[url] = true
+		title, _ := m["title"].(string)
+		sources = append(sources, provider.Source{Name: title, URL: url})
+	}
+
+	if citations, ok := metadata["citations"].([]any); ok {
+		for _, c := range citations {
+			if cm, ok := c.(map[string]any); ok {
+				add(cm["metadata"])
+			}
+		}
+	}
+	if refs, ok := metadata["content_references"].([]any); ok {
+		for _, r := range refs {
+			rm, ok := r.(map[string]any)
+			if !ok {
+				continue
+			}
+			if items, ok := rm["items"].([]any); ok {
+				for _, item := range items {
+					add(item)
+				}
+			}
+			if srcs, ok := rm["sources"].([]any); ok {
+				for _, s := range srcs {
+					add(s)
+				}
+			}
+		}
+	}
+	return sources
+}
+
+// prepareVariant turns req into a regeneration ("variant") of the question
+// that produced the answer at req.ParentMessageID, or at the tip of the
+// conversation when that is empty. The original user message is resent
+// under its own ID so the new answer becomes a sibling of the old one.
+func (p *Provider) prepareVariant(ctx context.Context, client *http.Client, token string, req *conversationRequest, logf func(string, ...any)) error {
+	detail, err := p.fetchConversation(ctx, client, token, req.ConversationID, logf)
+	if err != nil {
+		return err
+	}
+
+	id := req.ParentMessageID
+	if id == "" {
+		id = detail.CurrentNode
+	}
+	seen := make(map[string]bool)
+	for id != "" && !seen[id] {
+		seen[id] = true
+		node, ok := detail.Mapping[id]
+		if !ok {
+			break
+		}
+		if m := node.Message; m != nil && m.Author.Role == "user" {
+			msg := req.Messages[0]
+			msg.ID = node.ID
+			msg.Content = requestContent{ContentType: m.Content.ContentType, Parts: m.Content.Parts}
+			req.Action = "variant"
+			req.Messages = []message{msg}
+			req.ParentMessageID = node.Parent
+			logf("[chatgpt] regenerating answer to message %s", node.ID)
+			return nil
+		}
+		id = node.Parent
+	}
+	return fmt.Errorf("no question found in conversation %s", req.ConversationID)
+}
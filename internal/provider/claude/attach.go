@@ -0,0 +1,148 @@
+// Package claude implements the Claude.ai web API provider.
+//
+// attach.go prepares local files for a completion request. Images and PDFs
+// are uploaded to the org's upload endpoint and referenced by UUID in
+// "files"; text files are sent inline as extracted content in
+// "attachments", as the web client does.
+package claude
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+const (
+	uploadPath = "/api/%s/upload"
+
+	// maxAttachmentSize mirrors the Claude.ai per-file upload limit.
+	maxAttachmentSize = 30 << 20
+)
+
+// textAttachment is an inline file whose contents are sent with the prompt.
+type textAttachment struct {
+	FileName         string `json:"file_name"`
+	FileType         string `json:"file_type"`
+	FileSize         int    `json:"file_size"`
+	ExtractedContent string `json:"extracted_content"`
+}
+
+type uploadResponse struct {
+	FileUUID string `json:"file_uuid"`
+	FileName string `json:"file_name"`
+}
+
+// prepareAttachments uploads or inlines each path and returns the
+// completion request's attachments and files fields.
+func (p *Provider) prepareAttachments(ctx context.Context, orgID string, paths []string, logf func(string, ...any)) ([]interface{}, []interface{}, error) {
+	attachments := []interface{}{}
+	files := []interface{}{}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("attaching %s: %w", path, err)
+		}
+		if len(data) > maxAttachmentSize {
+			return nil, nil, fmt.Errorf("attaching %s: file is %d bytes, limit is %d", path, len(data), maxAttachmentSize)
+		}
+
+		name := filepath.Base(path)
+		mimeType := detectMimeType(path, data)
+
+		switch {
+		case strings.HasPrefix(mimeType, "image/") || mimeType == "application/pdf":
+			fileUUID, err := p.uploadFile(ctx, orgID, name, mimeType, data, logf)
+			if err != nil {
+				return nil, nil, fmt.Errorf("attaching %s: %w", path, err)
+			}
+			files = append(files, fileUUID)
+		case utf8.Valid(data):
+			logf("[claude] inlining %s (%d bytes)", name, len(data))
+			attachments = append(attachments, textAttachment{
+				FileName:         name,
+				FileType:         mimeType,
+				FileSize:         len(data),
+				ExtractedContent: string(data),
+			})
+		default:
+			return nil, nil, fmt.Errorf("attaching %s: unsupported binary file type %s", path, mimeType)
+		}
+	}
+
+	return attachments, files, nil
+}
+
+// uploadFile posts a file as multipart form data and returns its UUID.
+func (p *Provider) uploadFile(ctx context.Context, orgID, name, mimeType string, data []byte, logf func(string, ...any)) (string, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, name))
+	h.Set("Content-Type", mimeType)
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf(p.baseURL+uploadPath, orgID)
+	logf("[claude] POST %s (%s, %d bytes)", url, name, len(data))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return "", err
+	}
+	p.setHeaders(req, p.baseURL+"/new")
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", provider.HTTPError(resp.StatusCode, b)
+	}
+
+	var up uploadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&up); err != nil {
+		return "", fmt.Errorf("decoding upload: %w", err)
+	}
+	if up.FileUUID == "" {
+		return "", fmt.Errorf("empty file UUID in upload response")
+	}
+	return up.FileUUID, nil
+}
+
+// detectMimeType guesses a file's MIME type from its extension, falling back
+// to content sniffing.
+func detectMimeType(path string, data []byte) string {
+	if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(path))); t != "" {
+		t, _, _ = strings.Cut(t, ";")
+		return t
+	}
+	t, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	return t
+}
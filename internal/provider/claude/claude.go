@@ -22,6 +22,7 @@ const (
 	orgPath          = "/api/organizations"
 	conversationPath = "/api/organizations/%s/chat_conversations"
 	completionPath   = "/api/organizations/%s/chat_conversations/%s/completion"
+	retryPath        = "/api/organizations/%s/chat_conversations/%s/retry_completion"
 
 	cookieSessionKey = "sessionKey"
 	domainClaude     = "claude.ai"
@@ -50,7 +51,9 @@ type conversationResponse struct {
 }
 
 type conversationMessage struct {
-	UUID string `json:"uuid"`
+	UUID              string `json:"uuid"`
+	Sender            string `json:"sender"`
+	ParentMessageUUID string `json:"parent_message_uuid"`
 }
 
 type conversationDetailResponse struct {
@@ -69,6 +72,47 @@ type completionRequest struct {
 	Files             []interface{} `json:"files"`
 	RenderingMode     string        `json:"rendering_mode"`
 	Locale            string        `json:"locale,omitempty"`
+	// PersonalizedStyles carries a custom style, the web UI's per-message
+	// instructions mechanism.
+	PersonalizedStyles []personalizedStyle `json:"personalized_styles,omitempty"`
+	// Thinking caps extended thinking for this turn; the conversation is
+	// created in extended mode.
+	Thinking *thinkingConfig `json:"thinking,omitempty"`
+	// Tools overrides the account's tool settings when set; an empty list
+	// turns web search off.
+	Tools *[]completionTool `json:"tools,omitempty"`
+}
+
+type completionTool struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// webSearchTool is the claude.ai web search tool.
+var webSearchTool = completionTool{Type: "web_search_v0", Name: "web_search"}
+
+// citedSource is a search result or citation in a stream event.
+type citedSource struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type thinkingConfig struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+// MinThinkingBudget is the smallest thinking budget Claude accepts.
+const MinThinkingBudget = 1024
+
+type personalizedStyle struct {
+	Type      string `json:"type"`
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	Prompt    string `json:"prompt"`
+	Summary   string `json:"summary"`
+	IsDefault bool   `json:"isDefault"`
 }
 
 type sseEvent struct {
@@ -83,12 +127,16 @@ type sseEvent struct {
 		Name     string `json:"name"`
 		Language string `json:"language"`
 		Text     string `json:"text"`
+		// Content holds a tool result's output; its shape depends on the tool.
+		Content json.RawMessage `json:"content"`
 	} `json:"content_block"`
 	Delta struct {
-		Type        string `json:"type"`
-		Text        string `json:"text"`
-		Thinking    string `json:"thinking"`
-		PartialJSON string `json:"partial_json"`
+		Type        string      `json:"type"`
+		Text        string      `json:"text"`
+		Thinking    string      `json:"thinking"`
+		PartialJSON string      `json:"partial_json"`
+		Citation    citedSource `json:"citation"`
+		StopReason  string      `json:"stop_reason"`
 	} `json:"delta"`
 	Error struct {
 		Message string `json:"message"`
@@ -109,8 +157,11 @@ type Provider struct {
 	model          string
 	userAgent      string
 	timeout        time.Duration
+	proxy          string
 	sessionKey     string
 	thinkingEffort string
+	thinkingBudget int
+	webSearch      *bool
 	// Cached org ID.
 	orgID string
 }
@@ -139,6 +190,10 @@ func (p *Provider) CookieSpecs() []provider.CookieSpec {
 	}
 }
 
+// SetProxy routes requests through proxyURL instead of the environment's
+// HTTPS_PROXY/ALL_PROXY.
+func (p *Provider) SetProxy(proxyURL string) { p.proxy = proxyURL }
+
 func (p *Provider) SetCookies(cookies map[string]string) {
 	if v := cookies[cookieSessionKey]; v != "" {
 		p.sessionKey = v
@@ -148,9 +203,40 @@ func (p *Provider) SetCookies(cookies map[string]string) {
 // SetThinkingEffort sets the thinking effort level.
 func (p *Provider) SetThinkingEffort(effort string) { p.thinkingEffort = effort }
 
+// SetThinkingBudget caps extended thinking at tokens per answer. Zero
+// leaves the budget to claude.ai, which picks it from the account's
+// extended-thinking setting.
+func (p *Provider) SetThinkingBudget(tokens int) { p.thinkingBudget = tokens }
+
+// SetWebSearch turns the web search tool on or off for this provider's
+// asks, overriding the account setting.
+func (p *Provider) SetWebSearch(on bool) { p.webSearch = &on }
+
+// tools returns the tool override for a turn, or nil to keep the account
+// setting.
+func (p *Provider) tools() *[]completionTool {
+	if p.webSearch == nil {
+		return nil
+	}
+	tools := []completionTool{}
+	if *p.webSearch {
+		tools = append(tools, webSearchTool)
+	}
+	return &tools
+}
+
+// thinking returns the thinking settings for a turn, or nil to leave them
+// to the server.
+func (p *Provider) thinking() *thinkingConfig {
+	if p.thinkingBudget <= 0 {
+		return nil
+	}
+	return &thinkingConfig{Type: "enabled", BudgetTokens: max(p.thinkingBudget, MinThinkingBudget)}
+}
+
 func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
 	if p.sessionKey == "" {
-		return fmt.Errorf("no session cookie — log in to claude.ai in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to claude.ai in your browser")
 	}
 
 	logf := opts.LogFunc
@@ -169,6 +255,13 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 		return fmt.Errorf("getting org ID: %w", err)
 	}
 
+	// Prepare attachments before creating a conversation so a bad file
+	// does not leave an empty chat behind.
+	attachments, files, err := p.prepareAttachments(ctx, orgID, opts.Attachments, logf)
+	if err != nil {
+		return err
+	}
+
 	// 2. Create a new conversation, unless continuing an existing one.
 	convID := opts.ConversationID
 	if convID == "" {
@@ -180,7 +273,14 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 	logf("[claude] conversation=%s", convID)
 
 	sendOpts := opts
-	if convID != "" && sendOpts.ParentMessageID == "" {
+	if opts.Retry && opts.ConversationID != "" {
+		// A retry hangs the new answer off the question, not the old answer.
+		humanID, err := p.getRetryParentID(ctx, orgID, convID, logf)
+		if err != nil {
+			return fmt.Errorf("retry: %w", err)
+		}
+		sendOpts.ParentMessageID = humanID
+	} else if convID != "" && sendOpts.ParentMessageID == "" {
 		if parentID, parentErr := p.getLatestParentMessageID(ctx, orgID, convID, logf); parentErr == nil {
 			sendOpts.ParentMessageID = parentID
 		} else {
@@ -188,7 +288,7 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 		}
 	}
 
-	err = p.sendMessage(ctx, orgID, convID, query, model, sendOpts)
+	err = p.sendMessage(ctx, orgID, convID, query, model, attachments, files, sendOpts)
 
 	// 4. Delete conversation if temporary mode.
 	if opts.Temporary && opts.ConversationID == "" {
@@ -205,7 +305,7 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 		return fmt.Errorf("conversation ID is required")
 	}
 	if p.sessionKey == "" {
-		return fmt.Errorf("no session cookie — log in to claude.ai in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to claude.ai in your browser")
 	}
 
 	logf := opts.LogFunc
@@ -224,7 +324,7 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 // ListConversations fetches the user's recent Claude conversations.
 func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
 	if p.sessionKey == "" {
-		return nil, fmt.Errorf("no session cookie — log in to claude.ai in your browser")
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to claude.ai in your browser")
 	}
 
 	logf := opts.LogFunc
@@ -237,7 +337,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 		return nil, fmt.Errorf("getting org ID: %w", err)
 	}
 
-	url := fmt.Sprintf(p.baseURL+conversationPath+"?limit=%d&starred=false&consistency=eventual", orgID, opts.Limit)
+	url := fmt.Sprintf(p.baseURL+conversationPath+"?limit=%d&starred=false&consistency=eventual", orgID, opts.Want(20))
 	logf("[claude] GET %s", url)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -246,7 +346,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 	}
 	p.setHeaders(req, p.baseURL+"/recents")
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -255,7 +355,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, provider.HTTPError(resp.StatusCode, body)
 	}
 
 	var items []conversationListItem
@@ -263,14 +363,6 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 		return nil, fmt.Errorf("decoding conversations: %w", err)
 	}
 
-	limit := opts.Limit
-	if limit <= 0 {
-		limit = 20
-	}
-	if len(items) > limit {
-		items = items[:limit]
-	}
-
 	var conversations []provider.Conversation
 	for _, item := range items {
 		conv := provider.Conversation{
@@ -286,7 +378,99 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 		conversations = append(conversations, conv)
 	}
 
-	return conversations, nil
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	return provider.Page(conversations, opts), nil
+}
+
+type transcriptResponse struct {
+	UUID         string `json:"uuid"`
+	Name         string `json:"name"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+	ChatMessages []struct {
+		UUID      string `json:"uuid"`
+		Sender    string `json:"sender"`
+		Text      string `json:"text"`
+		CreatedAt string `json:"created_at"`
+		Content   []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"chat_messages"`
+}
+
+// FetchTranscript fetches the messages on the current branch of a conversation.
+func (p *Provider) FetchTranscript(ctx context.Context, conversationID string, opts provider.TranscriptOptions) (*provider.Transcript, error) {
+	if strings.TrimSpace(conversationID) == "" {
+		return nil, fmt.Errorf("conversation ID is required")
+	}
+	if p.sessionKey == "" {
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to claude.ai in your browser")
+	}
+
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	orgID, err := p.getOrgID(ctx, logf)
+	if err != nil {
+		return nil, fmt.Errorf("getting org ID: %w", err)
+	}
+
+	url := fmt.Sprintf(p.baseURL+conversationPath+"/%s?rendering_mode=messages", orgID, conversationID)
+	logf("[claude] GET %s", url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(req, fmt.Sprintf("%s/chat/%s", p.baseURL, conversationID))
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, provider.HTTPError(resp.StatusCode, body)
+	}
+
+	var detail transcriptResponse
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		return nil, fmt.Errorf("decoding conversation: %w", err)
+	}
+
+	t := &provider.Transcript{ID: detail.UUID, Title: detail.Name}
+	t.CreatedAt, _ = time.Parse(time.RFC3339Nano, detail.CreatedAt)
+	t.UpdatedAt, _ = time.Parse(time.RFC3339Nano, detail.UpdatedAt)
+
+	for _, m := range detail.ChatMessages {
+		role := "assistant"
+		if m.Sender == "human" {
+			role = "user"
+		}
+		text := m.Text
+		if text == "" {
+			var parts []string
+			for _, c := range m.Content {
+				if c.Type == "text" && c.Text != "" {
+					parts = append(parts, c.Text)
+				}
+			}
+			text = strings.Join(parts, "\n\n")
+		}
+		msg := provider.Message{ID: m.UUID, Role: role, Text: text}
+		msg.CreatedAt, _ = time.Parse(time.RFC3339Nano, m.CreatedAt)
+		t.Messages = append(t.Messages, msg)
+	}
+
+	return t, nil
 }
 
 // --- Internal API methods ---
@@ -305,7 +489,7 @@ func (p *Provider) getOrgID(ctx context.Context, logf func(string, ...any)) (str
 	}
 	p.setHeaders(req, p.baseURL+"/new")
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("request failed: %w", err)
@@ -314,7 +498,7 @@ func (p *Provider) getOrgID(ctx context.Context, logf func(string, ...any)) (str
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return "", provider.HTTPError(resp.StatusCode, body)
 	}
 
 	var orgs []orgResponse
@@ -363,7 +547,7 @@ func (p *Provider) createConversation(ctx context.Context, orgID, model, query s
 	}
 	p.setHeaders(req, p.baseURL+"/new")
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("request failed: %w", err)
@@ -372,7 +556,7 @@ func (p *Provider) createConversation(ctx context.Context, orgID, model, query s
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return "", provider.HTTPError(resp.StatusCode, body)
 	}
 
 	var conv conversationResponse
@@ -400,31 +584,10 @@ func conversationTitle(query string) string {
 }
 
 func (p *Provider) getLatestParentMessageID(ctx context.Context, orgID, convID string, logf func(string, ...any)) (string, error) {
-	url := fmt.Sprintf(p.baseURL+conversationPath+"/%s", orgID, convID)
-	logf("[claude] GET %s", url)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	detail, err := p.getConversationDetail(ctx, orgID, convID, logf)
 	if err != nil {
 		return "", err
 	}
-	p.setHeaders(req, fmt.Sprintf("%s/chat/%s", p.baseURL, convID))
-
-	client := httpclient.New(p.timeout)
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	var detail conversationDetailResponse
-	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
-		return "", fmt.Errorf("decoding conversation detail: %w", err)
-	}
 
 	if detail.CurrentLeafMessageUUID != "" {
 		return detail.CurrentLeafMessageUUID, nil
@@ -446,13 +609,82 @@ func (p *Provider) getLatestParentMessageID(ctx context.Context, orgID, convID s
 	return "", nil
 }
 
-func (p *Provider) sendMessage(ctx context.Context, orgID, convID, query, model string, opts provider.AskOptions) error {
+// getRetryParentID returns the UUID of the question behind the
+// conversation's current answer, which a retry regenerates.
+func (p *Provider) getRetryParentID(ctx context.Context, orgID, convID string, logf func(string, ...any)) (string, error) {
+	detail, err := p.getConversationDetail(ctx, orgID, convID, logf)
+	if err != nil {
+		return "", err
+	}
+
+	messages := detail.ChatMessages
+	if len(messages) == 0 {
+		messages = detail.Messages
+	}
+	byID := make(map[string]conversationMessage, len(messages))
+	for _, m := range messages {
+		byID[m.UUID] = m
+	}
+
+	id := detail.CurrentLeafMessageUUID
+	if id == "" && len(messages) > 0 {
+		id = messages[len(messages)-1].UUID
+	}
+	for seen := map[string]bool{}; id != "" && !seen[id]; {
+		seen[id] = true
+		m, ok := byID[id]
+		if !ok {
+			break
+		}
+		if m.Sender == "human" {
+			return m.UUID, nil
+		}
+		id = m.ParentMessageUUID
+	}
+	return "", fmt.Errorf("no question found in conversation %s", convID)
+}
+
+func (p *Provider) getConversationDetail(ctx context.Context, orgID, convID string, logf func(string, ...any)) (*conversationDetailResponse, error) {
+	url := fmt.Sprintf(p.baseURL+conversationPath+"/%s", orgID, convID)
+	logf("[claude] GET %s", url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(req, fmt.Sprintf("%s/chat/%s", p.baseURL, convID))
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, provider.HTTPError(resp.StatusCode, body)
+	}
+
+	var detail conversationDetailResponse
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		return nil, fmt.Errorf("decoding conversation detail: %w", err)
+	}
+	return &detail, nil
+}
+
+func (p *Provider) sendMessage(ctx context.Context, orgID, convID, query, model string, attachments, files []interface{}, opts provider.AskOptions) error {
 	logf := opts.LogFunc
 	if logf == nil {
 		logf = func(string, ...any) {}
 	}
 
-	url := fmt.Sprintf(p.baseURL+completionPath, orgID, convID)
+	path := completionPath
+	if opts.Retry {
+		path = retryPath
+		query = ""
+	}
+	url := fmt.Sprintf(p.baseURL+path, orgID, convID)
 	logf("[claude] POST %s", url)
 
 	reqBody := completionRequest{
@@ -460,16 +692,27 @@ func (p *Provider) sendMessage(ctx context.Context, orgID, convID, query, model
 		Model:             model,
 		ParentMessageUUID: "00000000-0000-4000-8000-000000000000",
 		Timezone:          "America/Los_Angeles",
-		Attachments:       []interface{}{},
-		Files:             []interface{}{},
+		Attachments:       attachments,
+		Files:             files,
 		RenderingMode:     "messages",
 	}
 	if opts.ParentMessageID != "" {
 		reqBody.ParentMessageUUID = opts.ParentMessageID
 	}
+	if sp := strings.TrimSpace(opts.SystemPrompt); sp != "" {
+		reqBody.PersonalizedStyles = []personalizedStyle{{
+			Type:    "custom",
+			Key:     "ask-system-prompt",
+			Name:    "ask",
+			Prompt:  sp,
+			Summary: "Instructions from ask",
+		}}
+	}
 
-	// Note: Claude.ai web API does NOT support the thinking field.
-	// Extended thinking is only available via the official Messages API.
+	reqBody.Tools = p.tools()
+	if reqBody.Thinking = p.thinking(); reqBody.Thinking != nil {
+		logf("[claude] thinking budget=%d tokens", reqBody.Thinking.BudgetTokens)
+	}
 
 	payload, err := json.Marshal(reqBody)
 	if err != nil {
@@ -486,7 +729,7 @@ func (p *Provider) sendMessage(ctx context.Context, orgID, convID, query, model
 	req.Header.Set("Accept", "text/event-stream, text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
@@ -495,10 +738,16 @@ func (p *Provider) sendMessage(ctx context.Context, orgID, convID, query, model
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return provider.HTTPError(resp.StatusCode, body)
 	}
 
-	return p.readStream(resp.Body, convID, opts)
+	outputs, err := p.readStream(resp.Body, convID, opts)
+	if err != nil {
+		return err
+	}
+	// Files go before a temporary conversation is deleted with them.
+	p.deliverFiles(ctx, orgID, convID, outputs, opts, logf)
+	return nil
 }
 
 func (p *Provider) deleteConversation(ctx context.Context, orgID, convID string, logf func(string, ...any)) error {
@@ -512,7 +761,7 @@ func (p *Provider) deleteConversation(ctx context.Context, orgID, convID string,
 	}
 	p.setHeaders(req, fmt.Sprintf("%s/chat/%s", p.baseURL, convID))
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
@@ -521,7 +770,7 @@ func (p *Provider) deleteConversation(ctx context.Context, orgID, convID string,
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return provider.HTTPError(resp.StatusCode, body)
 	}
 
 	logf("[claude] conversation deleted")
@@ -539,11 +788,32 @@ func (p *Provider) setHeaders(req *http.Request, referer string) {
 	req.AddCookie(&http.Cookie{Name: cookieSessionKey, Value: p.sessionKey})
 }
 
-func (p *Provider) readStream(r io.Reader, convID string, opts provider.AskOptions) error {
+// readStream streams the answer through opts and returns the paths of
+// the files it created.
+func (p *Provider) readStream(r io.Reader, convID string, opts provider.AskOptions) ([]string, error) {
 	scanner := bufio.NewScanner(r)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	lastMsgID := ""
 	artifactAnnounced := map[int]bool{}
+	var outputs outputFiles
+	// text keeps a short tail of the answer so a file link split across
+	// chunks is still found.
+	var text string
+	// Cited URLs are reported as the answer cites them; the search results
+	// stand in when the answer cites nothing.
+	var searchResults []citedSource
+	seenSources := map[string]bool{}
+	emitSource := func(src citedSource) {
+		if opts.OnSource == nil || src.URL == "" || seenSources[src.URL] {
+			return
+		}
+		seenSources[src.URL] = true
+		name := src.Title
+		if name == "" {
+			name = src.URL
+		}
+		opts.OnSource(name, src.URL)
+	}
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -566,11 +836,13 @@ func (p *Provider) readStream(r io.Reader, convID string, opts provider.AskOptio
 			if opts.OnError != nil {
 				opts.OnError(fmt.Errorf("claude: %s", event.Error.Message))
 			}
-			return fmt.Errorf("claude error: %s", event.Error.Message)
+			return nil, fmt.Errorf("claude error: %s", event.Error.Message)
 		}
 
 		if event.Delta.Type == "thinking_delta" && event.Delta.Thinking != "" {
-			if opts.LogFunc != nil {
+			if opts.OnThinking != nil {
+				opts.OnThinking(event.Delta.Thinking)
+			} else if opts.LogFunc != nil {
 				opts.LogFunc("%s", event.Delta.Thinking)
 			}
 		}
@@ -578,6 +850,26 @@ func (p *Provider) readStream(r io.Reader, convID string, opts provider.AskOptio
 			if opts.OnText != nil {
 				opts.OnText(event.Delta.Text)
 			}
+			text += event.Delta.Text
+			if i := strings.LastIndexAny(text, " \n)"); i >= 0 {
+				outputs.addText(text[:i+1])
+				text = text[i+1:]
+			}
+		}
+		if event.Type == "content_block_start" && event.ContentBlock.Type == "tool_use" {
+			outputs.startTool(event.Index, event.ContentBlock.Name)
+		}
+		if event.Delta.Type == "input_json_delta" {
+			outputs.toolDelta(event.Index, event.Delta.PartialJSON)
+		}
+		if event.Delta.Type == "citation_start_delta" {
+			emitSource(event.Delta.Citation)
+		}
+		if event.ContentBlock.Type == "tool_result" && len(event.ContentBlock.Content) > 0 {
+			var results []citedSource
+			if json.Unmarshal(event.ContentBlock.Content, &results) == nil {
+				searchResults = append(searchResults, results...)
+			}
 		}
 		if event.ContentBlock.Type == "artifact" {
 			if !artifactAnnounced[event.Index] {
@@ -600,6 +892,9 @@ func (p *Provider) readStream(r io.Reader, convID string, opts provider.AskOptio
 				opts.OnText(event.Delta.PartialJSON)
 			}
 		}
+		if event.Type == "message_delta" && event.Delta.StopReason == "max_tokens" && opts.OnTruncated != nil {
+			opts.OnTruncated()
+		}
 		if event.Type == "message_start" && event.Message.ID != "" {
 			lastMsgID = event.Message.ID
 		}
@@ -613,14 +908,22 @@ func (p *Provider) readStream(r io.Reader, convID string, opts provider.AskOptio
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("reading stream: %w", err)
+		return nil, fmt.Errorf("reading stream: %w", err)
+	}
+	outputs.addText(text)
+	outputs.finishTools()
+
+	if len(seenSources) == 0 {
+		for _, r := range searchResults {
+			emitSource(r)
+		}
 	}
 
 	if opts.OnConversation != nil && convID != "" {
 		opts.OnConversation(convID, lastMsgID, "")
 	}
 
-	return nil
+	return outputs.paths, nil
 }
 
 func newUUID() string {
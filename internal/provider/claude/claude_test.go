@@ -0,0 +1,88 @@
+package claude
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// replay serves the provider's requests from a cassette in testdata.
+func replay(t *testing.T, name string) {
+	t.Helper()
+	if err := httpclient.ReplayCassette(filepath.Join("testdata", name)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = httpclient.StopCassette() })
+}
+
+func TestAskReplay(t *testing.T) {
+	replay(t, "ask.json")
+
+	p := New("", "", "test-agent", 0)
+	p.SetCookies(map[string]string{cookieSessionKey: "test-session"})
+
+	var text, thinking strings.Builder
+	var sources []provider.Source
+	var convID, msgID string
+	done := false
+	err := p.Ask(context.Background(), "How tall is Mount Everest?", provider.AskOptions{
+		OnText:     func(s string) { text.WriteString(s) },
+		OnThinking: func(s string) { thinking.WriteString(s) },
+		OnSource:   func(name, url string) { sources = append(sources, provider.Source{Name: name, URL: url}) },
+		OnConversation: func(id, parent, _ string) {
+			convID, msgID = id, parent
+		},
+		OnDone: func() { done = true },
+	})
+	if err != nil {
+		t.Fatalf("Ask: %v", err)
+	}
+
+	if want := "Mount Everest is 8,849 m tall."; text.String() != want {
+		t.Errorf("text = %q, want %q", text.String(), want)
+	}
+	if want := "The user wants the height. I should search."; thinking.String() != want {
+		t.Errorf("thinking = %q, want %q", thinking.String(), want)
+	}
+	// The answer cites one result, so the other search results are left out.
+	wantSources := []provider.Source{{Name: "Mount Everest - Wikipedia", URL: "https://en.wikipedia.org/wiki/Mount_Everest"}}
+	if len(sources) != len(wantSources) || sources[0] != wantSources[0] {
+		t.Errorf("sources = %v, want %v", sources, wantSources)
+	}
+	if convID != "22222222-0000-4000-8000-000000000002" {
+		t.Errorf("conversation = %q", convID)
+	}
+	if msgID != "chatcompl_REDACTED" {
+		t.Errorf("message = %q", msgID)
+	}
+	if !done {
+		t.Error("OnDone not called")
+	}
+	if p.orgID != "11111111-0000-4000-8000-000000000001" {
+		t.Errorf("org = %q, want the personal organization", p.orgID)
+	}
+}
+
+func TestReadStreamSearchResults(t *testing.T) {
+	// With no citations in the answer, the search results stand in.
+	stream := `data: {"type":"content_block_start","index":0,"content_block":{"type":"tool_result","content":[{"title":"A","url":"https://a.example/"},{"title":"","url":"https://b.example/"}]}}
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"answer"}}
+data: {"type":"message_stop"}
+`
+	var sources []provider.Source
+	p := New("", "", "test-agent", 0)
+	_, err := p.readStream(strings.NewReader(stream), "", provider.AskOptions{
+		OnSource: func(name, url string) { sources = append(sources, provider.Source{Name: name, URL: url}) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []provider.Source{{Name: "A", URL: "https://a.example/"}, {Name: "https://b.example/", URL: "https://b.example/"}}
+	if len(sources) != len(want) || sources[0] != want[0] || sources[1] != want[1] {
+		t.Errorf("sources = %v, want %v", sources, want)
+	}
+}
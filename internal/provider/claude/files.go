@@ -0,0 +1,134 @@
+// Package claude — files.go saves files Claude created for an answer.
+//
+// With file creation on, Claude writes documents under
+// /mnt/user-data/outputs through its create_file tool and links them from
+// the answer as computer:///mnt/user-data/outputs/… . They are fetched
+// from the conversation's file endpoint:
+//
+//	GET /api/organizations/{org}/conversations/{conv}/wiggle/download-file?path=…
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+const (
+	downloadFilePath = "/api/organizations/%s/conversations/%s/wiggle/download-file"
+	outputsDir       = "/mnt/user-data/outputs/"
+)
+
+// outputLinkRE matches a link to a created file in answer text.
+var outputLinkRE = regexp.MustCompile(`computer://(` + regexp.QuoteMeta(outputsDir) + `[^)\s"'\]]+)`)
+
+// outputFiles collects the created files of one answer: paths written by
+// file tools and paths linked from the text, in order.
+type outputFiles struct {
+	paths []string
+	seen  map[string]bool
+	// toolInput holds the streamed JSON input of file tool calls by
+	// content block index.
+	toolInput map[int]*strings.Builder
+}
+
+func (o *outputFiles) add(p string) {
+	p = strings.TrimRight(p, ".,;:")
+	if !strings.HasPrefix(p, outputsDir) || o.seen[p] {
+		return
+	}
+	if o.seen == nil {
+		o.seen = map[string]bool{}
+	}
+	o.seen[p] = true
+	o.paths = append(o.paths, p)
+}
+
+// addText records the files linked from a piece of answer text.
+func (o *outputFiles) addText(text string) {
+	for _, m := range outputLinkRE.FindAllStringSubmatch(text, -1) {
+		o.add(m[1])
+	}
+}
+
+// startTool begins collecting the input of a file tool call.
+func (o *outputFiles) startTool(index int, name string) {
+	if name != "create_file" && name != "present_files" {
+		return
+	}
+	if o.toolInput == nil {
+		o.toolInput = map[int]*strings.Builder{}
+	}
+	o.toolInput[index] = &strings.Builder{}
+}
+
+func (o *outputFiles) toolDelta(index int, partial string) {
+	if b := o.toolInput[index]; b != nil {
+		b.WriteString(partial)
+	}
+}
+
+// finishTools records the paths named by the collected tool inputs.
+func (o *outputFiles) finishTools() {
+	for _, b := range o.toolInput {
+		var input struct {
+			Path      string   `json:"path"`
+			FilePaths []string `json:"filepaths"`
+		}
+		if json.Unmarshal([]byte(b.String()), &input) != nil {
+			continue
+		}
+		o.add(input.Path)
+		for _, p := range input.FilePaths {
+			o.add(p)
+		}
+	}
+	o.toolInput = nil
+}
+
+// deliverFiles downloads the answer's files to opts.DownloadDir, if set,
+// and reports each through opts.OnFile. A failed download is logged and
+// the file is still reported.
+func (p *Provider) deliverFiles(ctx context.Context, orgID, convID string, paths []string, opts provider.AskOptions, logf func(string, ...any)) {
+	if opts.DownloadDir == "" && opts.OnFile == nil {
+		return
+	}
+	for _, filePath := range paths {
+		u := fmt.Sprintf(p.baseURL+downloadFilePath, orgID, convID) + "?" + url.Values{"path": {filePath}}.Encode()
+		f := provider.GeneratedFile{Name: path.Base(filePath), URL: u}
+		if opts.DownloadDir != "" {
+			saved, err := p.downloadFile(ctx, convID, u, opts.DownloadDir, f.Name, logf)
+			if err != nil {
+				logf("[claude] warning: could not save %s: %v", f.Name, err)
+			}
+			f.Path = saved
+		}
+		if opts.OnFile != nil {
+			opts.OnFile(f)
+		}
+	}
+}
+
+func (p *Provider) downloadFile(ctx context.Context, convID, u, dir, name string, logf func(string, ...any)) (string, error) {
+	logf("[claude] GET %s", u)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	p.setHeaders(req, fmt.Sprintf("%s/chat/%s", p.baseURL, convID))
+	req.Header.Set("Accept", "*/*")
+
+	resp, err := httpclient.NewWithProxy(p.timeout, p.proxy).Do(req)
+	if err != nil {
+		return "", err
+	}
+	return provider.SaveFile(resp, dir, name)
+}
@@ -0,0 +1,617 @@
+// Package deepseek implements the chat.deepseek.com web API provider.
+package deepseek
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+	"github.com/kyupark/ask/internal/sse"
+)
+
+const (
+	defaultBaseURL      = "https://chat.deepseek.com"
+	currentUserPath     = "/api/v0/users/current"
+	createSessionPath   = "/api/v0/chat_session/create"
+	deleteSessionPath   = "/api/v0/chat_session/delete"
+	listSessionsPath    = "/api/v0/chat_session/fetch_page"
+	historyPath         = "/api/v0/chat/history_messages"
+	createChallengePath = "/api/v0/chat/create_pow_challenge"
+	completionPath      = "/api/v0/chat/completion"
+
+	cookieSessionID = "ds_session_id"
+	domainDeepSeek  = "deepseek.com"
+
+	modelChat     = "deepseek-chat"
+	modelReasoner = "deepseek-reasoner"
+)
+
+// modelAliases maps short names to model IDs.
+var modelAliases = map[string]string{
+	"chat":     modelChat,
+	"v3":       modelChat,
+	"reasoner": modelReasoner,
+	"r1":       modelReasoner,
+	"think":    modelReasoner,
+}
+
+// ResolveModel maps an alias to its model ID; other values pass through.
+func ResolveModel(model string) string {
+	model = strings.TrimSpace(model)
+	if id, ok := modelAliases[strings.ToLower(model)]; ok {
+		return id
+	}
+	if model == "" {
+		return modelChat
+	}
+	return model
+}
+
+// --- Request/Response types ---
+
+// envelope wraps every API response: code and biz_code are zero on
+// success, otherwise msg or biz_msg says why.
+type envelope struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		BizCode int             `json:"biz_code"`
+		BizMsg  string          `json:"biz_msg"`
+		BizData json.RawMessage `json:"biz_data"`
+	} `json:"data"`
+}
+
+type completionRequest struct {
+	ChatSessionID   string   `json:"chat_session_id"`
+	ParentMessageID *int     `json:"parent_message_id"`
+	Prompt          string   `json:"prompt"`
+	RefFileIDs      []string `json:"ref_file_ids"`
+	ThinkingEnabled bool     `json:"thinking_enabled"`
+	SearchEnabled   bool     `json:"search_enabled"`
+}
+
+type chatSession struct {
+	ID               string  `json:"id"`
+	Title            string  `json:"title"`
+	UpdatedAt        float64 `json:"updated_at"`
+	CurrentMessageID *int    `json:"current_message_id"`
+}
+
+// Provider implements the DeepSeek web API backend.
+type Provider struct {
+	baseURL   string
+	userAgent string
+	timeout   time.Duration
+	proxy     string
+	sessionID string
+	// userToken is the bearer token the web app keeps in local storage.
+	// It is looked up with the session cookie when not configured.
+	userToken string
+	webSearch bool
+}
+
+// New creates a DeepSeek provider.
+func New(baseURL, userAgent string, timeout time.Duration) *Provider {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Provider{
+		baseURL:   baseURL,
+		userAgent: userAgent,
+		timeout:   timeout,
+	}
+}
+
+func (p *Provider) Name() string { return "deepseek" }
+
+func (p *Provider) CookieSpecs() []provider.CookieSpec {
+	return []provider.CookieSpec{
+		{Domain: domainDeepSeek, Names: []string{cookieSessionID}},
+	}
+}
+
+// SetProxy routes requests through proxyURL instead of the environment's
+// HTTPS_PROXY/ALL_PROXY.
+func (p *Provider) SetProxy(proxyURL string) { p.proxy = proxyURL }
+
+func (p *Provider) SetCookies(cookies map[string]string) {
+	if v := cookies[cookieSessionID]; v != "" {
+		p.sessionID = v
+	}
+}
+
+// SetUserToken sets the bearer token instead of looking it up with the
+// session cookie.
+func (p *Provider) SetUserToken(token string) { p.userToken = strings.TrimSpace(token) }
+
+// SetWebSearch turns DeepSeek's web search on for asks.
+func (p *Provider) SetWebSearch(on bool) { p.webSearch = on }
+
+func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	if err := p.authenticate(ctx, logf); err != nil {
+		return err
+	}
+	query = provider.PrependSystemPrompt(query, opts)
+
+	model := ResolveModel(opts.Model)
+	logf("[deepseek] model=%s", model)
+
+	// 1. Create a chat session unless continuing one.
+	sessionID := opts.ConversationID
+	if sessionID == "" {
+		var err error
+		sessionID, err = p.createSession(ctx, logf)
+		if err != nil {
+			return fmt.Errorf("creating chat session: %w", err)
+		}
+	}
+	logf("[deepseek] session=%s", sessionID)
+
+	// 2. Reply to the given message, or the latest one when continuing.
+	req := completionRequest{
+		ChatSessionID:   sessionID,
+		Prompt:          query,
+		RefFileIDs:      []string{},
+		ThinkingEnabled: model == modelReasoner,
+		SearchEnabled:   p.webSearch,
+	}
+	parent := opts.ParentMessageID
+	if parent == "" && opts.ConversationID != "" {
+		session, err := p.fetchSession(ctx, sessionID, logf)
+		if err != nil {
+			logf("[deepseek] warning: could not resolve latest message: %v", err)
+		} else if session.CurrentMessageID != nil {
+			parent = strconv.Itoa(*session.CurrentMessageID)
+		}
+	}
+	if parent != "" {
+		id, err := strconv.Atoi(parent)
+		if err != nil {
+			return fmt.Errorf("invalid parent message ID %q", parent)
+		}
+		req.ParentMessageID = &id
+	}
+
+	err := p.complete(ctx, req, opts, logf)
+
+	// 3. Delete the session if temporary mode.
+	if opts.Temporary && opts.ConversationID == "" {
+		if delErr := p.deleteSession(ctx, sessionID, logf); delErr != nil {
+			logf("[deepseek] warning: failed to delete chat session: %v", delErr)
+		}
+	}
+	return err
+}
+
+// authenticate resolves the bearer token from the session cookie.
+func (p *Provider) authenticate(ctx context.Context, logf func(string, ...any)) error {
+	if p.userToken != "" {
+		return nil
+	}
+	if p.sessionID == "" {
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chat.deepseek.com in your browser")
+	}
+
+	var user struct {
+		Token string `json:"token"`
+	}
+	if err := p.doJSON(ctx, http.MethodGet, currentUserPath, nil, &user, logf); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	if user.Token == "" {
+		return fmt.Errorf("auth: no user token in response — log in to chat.deepseek.com again")
+	}
+	p.userToken = user.Token
+	return nil
+}
+
+func (p *Provider) createSession(ctx context.Context, logf func(string, ...any)) (string, error) {
+	var data struct {
+		ID          string      `json:"id"`
+		ChatSession chatSession `json:"chat_session"`
+	}
+	if err := p.doJSON(ctx, http.MethodPost, createSessionPath, map[string]any{"character_id": nil}, &data, logf); err != nil {
+		return "", err
+	}
+	if data.ChatSession.ID != "" {
+		return data.ChatSession.ID, nil
+	}
+	if data.ID == "" {
+		return "", fmt.Errorf("no session ID in response")
+	}
+	return data.ID, nil
+}
+
+func (p *Provider) deleteSession(ctx context.Context, sessionID string, logf func(string, ...any)) error {
+	return p.doJSON(ctx, http.MethodPost, deleteSessionPath, map[string]string{"chat_session_id": sessionID}, nil, logf)
+}
+
+// fetchSession loads a chat session's metadata from its history.
+func (p *Provider) fetchSession(ctx context.Context, sessionID string, logf func(string, ...any)) (*chatSession, error) {
+	var data struct {
+		ChatSession chatSession `json:"chat_session"`
+	}
+	path := historyPath + "?chat_session_id=" + url.QueryEscape(sessionID)
+	if err := p.doJSON(ctx, http.MethodGet, path, nil, &data, logf); err != nil {
+		return nil, err
+	}
+	return &data.ChatSession, nil
+}
+
+// complete answers the proof-of-work challenge and streams the reply.
+func (p *Provider) complete(ctx context.Context, body completionRequest, opts provider.AskOptions, logf func(string, ...any)) error {
+	var data struct {
+		Challenge powChallenge `json:"challenge"`
+	}
+	if err := p.doJSON(ctx, http.MethodPost, createChallengePath, map[string]string{"target_path": completionPath}, &data, logf); err != nil {
+		return fmt.Errorf("proof-of-work challenge: %w", err)
+	}
+	start := time.Now()
+	pow, err := solvePoW(data.Challenge)
+	if err != nil {
+		return err
+	}
+	logf("[deepseek] solved proof-of-work (difficulty %d) in %s", data.Challenge.Difficulty, time.Since(start).Round(time.Millisecond))
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshalling request: %w", err)
+	}
+	u := p.baseURL + completionPath
+	logf("[deepseek] POST %s", u)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	p.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("X-Ds-Pow-Response", pow)
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return provider.HTTPError(resp.StatusCode, b)
+	}
+	// Refusals such as an invalid session come back as a JSON envelope
+	// instead of a stream.
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		var env envelope
+		if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+		return env.err()
+	}
+
+	return p.readStream(resp.Body, body.ChatSessionID, opts, logf)
+}
+
+// readStream parses the completion stream. Newer streams send JSON-patch
+// style operations ({"p": path, "o": op, "v": value}) on a response
+// object whose fragments are THINK or RESPONSE text; older ones send
+// OpenAI-style choices whose delta type is "thinking" or "text".
+func (p *Provider) readStream(r io.Reader, sessionID string, opts provider.AskOptions, logf func(string, ...any)) error {
+	st := streamState{opts: opts, logf: logf}
+
+	err := sse.Read(r, func(event sse.Event) error {
+		if event.Data == "" || event.Data == "[DONE]" {
+			return nil
+		}
+		var ev streamEvent
+		if err := json.Unmarshal([]byte(event.Data), &ev); err != nil {
+			if opts.OnError != nil {
+				opts.OnError(fmt.Errorf("parsing event: %w", err))
+			}
+			return nil
+		}
+		return st.handle(ev)
+	})
+	if err != nil {
+		return err
+	}
+
+	if st.messageID != 0 && opts.OnConversation != nil {
+		opts.OnConversation(sessionID, strconv.Itoa(st.messageID), "")
+	}
+	if opts.OnDone != nil {
+		opts.OnDone()
+	}
+	return nil
+}
+
+type streamEvent struct {
+	// Patch-style events.
+	P string          `json:"p"`
+	O string          `json:"o"`
+	V json.RawMessage `json:"v"`
+
+	// Older choice-style events.
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+			Type    string `json:"type"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	MessageID int `json:"message_id"`
+
+	// The first event names the new messages.
+	ResponseMessageID int `json:"response_message_id"`
+
+	// Errors arrive as their own event.
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+type responseFragment struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// streamState tracks the patch path and fragment kind across events; a
+// patch without a path continues the previous one.
+type streamState struct {
+	opts      provider.AskOptions
+	logf      func(string, ...any)
+	path      string
+	thinking  bool
+	messageID int
+}
+
+func (s *streamState) handle(ev streamEvent) error {
+	if ev.ResponseMessageID != 0 {
+		s.messageID = ev.ResponseMessageID
+	}
+	if ev.MessageID != 0 {
+		s.messageID = ev.MessageID
+	}
+	if ev.Type == "error" {
+		msg := ev.Content
+		if msg == "" {
+			msg = string(ev.V)
+		}
+		return fmt.Errorf("deepseek: %s", msg)
+	}
+
+	for _, c := range ev.Choices {
+		s.emit(c.Delta.Content, c.Delta.Type == "thinking")
+		if c.FinishReason == "length" {
+			s.truncated()
+		}
+	}
+
+	if len(ev.V) == 0 {
+		return nil
+	}
+	if ev.P != "" {
+		s.path = ev.P
+	}
+
+	var text string
+	if json.Unmarshal(ev.V, &text) == nil {
+		switch {
+		case s.path == "response/status":
+			// INCOMPLETE is what the web app offers "Continue" for.
+			if text == "INCOMPLETE" {
+				s.truncated()
+			}
+		case s.path == "response/thinking_content":
+			s.emit(text, true)
+		case s.path == "response/content":
+			s.emit(text, false)
+		case strings.HasPrefix(s.path, "response/fragments/") && strings.HasSuffix(s.path, "/content"):
+			s.emit(text, s.thinking)
+		}
+		return nil
+	}
+
+	var fragments []responseFragment
+	if ev.P == "response/fragments" && json.Unmarshal(ev.V, &fragments) == nil {
+		s.emitFragments(fragments)
+		return nil
+	}
+
+	var obj struct {
+		Response *struct {
+			MessageID int                `json:"message_id"`
+			Fragments []responseFragment `json:"fragments"`
+		} `json:"response"`
+	}
+	if json.Unmarshal(ev.V, &obj) == nil && obj.Response != nil {
+		if obj.Response.MessageID != 0 {
+			s.messageID = obj.Response.MessageID
+		}
+		s.emitFragments(obj.Response.Fragments)
+	}
+	return nil
+}
+
+func (s *streamState) emitFragments(fragments []responseFragment) {
+	for _, f := range fragments {
+		s.thinking = f.Type == "THINK"
+		s.emit(f.Content, s.thinking)
+	}
+}
+
+func (s *streamState) truncated() {
+	if s.opts.OnTruncated != nil {
+		s.opts.OnTruncated()
+	}
+}
+
+// emit sends reasoning to OnThinking (or the debug log) and answer text
+// to OnText.
+func (s *streamState) emit(text string, thinking bool) {
+	if text == "" {
+		return
+	}
+	if !thinking {
+		if s.opts.OnText != nil {
+			s.opts.OnText(text)
+		}
+		return
+	}
+	if s.opts.OnThinking != nil {
+		s.opts.OnThinking(text)
+	} else {
+		s.logf("[deepseek] thinking: %s", text)
+	}
+}
+
+// DeleteConversation deletes a chat session.
+func (p *Provider) DeleteConversation(ctx context.Context, conversationID string, opts provider.DeleteOptions) error {
+	if strings.TrimSpace(conversationID) == "" {
+		return fmt.Errorf("conversation ID is required")
+	}
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	if err := p.authenticate(ctx, logf); err != nil {
+		return err
+	}
+	return p.deleteSession(ctx, conversationID, logf)
+}
+
+// ListConversations fetches the user's recent chat sessions.
+func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	if err := p.authenticate(ctx, logf); err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		ChatSessions []chatSession `json:"chat_sessions"`
+	}
+	path := fmt.Sprintf("%s?count=%d", listSessionsPath, opts.Want(20))
+	if err := p.doJSON(ctx, http.MethodGet, path, nil, &data, logf); err != nil {
+		return nil, err
+	}
+
+	var conversations []provider.Conversation
+	for _, s := range data.ChatSessions {
+		conv := provider.Conversation{ID: s.ID, Title: s.Title}
+		if s.UpdatedAt > 0 {
+			conv.UpdatedAt = time.Unix(0, int64(s.UpdatedAt*float64(time.Second)))
+		}
+		conversations = append(conversations, conv)
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	return provider.Page(conversations, opts), nil
+}
+
+func (p *Provider) ListModels() provider.ProviderModels {
+	return provider.ProviderModels{
+		Provider: "deepseek",
+		Models: []provider.ModelInfo{
+			{ID: modelChat, Name: "DeepSeek-V3", Description: "General chat (aliases: chat, v3)", Default: true, Tags: []string{"balanced"}},
+			{ID: modelReasoner, Name: "DeepSeek-R1", Description: "DeepThink reasoning with a visible trace (aliases: reasoner, r1, think)", Default: false, Tags: []string{"reasoning"}},
+		},
+	}
+}
+
+// --- HTTP helpers ---
+
+func (p *Provider) setHeaders(req *http.Request) {
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("Origin", p.baseURL)
+	req.Header.Set("Referer", p.baseURL+"/")
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("X-App-Version", "20241129.1")
+	req.Header.Set("X-Client-Platform", "web")
+	req.Header.Set("X-Client-Locale", "en_US")
+	if p.userToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p.userToken)
+	}
+	if p.sessionID != "" {
+		req.AddCookie(&http.Cookie{Name: cookieSessionID, Value: p.sessionID})
+	}
+}
+
+// doJSON sends an API request and decodes its biz_data into out, which
+// may be nil.
+func (p *Provider) doJSON(ctx context.Context, method, path string, body any, out any, logf func(string, ...any)) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshalling request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	u := p.baseURL + path
+	logf("[deepseek] %s %s", method, u)
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(req)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return provider.HTTPError(resp.StatusCode, b)
+	}
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if err := env.err(); err != nil {
+		return err
+	}
+	if out == nil || len(env.Data.BizData) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data.BizData, out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// err reports a failed API call.
+func (e envelope) err() error {
+	if e.Code != 0 {
+		return fmt.Errorf("deepseek error %d: %s", e.Code, e.Msg)
+	}
+	if e.Data.BizCode != 0 {
+		return fmt.Errorf("deepseek error %d: %s", e.Data.BizCode, e.Data.BizMsg)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Package deepseek — pow.go solves the proof-of-work challenge that
+// guards chat completions.
+//
+// Before each completion the web app fetches a challenge and searches for
+// a nonce whose DeepSeekHashV1 digest of salt_expireAt_nonce equals the
+// challenge. DeepSeekHashV1 is SHA3-256 with the first Keccak round
+// skipped, so the permutation is implemented here rather than taken from
+// x/crypto/sha3.
+package deepseek
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"strconv"
+)
+
+const powAlgorithm = "DeepSeekHashV1"
+
+// powChallenge is the biz_data.challenge of create_pow_challenge.
+type powChallenge struct {
+	Algorithm  string `json:"algorithm"`
+	Challenge  string `json:"challenge"`
+	Salt       string `json:"salt"`
+	Signature  string `json:"signature"`
+	Difficulty int    `json:"difficulty"`
+	ExpireAt   int64  `json:"expire_at"`
+	TargetPath string `json:"target_path"`
+}
+
+// powResponse is sent base64-encoded in the x-ds-pow-response header.
+type powResponse struct {
+	Algorithm  string `json:"algorithm"`
+	Challenge  string `json:"challenge"`
+	Salt       string `json:"salt"`
+	Answer     int    `json:"answer"`
+	Signature  string `json:"signature"`
+	TargetPath string `json:"target_path"`
+}
+
+// solvePoW finds the challenge's nonce and returns the header value.
+func solvePoW(c powChallenge) (string, error) {
+	if c.Algorithm != powAlgorithm {
+		return "", fmt.Errorf("unsupported proof-of-work algorithm %q", c.Algorithm)
+	}
+	target, err := hex.DecodeString(c.Challenge)
+	if err != nil || len(target) != 32 {
+		return "", fmt.Errorf("malformed proof-of-work challenge %q", c.Challenge)
+	}
+
+	prefix := c.Salt + "_" + strconv.FormatInt(c.ExpireAt, 10) + "_"
+	buf := make([]byte, 0, len(prefix)+20)
+	for nonce := 0; nonce < c.Difficulty; nonce++ {
+		buf = strconv.AppendInt(append(buf[:0], prefix...), int64(nonce), 10)
+		if sum := deepSeekHash(buf); string(sum[:]) == string(target) {
+			data, err := json.Marshal(powResponse{
+				Algorithm:  c.Algorithm,
+				Challenge:  c.Challenge,
+				Salt:       c.Salt,
+				Answer:     nonce,
+				Signature:  c.Signature,
+				TargetPath: c.TargetPath,
+			})
+			if err != nil {
+				return "", err
+			}
+			return base64.StdEncoding.EncodeToString(data), nil
+		}
+	}
+	return "", fmt.Errorf("no proof-of-work answer below difficulty %d", c.Difficulty)
+}
+
+// sha3Rate is the SHA3-256 block size in bytes.
+const sha3Rate = 136
+
+// deepSeekHash is SHA3-256 using keccakF with the given first round.
+func deepSeekHash(data []byte) [32]byte {
+	return sha3Sum(data, 1)
+}
+
+func sha3Sum(data []byte, firstRound int) [32]byte {
+	var a [25]uint64
+	absorb := func(block []byte) {
+		for i := 0; i < sha3Rate/8; i++ {
+			a[i] ^= binary.LittleEndian.Uint64(block[i*8:])
+		}
+		keccakF(&a, firstRound)
+	}
+
+	for len(data) >= sha3Rate {
+		absorb(data[:sha3Rate])
+		data = data[sha3Rate:]
+	}
+	var last [sha3Rate]byte
+	copy(last[:], data)
+	last[len(data)] ^= 0x06
+	last[sha3Rate-1] ^= 0x80
+	absorb(last[:])
+
+	var out [32]byte
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], a[i])
+	}
+	return out
+}
+
+var keccakRC = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotc and keccakPiln are the rho offsets and pi lane order.
+var (
+	keccakRotc = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+	keccakPiln = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+)
+
+// keccakF applies the Keccak-f[1600] rounds from firstRound to 23.
+func keccakF(a *[25]uint64, firstRound int) {
+	var c [5]uint64
+	for round := firstRound; round < 24; round++ {
+		// θ
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[y+x] ^= d
+			}
+		}
+		// ρ and π
+		t := a[1]
+		for i := 0; i < 24; i++ {
+			j := keccakPiln[i]
+			t, a[j] = a[j], bits.RotateLeft64(t, keccakRotc[i])
+		}
+		// χ
+		for y := 0; y < 25; y += 5 {
+			copy(c[:], a[y:y+5])
+			for x := 0; x < 5; x++ {
+				a[y+x] = c[x] ^ (^c[(x+1)%5] & c[(x+2)%5])
+			}
+		}
+		// ι
+		a[0] ^= keccakRC[round]
+	}
+}
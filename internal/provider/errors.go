@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Kinds of provider failure. Errors returned by providers match at most
+// one of them with errors.Is, so callers can tell "log in again" from
+// "try again later" without parsing messages.
+var (
+	// ErrAuthExpired means the session is missing, expired or rejected.
+	ErrAuthExpired = errors.New("not logged in or session expired")
+	// ErrRateLimited means a rate limit or usage cap was hit.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrCloudflareBlocked means Cloudflare answered with a challenge
+	// page instead of letting the request through.
+	ErrCloudflareBlocked = errors.New("blocked by Cloudflare")
+	// ErrModelUnavailable means the requested model or mode is unknown
+	// or not offered to this account.
+	ErrModelUnavailable = errors.New("model unavailable")
+	// ErrUnavailable means the provider failed on its side (HTTP 5xx).
+	ErrUnavailable = errors.New("provider unavailable")
+)
+
+// Errorf formats an error that matches kind, one of the errors above,
+// while keeping its own message. A nil kind gives a plain error.
+func Errorf(kind error, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	if kind == nil {
+		return err
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string   { return e.err.Error() }
+func (e *kindError) Unwrap() []error { return []error{e.err, e.kind} }
+
+// StatusError is an unexpected HTTP answer from a provider. It matches
+// the kind of failure its status and body point to.
+type StatusError struct {
+	Status int
+	Body   string
+}
+
+// HTTPError returns the error for a response with an unexpected status,
+// given the start of its body.
+func HTTPError(status int, body []byte) error {
+	return &StatusError{Status: status, Body: string(body)}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.Status, e.Body)
+}
+
+func (e *StatusError) Unwrap() error { return StatusKind(e.Status, e.Body) }
+
+// StatusKind returns the kind of failure an HTTP status and response
+// body (or error message) point to, or nil for none in particular.
+func StatusKind(status int, body string) error {
+	lower := strings.ToLower(body)
+	switch {
+	case (status == http.StatusForbidden || status == http.StatusServiceUnavailable) && isCloudflareChallenge(lower):
+		return ErrCloudflareBlocked
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrAuthExpired
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case (status == http.StatusBadRequest || status == http.StatusNotFound) && strings.Contains(lower, "model"):
+		return ErrModelUnavailable
+	case status >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// isCloudflareChallenge reports whether a lowercased response body is a
+// Cloudflare challenge or block page.
+func isCloudflareChallenge(body string) bool {
+	return strings.Contains(body, "challenge-platform") || strings.Contains(body, "cf_chl") ||
+		strings.Contains(body, "just a moment...") || strings.Contains(body, "attention required! | cloudflare")
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GeneratedFile is a file a provider created for an answer, such as a
+// chart or spreadsheet written by a code interpreter.
+type GeneratedFile struct {
+	// Name is the file's name on the provider's side.
+	Name string
+	// URL is where the file was downloaded from; empty when it was not
+	// resolved.
+	URL string
+	// Path is where the file was saved; empty when AskOptions.DownloadDir
+	// is unset or the download failed.
+	Path string
+}
+
+// SaveFile writes a download response body to dir under name, creating
+// dir if needed. An existing file is not overwritten: "-2", "-3", ... is
+// added before the extension instead. It returns the file's path and
+// closes the body.
+func SaveFile(resp *http.Response, dir, name string) (string, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading file: HTTP %d", resp.StatusCode)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	name = filepath.Base(filepath.Clean("/" + name))
+	if name == "/" || name == "." {
+		name = "download"
+	}
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	path := filepath.Join(dir, name)
+	var (
+		f   *os.File
+		err error
+	)
+	for i := 2; ; i++ {
+		f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if !os.IsExist(err) {
+			break
+		}
+		path = filepath.Join(dir, stem+"-"+strconv.Itoa(i)+ext)
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("downloading file: %w", err)
+	}
+	return path, f.Close()
+}
@@ -0,0 +1,157 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// rpcReadConversation loads a conversation's turns, newest first.
+const rpcReadConversation = "hNvQHb"
+
+// latestTurn returns the reply ID (rid) and draft ID (rcid) of the newest
+// turn in a conversation, which a follow-up must send to continue it.
+func (p *Provider) latestTurn(ctx context.Context, conversationID string, logf func(string, ...any)) (string, string, error) {
+	if !strings.HasPrefix(conversationID, "c_") {
+		conversationID = "c_" + conversationID
+	}
+	if p.snlm0e == "" {
+		if err := p.initialize(ctx, logf); err != nil {
+			return "", "", fmt.Errorf("initialize: %w", err)
+		}
+	}
+
+	text, err := p.batchExecute(ctx, rpcReadConversation, []any{conversationID, 1, nil, 1, []any{0}, []any{4}, nil, 1}, logf)
+	if err != nil {
+		return "", "", err
+	}
+
+	var rid, rcid string
+	forEachRPCResult(text, rpcReadConversation, func(inner any) {
+		if rid != "" {
+			return
+		}
+		rid, rcid = parseLatestTurn(inner, conversationID)
+	})
+	if rid == "" {
+		return "", "", fmt.Errorf("no turns found in conversation %s", conversationID)
+	}
+	logf("[gemini] conversation %s continues from rid=%s rcid=%s", conversationID, rid, rcid)
+	return rid, rcid, nil
+}
+
+// parseLatestTurn finds the first [cid, rid] pair for conversationID and
+// the first draft ID after it. Turns are listed newest first.
+func parseLatestTurn(node any, conversationID string) (rid, rcid string) {
+	var walk func(v any)
+	walk = func(v any) {
+		arr, ok := v.([]any)
+		if !ok || rcid != "" {
+			return
+		}
+		if len(arr) == 2 && rid == "" {
+			if cid, _ := arr[0].(string); cid == conversationID {
+				if r, ok := arr[1].(string); ok && strings.HasPrefix(r, "r_") {
+					rid = r
+					return
+				}
+			}
+		}
+		if rid != "" && len(arr) >= 2 {
+			if id, ok := arr[0].(string); ok && strings.HasPrefix(id, "rc_") {
+				rcid = id
+				return
+			}
+		}
+		for _, child := range arr {
+			walk(child)
+		}
+	}
+	walk(node)
+	return rid, rcid
+}
+
+// batchExecute calls one batchexecute RPC and returns the raw response.
+func (p *Provider) batchExecute(ctx context.Context, rpcID string, payload any, logf func(string, ...any)) (string, error) {
+	innerPayload, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	reqBody, err := json.Marshal([]any{[]any{[]any{rpcID, string(innerPayload), nil, "generic"}}})
+	if err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	values.Set("f.req", string(reqBody))
+	values.Set("at", p.snlm0e)
+
+	params := url.Values{}
+	params.Set("rpcids", rpcID)
+	params.Set("source-path", "/app")
+	params.Set("bl", p.bl)
+	params.Set("_reqid", strconv.Itoa(rand.Intn(900000)+100000))
+	params.Set("rt", "c")
+	reqURL := batchExecURL + "?" + params.Encode()
+
+	logf("[gemini] POST %s (rpc=%s)", batchExecURL, rpcID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return "", err
+	}
+	p.setAPIHeaders(req)
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s request: %w", rpcID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("%s HTTP %s: %s", rpcID, resp.Status, string(body))
+	}
+
+	text, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// forEachRPCResult decodes each ["wrb.fr", rpcID, "<json>"] envelope in a
+// batchexecute response and passes its payload to fn.
+func forEachRPCResult(text, rpcID string, fn func(inner any)) {
+	text = strings.TrimPrefix(text, ")]}'")
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || numericLineRE.MatchString(line) {
+			continue
+		}
+		var parsed []any
+		if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+			continue
+		}
+		for _, item := range parsed {
+			env, ok := item.([]any)
+			if !ok || len(env) < 3 || env[0] != "wrb.fr" || env[1] != rpcID {
+				continue
+			}
+			innerText, ok := env[2].(string)
+			if !ok {
+				continue
+			}
+			var inner any
+			if err := json.Unmarshal([]byte(innerText), &inner); err == nil {
+				fn(inner)
+			}
+		}
+	}
+}
@@ -13,8 +13,10 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/kyupark/ask/internal/httpclient"
 	"github.com/kyupark/ask/internal/provider"
 )
 
@@ -44,9 +46,17 @@ var (
 
 // Provider implements the Gemini web API backend.
 type Provider struct {
-	userAgent    string
-	timeout      time.Duration
-	cookieHeader string
+	userAgent string
+	timeout   time.Duration
+	proxy     string
+
+	// Session cookies and the Cookie header built from them. Both are
+	// updated in place when Google rotates the cookies (see rotate.go).
+	cookieMu       sync.Mutex
+	cookies        map[string]string
+	cookieHeader   string
+	lastRotated    time.Time
+	onCookieUpdate func(cookies map[string]string, rotatedAt time.Time)
 
 	// Scraped session tokens.
 	snlm0e string
@@ -76,30 +86,39 @@ func (p *Provider) CookieSpecs() []provider.CookieSpec {
 	}
 }
 
+// SetProxy routes requests through proxyURL instead of the environment's
+// HTTPS_PROXY/ALL_PROXY.
+func (p *Provider) SetProxy(proxyURL string) { p.proxy = proxyURL }
+
 func (p *Provider) SetCookies(cookies map[string]string) {
 	// Build a full cookie header from all provided cookies.
-	var pairs []string
+	set := make(map[string]string)
 	for k, v := range cookies {
 		if v != "" {
-			pairs = append(pairs, k+"="+v)
+			set[k] = v
 		}
 	}
-	if len(pairs) > 0 {
-		p.cookieHeader = strings.Join(pairs, "; ")
+	if len(set) == 0 {
+		return
 	}
+	p.cookieMu.Lock()
+	p.cookies = set
+	p.rebuildCookieHeader()
+	p.cookieMu.Unlock()
 }
 
 // SetModel sets the model to use for subsequent requests.
 func (p *Provider) SetModel(model string) { p.selectedModel = model }
 func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
 	if p.cookieHeader == "" {
-		return fmt.Errorf("no cookies — log in to gemini.google.com in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "no cookies — log in to gemini.google.com in your browser")
 	}
 
 	logf := opts.LogFunc
 	if logf == nil {
 		logf = func(string, ...any) {}
 	}
+	query = provider.PrependSystemPrompt(query, opts)
 
 	// Apply model selection.
 	if opts.Model != "" {
@@ -122,18 +141,46 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 			logf("[gemini] warning: could not disable activity: %v", err)
 		}
 	}
+	files, err := p.uploadFiles(ctx, opts.Attachments, logf)
+	if err != nil {
+		return err
+	}
+
+	// Continuing a conversation needs its latest reply and draft; look them
+	// up when only the conversation ID is known.
+	if opts.ConversationID != "" && !strings.HasPrefix(opts.ConversationID, "c_") {
+		opts.ConversationID = "c_" + opts.ConversationID
+	}
+	responseID, candidateID := opts.ResponseID, opts.ParentMessageID
+	if opts.ConversationID != "" && responseID == "" {
+		if rid, rcid, err := p.latestTurn(ctx, opts.ConversationID, logf); err == nil {
+			responseID, candidateID = rid, rcid
+		} else {
+			logf("[gemini] warning: could not load conversation context: %v", err)
+		}
+	}
+
 	// Send the chat request.
-	resp, err := p.chat(ctx, query, opts.ConversationID, opts.ResponseID, logf)
+	resp, err := p.chat(ctx, query, files, opts.ConversationID, responseID, candidateID, logf)
 	if err != nil {
 		return err
 	}
 	if opts.OnConversation != nil {
-		opts.OnConversation(resp.ConversationID, "", resp.ResponseID)
+		opts.OnConversation(resp.ConversationID, resp.CandidateID, resp.ResponseID)
 	}
 
 	if opts.OnText != nil {
 		opts.OnText(resp.Content)
 	}
+	if opts.OnSource != nil {
+		for _, src := range resp.Sources {
+			opts.OnSource(src.Name, src.URL)
+		}
+	}
+	if len(resp.Images) > 0 {
+		logf("[gemini] %d generated image(s)", len(resp.Images))
+		p.deliverImages(ctx, resp.Images, opts, logf)
+	}
 	if opts.OnDone != nil {
 		opts.OnDone()
 	}
@@ -144,6 +191,10 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 // --- Internal methods ---
 
 func (p *Provider) initialize(ctx context.Context, logf func(string, ...any)) error {
+	// Refresh the cookies first so a stale __Secure-1PSIDTS doesn't fail
+	// the page load.
+	p.rotateCookies(ctx, logf)
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, geminiBaseURL, nil)
 	if err != nil {
 		return err
@@ -157,7 +208,7 @@ func (p *Provider) initialize(ctx context.Context, logf func(string, ...any)) er
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Gemini page returned %s", resp.Status)
+		return provider.Errorf(provider.StatusKind(resp.StatusCode, ""), "Gemini page returned %s", resp.Status)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -178,7 +229,7 @@ func (p *Provider) initialize(ctx context.Context, logf func(string, ...any)) er
 
 	if p.snlm0e == "" {
 		if strings.Contains(page, "Sign in") || strings.Contains(page, "accounts.google.com") {
-			return errors.New("not logged in — log in to gemini.google.com in your browser")
+			return provider.Errorf(provider.ErrAuthExpired, "not logged in — log in to gemini.google.com in your browser")
 		}
 		return errors.New("could not extract session token (SNlM0e) — API may have changed")
 	}
@@ -245,14 +296,20 @@ type chatResponse struct {
 	Content        string
 	ConversationID string
 	ResponseID     string
+	// CandidateID identifies the draft shown; a follow-up continues from it.
+	CandidateID string
+	// Images are the URLs of images generated for the reply.
+	Images []string
+	// Sources are the pages the reply cites.
+	Sources []provider.Source
 }
 
-func (p *Provider) chat(ctx context.Context, prompt, conversationID, responseID string, logf func(string, ...any)) (chatResponse, error) {
+func (p *Provider) chat(ctx context.Context, prompt string, files []uploadedFile, conversationID, responseID, candidateID string, logf func(string, ...any)) (chatResponse, error) {
 	const maxRetries = 3
 	const baseDelayMs = 2000
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		resp, err := p.chatOnce(ctx, prompt, conversationID, responseID, logf)
+		resp, err := p.chatOnce(ctx, prompt, files, conversationID, responseID, candidateID, logf)
 		if err == nil && resp.Success {
 			return resp, nil
 		}
@@ -272,12 +329,16 @@ func (p *Provider) chat(ctx context.Context, prompt, conversationID, responseID
 	return chatResponse{}, errors.New("max retries exceeded")
 }
 
-func (p *Provider) chatOnce(ctx context.Context, prompt, conversationID, responseID string, logf func(string, ...any)) (chatResponse, error) {
+func (p *Provider) chatOnce(ctx context.Context, prompt string, files []uploadedFile, conversationID, responseID, candidateID string, logf func(string, ...any)) (chatResponse, error) {
 	var convContext any
 	if conversationID != "" {
-		convContext = []any{conversationID, responseID, nil}
+		var rcid any
+		if candidateID != "" {
+			rcid = candidateID
+		}
+		convContext = []any{conversationID, responseID, rcid}
 	}
-	msg := []any{[]string{prompt}, nil, convContext}
+	msg := []any{promptPart(prompt, files), nil, convContext}
 	inner, err := json.Marshal(msg)
 	if err != nil {
 		return chatResponse{}, err
@@ -331,11 +392,20 @@ func (p *Provider) chatOnce(ctx context.Context, prompt, conversationID, respons
 
 func (p *Provider) client() *http.Client {
 	if p.httpClient == nil {
-		p.httpClient = &http.Client{Timeout: p.timeout}
+		p.httpClient = &http.Client{
+			Timeout:   p.timeout,
+			Transport: &cookieTransport{p: p, base: p.transport()},
+		}
 	}
 	return p.httpClient
 }
 
+// transport is the base transport for Gemini requests, using the
+// configured proxy.
+func (p *Provider) transport() http.RoundTripper {
+	return httpclient.PlainTransport(p.proxy)
+}
+
 func (p *Provider) setPageHeaders(req *http.Request) {
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8")
 	req.Header.Set("Cache-Control", "max-age=0")
@@ -444,12 +514,19 @@ func parseChatInner(inner any) chatResponse {
 		}
 	}
 
-	if text, ok := findResponseText(arr); ok {
+	if text, rcid, ok := findResponseText(arr); ok {
+		var images []string
+		findGeneratedImages(arr, map[string]bool{}, &images)
+		var sources []provider.Source
+		findSources(arr, map[string]bool{}, &sources)
 		return chatResponse{
 			Success:        true,
-			Content:        text,
+			Content:        stripImagePlaceholders(text),
 			ConversationID: convID,
 			ResponseID:     respID,
+			CandidateID:    rcid,
+			Images:         images,
+			Sources:        sources,
 		}
 	}
 
@@ -468,29 +545,34 @@ func parseChatInner(inner any) chatResponse {
 	return chatResponse{}
 }
 
-func findResponseText(node any) (string, bool) {
+// findResponseText finds the first reply draft, a [rcid, [text, ...]]
+// pair, and returns its text and candidate ID.
+func findResponseText(node any) (string, string, bool) {
 	switch v := node.(type) {
 	case []any:
 		if len(v) >= 2 {
-			if _, ok := v[0].(string); ok {
+			if rcid, ok := v[0].(string); ok {
 				if text, ok := textFromCandidate(v[1]); ok && isLikelyText(text) {
-					return text, true
+					if !strings.HasPrefix(rcid, "rc_") {
+						rcid = ""
+					}
+					return text, rcid, true
 				}
 			}
 		}
 		for _, child := range v {
-			if text, ok := findResponseText(child); ok {
-				return text, true
+			if text, rcid, ok := findResponseText(child); ok {
+				return text, rcid, true
 			}
 		}
 	case map[string]any:
 		for _, child := range v {
-			if text, ok := findResponseText(child); ok {
-				return text, true
+			if text, rcid, ok := findResponseText(child); ok {
+				return text, rcid, true
 			}
 		}
 	}
-	return "", false
+	return "", "", false
 }
 
 func textFromCandidate(v any) (string, bool) {
@@ -535,7 +617,7 @@ func isRetryable(msg string) bool {
 
 func (p *Provider) DeleteConversation(ctx context.Context, conversationID string, opts provider.DeleteOptions) error {
 	if p.cookieHeader == "" {
-		return fmt.Errorf("no cookies — log in to gemini.google.com in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "no cookies — log in to gemini.google.com in your browser")
 	}
 
 	logf := opts.LogFunc
@@ -602,7 +684,7 @@ const rpcListConversations = "MaZiqc"
 // ListConversations fetches recent conversations via the MaZiqc batchexecute RPC.
 func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
 	if p.cookieHeader == "" {
-		return nil, fmt.Errorf("no cookies \u2014 log in to gemini.google.com in your browser")
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no cookies \u2014 log in to gemini.google.com in your browser")
 	}
 
 	logf := opts.LogFunc
@@ -617,13 +699,8 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 		}
 	}
 
-	limit := opts.Limit
-	if limit <= 0 {
-		limit = 50
-	}
-
 	// Build the batchexecute payload: [count, null, [0, null, 1]]
-	innerPayload, _ := json.Marshal([]any{limit, nil, []any{0, nil, 1}})
+	innerPayload, _ := json.Marshal([]any{opts.Want(50), nil, []any{0, nil, 1}})
 	reqBody, _ := json.Marshal([]any{[]any{[]any{rpcListConversations, string(innerPayload), nil, "generic"}}})
 
 	values := url.Values{}
@@ -662,7 +739,11 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 		return nil, err
 	}
 
-	return parseListResponse(string(text), logf)
+	conversations, err := parseListResponse(string(text), logf)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Page(conversations, opts), nil
 }
 
 func parseListResponse(text string, logf func(string, ...any)) ([]provider.Conversation, error) {
@@ -756,13 +837,30 @@ func extractConversationsFromInner(node any, out *[]provider.Conversation) {
 		}
 		if !dupe {
 			*out = append(*out, provider.Conversation{
-				ID:    id,
-				Title: title,
+				ID:        id,
+				Title:     title,
+				UpdatedAt: entryTime(itemArr[2:]),
 			})
 		}
 	}
 }
 
+// entryTime returns the first [seconds, nanos] timestamp among fields.
+func entryTime(fields []any) time.Time {
+	for _, f := range fields {
+		ts, ok := f.([]any)
+		if !ok || len(ts) != 2 {
+			continue
+		}
+		sec, ok1 := ts[0].(float64)
+		nsec, ok2 := ts[1].(float64)
+		if ok1 && ok2 && sec > 0 {
+			return time.Unix(int64(sec), int64(nsec))
+		}
+	}
+	return time.Time{}
+}
+
 // --- Model catalog ---
 
 // geminiModelHeaders maps model names to the x-goog-ext-525001261-jspb header value.
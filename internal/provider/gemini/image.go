@@ -0,0 +1,93 @@
+// Package gemini — image.go handles images generated by Imagen.
+//
+// When a prompt asks for a picture, the reply text carries placeholder
+// links (http://googleusercontent.com/image_generation_content/N) and the
+// candidate lists the generated images under lh3.googleusercontent.com/gg
+// URLs. The placeholders are dropped from the text and the images are
+// downloaded at full size with the session cookies.
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// generatedImagePrefix marks the URL of an image Gemini generated.
+const generatedImagePrefix = "https://lh3.googleusercontent.com/gg"
+
+// imagePlaceholderRE matches the placeholder links left in reply text
+// where generated images are shown.
+var imagePlaceholderRE = regexp.MustCompile(`\n*https?://googleusercontent\.com/image_generation_content/\d+\n*`)
+
+// stripImagePlaceholders removes image placeholder links from reply text.
+func stripImagePlaceholders(text string) string {
+	return strings.TrimSpace(imagePlaceholderRE.ReplaceAllString(text, "\n\n"))
+}
+
+// findGeneratedImages collects the distinct generated image URLs in a
+// response node.
+func findGeneratedImages(node any, seen map[string]bool, out *[]string) {
+	switch v := node.(type) {
+	case string:
+		if strings.HasPrefix(v, generatedImagePrefix) && !seen[v] {
+			seen[v] = true
+			*out = append(*out, v)
+		}
+	case []any:
+		for _, child := range v {
+			findGeneratedImages(child, seen, out)
+		}
+	case map[string]any:
+		for _, child := range v {
+			findGeneratedImages(child, seen, out)
+		}
+	}
+}
+
+// deliverImages downloads the reply's images to opts.ImageDir, if set,
+// and reports each through opts.OnImage. A failed download is logged and
+// the image is still reported by URL.
+func (p *Provider) deliverImages(ctx context.Context, urls []string, opts provider.AskOptions, logf func(string, ...any)) {
+	stamp := time.Now().Format("20060102-150405")
+	for i, u := range urls {
+		img := provider.GeneratedImage{URL: u}
+		if opts.ImageDir != "" {
+			name := fmt.Sprintf("gemini-%s-%d", stamp, i+1)
+			path, err := p.downloadImage(ctx, u, opts.ImageDir, name, logf)
+			if err != nil {
+				logf("[gemini] warning: could not save image %d: %v", i+1, err)
+			}
+			img.Path = path
+		}
+		if opts.OnImage != nil {
+			opts.OnImage(img)
+		}
+	}
+}
+
+// downloadImage fetches a generated image at full resolution.
+func (p *Provider) downloadImage(ctx context.Context, imageURL, dir, name string, logf func(string, ...any)) (string, error) {
+	// The "=s2048" size suffix asks for the full-size image rather than
+	// the preview.
+	u := imageURL + "=s2048"
+	logf("[gemini] GET %s", u)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("Referer", geminiBaseURL+"/")
+	req.Header.Set("Cookie", p.cookieHeader)
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	return provider.SaveImage(resp, dir, name)
+}
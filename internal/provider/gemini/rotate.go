@@ -0,0 +1,161 @@
+// Package gemini implements the Google Gemini web API provider.
+//
+// rotate.go keeps the session cookies fresh. Google rotates
+// __Secure-1PSIDTS (and __Secure-1PSIDCC alongside it) every few hours and
+// the old value stops working soon after, so a copy taken from the
+// browser expires within about a day. Two things keep it alive:
+//
+//   - every response passes through a transport that picks up Set-Cookie
+//     values for the session cookies, and
+//   - before initializing, the provider calls accounts.google.com's
+//     RotateCookies endpoint (the same call the web app makes) when the last
+//     rotation is older than rotateInterval.
+//
+// Rotated values are reported through the handler set with
+// SetCookieRefresh so the caller can persist them.
+package gemini
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	rotateCookiesURL = "https://accounts.google.com/RotateCookies"
+	rotateBody       = `[000,"-0000000000000000000"]`
+
+	// rotateInterval matches how often the web app rotates. Google answers
+	// more frequent calls with 401.
+	rotateInterval = 10 * time.Minute
+)
+
+// rotatedCookies are the session cookies worth capturing from responses.
+var rotatedCookies = map[string]bool{cookiePSIDTS: true, cookiePSIDCC: true}
+
+// SetCookieRefresh records when the cookies were last rotated and sets the
+// handler called whenever a session cookie changes. rotatedAt is the time
+// of a successful RotateCookies call, or zero when the new values only
+// came from Set-Cookie headers on other responses.
+func (p *Provider) SetCookieRefresh(lastRotated time.Time, onUpdate func(cookies map[string]string, rotatedAt time.Time)) {
+	p.lastRotated = lastRotated
+	p.onCookieUpdate = onUpdate
+}
+
+// rebuildCookieHeader regenerates the Cookie header from p.cookies in a
+// stable order. Callers hold p.cookieMu.
+func (p *Provider) rebuildCookieHeader() {
+	names := make([]string, 0, len(p.cookies))
+	for name, v := range p.cookies {
+		if v != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + p.cookies[name]
+	}
+	p.cookieHeader = strings.Join(pairs, "; ")
+}
+
+// captureCookies applies rotated session cookies from resp and reports
+// whether any changed.
+func (p *Provider) captureCookies(resp *http.Response) bool {
+	p.cookieMu.Lock()
+	changed := false
+	for _, c := range resp.Cookies() {
+		if !rotatedCookies[c.Name] || c.Value == "" || c.Value == p.cookies[c.Name] {
+			continue
+		}
+		if p.cookies == nil {
+			p.cookies = make(map[string]string)
+		}
+		p.cookies[c.Name] = c.Value
+		changed = true
+	}
+	if changed {
+		p.rebuildCookieHeader()
+	}
+	p.cookieMu.Unlock()
+	return changed
+}
+
+// notifyCookies hands a snapshot of the session cookies to the refresh
+// handler.
+func (p *Provider) notifyCookies(rotatedAt time.Time) {
+	if p.onCookieUpdate == nil {
+		return
+	}
+	p.cookieMu.Lock()
+	snapshot := make(map[string]string, len(p.cookies))
+	for k, v := range p.cookies {
+		snapshot[k] = v
+	}
+	p.cookieMu.Unlock()
+	p.onCookieUpdate(snapshot, rotatedAt)
+}
+
+// rotateCookies asks Google for a fresh __Secure-1PSIDTS when the last
+// rotation is stale. Failures are logged and otherwise ignored: the
+// current cookies may well still be valid.
+func (p *Provider) rotateCookies(ctx context.Context, logf func(string, ...any)) {
+	if p.cookies[cookiePSID] == "" || time.Since(p.lastRotated) < rotateInterval {
+		return
+	}
+
+	logf("[gemini] POST %s", rotateCookiesURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rotateCookiesURL, strings.NewReader(rotateBody))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", "https://accounts.google.com")
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("Cookie", p.cookieHeader)
+
+	// Bypass the capturing transport so the handler runs once, with the
+	// rotation time.
+	client := &http.Client{Timeout: p.timeout, Transport: p.transport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		logf("[gemini] cookie rotation failed: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		logf("[gemini] cookie rotation failed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		return
+	}
+
+	now := time.Now()
+	p.lastRotated = now
+	if p.captureCookies(resp) {
+		logf("[gemini] session cookies rotated")
+	} else {
+		logf("[gemini] cookie rotation returned no new cookies")
+	}
+	p.notifyCookies(now)
+}
+
+// cookieTransport captures rotated session cookies from every response.
+type cookieTransport struct {
+	p    *Provider
+	base http.RoundTripper
+}
+
+func (t *cookieTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.p.captureCookies(resp) {
+		t.p.notifyCookies(time.Time{})
+	}
+	return resp, nil
+}
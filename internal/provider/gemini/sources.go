@@ -0,0 +1,72 @@
+// Package gemini — sources.go finds the pages a reply cites.
+//
+// When Gemini grounds a reply in a search, the candidate carries each
+// cited page as an array holding its URL and, nearby, its title. The
+// layout is undocumented, so any web URL in the response that is not
+// Google's own is taken as a source, titled by the first short string
+// beside it.
+package gemini
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// maxSourceTitle bounds what counts as a title; longer strings are text.
+const maxSourceTitle = 300
+
+// findSources collects the distinct cited pages in a response node.
+func findSources(node any, seen map[string]bool, out *[]provider.Source) {
+	switch v := node.(type) {
+	case []any:
+		for _, child := range v {
+			u, ok := child.(string)
+			if !ok || !isSourceURL(u) || seen[u] {
+				continue
+			}
+			seen[u] = true
+			*out = append(*out, provider.Source{Name: siblingTitle(v), URL: u})
+		}
+		for _, child := range v {
+			findSources(child, seen, out)
+		}
+	case map[string]any:
+		for _, child := range v {
+			findSources(child, seen, out)
+		}
+	}
+}
+
+// isSourceURL reports whether s is a web page outside Google's own hosts.
+func isSourceURL(s string) bool {
+	if !strings.HasPrefix(s, "https://") && !strings.HasPrefix(s, "http://") {
+		return false
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, google := range []string{"google.com", "gstatic.com", "googleusercontent.com", "googleapis.com"} {
+		if host == google || strings.HasSuffix(host, "."+google) {
+			return false
+		}
+	}
+	return true
+}
+
+// siblingTitle returns the first title-like string in arr.
+func siblingTitle(arr []any) string {
+	for _, child := range arr {
+		s, ok := child.(string)
+		if !ok || strings.Contains(s, "://") || strings.ContainsRune(s, '\n') {
+			continue
+		}
+		if s = strings.TrimSpace(s); len(s) > 1 && len(s) <= maxSourceTitle {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,165 @@
+// Package gemini — transcript.go reads a whole conversation back.
+//
+// The hNvQHb RPC lists a conversation's turns newest first. Each turn is
+// an array that starts with its [cid, rid] pair, holds the prompt at
+// [2][0][0], and carries the reply candidates as ["rc_...", [text], ...]
+// arrays further on, the first of which is the reply shown.
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// maxTranscriptTurns is how many turns a transcript fetch asks for.
+const maxTranscriptTurns = 1000
+
+// FetchTranscript returns every prompt and reply of a conversation.
+func (p *Provider) FetchTranscript(ctx context.Context, conversationID string, opts provider.TranscriptOptions) (*provider.Transcript, error) {
+	if p.cookieHeader == "" {
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no cookies — log in to gemini.google.com in your browser")
+	}
+	conversationID = strings.TrimSpace(conversationID)
+	if conversationID == "" {
+		return nil, fmt.Errorf("conversation ID is required")
+	}
+	if !strings.HasPrefix(conversationID, "c_") {
+		conversationID = "c_" + conversationID
+	}
+
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	if p.snlm0e == "" {
+		if err := p.initialize(ctx, logf); err != nil {
+			return nil, fmt.Errorf("initialize: %w", err)
+		}
+	}
+
+	text, err := p.batchExecute(ctx, rpcReadConversation, []any{conversationID, maxTranscriptTurns, nil, 1, []any{0}, []any{4}, nil, 1}, logf)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &provider.Transcript{ID: conversationID}
+	forEachRPCResult(text, rpcReadConversation, func(inner any) {
+		if len(t.Messages) == 0 {
+			t.Messages = parseTranscript(inner, conversationID)
+		}
+	})
+	if len(t.Messages) == 0 {
+		return nil, fmt.Errorf("no turns found in conversation %s", conversationID)
+	}
+	for _, m := range t.Messages {
+		if m.CreatedAt.IsZero() {
+			continue
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = m.CreatedAt
+		}
+		t.UpdatedAt = m.CreatedAt
+	}
+	logf("[gemini] conversation %s: %d messages", conversationID, len(t.Messages))
+	return t, nil
+}
+
+// parseTranscript turns the turns of a read-conversation response into
+// messages, oldest first.
+func parseTranscript(node any, conversationID string) []provider.Message {
+	var turns [][]any
+	var walk func(v any)
+	walk = func(v any) {
+		arr, ok := v.([]any)
+		if !ok {
+			return
+		}
+		if isTurn(arr, conversationID) {
+			turns = append(turns, arr)
+			return
+		}
+		for _, child := range arr {
+			walk(child)
+		}
+	}
+	walk(node)
+	slices.Reverse(turns)
+
+	var msgs []provider.Message
+	for _, turn := range turns {
+		created := turnTime(turn)
+		if prompt, ok := at(turn, 2, 0, 0).(string); ok && strings.TrimSpace(prompt) != "" {
+			msgs = append(msgs, provider.Message{Role: "user", Text: strings.TrimSpace(prompt), CreatedAt: created})
+		}
+		if candidate := firstCandidate(turn[3:]); candidate != nil {
+			text, _ := at(candidate, 1, 0).(string)
+			if text = stripImagePlaceholders(text); text != "" {
+				msg := provider.Message{Role: "assistant", Text: text, CreatedAt: created}
+				findSources(candidate, map[string]bool{}, &msg.Sources)
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+	return msgs
+}
+
+// isTurn reports whether arr is one turn of conversationID: an array
+// opening with its [cid, rid] pair.
+func isTurn(arr []any, conversationID string) bool {
+	if len(arr) < 4 {
+		return false
+	}
+	ids, ok := arr[0].([]any)
+	if !ok || len(ids) < 2 {
+		return false
+	}
+	cid, _ := ids[0].(string)
+	rid, _ := ids[1].(string)
+	return cid == conversationID && strings.HasPrefix(rid, "r_")
+}
+
+// firstCandidate finds the first ["rc_...", [text, ...], ...] array.
+func firstCandidate(node any) []any {
+	arr, ok := node.([]any)
+	if !ok {
+		return nil
+	}
+	if id, ok := at(arr, 0).(string); ok && strings.HasPrefix(id, "rc_") {
+		if _, ok := at(arr, 1, 0).(string); ok {
+			return arr
+		}
+	}
+	for _, child := range arr {
+		if c := firstCandidate(child); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// turnTime reads a turn's [seconds, nanos] timestamp, its last element.
+func turnTime(turn []any) time.Time {
+	if secs, ok := at(turn, len(turn)-1, 0).(float64); ok && secs > 1e9 {
+		nanos, _ := at(turn, len(turn)-1, 1).(float64)
+		return time.Unix(int64(secs), int64(nanos))
+	}
+	return time.Time{}
+}
+
+// at follows path through nested arrays, returning nil when it leads
+// nowhere.
+func at(v any, path ...int) any {
+	for _, i := range path {
+		arr, ok := v.([]any)
+		if !ok || i < 0 || i >= len(arr) {
+			return nil
+		}
+		v = arr[i]
+	}
+	return v
+}
@@ -0,0 +1,112 @@
+// Package gemini implements the Google Gemini web API provider.
+//
+// upload.go pushes local files (typically screenshots) to Google's content
+// push service. The returned file reference is embedded in the
+// StreamGenerate prompt so Gemini can see the file.
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	uploadURL    = "https://content-push.googleapis.com/upload"
+	uploadPushID = "feeds/mcudyrk2a4khkz"
+
+	// maxUploadSize mirrors the Gemini web per-file limit.
+	maxUploadSize = 100 << 20
+)
+
+// uploadedFile is a file reference accepted by StreamGenerate.
+type uploadedFile struct {
+	ref  string
+	name string
+}
+
+// uploadFiles uploads each path and returns references in order.
+func (p *Provider) uploadFiles(ctx context.Context, paths []string, logf func(string, ...any)) ([]uploadedFile, error) {
+	var files []uploadedFile
+	for _, path := range paths {
+		f, err := p.uploadFile(ctx, path, logf)
+		if err != nil {
+			return nil, fmt.Errorf("attaching %s: %w", path, err)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func (p *Provider) uploadFile(ctx context.Context, path string, logf func(string, ...any)) (uploadedFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return uploadedFile{}, err
+	}
+	if len(data) > maxUploadSize {
+		return uploadedFile{}, fmt.Errorf("file is %d bytes, limit is %d", len(data), maxUploadSize)
+	}
+	name := filepath.Base(path)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		return uploadedFile{}, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return uploadedFile{}, err
+	}
+	if err := mw.Close(); err != nil {
+		return uploadedFile{}, err
+	}
+
+	logf("[gemini] POST %s (%s, %d bytes)", uploadURL, name, len(data))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, &body)
+	if err != nil {
+		return uploadedFile{}, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Push-ID", uploadPushID)
+	req.Header.Set("Origin", geminiBaseURL)
+	req.Header.Set("Referer", geminiBaseURL+"/")
+	req.Header.Set("User-Agent", p.userAgent)
+	req.Header.Set("Cookie", p.cookieHeader)
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return uploadedFile{}, fmt.Errorf("upload request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	text, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return uploadedFile{}, fmt.Errorf("upload returned %s: %s", resp.Status, string(text))
+	}
+
+	ref := strings.TrimSpace(string(text))
+	if ref == "" {
+		return uploadedFile{}, fmt.Errorf("upload returned an empty file reference")
+	}
+	return uploadedFile{ref: ref, name: name}, nil
+}
+
+// promptPart builds the first element of the StreamGenerate message: the
+// prompt alone, or the prompt followed by its file references.
+func promptPart(prompt string, files []uploadedFile) []any {
+	if len(files) == 0 {
+		return []any{prompt}
+	}
+	refs := make([]any, 0, len(files))
+	for _, f := range files {
+		refs = append(refs, []any{[]any{f.ref, 1}, f.name})
+	}
+	return []any{prompt, 0, nil, refs}
+}
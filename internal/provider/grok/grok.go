@@ -69,6 +69,30 @@ func ResolveModel(input string) string {
 	return input
 }
 
+// --- personas ---
+
+// personas maps a persona name to the systemPromptName that selects it.
+var personas = map[string]string{
+	"regular": "",
+	"normal":  "",
+	"fun":     "fun",
+}
+
+// PersonaNames lists the personas ResolvePersona accepts.
+var PersonaNames = []string{"regular", "fun"}
+
+// ResolvePersona maps a persona name to its systemPromptName. An empty
+// name is the regular persona.
+func ResolvePersona(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	if id, ok := personas[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("unknown Grok persona %q (use %s)", name, strings.Join(PersonaNames, ", "))
+}
+
 // --- feature flags ---
 
 func buildGrokFeatures() map[string]bool {
@@ -123,12 +147,15 @@ func buildGrokFeatures() map[string]bool {
 type Provider struct {
 	userAgent string
 	timeout   time.Duration
+	proxy     string
 	authToken string
 	ct0       string
 
 	txnGen     *transactionGenerator
 	deepsearch bool
 	reasoning  bool
+	// persona is the systemPromptName sent with each message.
+	persona string
 }
 
 // New creates a Grok provider.
@@ -147,6 +174,10 @@ func (p *Provider) CookieSpecs() []provider.CookieSpec {
 	}
 }
 
+// SetProxy routes requests through proxyURL instead of the environment's
+// HTTPS_PROXY/ALL_PROXY.
+func (p *Provider) SetProxy(proxyURL string) { p.proxy = proxyURL }
+
 func (p *Provider) SetCookies(cookies map[string]string) {
 	if v := cookies[cookieAuthToken]; v != "" {
 		p.authToken = v
@@ -161,19 +192,25 @@ func (p *Provider) SetDeepSearch(enabled bool) { p.deepsearch = enabled }
 
 // SetReasoning enables or disables reasoning mode.
 func (p *Provider) SetReasoning(enabled bool) { p.reasoning = enabled }
+
+// SetPersona selects a persona by its systemPromptName (see
+// ResolvePersona); empty is the regular one.
+func (p *Provider) SetPersona(systemPromptName string) { p.persona = systemPromptName }
+
 func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
 	if p.authToken == "" || p.ct0 == "" {
-		return fmt.Errorf("missing X.com cookies (auth_token, ct0) — log into x.com in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "missing X.com cookies (auth_token, ct0) — log into x.com in your browser")
 	}
 
 	logf := opts.LogFunc
 	if logf == nil {
 		logf = func(string, ...any) {}
 	}
+	query = provider.PrependSystemPrompt(query, opts)
 
 	// Lazily init the transaction generator.
 	if p.txnGen == nil {
-		p.txnGen = newTransactionGenerator(p.userAgent, logf)
+		p.txnGen = newTransactionGenerator(p.userAgent, p.proxy, logf)
 	}
 
 	model := ResolveModel(opts.Model)
@@ -254,7 +291,7 @@ func (p *Provider) doRequest(ctx context.Context, method, reqURL string, body io
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	return client.Do(req)
 }
 
@@ -285,7 +322,7 @@ func (p *Provider) createConversation(ctx context.Context, logf func(string, ...
 		}
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			text, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-			return "", fmt.Errorf("create conversation HTTP %d: %s", resp.StatusCode, string(text))
+			return "", fmt.Errorf("create conversation %w", provider.HTTPError(resp.StatusCode, text))
 		}
 
 		text, err := io.ReadAll(resp.Body)
@@ -322,7 +359,7 @@ func (p *Provider) sendMessage(ctx context.Context, conversationID, query, model
 				"fileAttachments": []any{},
 			},
 		},
-		"systemPromptName":    "",
+		"systemPromptName":    p.persona,
 		"grokModelOptionId":   model,
 		"conversationId":      conversationID,
 		"returnSearchResults": true,
@@ -381,7 +418,7 @@ func (p *Provider) sendMessage(ctx context.Context, conversationID, query, model
 			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 				body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
 				resp.Body.Close()
-				lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncStr(string(body), 200))
+				lastErr = &provider.StatusError{Status: resp.StatusCode, Body: truncStr(string(body), 200)}
 				continue
 			}
 			// Read full body for debugging, then parse NDJSON.
@@ -406,7 +443,7 @@ func (p *Provider) sendMessage(ctx context.Context, conversationID, query, model
 				}
 			}
 
-			if err := p.readNDJSON(bytes.NewReader(bodyBytes), wrapped); err != nil {
+			if err := p.readNDJSON(ctx, bytes.NewReader(bodyBytes), wrapped); err != nil {
 				return err
 			}
 			if sawText {
@@ -467,16 +504,71 @@ func (p *Provider) emitFallbackMessage(ctx context.Context, conversationID strin
 }
 
 func (p *Provider) fetchLatestAssistantMessage(ctx context.Context, conversationID string, logf func(string, ...any)) (string, error) {
+	conv, err := p.fetchConversationItems(ctx, conversationID, logf)
+	if err != nil || conv == nil {
+		return "", err
+	}
+
+	for i := len(conv.Items) - 1; i >= 0; i-- {
+		item := conv.Items[i]
+		if item.isAssistant() && strings.TrimSpace(item.Message) != "" {
+			return item.Message, nil
+		}
+	}
+	for i := len(conv.Messages) - 1; i >= 0; i-- {
+		msg := conv.Messages[i]
+		if msg.Sender == 2 && strings.TrimSpace(msg.Message) != "" {
+			return msg.Message, nil
+		}
+	}
+	return "", nil
+}
+
+// grokConversation is the payload of GrokConversationItemsByRestId. Older
+// responses use messages with a numeric sender; newer ones use items.
+type grokConversation struct {
+	Messages []struct {
+		Message string `json:"message"`
+		Sender  int    `json:"sender"`
+	} `json:"messages"`
+	Items []grokConversationItem `json:"items"`
+}
+
+type grokConversationItem struct {
+	Message     string          `json:"message"`
+	SenderType  string          `json:"sender_type"`
+	CreatedAtMs int64           `json:"created_at_ms"`
+	GrokMode    string          `json:"grok_mode"`
+	WebResults  []grokWebResult `json:"web_results"`
+}
+
+// grokWebResult is a web page Grok consulted for an answer.
+type grokWebResult struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+func (it grokConversationItem) isAssistant() bool {
+	switch strings.ToLower(it.SenderType) {
+	case "agent", "assistant", "grok":
+		return true
+	}
+	return false
+}
+
+// fetchConversationItems loads every item in a conversation. It returns
+// nil without error when the conversation has no items yet.
+func (p *Provider) fetchConversationItems(ctx context.Context, conversationID string, logf func(string, ...any)) (*grokConversation, error) {
 	features := buildGrokFeatures()
 	variables := map[string]any{"restId": conversationID}
 
 	variablesJSON, err := json.Marshal(variables)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	featuresJSON, err := json.Marshal(features)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	params := url.Values{}
@@ -493,64 +585,122 @@ func (p *Provider) fetchLatestAssistantMessage(ctx context.Context, conversation
 		body, readErr := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if readErr != nil {
-			return "", readErr
+			return nil, readErr
 		}
 		if resp.StatusCode == 404 {
-			logf("[grok] fallback conversation query %s returned 404", queryID)
+			logf("[grok] conversation query %s returned 404", queryID)
 			continue
 		}
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			logf("[grok] fallback conversation HTTP %d body=%s", resp.StatusCode, truncStr(string(body), 300))
-			return "", fmt.Errorf("fetch conversation HTTP %d: %s", resp.StatusCode, truncStr(string(body), 200))
+			logf("[grok] conversation HTTP %d body=%s", resp.StatusCode, truncStr(string(body), 300))
+			return nil, fmt.Errorf("fetch conversation %w", &provider.StatusError{Status: resp.StatusCode, Body: truncStr(string(body), 200)})
 		}
-		logf("[grok] fallback conversation body: %s", truncStr(string(body), 300))
+		logf("[grok] conversation body: %s", truncStr(string(body), 300))
 
 		var payload struct {
 			Data struct {
-				Conversation *struct {
-					Messages []struct {
-						Message string `json:"message"`
-						Sender  int    `json:"sender"`
-					} `json:"messages"`
-					Items []struct {
-						Message    string `json:"message"`
-						SenderType string `json:"sender_type"`
-					} `json:"items"`
-				} `json:"grok_conversation_items_by_rest_id"`
+				Conversation *grokConversation `json:"grok_conversation_items_by_rest_id"`
 			} `json:"data"`
 		}
 		if err := json.Unmarshal(body, &payload); err != nil {
-			return "", fmt.Errorf("parsing conversation response: %w", err)
+			return nil, fmt.Errorf("parsing conversation response: %w", err)
 		}
+		return payload.Data.Conversation, nil
+	}
 
-		if payload.Data.Conversation == nil {
-			return "", nil
-		}
+	return nil, fmt.Errorf("all GrokConversationItemsByRestId query IDs exhausted")
+}
+
+// FetchTranscript fetches every message in a Grok conversation.
+func (p *Provider) FetchTranscript(ctx context.Context, conversationID string, opts provider.TranscriptOptions) (*provider.Transcript, error) {
+	if p.authToken == "" || p.ct0 == "" {
+		return nil, provider.Errorf(provider.ErrAuthExpired, "missing X.com cookies (auth_token, ct0) — log into x.com in your browser")
+	}
+
+	conversationID = strings.TrimSpace(conversationID)
+	if conversationID == "" {
+		return nil, fmt.Errorf("conversation ID is required")
+	}
+
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	conv, err := p.fetchConversationItems(ctx, conversationID, logf)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &provider.Transcript{ID: conversationID}
+	if conv == nil {
+		return t, nil
+	}
 
-		for i := len(payload.Data.Conversation.Items) - 1; i >= 0; i-- {
-			item := payload.Data.Conversation.Items[i]
-			sender := strings.ToLower(item.SenderType)
-			if (sender == "agent" || sender == "assistant" || sender == "grok") && strings.TrimSpace(item.Message) != "" {
-				return item.Message, nil
+	for _, item := range conv.Items {
+		text := strings.TrimSpace(item.Message)
+		if text == "" {
+			continue
+		}
+		msg := provider.Message{Role: "user", Text: text}
+		if item.isAssistant() {
+			msg.Role = "assistant"
+			msg.Model = item.GrokMode
+			for _, r := range item.WebResults {
+				if r.URL != "" {
+					msg.Sources = append(msg.Sources, provider.Source{Name: r.Title, URL: r.URL})
+				}
 			}
 		}
-		for i := len(payload.Data.Conversation.Messages) - 1; i >= 0; i-- {
-			msg := payload.Data.Conversation.Messages[i]
-			if msg.Sender == 2 && strings.TrimSpace(msg.Message) != "" {
-				return msg.Message, nil
+		if item.CreatedAtMs > 0 {
+			msg.CreatedAt = time.UnixMilli(item.CreatedAtMs)
+			if t.CreatedAt.IsZero() {
+				t.CreatedAt = msg.CreatedAt
 			}
+			t.UpdatedAt = msg.CreatedAt
+		}
+		t.Messages = append(t.Messages, msg)
+	}
+
+	// Older payloads only carry messages; sender 1 is the user, 2 is Grok.
+	if len(conv.Items) == 0 {
+		for _, m := range conv.Messages {
+			text := strings.TrimSpace(m.Message)
+			if text == "" {
+				continue
+			}
+			role := "user"
+			if m.Sender == 2 {
+				role = "assistant"
+			}
+			t.Messages = append(t.Messages, provider.Message{Role: role, Text: text})
 		}
-		return "", nil
 	}
 
-	return "", fmt.Errorf("all GrokConversationItemsByRestId query IDs exhausted")
+	// The items query returns newest first on some deployments.
+	if n := len(t.Messages); n > 1 && t.Messages[0].CreatedAt.After(t.Messages[n-1].CreatedAt) {
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			t.Messages[i], t.Messages[j] = t.Messages[j], t.Messages[i]
+		}
+		t.CreatedAt, t.UpdatedAt = t.UpdatedAt, t.CreatedAt
+	}
+
+	logf("[grok] transcript has %d message(s)", len(t.Messages))
+	return t, nil
 }
 
 // readNDJSON reads newline-delimited JSON from r, calling opts.OnText
 // for each message chunk. This provides real-time streaming output.
-func (p *Provider) readNDJSON(r io.Reader, opts provider.AskOptions) error {
+// Generated images are delivered once the stream ends.
+func (p *Provider) readNDJSON(ctx context.Context, r io.Reader, opts provider.AskOptions) error {
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
 	scanner := bufio.NewScanner(r)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var images imageCollector
+	seenSources := map[string]bool{}
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -560,7 +710,14 @@ func (p *Provider) readNDJSON(r io.Reader, opts provider.AskOptions) error {
 
 		var obj struct {
 			Result struct {
-				Message string `json:"message"`
+				Message         string               `json:"message"`
+				ImageAttachment *grokImageAttachment `json:"imageAttachment"`
+				// WebResults are the pages searched for the answer,
+				// DeepSearch's sites included.
+				WebResults []grokWebResult `json:"webResults"`
+				Event      struct {
+					ImageAttachmentUpdate *grokImageUpdate `json:"imageAttachmentUpdate"`
+				} `json:"event"`
 			} `json:"result"`
 		}
 		if err := json.Unmarshal([]byte(line), &obj); err != nil {
@@ -569,12 +726,30 @@ func (p *Provider) readNDJSON(r io.Reader, opts provider.AskOptions) error {
 		if obj.Result.Message != "" && opts.OnText != nil {
 			opts.OnText(obj.Result.Message)
 		}
+		for _, r := range obj.Result.WebResults {
+			if r.URL == "" || seenSources[r.URL] || opts.OnSource == nil {
+				continue
+			}
+			seenSources[r.URL] = true
+			opts.OnSource(r.Title, r.URL)
+		}
+		if img := obj.Result.ImageAttachment; img != nil {
+			images.add(img.ImageURL, imageTitle(img.FileName))
+		}
+		if u := obj.Result.Event.ImageAttachmentUpdate; u != nil && u.Progress >= 100 {
+			images.add(u.ImageURL, "")
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
 		return fmt.Errorf("reading NDJSON stream: %w", err)
 	}
 
+	if len(images.images) > 0 {
+		logf("[grok] %d generated image(s)", len(images.images))
+		p.deliverImages(ctx, images.images, opts, logf)
+	}
+
 	if opts.OnDone != nil {
 		opts.OnDone()
 	}
@@ -630,7 +805,7 @@ type rawGrokHistoryItem struct {
 // ListConversations fetches recent Grok conversations via the GrokHistory GraphQL query.
 func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
 	if p.authToken == "" || p.ct0 == "" {
-		return nil, fmt.Errorf("missing X.com cookies (auth_token, ct0) \u2014 log into x.com in your browser")
+		return nil, provider.Errorf(provider.ErrAuthExpired, "missing X.com cookies (auth_token, ct0) \u2014 log into x.com in your browser")
 	}
 
 	logf := opts.LogFunc
@@ -638,10 +813,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 		logf = func(string, ...any) {}
 	}
 
-	limit := opts.Limit
-	if limit <= 0 {
-		limit = 20
-	}
+	limit := opts.Want(20)
 
 	features := buildGrokFeatures()
 
@@ -671,7 +843,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 		}
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-			return nil, fmt.Errorf("list conversations HTTP %d: %s", resp.StatusCode, string(body))
+			return nil, fmt.Errorf("list conversations %w", provider.HTTPError(resp.StatusCode, body))
 		}
 
 		var data struct {
@@ -710,7 +882,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 		}
 
 		logf("[grok] fetched %d conversations", len(result))
-		return result, nil
+		return provider.Page(result, opts), nil
 	}
 
 	return nil, fmt.Errorf("all GrokHistory query IDs exhausted")
@@ -718,7 +890,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 
 func (p *Provider) DeleteConversation(ctx context.Context, conversationID string, opts provider.DeleteOptions) error {
 	if p.authToken == "" || p.ct0 == "" {
-		return fmt.Errorf("missing X.com cookies (auth_token, ct0) — log into x.com in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "missing X.com cookies (auth_token, ct0) — log into x.com in your browser")
 	}
 
 	conversationID = strings.TrimSpace(conversationID)
@@ -731,8 +903,13 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 		logf = func(string, ...any) {}
 	}
 
+	// Write endpoints need a signed transaction ID.
+	if p.txnGen == nil {
+		p.txnGen = newTransactionGenerator(p.userAgent, p.proxy, logf)
+	}
+
 	if !strings.EqualFold(conversationID, "all") {
-		return fmt.Errorf("grok currently supports only bulk deletion; use 'ask grok delete all'")
+		return p.deleteConversation(ctx, conversationID, logf)
 	}
 
 	queryIDs := fallbackQueryIDs["ClearGrokConversations"]
@@ -760,13 +937,64 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return provider.HTTPError(resp.StatusCode, body)
 	}
 
 	logf("[grok] all conversations deleted")
 	return nil
 }
 
+// deleteConversation removes a single conversation with the
+// DeleteGrokConversation mutation.
+func (p *Provider) deleteConversation(ctx context.Context, conversationID string, logf func(string, ...any)) error {
+	queryIDs := p.queryIDs(ctx, "DeleteGrokConversation", logf)
+	if len(queryIDs) == 0 {
+		return fmt.Errorf("no DeleteGrokConversation query ID available; use 'ask grok delete all' to clear every conversation")
+	}
+
+	for _, queryID := range queryIDs {
+		payload, err := json.Marshal(map[string]any{
+			"variables": map[string]any{"conversationId": conversationID},
+			"queryId":   queryID,
+		})
+		if err != nil {
+			return fmt.Errorf("marshalling request: %w", err)
+		}
+
+		u := fmt.Sprintf("%s/%s/DeleteGrokConversation", twitterAPIBase, queryID)
+		logf("[grok] POST %s", u)
+
+		resp, err := p.doRequest(ctx, http.MethodPost, u, bytes.NewReader(payload), p.grokWriteHeaders(http.MethodPost, u))
+		if err != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+
+		if resp.StatusCode == 404 {
+			logf("[grok] DeleteGrokConversation query %s returned 404", queryID)
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return provider.HTTPError(resp.StatusCode, body)
+		}
+		// GraphQL reports failures in a 200 body.
+		var result struct {
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		if json.Unmarshal(body, &result) == nil && len(result.Errors) > 0 {
+			return fmt.Errorf("delete failed: %s", result.Errors[0].Message)
+		}
+
+		logf("[grok] conversation deleted")
+		return nil
+	}
+
+	return fmt.Errorf("all DeleteGrokConversation query IDs exhausted")
+}
+
 // --- Model catalog ---
 
 // ListModels returns the available Grok models and modes.
@@ -787,6 +1015,7 @@ func (p *Provider) ListModels() provider.ProviderModels {
 			{ID: "normal", Name: "Normal", Description: "Standard response", Default: true},
 			{ID: "deepsearch", Name: "DeepSearch", Description: "Deep web search mode (isDeepsearch=true)", Default: false},
 			{ID: "reasoning", Name: "Reasoning", Description: "Reasoning mode (isReasoning=true)", Default: false},
+			{ID: "fun", Name: "Fun persona", Description: "Witty, irreverent answers (--persona fun)", Default: false},
 		},
 	}
 }
@@ -0,0 +1,91 @@
+package grok
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// Generated images arrive in the add_response stream, either as progress
+// events (result.event.imageAttachmentUpdate, final at progress 100) or as
+// a finished attachment (result.imageAttachment). Their ton.x.com URLs
+// need the same session auth as the API.
+
+type grokImageAttachment struct {
+	ImageURL string `json:"imageUrl"`
+	FileName string `json:"fileName"`
+}
+
+type grokImageUpdate struct {
+	ImageURL string `json:"imageUrl"`
+	Progress int    `json:"progress"`
+}
+
+// imageCollector gathers the distinct finished images of one reply.
+type imageCollector struct {
+	index  map[string]int
+	images []provider.GeneratedImage
+}
+
+// add records an image, filling in the title of one already seen.
+func (c *imageCollector) add(imageURL, title string) {
+	if imageURL == "" {
+		return
+	}
+	if i, ok := c.index[imageURL]; ok {
+		if c.images[i].Title == "" {
+			c.images[i].Title = title
+		}
+		return
+	}
+	if c.index == nil {
+		c.index = make(map[string]int)
+	}
+	c.index[imageURL] = len(c.images)
+	c.images = append(c.images, provider.GeneratedImage{URL: imageURL, Title: title})
+}
+
+// deliverImages downloads the reply's images to opts.ImageDir, if set,
+// and reports each through opts.OnImage. A failed download is logged and
+// the image is still reported by URL.
+func (p *Provider) deliverImages(ctx context.Context, images []provider.GeneratedImage, opts provider.AskOptions, logf func(string, ...any)) {
+	stamp := time.Now().Format("20060102-150405")
+	for i, img := range images {
+		if opts.ImageDir != "" {
+			name := fmt.Sprintf("grok-%s-%d", stamp, i+1)
+			saved, err := p.downloadImage(ctx, img.URL, opts.ImageDir, name, logf)
+			if err != nil {
+				logf("[grok] warning: could not save image %d: %v", i+1, err)
+			}
+			img.Path = saved
+		}
+		if opts.OnImage != nil {
+			opts.OnImage(img)
+		}
+	}
+}
+
+// downloadImage fetches a generated image through the authenticated media
+// endpoint.
+func (p *Provider) downloadImage(ctx context.Context, imageURL, dir, name string, logf func(string, ...any)) (string, error) {
+	logf("[grok] GET %s", imageURL)
+	headers := p.baseHeaders()
+	headers["accept"] = "image/avif,image/webp,image/png,image/*;q=0.8"
+	headers["referer"] = "https://x.com/i/grok"
+	resp, err := p.doRequest(ctx, http.MethodGet, imageURL, nil, headers)
+	if err != nil {
+		return "", err
+	}
+	return provider.SaveImage(resp, dir, name)
+}
+
+// imageTitle is a readable caption for an attachment: its file name
+// without the extension.
+func imageTitle(fileName string) string {
+	return strings.TrimSuffix(fileName, path.Ext(fileName))
+}
@@ -0,0 +1,81 @@
+// Package grok implements the Grok (X.com) web API provider.
+//
+// queryid.go discovers GraphQL query IDs from the X web client bundle.
+// Query IDs rotate with each web deploy; the hardcoded fallbacks in
+// fallbackQueryIDs go stale, so operations without a known-good ID are
+// looked up in main.<hash>.js at runtime.
+package grok
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+var reMainBundle = regexp.MustCompile(`https://abs\.twimg\.com/responsive-web/client-web/main\.[0-9a-f]+[a-z]?\.js`)
+
+// queryIDs returns candidate query IDs for a GraphQL operation: the
+// hardcoded fallbacks first, then the ID found in the live web bundle.
+func (p *Provider) queryIDs(ctx context.Context, operation string, logf func(string, ...any)) []string {
+	ids := append([]string(nil), fallbackQueryIDs[operation]...)
+
+	id, err := p.discoverQueryID(ctx, operation)
+	if err != nil {
+		logf("[grok] query ID discovery for %s failed: %v", operation, err)
+		return ids
+	}
+	logf("[grok] discovered %s query ID %s", operation, id)
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+// discoverQueryID fetches the X homepage, follows it to the main client
+// bundle, and extracts the query ID registered for operation.
+func (p *Provider) discoverQueryID(ctx context.Context, operation string) (string, error) {
+	home, err := p.fetchText(ctx, "https://x.com")
+	if err != nil {
+		return "", fmt.Errorf("fetching X homepage: %w", err)
+	}
+	bundleURL := reMainBundle.FindString(home)
+	if bundleURL == "" {
+		return "", fmt.Errorf("main bundle not found on X homepage")
+	}
+
+	bundle, err := p.fetchText(ctx, bundleURL)
+	if err != nil {
+		return "", fmt.Errorf("fetching %s: %w", bundleURL, err)
+	}
+
+	re := regexp.MustCompile(`queryId:"([\w-]+)",operationName:"` + regexp.QuoteMeta(operation) + `"`)
+	m := re.FindStringSubmatch(bundle)
+	if len(m) < 2 {
+		return "", fmt.Errorf("operation %s not found in main bundle", operation)
+	}
+	return m[1], nil
+}
+
+func (p *Provider) fetchText(ctx context.Context, u string) (string, error) {
+	resp, err := p.doRequest(ctx, http.MethodGet, u, nil, map[string]string{
+		"accept":          "*/*",
+		"accept-language": "en-US,en;q=0.9",
+		"user-agent":      p.userAgent,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
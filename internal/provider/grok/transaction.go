@@ -21,6 +21,8 @@ import (
 	"sync"
 	"time"
 	"unicode"
+
+	"github.com/kyupark/ask/internal/httpclient"
 )
 
 const (
@@ -53,16 +55,26 @@ type transactionGenerator struct {
 	cachedAt          time.Time
 
 	userAgent string
+	proxy     string
 	logf      func(string, ...any)
 }
 
-func newTransactionGenerator(userAgent string, logf func(string, ...any)) *transactionGenerator {
+func newTransactionGenerator(userAgent, proxy string, logf func(string, ...any)) *transactionGenerator {
 	return &transactionGenerator{
 		userAgent: userAgent,
+		proxy:     proxy,
 		logf:      logf,
 	}
 }
 
+// client returns a plain HTTP client using the provider's proxy.
+func (g *transactionGenerator) client() *http.Client {
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: httpclient.PlainTransport(g.proxy),
+	}
+}
+
 // generateID produces a transaction ID for the given HTTP method + path.
 func (g *transactionGenerator) generateID(method, path string) (string, error) {
 	g.mu.Lock()
@@ -138,7 +150,7 @@ func (g *transactionGenerator) fetchHomepageHTML() (string, error) {
 	req.Header.Set("accept-language", "en-US,en;q=0.9")
 	req.Header.Set("user-agent", g.userAgent)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := g.client()
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
@@ -161,7 +173,7 @@ func (g *transactionGenerator) getIndices() (int, []int, error) {
 	}
 
 	onDemandURL := fmt.Sprintf("https://abs.twimg.com/responsive-web/client-web/ondemand.s.%sa.js", match[1])
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := g.client()
 	resp, err := client.Get(onDemandURL)
 	if err != nil {
 		return 0, nil, fmt.Errorf("fetching ondemand file: %w", err)
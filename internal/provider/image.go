@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GeneratedImage is an image a provider created for an answer.
+type GeneratedImage struct {
+	URL string
+	// Title is the image's alt text or caption, when given.
+	Title string
+	// Path is where the image was saved; empty when AskOptions.ImageDir
+	// is unset or the download failed.
+	Path string
+}
+
+// SaveImage writes an image response body to dir as name plus an
+// extension taken from its Content-Type, creating dir if needed. It
+// returns the file's path and closes the body.
+func SaveImage(resp *http.Response, dir, name string) (string, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading image: HTTP %d", resp.StatusCode)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	ext := ".png"
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, _ := mime.ParseMediaType(ct)
+		if !strings.HasPrefix(mediaType, "image/") {
+			return "", fmt.Errorf("downloading image: unexpected content type %q", ct)
+		}
+		switch mediaType {
+		case "image/jpeg":
+			ext = ".jpg"
+		case "image/webp":
+			ext = ".webp"
+		case "image/gif":
+			ext = ".gif"
+		}
+	}
+
+	path := filepath.Join(dir, name+ext)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("downloading image: %w", err)
+	}
+	return path, f.Close()
+}
+
+// MediaResult is an image or video a search turned up for an answer.
+type MediaResult struct {
+	// Kind is "image" or "video".
+	Kind  string
+	Title string
+	// URL is the image or video itself; PageURL is the page it was found on.
+	URL     string
+	PageURL string
+	// Thumbnail is a preview image URL, when given.
+	Thumbnail string
+	// Path is where the thumbnail was saved; empty when
+	// AskOptions.ImageDir is unset or the download failed.
+	Path string
+}
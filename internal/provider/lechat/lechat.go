@@ -0,0 +1,434 @@
+// Package lechat implements the Mistral Le Chat (chat.mistral.ai) web API
+// provider.
+//
+// Le Chat's web app talks tRPC for bookkeeping (creating, listing and
+// deleting chats) and streams answers from /api/chat. A new chat is
+// created with its first message, then streamed with mode "start"; a
+// follow-up is streamed with mode "append" and the new message.
+package lechat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+const (
+	defaultBaseURL = "https://chat.mistral.ai"
+	trpcPath       = "/api/trpc/"
+	chatPath       = "/api/chat"
+
+	// SessionCookieName is the Ory session cookie for Mistral's login
+	// project.
+	SessionCookieName = "ory_session_coolcurranf83m3srkfl"
+	domainMistral     = "mistral.ai"
+
+	defaultModel = "mistral-medium-latest"
+)
+
+// --- Request/Response types ---
+
+type contentPart struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type newChatInput struct {
+	Content   []contentPart `json:"content"`
+	Model     string        `json:"model"`
+	Incognito bool          `json:"incognito"`
+	AgentID   *string       `json:"agentId"`
+	Features  []string      `json:"features"`
+}
+
+type newChatOutput struct {
+	ChatID string `json:"chatId"`
+}
+
+type chatRequest struct {
+	ChatID       string        `json:"chatId"`
+	Mode         string        `json:"mode"`
+	Model        string        `json:"model,omitempty"`
+	MessageID    string        `json:"messageId,omitempty"`
+	MessageInput []contentPart `json:"messageInput,omitempty"`
+	Features     []string      `json:"features"`
+}
+
+type chatListItem struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// Provider implements the Le Chat web API backend.
+type Provider struct {
+	baseURL       string
+	userAgent     string
+	timeout       time.Duration
+	proxy         string
+	sessionCookie string
+}
+
+// New creates a Le Chat provider.
+func New(baseURL, userAgent string, timeout time.Duration) *Provider {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Provider{
+		baseURL:   baseURL,
+		userAgent: userAgent,
+		timeout:   timeout,
+	}
+}
+
+func (p *Provider) Name() string { return "lechat" }
+
+func (p *Provider) CookieSpecs() []provider.CookieSpec {
+	return []provider.CookieSpec{
+		{Domain: domainMistral, Names: []string{SessionCookieName}},
+	}
+}
+
+// SetProxy routes requests through proxyURL instead of the environment's
+// HTTPS_PROXY/ALL_PROXY.
+func (p *Provider) SetProxy(proxyURL string) { p.proxy = proxyURL }
+
+func (p *Provider) SetCookies(cookies map[string]string) {
+	if v := cookies[SessionCookieName]; v != "" {
+		p.sessionCookie = v
+	}
+}
+
+func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
+	if p.sessionCookie == "" {
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chat.mistral.ai in your browser")
+	}
+
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	query = provider.PrependSystemPrompt(query, opts)
+
+	model := opts.Model
+	if model == "" {
+		model = defaultModel
+	}
+	logf("[lechat] model=%s", model)
+
+	req := chatRequest{ChatID: opts.ConversationID, Features: []string{}}
+	if req.ChatID == "" {
+		// 1. A new chat is created together with its first message.
+		var out newChatOutput
+		err := p.trpcMutation(ctx, "message.newChat", newChatInput{
+			Content:   []contentPart{{Type: "text", Text: query}},
+			Model:     model,
+			Incognito: opts.Temporary,
+			Features:  []string{},
+		}, &out, logf)
+		if err != nil {
+			return fmt.Errorf("creating chat: %w", err)
+		}
+		if out.ChatID == "" {
+			return fmt.Errorf("creating chat: no chat ID in response")
+		}
+		req.ChatID = out.ChatID
+		req.Mode = "start"
+	} else {
+		// 2. A follow-up is appended to the existing chat.
+		req.Mode = "append"
+		req.Model = model
+		req.MessageID = newUUID()
+		req.MessageInput = []contentPart{{Type: "text", Text: query}}
+	}
+	logf("[lechat] chat=%s mode=%s", req.ChatID, req.Mode)
+
+	if err := p.streamChat(ctx, req, opts, logf); err != nil {
+		return err
+	}
+	if opts.OnConversation != nil {
+		opts.OnConversation(req.ChatID, "", "")
+	}
+	return nil
+}
+
+// streamChat posts to the chat endpoint and streams the answer.
+func (p *Provider) streamChat(ctx context.Context, body chatRequest, opts provider.AskOptions, logf func(string, ...any)) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshalling request: %w", err)
+	}
+
+	u := p.baseURL + chatPath
+	logf("[lechat] POST %s", u)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	p.setHeaders(req, p.baseURL+"/chat/"+body.ChatID)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "*/*")
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return provider.HTTPError(resp.StatusCode, b)
+	}
+	return readStream(resp.Body, opts)
+}
+
+// streamLine is the payload of one "<n>:<json>" stream line. Text arrives
+// as JSON patches against the answer message; each append to a text path
+// is the next piece of the answer.
+type streamLine struct {
+	JSON struct {
+		Type    string `json:"type"`
+		Patches []struct {
+			Op    string          `json:"op"`
+			Path  string          `json:"path"`
+			Value json.RawMessage `json:"value"`
+		} `json:"patches"`
+		Message string `json:"message"`
+	} `json:"json"`
+}
+
+// readStream reads the answer stream, one tRPC-style "<n>:<json>" line
+// per event.
+func readStream(r io.Reader, opts provider.AskOptions) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		_, data, ok := strings.Cut(line, ":")
+		if !ok || data == "" {
+			continue
+		}
+
+		var ev streamLine
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			if opts.OnError != nil {
+				opts.OnError(fmt.Errorf("parsing event: %w", err))
+			}
+			continue
+		}
+		if ev.JSON.Type == "error" {
+			return fmt.Errorf("lechat: %s", ev.JSON.Message)
+		}
+
+		for _, patch := range ev.JSON.Patches {
+			if text := patchText(patch.Op, patch.Path, patch.Value); text != "" && opts.OnText != nil {
+				opts.OnText(text)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading stream: %w", err)
+	}
+
+	if opts.OnDone != nil {
+		opts.OnDone()
+	}
+	return nil
+}
+
+// patchText returns the answer text a patch adds: an append to a text
+// field, or a new text chunk.
+func patchText(op, path string, value json.RawMessage) string {
+	switch op {
+	case "append":
+		if !strings.HasSuffix(path, "/text") {
+			return ""
+		}
+		var text string
+		_ = json.Unmarshal(value, &text)
+		return text
+	case "add", "replace":
+		if !strings.HasPrefix(path, "/contentChunks") {
+			return ""
+		}
+		var chunk contentPart
+		if json.Unmarshal(value, &chunk) == nil && chunk.Type == "text" {
+			return chunk.Text
+		}
+	}
+	return ""
+}
+
+// DeleteConversation deletes a chat.
+func (p *Provider) DeleteConversation(ctx context.Context, conversationID string, opts provider.DeleteOptions) error {
+	if strings.TrimSpace(conversationID) == "" {
+		return fmt.Errorf("conversation ID is required")
+	}
+	if p.sessionCookie == "" {
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chat.mistral.ai in your browser")
+	}
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	return p.trpcMutation(ctx, "chat.delete", map[string]string{"chatId": conversationID}, nil, logf)
+}
+
+// ListConversations fetches the user's recent chats.
+func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
+	if p.sessionCookie == "" {
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to chat.mistral.ai in your browser")
+	}
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	var out struct {
+		Items []chatListItem `json:"items"`
+	}
+	if err := p.trpcQuery(ctx, "chat.list", map[string]int{"limit": opts.Want(20)}, &out, logf); err != nil {
+		return nil, err
+	}
+
+	var conversations []provider.Conversation
+	for _, item := range out.Items {
+		conv := provider.Conversation{ID: item.ID, Title: item.Title}
+		if t, err := time.Parse(time.RFC3339Nano, item.CreatedAt); err == nil {
+			conv.CreatedAt = t
+		}
+		if t, err := time.Parse(time.RFC3339Nano, item.UpdatedAt); err == nil {
+			conv.UpdatedAt = t
+		}
+		conversations = append(conversations, conv)
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	return provider.Page(conversations, opts), nil
+}
+
+func (p *Provider) ListModels() provider.ProviderModels {
+	return provider.ProviderModels{
+		Provider: "lechat",
+		Models: []provider.ModelInfo{
+			{ID: "mistral-medium-latest", Name: "Mistral Medium", Description: "Le Chat's default model", Default: true, Tags: []string{"balanced"}},
+			{ID: "mistral-large-latest", Name: "Mistral Large", Description: "Largest general model", Default: false, Tags: []string{"flagship"}},
+			{ID: "magistral-medium-latest", Name: "Magistral Medium", Description: "Reasoning model (Think mode)", Default: false, Tags: []string{"reasoning"}},
+			{ID: "codestral-latest", Name: "Codestral", Description: "Code-focused model", Default: false, Tags: []string{"code"}},
+		},
+	}
+}
+
+// --- tRPC helpers ---
+
+// trpcResult is one entry of a batched tRPC response.
+type trpcResult struct {
+	Result struct {
+		Data struct {
+			JSON json.RawMessage `json:"json"`
+		} `json:"data"`
+	} `json:"result"`
+	Error *struct {
+		JSON struct {
+			Message string `json:"message"`
+		} `json:"json"`
+	} `json:"error"`
+}
+
+// trpcQuery calls a tRPC query procedure with GET.
+func (p *Provider) trpcQuery(ctx context.Context, procedure string, input, out any, logf func(string, ...any)) error {
+	data, err := json.Marshal(map[string]any{"0": map[string]any{"json": input}})
+	if err != nil {
+		return err
+	}
+	u := p.baseURL + trpcPath + procedure + "?batch=1&input=" + url.QueryEscape(string(data))
+	return p.trpc(ctx, http.MethodGet, u, nil, out, logf)
+}
+
+// trpcMutation calls a tRPC mutation procedure with POST.
+func (p *Provider) trpcMutation(ctx context.Context, procedure string, input, out any, logf func(string, ...any)) error {
+	data, err := json.Marshal(map[string]any{"0": map[string]any{"json": input}})
+	if err != nil {
+		return err
+	}
+	u := p.baseURL + trpcPath + procedure + "?batch=1"
+	return p.trpc(ctx, http.MethodPost, u, data, out, logf)
+}
+
+func (p *Provider) trpc(ctx context.Context, method, u string, body []byte, out any, logf func(string, ...any)) error {
+	logf("[lechat] %s %s", method, u)
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(req, p.baseURL+"/chat")
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return provider.HTTPError(resp.StatusCode, b)
+	}
+
+	var results []trpcResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("empty response")
+	}
+	if e := results[0].Error; e != nil {
+		return fmt.Errorf("lechat: %s", e.JSON.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(results[0].Result.Data.JSON, out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+func (p *Provider) setHeaders(req *http.Request, referer string) {
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("Origin", p.baseURL)
+	req.Header.Set("Referer", referer)
+	req.Header.Set("User-Agent", p.userAgent)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: p.sessionCookie})
+}
+
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,219 @@
+// Package localchat keeps conversations on disk for providers whose API is
+// stateless (Ollama, the Anthropic API): every request carries the whole
+// conversation, so the history has to live somewhere between questions.
+// Each conversation is a JSON file named <id>.json in the store's
+// directory.
+package localchat
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// Conversation is one saved chat.
+type Conversation struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Model     string    `json:"model,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Messages  []Message `json:"messages"`
+}
+
+// Message is one turn of a saved chat.
+type Message struct {
+	Role      string    `json:"role"` // "system", "user" or "assistant"
+	Content   string    `json:"content"`
+	Model     string    `json:"model,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// DropLastTurn removes the last question and its answer so the question
+// can be asked again.
+func (c *Conversation) DropLastTurn() {
+	for i := len(c.Messages) - 1; i >= 0; i-- {
+		if c.Messages[i].Role == "user" {
+			c.Messages = c.Messages[:i]
+			return
+		}
+	}
+}
+
+// Store saves conversations under a directory. A Store with no directory
+// saves nothing.
+type Store struct {
+	dir    string
+	prefix string
+}
+
+// New returns a store in dir whose conversation IDs start with prefix
+// (e.g. "ol-").
+func New(dir, prefix string) *Store {
+	return &Store{dir: dir, prefix: prefix}
+}
+
+// Enabled reports whether conversations are saved.
+func (s *Store) Enabled() bool { return s.dir != "" }
+
+// Create starts an unsaved conversation titled after its first question.
+func (s *Store) Create(query string) *Conversation {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	title := strings.Join(strings.Fields(query), " ")
+	if r := []rune(title); len(r) > 60 {
+		title = string(r[:60]) + "…"
+	}
+	now := time.Now()
+	return &Conversation{
+		ID:        s.prefix + hex.EncodeToString(b),
+		Title:     title,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// path returns the file for id, rejecting IDs that would escape the
+// directory.
+func (s *Store) path(id string) (string, error) {
+	if s.dir == "" {
+		return "", fmt.Errorf("conversations are not saved (no data directory)")
+	}
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid conversation ID %q", id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+// Load reads a saved conversation.
+func (s *Store) Load(id string) (*Conversation, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("conversation not found: %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c Conversation
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("reading conversation %s: %w", id, err)
+	}
+	return &c, nil
+}
+
+// Save writes c, replacing any earlier copy.
+func (s *Store) Save(c *Conversation) error {
+	path, err := s.path(c.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("creating conversation directory: %w", err)
+	}
+	c.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("saving conversation: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// List returns saved conversations, most recent first. A limit of zero
+// returns them all.
+func (s *Store) List(opts provider.ListOptions) ([]provider.Conversation, error) {
+	if s.dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var conversations []provider.Conversation
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		c, err := s.Load(id)
+		if err != nil {
+			if opts.LogFunc != nil {
+				opts.LogFunc("[localchat] skipping %s: %v", e.Name(), err)
+			}
+			continue
+		}
+		conversations = append(conversations, provider.Conversation{
+			ID:        c.ID,
+			Title:     c.Title,
+			CreatedAt: c.CreatedAt,
+			UpdatedAt: c.UpdatedAt,
+		})
+	}
+
+	sort.Slice(conversations, func(i, j int) bool {
+		return conversations[i].UpdatedAt.After(conversations[j].UpdatedAt)
+	})
+	return provider.Page(conversations, opts), nil
+}
+
+// Delete removes a saved conversation.
+func (s *Store) Delete(id string) error {
+	path, err := s.path(strings.TrimSpace(id))
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("conversation not found: %s", id)
+		}
+		return err
+	}
+	return nil
+}
+
+// Transcript returns a saved conversation. System instructions are left
+// out.
+func (s *Store) Transcript(id string) (*provider.Transcript, error) {
+	c, err := s.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	t := &provider.Transcript{
+		ID:        c.ID,
+		Title:     c.Title,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	for _, m := range c.Messages {
+		if m.Role == "system" {
+			continue
+		}
+		t.Messages = append(t.Messages, provider.Message{
+			Role:      m.Role,
+			Text:      m.Content,
+			Model:     m.Model,
+			CreatedAt: m.CreatedAt,
+		})
+	}
+	return t, nil
+}
@@ -0,0 +1,381 @@
+// Package ollama implements a provider for a local Ollama server.
+//
+// Ollama needs no login and keeps no chat history of its own: every
+// request to /api/chat carries the whole conversation. Conversations are
+// kept on disk with localchat so that -r, -c, list and delete behave like
+// they do for the web providers.
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+	"github.com/kyupark/ask/internal/provider/localchat"
+)
+
+const (
+	defaultBaseURL = "http://localhost:11434"
+	chatPath       = "/api/chat"
+	tagsPath       = "/api/tags"
+
+	conversationPrefix = "ol-"
+
+	// modelsTimeout bounds the model list request made by ListModels,
+	// which has no context of its own.
+	modelsTimeout = 5 * time.Second
+)
+
+// --- Request/Response types ---
+
+type chatMessage struct {
+	Role     string `json:"role"`
+	Content  string `json:"content"`
+	Thinking string `json:"thinking,omitempty"`
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+// chatChunk is one line of the NDJSON answer stream.
+type chatChunk struct {
+	Model   string      `json:"model"`
+	Message chatMessage `json:"message"`
+	Done    bool        `json:"done"`
+	// DoneReason is "length" when num_predict cut the answer short.
+	DoneReason string `json:"done_reason"`
+	Error      string `json:"error"`
+}
+
+type tagsResponse struct {
+	Models []struct {
+		Name    string `json:"name"`
+		Details struct {
+			Family            string `json:"family"`
+			ParameterSize     string `json:"parameter_size"`
+			QuantizationLevel string `json:"quantization_level"`
+		} `json:"details"`
+	} `json:"models"`
+}
+
+// Provider implements the Ollama backend.
+type Provider struct {
+	baseURL string
+	model   string
+	timeout time.Duration
+	proxy   string
+	store   *localchat.Store
+}
+
+// New creates an Ollama provider. An empty baseURL uses OLLAMA_HOST, then
+// http://localhost:11434. model is the default when AskOptions names none;
+// if both are empty the first installed model is used.
+func New(baseURL, model string, timeout time.Duration) *Provider {
+	if baseURL == "" {
+		baseURL = hostFromEnv()
+	}
+	return &Provider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   model,
+		timeout: timeout,
+		store:   localchat.New("", conversationPrefix),
+	}
+}
+
+// hostFromEnv returns the server URL named by OLLAMA_HOST, which the
+// ollama CLI also reads, or the default.
+func hostFromEnv() string {
+	host := strings.TrimSpace(os.Getenv("OLLAMA_HOST"))
+	if host == "" {
+		return defaultBaseURL
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return host
+}
+
+func (p *Provider) Name() string { return "ollama" }
+
+// CookieSpecs returns nothing: a local server needs no login.
+func (p *Provider) CookieSpecs() []provider.CookieSpec { return nil }
+
+func (p *Provider) SetCookies(map[string]string) {}
+
+// SetProxy routes requests through proxyURL instead of the environment's
+// HTTP_PROXY/ALL_PROXY. Only useful for a remote server.
+func (p *Provider) SetProxy(proxyURL string) { p.proxy = proxyURL }
+
+// SetDataDir sets where conversations are saved. Without it nothing is
+// saved and every question starts a new conversation.
+func (p *Provider) SetDataDir(dir string) { p.store = localchat.New(dir, conversationPrefix) }
+
+// BaseURL returns the server URL requests are sent to.
+func (p *Provider) BaseURL() string { return p.baseURL }
+
+func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	// 1. Load the conversation being continued, or start a new one.
+	var conv *localchat.Conversation
+	if opts.ConversationID != "" {
+		c, err := p.store.Load(opts.ConversationID)
+		if err != nil {
+			return err
+		}
+		conv = c
+		if opts.Retry {
+			conv.DropLastTurn()
+		}
+	} else {
+		conv = p.store.Create(query)
+		if sp := strings.TrimSpace(opts.SystemPrompt); sp != "" {
+			conv.Messages = append(conv.Messages, localchat.Message{Role: "system", Content: sp})
+		}
+	}
+
+	model := opts.Model
+	if model == "" {
+		model = p.model
+	}
+	if model == "" && opts.ConversationID != "" {
+		model = conv.Model
+	}
+	if model == "" {
+		m, err := p.firstModel(ctx)
+		if err != nil {
+			return err
+		}
+		model = m
+	}
+	logf("[ollama] model=%s conversation=%s", model, conv.ID)
+
+	// 2. Send the whole conversation plus the new question.
+	conv.Messages = append(conv.Messages, localchat.Message{Role: "user", Content: query, CreatedAt: time.Now()})
+	req := chatRequest{Model: model, Stream: true}
+	for _, m := range conv.Messages {
+		req.Messages = append(req.Messages, chatMessage{Role: m.Role, Content: m.Content})
+	}
+
+	var answer strings.Builder
+	streamOpts := opts
+	streamOpts.OnText = func(text string) {
+		answer.WriteString(text)
+		if opts.OnText != nil {
+			opts.OnText(text)
+		}
+	}
+	// An answer stopped part way by cancelling ctx is kept, as web chats
+	// keep a stopped reply, so the conversation can go on from it.
+	streamErr := p.streamChat(ctx, req, streamOpts, logf)
+	if streamErr != nil && (ctx.Err() == nil || answer.Len() == 0) {
+		return streamErr
+	}
+
+	// 3. Save the answered turn.
+	if opts.Temporary || !p.store.Enabled() {
+		return streamErr
+	}
+	conv.Model = model
+	conv.Messages = append(conv.Messages, localchat.Message{Role: "assistant", Content: answer.String(), Model: model, CreatedAt: time.Now()})
+	if err := p.store.Save(conv); err != nil {
+		return err
+	}
+	if opts.OnConversation != nil {
+		opts.OnConversation(conv.ID, "", "")
+	}
+	return streamErr
+}
+
+// streamChat posts to /api/chat and streams the answer.
+func (p *Provider) streamChat(ctx context.Context, body chatRequest, opts provider.AskOptions, logf func(string, ...any)) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshalling request: %w", err)
+	}
+
+	u := p.baseURL + chatPath
+	logf("[ollama] POST %s (%d messages)", u, len(body.Messages))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	resp, err := p.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return readStream(resp.Body, opts)
+}
+
+// readStream reads the NDJSON answer stream, one chatChunk per line.
+func readStream(r io.Reader, opts provider.AskOptions) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk chatChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			if opts.OnError != nil {
+				opts.OnError(fmt.Errorf("parsing chunk: %w", err))
+			}
+			continue
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("ollama: %s", chunk.Error)
+		}
+		if chunk.Message.Thinking != "" && opts.OnThinking != nil {
+			opts.OnThinking(chunk.Message.Thinking)
+		}
+		if chunk.Message.Content != "" && opts.OnText != nil {
+			opts.OnText(chunk.Message.Content)
+		}
+		if chunk.Done {
+			if chunk.DoneReason == "length" && opts.OnTruncated != nil {
+				opts.OnTruncated()
+			}
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading stream: %w", err)
+	}
+
+	if opts.OnDone != nil {
+		opts.OnDone()
+	}
+	return nil
+}
+
+// ListConversations lists saved conversations, most recent first.
+func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
+	return p.store.List(opts)
+}
+
+// DeleteConversation removes a saved conversation.
+func (p *Provider) DeleteConversation(ctx context.Context, conversationID string, opts provider.DeleteOptions) error {
+	return p.store.Delete(conversationID)
+}
+
+// FetchTranscript returns a saved conversation.
+func (p *Provider) FetchTranscript(ctx context.Context, conversationID string, opts provider.TranscriptOptions) (*provider.Transcript, error) {
+	return p.store.Transcript(conversationID)
+}
+
+// InstalledModels returns the models pulled on the server.
+func (p *Provider) InstalledModels(ctx context.Context) ([]provider.ModelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+tagsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+	var tags tagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("decoding model list: %w", err)
+	}
+
+	models := make([]provider.ModelInfo, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		info := provider.ModelInfo{ID: m.Name, Name: m.Name}
+		var parts []string
+		for _, s := range []string{m.Details.Family, m.Details.ParameterSize, m.Details.QuantizationLevel} {
+			if s != "" {
+				parts = append(parts, s)
+			}
+		}
+		info.Description = strings.Join(parts, ", ")
+		models = append(models, info)
+	}
+	return models, nil
+}
+
+// firstModel picks the model to use when none is configured.
+func (p *Provider) firstModel(ctx context.Context) (string, error) {
+	models, err := p.InstalledModels(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(models) == 0 {
+		return "", fmt.Errorf("no models installed — run: ollama pull llama3")
+	}
+	return models[0].ID, nil
+}
+
+// ListModels returns the installed models, marking the configured
+// default. It is empty when the server cannot be reached.
+func (p *Provider) ListModels() provider.ProviderModels {
+	ctx, cancel := context.WithTimeout(context.Background(), modelsTimeout)
+	defer cancel()
+
+	models, _ := p.InstalledModels(ctx)
+	for i := range models {
+		if models[i].ID == p.model || strings.TrimSuffix(models[i].ID, ":latest") == p.model {
+			models[i].Default = true
+		}
+	}
+	return provider.ProviderModels{Provider: "ollama", Models: models}
+}
+
+// do sends req, turning a refused connection into a hint to start the
+// server.
+func (p *Provider) do(req *http.Request) (*http.Response, error) {
+	resp, err := httpclient.NewWithProxy(p.timeout, p.proxy).Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return nil, fmt.Errorf("cannot reach Ollama at %s — is `ollama serve` running?", p.baseURL)
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// responseError reads Ollama's {"error": "..."} body from a failed
+// response.
+func responseError(resp *http.Response) error {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(b, &body) == nil && body.Error != "" {
+		return provider.Errorf(provider.StatusKind(resp.StatusCode, body.Error), "ollama: %s (HTTP %d)", body.Error, resp.StatusCode)
+	}
+	return provider.HTTPError(resp.StatusCode, b)
+}
@@ -0,0 +1,77 @@
+package perplexity
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// citationPattern matches the citation markers Perplexity puts in its
+// markdown: [1](https://...) links, [^1] footnotes and [web:1] tags. A
+// link's URL may hold balanced parentheses, as Wikipedia's often do.
+var citationPattern = regexp.MustCompile(`\[(\d+)\]\((https?://(?:[^()\s]|\([^()\s]*\))*)\)|\[\^(\d+)\]|\[web:(\d+)\]|\[(\d+)\]`)
+
+// strippedPattern is citationPattern with the spaces before a marker, so
+// removing one leaves no gap before the punctuation that follows.
+var strippedPattern = regexp.MustCompile(`[ \t]*(?:` + citationPattern.String() + `)`)
+
+// maxMarkerLen bounds how much trailing text is held back in case it is
+// the start of a marker; anything longer is not one.
+const maxMarkerLen = 512
+
+// citations turns citation markers into plain [n] footnotes numbered like
+// the source list, or removes them when inline is false.
+type citations struct {
+	inline bool
+	// index maps a source URL to its 1-based place in the source list.
+	index map[string]int
+}
+
+func (c *citations) add(url string) {
+	if c.index == nil {
+		c.index = map[string]int{}
+	}
+	if _, ok := c.index[url]; !ok {
+		c.index[url] = len(c.index) + 1
+	}
+}
+
+// rewrite replaces every complete marker in text.
+func (c *citations) rewrite(text string) string {
+	if !c.inline {
+		return strippedPattern.ReplaceAllString(text, "")
+	}
+	return citationPattern.ReplaceAllStringFunc(text, func(m string) string {
+		sub := citationPattern.FindStringSubmatch(m)
+		if sub[2] != "" {
+			if n, ok := c.index[sub[2]]; ok {
+				return "[" + strconv.Itoa(n) + "]"
+			}
+		}
+		for _, n := range sub[1:] {
+			if _, err := strconv.Atoi(n); err == nil {
+				return "[" + n + "]"
+			}
+		}
+		return m
+	})
+}
+
+// stable returns how much of text can be rewritten now: everything up to
+// a trailing "[" that a later chunk may still turn into a marker, and the
+// spaces before it.
+func stable(text string) int {
+	i := strings.LastIndexByte(text, '[')
+	if i < 0 || len(text)-i > maxMarkerLen {
+		return len(strings.TrimRight(text, " \t"))
+	}
+	tail := text[i:]
+	close := strings.IndexByte(tail, ']')
+	switch {
+	case close < 0, // "[1" — unfinished
+		close == len(tail)-1, // "[1]" — a "(url)" may follow
+		tail[close+1] == '(' && strings.Count(tail, "(") > strings.Count(tail, ")"): // "[1](https://..." — unfinished link
+		return len(strings.TrimRight(text[:i], " \t"))
+	}
+	return len(strings.TrimRight(text, " \t"))
+}
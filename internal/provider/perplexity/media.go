@@ -0,0 +1,108 @@
+package perplexity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// mediaBlock lists the images and videos found while searching. Like the
+// other blocks it is resent, growing, with each event.
+type mediaBlock struct {
+	MediaItems []mediaItem `json:"media_items"`
+}
+
+type mediaItem struct {
+	Medium    string `json:"medium"` // image, video
+	Name      string `json:"name"`
+	Image     string `json:"image"`
+	URL       string `json:"url"`
+	Thumbnail string `json:"thumbnail"`
+	Source    string `json:"source"`
+}
+
+// mediaCollector gathers the distinct media results of one answer.
+type mediaCollector struct {
+	seen  map[string]bool
+	items []provider.MediaResult
+}
+
+func (c *mediaCollector) add(items []mediaItem) {
+	for _, it := range items {
+		m := mediaResult(it)
+		if m.URL == "" || c.seen[m.URL] {
+			continue
+		}
+		if c.seen == nil {
+			c.seen = make(map[string]bool)
+		}
+		c.seen[m.URL] = true
+		c.items = append(c.items, m)
+	}
+}
+
+// mediaResult converts a media item. An image result points at the image
+// itself; a video result points at its page, with the image field as its
+// still.
+func mediaResult(it mediaItem) provider.MediaResult {
+	m := provider.MediaResult{
+		Kind:      "image",
+		Title:     strings.TrimSpace(it.Name),
+		URL:       it.Image,
+		PageURL:   it.URL,
+		Thumbnail: it.Thumbnail,
+	}
+	if strings.EqualFold(it.Medium, "video") {
+		m.Kind, m.URL = "video", it.URL
+	}
+	if m.URL == "" {
+		m.URL = it.URL
+	}
+	if m.Thumbnail == "" {
+		m.Thumbnail = it.Image
+	}
+	return m
+}
+
+// deliverMedia saves thumbnails to opts.ImageDir, if set, and reports
+// each result through opts.OnMedia. A failed download is logged and the
+// result is still reported by URL.
+func (p *Provider) deliverMedia(ctx context.Context, items []provider.MediaResult, opts provider.AskOptions, logf func(string, ...any)) {
+	if opts.OnMedia == nil && opts.ImageDir == "" {
+		return
+	}
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
+	stamp := time.Now().Format("20060102-150405")
+	for i, m := range items {
+		if opts.ImageDir != "" && m.Thumbnail != "" {
+			name := fmt.Sprintf("perplexity-%s-%d", stamp, i+1)
+			path, err := downloadThumbnail(ctx, client, m.Thumbnail, opts.ImageDir, name, p.userAgent, logf)
+			if err != nil {
+				logf("[perplexity] warning: could not save thumbnail %d: %v", i+1, err)
+			}
+			m.Path = path
+		}
+		if opts.OnMedia != nil {
+			opts.OnMedia(m)
+		}
+	}
+}
+
+func downloadThumbnail(ctx context.Context, client *http.Client, thumbURL, dir, name, userAgent string, logf func(string, ...any)) (string, error) {
+	logf("[perplexity] GET %s", thumbURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, thumbURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	return provider.SaveImage(resp, dir, name)
+}
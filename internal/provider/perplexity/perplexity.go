@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -51,17 +52,69 @@ type askParams struct {
 
 // askResponse is a single SSE event from the ask endpoint.
 type askResponse struct {
-	Blocks []block `json:"blocks"`
-	Status string  `json:"status"`
+	Blocks         []block  `json:"blocks"`
+	Status         string   `json:"status"`
+	RelatedQueries []string `json:"related_queries"`
 }
 
 type block struct {
-	MarkdownBlock  *markdownBlock  `json:"markdown_block,omitempty"`
-	WebResultBlock *webResultBlock `json:"web_result_block,omitempty"`
+	MarkdownBlock       *markdownBlock       `json:"markdown_block,omitempty"`
+	WebResultBlock      *webResultBlock      `json:"web_result_block,omitempty"`
+	RelatedQueriesBlock *relatedQueriesBlock `json:"related_queries_block,omitempty"`
+	MediaBlock          *mediaBlock          `json:"media_block,omitempty"`
+	PlanBlock           *planBlock           `json:"plan_block,omitempty"`
+}
+
+// relatedQueriesBlock carries suggested follow-up questions. Newer
+// streams send them as a block rather than the top-level related_queries.
+type relatedQueriesBlock struct {
+	RelatedQueries []relatedQuery `json:"related_queries"`
+}
+
+// relatedQuery is a suggested question, sent either as a bare string or
+// as an object with its text.
+type relatedQuery string
+
+func (q *relatedQuery) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*q = relatedQuery(text)
+		return nil
+	}
+	var obj struct {
+		Text  string `json:"text"`
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	if obj.Text == "" {
+		obj.Text = obj.Query
+	}
+	*q = relatedQuery(obj.Text)
+	return nil
+}
+
+// relatedQueries returns the event's suggested follow-ups from either
+// the top-level list or a related queries block.
+func (r askResponse) relatedQueries() []string {
+	related := r.RelatedQueries
+	for _, b := range r.Blocks {
+		if b.RelatedQueriesBlock == nil {
+			continue
+		}
+		for _, q := range b.RelatedQueriesBlock.RelatedQueries {
+			if text := strings.TrimSpace(string(q)); text != "" {
+				related = append(related, text)
+			}
+		}
+	}
+	return related
 }
 
 type markdownBlock struct {
 	Chunks []string `json:"chunks"`
+	Answer string   `json:"answer,omitempty"`
 }
 
 type webResultBlock struct {
@@ -78,10 +131,13 @@ type Provider struct {
 	baseURL       string
 	userAgent     string
 	timeout       time.Duration
+	proxy         string
 	cfClearance   string
 	sessionCookie string
 	modeOverride  string
 	focusOverride string
+	// stripCitations removes the [n] markers from answers.
+	stripCitations bool
 }
 
 // New creates a Perplexity provider with the given settings.
@@ -104,6 +160,10 @@ func (p *Provider) CookieSpecs() []provider.CookieSpec {
 	}
 }
 
+// SetProxy routes requests through proxyURL instead of the environment's
+// HTTPS_PROXY/ALL_PROXY.
+func (p *Provider) SetProxy(proxyURL string) { p.proxy = proxyURL }
+
 func (p *Provider) SetCookies(cookies map[string]string) {
 	if v := cookies[cookieCfClearance]; v != "" {
 		p.cfClearance = v
@@ -119,9 +179,13 @@ func (p *Provider) SetMode(mode string) { p.modeOverride = mode }
 // SetSearchFocus overrides the default search focus (internet, scholar, social, edgar, writing).
 func (p *Provider) SetSearchFocus(focus string) { p.focusOverride = focus }
 
+// SetCitations keeps [n] citation markers in answers, numbered like the
+// sources (the default), or removes them.
+func (p *Provider) SetCitations(inline bool) { p.stripCitations = !inline }
+
 func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptions) error {
 	if p.sessionCookie == "" {
-		return fmt.Errorf("no session cookie — log in to perplexity.ai in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to perplexity.ai in your browser")
 	}
 
 	logf := opts.LogFunc
@@ -129,6 +193,7 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 		logf = func(string, ...any) {}
 	}
 
+	query = provider.PrependSystemPrompt(query, opts)
 	reqBody := askRequest{
 		QueryStr: query,
 		Params: askParams{
@@ -182,7 +247,7 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 	}
 	req.AddCookie(&http.Cookie{Name: cookieSessionToken, Value: p.sessionCookie})
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -192,12 +257,20 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return provider.HTTPError(resp.StatusCode, body)
 	}
 
 	// Track total text length for delta — the API sends cumulative
-	// chunks where each event repeats prior text.
+	// chunks where each event repeats prior text. A trailing citation
+	// marker is held back until it is complete.
 	var totalPrinted int
+	var full string
+	var media mediaCollector
+	var research *researchProgress
+	if reqBody.Params.Mode == deepResearchMode && opts.OnProgress != nil {
+		research = &researchProgress{}
+	}
+	cites := citations{inline: !p.stripCitations}
 
 	err = sse.Read(resp.Body, func(event sse.Event) error {
 		var r askResponse
@@ -209,23 +282,38 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 		}
 
 		for _, b := range r.Blocks {
-			if b.MarkdownBlock != nil && opts.OnText != nil {
-				var full string
-				for _, chunk := range b.MarkdownBlock.Chunks {
-					full += chunk
+			if b.WebResultBlock != nil {
+				for _, src := range b.WebResultBlock.WebResults {
+					if src.URL == "" || cites.index[src.URL] != 0 {
+						continue
+					}
+					cites.add(src.URL)
+					if opts.OnSource != nil {
+						opts.OnSource(src.Name, src.URL)
+					}
 				}
-				if len(full) > totalPrinted {
-					opts.OnText(full[totalPrinted:])
-					totalPrinted = len(full)
+			}
+			if b.MarkdownBlock != nil && opts.OnText != nil {
+				full = strings.Join(b.MarkdownBlock.Chunks, "")
+				if cut := totalPrinted + stable(full[min(totalPrinted, len(full)):]); cut > totalPrinted {
+					opts.OnText(cites.rewrite(full[totalPrinted:cut]))
+					totalPrinted = cut
 				}
 			}
-			if b.WebResultBlock != nil && opts.OnSource != nil {
-				for _, src := range b.WebResultBlock.WebResults {
-					opts.OnSource(src.Name, src.URL)
+			if b.MediaBlock != nil {
+				media.add(b.MediaBlock.MediaItems)
+			}
+			if b.PlanBlock != nil && research != nil {
+				for _, line := range research.add(b.PlanBlock) {
+					opts.OnProgress(line)
 				}
 			}
 		}
 
+		if related := r.relatedQueries(); len(related) > 0 && opts.OnFollowUps != nil {
+			opts.OnFollowUps(related)
+		}
+
 		if r.Status == "COMPLETED" {
 			if opts.OnDone != nil {
 				opts.OnDone()
@@ -237,6 +325,13 @@ func (p *Provider) Ask(ctx context.Context, query string, opts provider.AskOptio
 	if err != nil {
 		return err
 	}
+	if len(full) > totalPrinted && opts.OnText != nil {
+		opts.OnText(cites.rewrite(full[totalPrinted:]))
+	}
+	if len(media.items) > 0 {
+		logf("[perplexity] %d media result(s)", len(media.items))
+		p.deliverMedia(ctx, media.items, opts, logf)
+	}
 	if opts.OnConversation != nil {
 		opts.OnConversation(reqBody.Params.FrontendContextUUID, "", "")
 	}
@@ -277,14 +372,19 @@ type threadDetails struct {
 }
 
 type threadEntry struct {
-	BackendUUID    string `json:"backend_uuid"`
-	ReadWriteToken string `json:"read_write_token"`
+	BackendUUID          string  `json:"backend_uuid"`
+	ReadWriteToken       string  `json:"read_write_token"`
+	QueryStr             string  `json:"query_str"`
+	ThreadTitle          string  `json:"thread_title"`
+	DisplayModel         string  `json:"display_model"`
+	EntryCreatedDatetime string  `json:"entry_created_datetime"`
+	Blocks               []block `json:"blocks"`
 }
 
 // ListConversations fetches recent threads from the Perplexity web API.
 func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOptions) ([]provider.Conversation, error) {
 	if p.sessionCookie == "" {
-		return nil, fmt.Errorf("no session cookie — log in to perplexity.ai in your browser")
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to perplexity.ai in your browser")
 	}
 
 	logf := opts.LogFunc
@@ -300,8 +400,8 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 	reqBody := listThreadsRequest{
 		Limit:      limit,
 		Ascending:  false,
-		Offset:     0,
-		SearchTerm: "",
+		Offset:     opts.Offset,
+		SearchTerm: opts.Search,
 	}
 
 	payload, err := json.Marshal(reqBody)
@@ -328,7 +428,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 	}
 	req.AddCookie(&http.Cookie{Name: cookieSessionToken, Value: p.sessionCookie})
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -337,7 +437,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, provider.HTTPError(resp.StatusCode, body)
 	}
 
 	var threads []threadItem
@@ -351,11 +451,13 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 			ID:    t.ContextUUID,
 			Title: t.Title,
 		}
+		// The last query is when the thread was last updated; the listing
+		// has no creation time, so it stands in for that too.
 		if t.LastQueryDatetime != "" {
 			if parsed, err := time.Parse(time.RFC3339Nano, t.LastQueryDatetime); err == nil {
-				c.CreatedAt = parsed
+				c.CreatedAt, c.UpdatedAt = parsed, parsed
 			} else if parsed, err := time.Parse(time.RFC3339, t.LastQueryDatetime); err == nil {
-				c.CreatedAt = parsed
+				c.CreatedAt, c.UpdatedAt = parsed, parsed
 			}
 		}
 		result = append(result, c)
@@ -367,7 +469,7 @@ func (p *Provider) ListConversations(ctx context.Context, opts provider.ListOpti
 
 func (p *Provider) DeleteConversation(ctx context.Context, conversationID string, opts provider.DeleteOptions) error {
 	if p.sessionCookie == "" {
-		return fmt.Errorf("no session cookie — log in to perplexity.ai in your browser")
+		return provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to perplexity.ai in your browser")
 	}
 	conversationID = strings.TrimSpace(conversationID)
 	if conversationID == "" {
@@ -387,7 +489,7 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 	entryUUID := ""
 	readWriteToken := strings.TrimSpace(thread.ReadWriteToken)
 	if strings.TrimSpace(thread.Slug) != "" {
-		details, err := p.fetchThreadDetails(ctx, thread.Slug)
+		details, err := p.fetchThreadDetails(ctx, thread.Slug, 10)
 		if err != nil {
 			logf("[perplexity] unable to fetch thread details for slug=%s: %v", thread.Slug, err)
 		} else {
@@ -439,7 +541,7 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 	}
 	req.AddCookie(&http.Cookie{Name: cookieSessionToken, Value: p.sessionCookie})
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
@@ -448,7 +550,7 @@ func (p *Provider) DeleteConversation(ctx context.Context, conversationID string
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return provider.HTTPError(resp.StatusCode, body)
 	}
 
 	logf("[perplexity] conversation deleted")
@@ -461,7 +563,7 @@ func (p *Provider) findThreadByContextID(ctx context.Context, contextID string)
 		return nil, fmt.Errorf("conversation ID is required")
 	}
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	for offset := 0; offset < 1000; offset += 50 {
 		reqBody := listThreadsRequest{Limit: 50, Ascending: false, Offset: offset, SearchTerm: ""}
 		payload, err := json.Marshal(reqBody)
@@ -494,7 +596,7 @@ func (p *Provider) findThreadByContextID(ctx context.Context, contextID string)
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
 			resp.Body.Close()
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+			return nil, provider.HTTPError(resp.StatusCode, body)
 		}
 
 		var threads []threadItem
@@ -519,7 +621,7 @@ func (p *Provider) findThreadByContextID(ctx context.Context, contextID string)
 	return nil, fmt.Errorf("conversation %s not found", contextID)
 }
 
-func (p *Provider) fetchThreadDetails(ctx context.Context, slug string) (*threadDetails, error) {
+func (p *Provider) fetchThreadDetails(ctx context.Context, slug string, limit int) (*threadDetails, error) {
 	slug = strings.TrimSpace(slug)
 	if slug == "" {
 		return nil, fmt.Errorf("thread slug is required")
@@ -530,7 +632,7 @@ func (p *Provider) fetchThreadDetails(ctx context.Context, slug string) (*thread
 	params.Set("with_schematized_response", "true")
 	params.Set("version", "2.18")
 	params.Set("source", "default")
-	params.Set("limit", "10")
+	params.Set("limit", strconv.Itoa(limit))
 	params.Set("offset", "0")
 	params.Set("from_first", "true")
 	u := fmt.Sprintf("%s%s/%s?%s", p.baseURL, threadPath, slug, params.Encode())
@@ -550,7 +652,7 @@ func (p *Provider) fetchThreadDetails(ctx context.Context, slug string) (*thread
 	}
 	req.AddCookie(&http.Cookie{Name: cookieSessionToken, Value: p.sessionCookie})
 
-	client := httpclient.New(p.timeout)
+	client := httpclient.NewWithProxy(p.timeout, p.proxy)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -559,7 +661,7 @@ func (p *Provider) fetchThreadDetails(ctx context.Context, slug string) (*thread
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, provider.HTTPError(resp.StatusCode, body)
 	}
 
 	var details threadDetails
@@ -569,6 +671,105 @@ func (p *Provider) fetchThreadDetails(ctx context.Context, slug string) (*thread
 	return &details, nil
 }
 
+// --- Transcripts ---
+
+// threadEntryLimit caps how many entries a transcript fetch requests.
+const threadEntryLimit = 100
+
+// FetchTranscript fetches every query and answer in a thread. The ID may
+// be the context UUID shown by list or the thread slug from the web URL.
+func (p *Provider) FetchTranscript(ctx context.Context, conversationID string, opts provider.TranscriptOptions) (*provider.Transcript, error) {
+	if p.sessionCookie == "" {
+		return nil, provider.Errorf(provider.ErrAuthExpired, "no session cookie — log in to perplexity.ai in your browser")
+	}
+	conversationID = strings.TrimSpace(conversationID)
+	if conversationID == "" {
+		return nil, fmt.Errorf("conversation ID is required")
+	}
+
+	logf := opts.LogFunc
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+
+	t := &provider.Transcript{ID: conversationID}
+	slug := conversationID
+	thread, err := p.findThreadByContextID(ctx, conversationID)
+	if err != nil {
+		logf("[perplexity] %v; trying %s as a slug", err, conversationID)
+	} else {
+		slug = thread.Slug
+		t.Title = thread.Title
+	}
+
+	logf("[perplexity] GET %s/%s", threadPath, slug)
+	details, err := p.fetchThreadDetails(ctx, slug, threadEntryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range details.Entries {
+		created := parseThreadTime(entry.EntryCreatedDatetime)
+		if t.Title == "" {
+			t.Title = entry.ThreadTitle
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = created
+		}
+		if !created.IsZero() {
+			t.UpdatedAt = created
+		}
+
+		if q := strings.TrimSpace(entry.QueryStr); q != "" {
+			t.Messages = append(t.Messages, provider.Message{Role: "user", Text: q, CreatedAt: created})
+		}
+
+		answer := provider.Message{Role: "assistant", Model: entry.DisplayModel, CreatedAt: created}
+		cites := citations{inline: !p.stripCitations}
+		for _, b := range entry.Blocks {
+			if b.MarkdownBlock != nil {
+				text := b.MarkdownBlock.Answer
+				if text == "" {
+					text = strings.Join(b.MarkdownBlock.Chunks, "")
+				}
+				if len(text) > len(answer.Text) {
+					answer.Text = text
+				}
+			}
+			if b.WebResultBlock != nil {
+				for _, src := range b.WebResultBlock.WebResults {
+					if src.URL == "" || cites.index[src.URL] != 0 {
+						continue
+					}
+					cites.add(src.URL)
+					answer.Sources = append(answer.Sources, provider.Source{Name: src.Name, URL: src.URL})
+				}
+			}
+		}
+		answer.Text = cites.rewrite(answer.Text)
+		if strings.TrimSpace(answer.Text) != "" {
+			t.Messages = append(t.Messages, answer)
+		}
+	}
+
+	logf("[perplexity] thread has %d entries", len(details.Entries))
+	return t, nil
+}
+
+func parseThreadTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t
+	}
+	// Some entries omit the zone; parse those as UTC.
+	if t, err := time.Parse("2006-01-02T15:04:05.999999", s); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
 // --- Model catalog ---
 
 // ListModels returns the available Perplexity models, modes, and search focuses.
@@ -0,0 +1,129 @@
+package perplexity
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kyupark/ask/internal/httpclient"
+	"github.com/kyupark/ask/internal/provider"
+)
+
+// replay serves the provider's requests from a cassette in testdata.
+func replay(t *testing.T, name string) {
+	t.Helper()
+	if err := httpclient.ReplayCassette(filepath.Join("testdata", name)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = httpclient.StopCassette() })
+}
+
+func TestAskReplay(t *testing.T) {
+	tests := []struct {
+		name     string
+		inline   bool
+		wantText string
+	}{
+		{"inline citations", true, "Go was designed at Google in 2007 [1]. It is open source [2]."},
+		{"stripped citations", false, "Go was designed at Google in 2007. It is open source."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay(t, "ask.json")
+
+			p := New("", "test-agent", 0)
+			p.SetCookies(map[string]string{cookieSessionToken: "test-session"})
+			p.SetCitations(tt.inline)
+
+			var text strings.Builder
+			var sources []provider.Source
+			var followUps []string
+			var convID string
+			done := false
+			err := p.Ask(context.Background(), "When was Go created?", provider.AskOptions{
+				OnText:         func(s string) { text.WriteString(s) },
+				OnSource:       func(name, url string) { sources = append(sources, provider.Source{Name: name, URL: url}) },
+				OnFollowUps:    func(q []string) { followUps = q },
+				OnConversation: func(id, _, _ string) { convID = id },
+				OnDone:         func() { done = true },
+			})
+			if err != nil {
+				t.Fatalf("Ask: %v", err)
+			}
+
+			if text.String() != tt.wantText {
+				t.Errorf("text = %q, want %q", text.String(), tt.wantText)
+			}
+			wantSources := []provider.Source{
+				{Name: "Go (programming language) - Wikipedia", URL: "https://en.wikipedia.org/wiki/Go_(programming_language)"},
+				{Name: "The Go Programming Language", URL: "https://go.dev/"},
+			}
+			if !slices.Equal(sources, wantSources) {
+				t.Errorf("sources = %v, want %v", sources, wantSources)
+			}
+			if want := []string{"Who designed Go?", "What is Go used for?"}; !slices.Equal(followUps, want) {
+				t.Errorf("follow-ups = %q, want %q", followUps, want)
+			}
+			if convID == "" {
+				t.Error("no conversation reported")
+			}
+			if !done {
+				t.Error("OnDone not called")
+			}
+		})
+	}
+}
+
+func TestAskReplayDeepResearch(t *testing.T) {
+	replay(t, "deep_research.json")
+
+	p := New("", "test-agent", 0)
+	p.SetCookies(map[string]string{cookieSessionToken: "test-session"})
+	p.SetMode(deepResearchMode)
+
+	var text strings.Builder
+	var progress []string
+	err := p.Ask(context.Background(), "History of Go", provider.AskOptions{
+		OnText:     func(s string) { text.WriteString(s) },
+		OnProgress: func(line string) { progress = append(progress, line) },
+	})
+	if err != nil {
+		t.Fatalf("Ask: %v", err)
+	}
+
+	if want := "Go was announced in 2009."; text.String() != want {
+		t.Errorf("text = %q, want %q", text.String(), want)
+	}
+	want := []string{
+		"Find when Go was created",
+		"Summarize its design goals",
+		"Searching Go language history…",
+		"Reading en.wikipedia.org, go.dev…",
+		"Reading go.dev, research.swtch.com, infoq.com and 1 more…",
+	}
+	if !slices.Equal(progress, want) {
+		t.Errorf("progress =\n%q\nwant\n%q", progress, want)
+	}
+}
+
+func TestStable(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"plain text", len("plain text")},
+		{"text [1", len("text")},
+		{"text [1]", len("text")},
+		{"text [1](https://a.example", len("text")},
+		{"text [1](https://a.example/).", len("text [1](https://a.example/).")},
+		{"text [1](https://a.example/Go_(lang)", len("text")},
+		{"text [web:2] more", len("text [web:2] more")},
+	}
+	for _, tt := range tests {
+		if got := stable(tt.in); got != tt.want {
+			t.Errorf("stable(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
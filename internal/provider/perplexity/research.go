@@ -0,0 +1,122 @@
+package perplexity
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// deepResearchMode is the mode whose plan is reported as progress.
+const deepResearchMode = "deep research"
+
+// maxReadHosts bounds how many sites one "Reading" line names.
+const maxReadHosts = 3
+
+// planBlock is the research plan: the goals Perplexity set itself and the
+// steps taken so far. Like the other blocks it is resent, growing, with
+// each event.
+type planBlock struct {
+	Goals []planGoal `json:"goals"`
+	Steps []planStep `json:"steps"`
+}
+
+type planGoal struct {
+	Description string `json:"description"`
+}
+
+type planStep struct {
+	StepType           string              `json:"step_type"` // SEARCH_WEB, SEARCH_RESULTS, READ_RESULTS, ...
+	SearchWebContent   *searchWebContent   `json:"search_web_content,omitempty"`
+	WebResultsContent  *webResultsContent  `json:"web_results_content,omitempty"`
+	ReadResultsContent *readResultsContent `json:"read_results_content,omitempty"`
+}
+
+type searchWebContent struct {
+	Queries []struct {
+		Query string `json:"query"`
+	} `json:"queries"`
+}
+
+type webResultsContent struct {
+	WebResults []webResult `json:"web_results"`
+}
+
+type readResultsContent struct {
+	URLs []string `json:"urls"`
+}
+
+// researchProgress turns the plan of a deep research answer into status
+// lines, reporting each goal, search and batch of pages read once.
+type researchProgress struct {
+	seen map[string]bool
+}
+
+// add returns the lines for what plan has that earlier events did not.
+func (r *researchProgress) add(plan *planBlock) []string {
+	var lines []string
+	for _, g := range plan.Goals {
+		if desc := strings.TrimSpace(g.Description); desc != "" && r.first("goal:"+desc) {
+			lines = append(lines, desc)
+		}
+	}
+	for _, s := range plan.Steps {
+		if s.SearchWebContent != nil {
+			for _, q := range s.SearchWebContent.Queries {
+				if query := strings.TrimSpace(q.Query); query != "" && r.first("search:"+query) {
+					lines = append(lines, "Searching "+query+"…")
+				}
+			}
+		}
+		var urls []string
+		if s.WebResultsContent != nil {
+			for _, w := range s.WebResultsContent.WebResults {
+				urls = append(urls, w.URL)
+			}
+		}
+		if s.ReadResultsContent != nil {
+			urls = append(urls, s.ReadResultsContent.URLs...)
+		}
+		if line := r.reading(urls); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// reading names the sites of urls not reported before, a few at most.
+func (r *researchProgress) reading(urls []string) string {
+	var hosts []string
+	hostSeen := map[string]bool{}
+	for _, u := range urls {
+		if u == "" || !r.first("read:"+u) {
+			continue
+		}
+		host := u
+		if parsed, err := url.Parse(u); err == nil && parsed.Host != "" {
+			host = strings.TrimPrefix(parsed.Host, "www.")
+		}
+		if !hostSeen[host] {
+			hostSeen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	switch {
+	case len(hosts) == 0:
+		return ""
+	case len(hosts) > maxReadHosts:
+		return fmt.Sprintf("Reading %s and %d more…", strings.Join(hosts[:maxReadHosts], ", "), len(hosts)-maxReadHosts)
+	}
+	return "Reading " + strings.Join(hosts, ", ") + "…"
+}
+
+// first reports whether key is new, and remembers it.
+func (r *researchProgress) first(key string) bool {
+	if r.seen == nil {
+		r.seen = map[string]bool{}
+	}
+	if r.seen[key] {
+		return false
+	}
+	r.seen[key] = true
+	return true
+}
@@ -3,6 +3,7 @@ package provider
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
@@ -16,8 +17,19 @@ type AskOptions struct {
 	ConversationID string
 	// ParentMessageID is the last message ID for continuation context.
 	ParentMessageID string
+	// Retry asks for a new answer to the last question of ConversationID
+	// instead of adding a turn; the query is that question. Providers
+	// without a regenerate flow send the question again.
+	Retry bool
 	// ResponseID is provider-specific continuation context (Gemini).
 	ResponseID string
+	// Attachments lists local file paths to upload with the question.
+	// Providers without upload support ignore it.
+	Attachments []string
+	// SystemPrompt holds standing instructions for every answer. Providers
+	// with a per-request instructions field send it there; the others
+	// prepend it to the first message of a new conversation.
+	SystemPrompt string
 
 	// OnConversation is called with conversation metadata for state persistence.
 	// Called once per Ask invocation with the conversation context.
@@ -25,8 +37,34 @@ type AskOptions struct {
 
 	// OnText is called with incremental text chunks as they arrive.
 	OnText func(text string)
+	// OnThinking is called with incremental reasoning-summary text, ahead
+	// of the answer, by providers that expose it.
+	OnThinking func(text string)
 	// OnSource is called with citation sources (name, url) when available.
 	OnSource func(name, url string)
+	// OnProgress is called with status lines from long-running modes such
+	// as deep research, before and between answer text.
+	OnProgress func(status string)
+	// ImageDir, when set, is where generated images and media thumbnails
+	// are downloaded.
+	ImageDir string
+	// OnImage is called for each image generated for the answer, after it
+	// is saved to ImageDir.
+	OnImage func(img GeneratedImage)
+	// DownloadDir, when set, is where files generated for the answer
+	// (code interpreter outputs, created documents) are downloaded.
+	DownloadDir string
+	// OnFile is called for each file generated for the answer, after it
+	// is saved to DownloadDir.
+	OnFile func(f GeneratedFile)
+	// OnMedia is called for each image or video search result, after its
+	// thumbnail is saved to ImageDir.
+	OnMedia func(m MediaResult)
+	// OnFollowUps is called with suggested follow-up questions when available.
+	OnFollowUps func(suggestions []string)
+	// OnTruncated is called when the answer stopped at the model's output
+	// length limit instead of finishing.
+	OnTruncated func()
 	// OnError is called for non-fatal errors during streaming.
 	OnError func(err error)
 	// OnDone is called when the stream completes.
@@ -65,9 +103,58 @@ type Conversation struct {
 
 // ListOptions configures a list invocation.
 type ListOptions struct {
-	Limit   int
-	Verbose bool
-	LogFunc func(format string, args ...any)
+	Limit int
+	// Offset skips that many of the most recent conversations.
+	Offset int
+	// Search keeps only conversations matching the term. Providers with
+	// server-side search (ChatGPT, Perplexity) match their content too;
+	// the others match titles.
+	Search   string
+	Archived bool // list archived conversations instead of active ones
+	Verbose  bool
+	LogFunc  func(format string, args ...any)
+}
+
+// searchWindow is how many recent conversations are fetched to search
+// titles locally.
+const searchWindow = 200
+
+// Want returns how many of the most recent conversations a provider
+// without server-side paging must fetch to serve opts with Page: the page
+// and those before it, or a wider window when searching titles. def is
+// the provider's page size when opts.Limit is unset.
+func (o ListOptions) Want(def int) int {
+	limit := o.Limit
+	if limit <= 0 {
+		limit = def
+	}
+	n := o.Offset + limit
+	if o.Search != "" {
+		n = max(n, searchWindow)
+	}
+	return n
+}
+
+// Page applies opts.Search as a title match, then opts.Offset and
+// opts.Limit, to conversations fetched most recent first.
+func Page(conversations []Conversation, opts ListOptions) []Conversation {
+	if term := strings.ToLower(strings.TrimSpace(opts.Search)); term != "" {
+		var matched []Conversation
+		for _, c := range conversations {
+			if strings.Contains(strings.ToLower(c.Title), term) {
+				matched = append(matched, c)
+			}
+		}
+		conversations = matched
+	}
+	if opts.Offset >= len(conversations) {
+		return nil
+	}
+	conversations = conversations[max(opts.Offset, 0):]
+	if opts.Limit > 0 && len(conversations) > opts.Limit {
+		conversations = conversations[:opts.Limit]
+	}
+	return conversations
 }
 
 type DeleteOptions struct {
@@ -84,6 +171,67 @@ type Deleter interface {
 	DeleteConversation(ctx context.Context, conversationID string, opts DeleteOptions) error
 }
 
+// UpdateOptions configures rename and archive invocations.
+type UpdateOptions struct {
+	Verbose bool
+	LogFunc func(format string, args ...any)
+}
+
+// Archiver is an optional interface for providers that can archive conversations.
+type Archiver interface {
+	ArchiveConversation(ctx context.Context, conversationID string, archived bool, opts UpdateOptions) error
+}
+
+// Sharer is an optional interface for providers that can publish a
+// conversation as a public link.
+type Sharer interface {
+	ShareConversation(ctx context.Context, conversationID string, opts UpdateOptions) (string, error)
+}
+
+// Renamer is an optional interface for providers that can retitle conversations.
+type Renamer interface {
+	RenameConversation(ctx context.Context, conversationID, title string, opts UpdateOptions) error
+}
+
+// Source is a citation attached to a message.
+type Source struct {
+	Name string
+	URL  string
+}
+
+// Message is a single turn in a conversation transcript.
+type Message struct {
+	// ID is the provider's message ID, when known. Asking with it as
+	// ParentMessageID branches the conversation after this message.
+	ID        string
+	Role      string // "user" or "assistant"
+	Text      string
+	Model     string
+	CreatedAt time.Time
+	Sources   []Source
+}
+
+// Transcript is the full content of a remote conversation.
+type Transcript struct {
+	ID        string
+	Title     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Messages  []Message
+}
+
+// TranscriptOptions configures a transcript fetch.
+type TranscriptOptions struct {
+	Verbose bool
+	LogFunc func(format string, args ...any)
+}
+
+// TranscriptFetcher is an optional interface for providers that can fetch
+// a conversation's full message history.
+type TranscriptFetcher interface {
+	FetchTranscript(ctx context.Context, conversationID string, opts TranscriptOptions) (*Transcript, error)
+}
+
 // ModelInfo describes a single model available from a provider.
 type ModelInfo struct {
 	ID          string   // API identifier (e.g. "gpt-5-2", "claude-opus-4-6")
@@ -109,7 +257,25 @@ type ProviderModels struct {
 	SearchFocus []ModeInfo  // Search focus options (optional, Perplexity)
 }
 
+// Warmer is an optional interface for providers that can do their
+// per-question setup, such as fetching tokens, ahead of time so the next
+// Ask starts sooner.
+type Warmer interface {
+	Warm(ctx context.Context, logf func(format string, args ...any)) error
+}
+
 // ModelLister is an optional interface for providers that expose their model catalog.
 type ModelLister interface {
 	ListModels() ProviderModels
 }
+
+// PrependSystemPrompt returns query with opts.SystemPrompt in front when
+// opts starts a new conversation. Continued conversations already carry
+// the instructions in their first message.
+func PrependSystemPrompt(query string, opts AskOptions) string {
+	sp := strings.TrimSpace(opts.SystemPrompt)
+	if sp == "" || opts.ConversationID != "" {
+		return query
+	}
+	return "<instructions>\n" + sp + "\n</instructions>\n\n" + query
+}
@@ -0,0 +1,107 @@
+// Package redact scrubs secrets and personal data from outbound text
+// before it is sent to a provider.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule is a single named redaction pattern.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Finding reports how many times a rule matched.
+type Finding struct {
+	Rule  string
+	Count int
+}
+
+// builtinRules are the rules available by name in config.
+var builtinRules = []Rule{
+	{Name: "private_keys", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{Name: "api_keys", Pattern: regexp.MustCompile(`\b(?:sk-(?:ant-|proj-)?[A-Za-z0-9_\-]{20,}|AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|xox[abposr]-[A-Za-z0-9\-]{10,}|AIza[0-9A-Za-z_\-]{35})\b`)},
+	{Name: "bearer_tokens", Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/\-]{16,}=*`)},
+	{Name: "secret_assignments", Pattern: regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|token|password|passwd)\b["']?\s*[:=]\s*["']?[^\s"',;]{6,}`)},
+	{Name: "emails", Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`)},
+	{Name: "ips", Pattern: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`)},
+}
+
+// BuiltinNames returns the names of the built-in rules.
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtinRules))
+	for _, r := range builtinRules {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// Redactor applies an ordered set of rules to text.
+type Redactor struct {
+	rules []Rule
+}
+
+// New builds a Redactor from built-in rule names (all built-ins when empty)
+// and custom name -> regex patterns.
+func New(builtin []string, custom map[string]string) (*Redactor, error) {
+	r := &Redactor{}
+
+	if len(builtin) == 0 {
+		r.rules = append(r.rules, builtinRules...)
+	} else {
+		for _, name := range builtin {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			found := false
+			for _, rule := range builtinRules {
+				if rule.Name == name {
+					r.rules = append(r.rules, rule)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown redaction rule %q (available: %s)", name, strings.Join(BuiltinNames(), ", "))
+			}
+		}
+	}
+
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(custom[name])
+		if err != nil {
+			return nil, fmt.Errorf("redaction pattern %q: %w", name, err)
+		}
+		r.rules = append(r.rules, Rule{Name: name, Pattern: re})
+	}
+
+	return r, nil
+}
+
+// Apply replaces every match with a [REDACTED:<rule>] marker and reports
+// which rules fired.
+func (r *Redactor) Apply(text string) (string, []Finding) {
+	var findings []Finding
+	for _, rule := range r.rules {
+		count := 0
+		marker := "[REDACTED:" + rule.Name + "]"
+		text = rule.Pattern.ReplaceAllStringFunc(text, func(string) string {
+			count++
+			return marker
+		})
+		if count > 0 {
+			findings = append(findings, Finding{Rule: rule.Name, Count: count})
+		}
+	}
+	return text, findings
+}
@@ -0,0 +1,241 @@
+// Package webpage fetches a web page and reduces it to readable text,
+// dropping scripts, navigation and other page furniture.
+package webpage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/kyupark/ask/internal/httpclient"
+)
+
+// maxBodyBytes caps how much of a page is read.
+const maxBodyBytes = 5 << 20
+
+// Page is the readable content of a fetched URL.
+type Page struct {
+	URL   string
+	Title string
+	Text  string
+}
+
+// Fetch downloads url and extracts its text. HTML is reduced to its main
+// content; plain text, Markdown and JSON are returned as they are. Other
+// content types, such as PDFs and images, are refused.
+func Fetch(ctx context.Context, url, userAgent, proxy string, timeout time.Duration) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,*/*;q=0.8")
+
+	resp, err := httpclient.NewWithProxy(timeout, proxy).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	body := io.LimitReader(resp.Body, maxBodyBytes)
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	page := &Page{URL: resp.Request.URL.String()}
+	switch {
+	case mediaType == "" || mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		page.Title, page.Text, err = Extract(body)
+	case strings.HasPrefix(mediaType, "text/") || mediaType == "application/json":
+		var data []byte
+		data, err = io.ReadAll(body)
+		page.Text = strings.TrimSpace(string(data))
+	default:
+		return nil, fmt.Errorf("fetching %s: can't read %s content", url, mediaType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", url, err)
+	}
+	return page, nil
+}
+
+// skipped holds the elements whose content is never part of the text.
+var skipped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Svg: true, atom.Iframe: true, atom.Canvas: true, atom.Form: true,
+	atom.Button: true, atom.Select: true, atom.Nav: true, atom.Header: true,
+	atom.Footer: true, atom.Aside: true, atom.Head: true,
+}
+
+// blocks holds the elements that start a new line in the text.
+var blocks = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true,
+	atom.Main: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Table: true, atom.Ul: true,
+	atom.Ol: true, atom.Dl: true, atom.Dt: true, atom.Dd: true, atom.Hr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true,
+	atom.H6: true, atom.Figcaption: true,
+}
+
+var headings = map[atom.Atom]string{
+	atom.H1: "# ", atom.H2: "## ", atom.H3: "### ",
+	atom.H4: "#### ", atom.H5: "##### ", atom.H6: "###### ",
+}
+
+// Extract returns the title and readable text of an HTML document. When
+// the page marks its content with <article> or <main>, only that is kept.
+func Extract(r io.Reader) (title, text string, err error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", "", err
+	}
+	if t := find(doc, atom.Title); t != nil {
+		title = collapse(textOf(t))
+	}
+	root := find(doc, atom.Article)
+	if root == nil {
+		root = find(doc, atom.Main)
+	}
+	if root == nil {
+		root = find(doc, atom.Body)
+	}
+	if root == nil {
+		root = doc
+	}
+
+	var w textWriter
+	w.walk(root)
+	return title, w.String(), nil
+}
+
+// find returns the first element of type a under n, depth first.
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if f := find(c, a); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func textOf(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+	}
+	return b.String()
+}
+
+// collapse folds runs of whitespace into single spaces.
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// textWriter builds the text line by line: inline content joins the
+// current line and block elements end it.
+type textWriter struct {
+	lines []string
+	cur   strings.Builder
+	pre   int
+}
+
+func (w *textWriter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		if w.pre > 0 {
+			w.cur.WriteString(n.Data)
+			return
+		}
+		s := collapse(n.Data)
+		if s == "" {
+			return
+		}
+		if w.cur.Len() > 0 && !strings.HasSuffix(w.cur.String(), " ") && startsWithSpace(n.Data) {
+			w.cur.WriteByte(' ')
+		}
+		w.cur.WriteString(s)
+		if endsWithSpace(n.Data) {
+			w.cur.WriteByte(' ')
+		}
+		return
+	case html.ElementNode:
+		if skipped[n.DataAtom] || hidden(n) {
+			return
+		}
+	}
+
+	block := n.Type == html.ElementNode && blocks[n.DataAtom]
+	if block {
+		w.endLine()
+		if prefix, ok := headings[n.DataAtom]; ok {
+			w.cur.WriteString(prefix)
+		} else if n.DataAtom == atom.Li {
+			w.cur.WriteString("- ")
+		}
+	}
+	if n.DataAtom == atom.Pre {
+		w.pre++
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c)
+	}
+	if n.DataAtom == atom.Pre {
+		w.pre--
+	}
+	if block {
+		w.endLine()
+	}
+}
+
+func (w *textWriter) endLine() {
+	line := strings.TrimRight(w.cur.String(), " ")
+	w.cur.Reset()
+	if strings.TrimSpace(line) == "" || line == "- " {
+		return
+	}
+	w.lines = append(w.lines, line)
+}
+
+func (w *textWriter) String() string {
+	w.endLine()
+	return strings.Join(w.lines, "\n")
+}
+
+// hidden reports whether an element is hidden from readers.
+func hidden(n *html.Node) bool {
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "hidden":
+			return true
+		case "aria-hidden":
+			if a.Val == "true" {
+				return true
+			}
+		case "style":
+			if strings.Contains(strings.ReplaceAll(a.Val, " ", ""), "display:none") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func startsWithSpace(s string) bool {
+	return s != "" && strings.ContainsRune(" \t\n\r\f", rune(s[0]))
+}
+
+func endsWithSpace(s string) bool {
+	return s != "" && strings.ContainsRune(" \t\n\r\f", rune(s[len(s)-1]))
+}